@@ -0,0 +1,418 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tar
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxSymlinks is the maximum number of symbolic links that FS.Open
+// follows while resolving a single name.
+const maxSymlinks = 40
+
+// An FS is a read-only file system view of a tar archive.
+// It implements fs.FS, fs.ReadDirFS and fs.StatFS.
+//
+// The archive is indexed the first time the FS is used.
+// The contents of individual files are not held in memory;
+// they are read on demand by seeking within the underlying archive.
+//
+// Entries follow the semantics of extracting the archive in order:
+// when several entries share a name, the last one wins.
+// Hard links are resolved to the entry they refer to, and
+// symbolic links are followed by Open and Stat but reported
+// as links by ReadDir.
+type FS struct {
+	r io.ReaderAt
+
+	once  sync.Once
+	err   error     // error encountered while indexing, if any
+	files []fsEntry // sorted by fsEntryLess
+}
+
+// NewFS returns an FS that reads the tar archive stored in r,
+// which must begin at offset 0.
+//
+// If r also implements io.ReaderAt, files may be read concurrently
+// without serializing access to r.
+func NewFS(r io.ReadSeeker) *FS {
+	ra, ok := r.(io.ReaderAt)
+	if !ok {
+		ra = &readSeekerAt{rs: r}
+	}
+	return &FS{r: ra}
+}
+
+// readSeekerAt adapts an io.ReadSeeker to an io.ReaderAt
+// by serializing Seek and Read calls.
+type readSeekerAt struct {
+	mu sync.Mutex
+	rs io.ReadSeeker
+}
+
+func (r *readSeekerAt) ReadAt(b []byte, off int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.rs.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := io.ReadFull(r.rs, b)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// An fsEntry is a single file or directory in an FS.
+// If hdr == nil, the entry describes a directory that is only
+// implied by the names of other entries.
+type fsEntry struct {
+	name  string
+	hdr   *Header
+	isDir bool
+	isDup bool // conflicting file and directory entries
+
+	// linkName is the name of the symbolic link this entry was
+	// reached through, if any, which is the name it reports.
+	linkName string
+
+	offset int64       // offset of the data section in the archive
+	size   int64       // physical size of the data section
+	sp     sparseHoles // sparse holes; nil unless a sparse file
+}
+
+// index reads every header in the archive and builds fsys.files.
+func (fsys *FS) index() {
+	fsys.once.Do(func() {
+		fsys.err = fsys.buildIndex()
+	})
+}
+
+func (fsys *FS) buildIndex() error {
+	// The section is larger than any real archive; the reader
+	// stops at the end-of-archive marker or the real end of r.
+	sr := io.NewSectionReader(fsys.r, 0, 1<<63-1)
+	tr := NewReader(sr)
+
+	byName := make(map[string]int) // name to index in entries
+	var entries []fsEntry
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != ErrInsecurePath {
+			return err
+		}
+		name := toValidName(hdr.Name)
+		if name == "" || name == "." {
+			continue
+		}
+		e := fsEntry{
+			name:  name,
+			hdr:   hdr,
+			isDir: hdr.Typeflag == TypeDir,
+			size:  tr.curr.physicalRemaining(),
+		}
+		if e.offset, err = sr.Seek(0, io.SeekCurrent); err != nil {
+			return err
+		}
+		if sfr, ok := tr.curr.(*sparseFileReader); ok {
+			e.sp = append(sparseHoles{}, sfr.sp...)
+		}
+		if idx, ok := byName[name]; ok {
+			entries[idx] = e
+			continue
+		}
+		byName[name] = len(entries)
+		entries = append(entries, e)
+	}
+
+	// Resolve hard links to the entry they refer to,
+	// keeping the name of the link.
+	for i := range entries {
+		e := &entries[i]
+		if e.hdr.Typeflag != TypeLink {
+			continue
+		}
+		idx, ok := byName[toValidName(e.hdr.Linkname)]
+		if !ok || entries[idx].hdr.Typeflag == TypeLink {
+			continue
+		}
+		target := entries[idx]
+		hdr := *target.hdr
+		hdr.Name = e.hdr.Name
+		e.hdr = &hdr
+		e.isDir = target.isDir
+		e.offset, e.size, e.sp = target.offset, target.size, target.sp
+	}
+
+	// Synthesize entries for directories implied by other names.
+	dirs := make(map[string]bool)
+	for _, e := range entries {
+		for dir := path.Dir(e.name); dir != "."; dir = path.Dir(dir) {
+			dirs[dir] = true
+		}
+	}
+	for dir := range dirs {
+		if idx, ok := byName[dir]; ok {
+			if !entries[idx].isDir {
+				entries[idx].isDup = true
+			}
+			continue
+		}
+		entries = append(entries, fsEntry{name: dir, isDir: true})
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return fsEntryLess(entries[i].name, entries[j].name) })
+	fsys.files = entries
+	return nil
+}
+
+// toValidName coerces name to be a valid name for fs.FS.Open.
+func toValidName(name string) string {
+	name = strings.ReplaceAll(name, `\`, `/`)
+	p := path.Clean(name)
+	p = strings.TrimPrefix(p, "/")
+	for strings.HasPrefix(p, "../") {
+		p = p[len("../"):]
+	}
+	if p == ".." {
+		return ""
+	}
+	return p
+}
+
+func fsEntryLess(x, y string) bool {
+	xdir, xelem := path.Split(x)
+	ydir, yelem := path.Split(y)
+	return xdir < ydir || xdir == ydir && xelem < yelem
+}
+
+// lookup returns the entry for name, or nil if there is none.
+func (fsys *FS) lookup(name string) *fsEntry {
+	if name == "." {
+		return &fsEntry{name: ".", isDir: true}
+	}
+	dir, elem := path.Split(name)
+	files := fsys.files
+	i := sort.Search(len(files), func(i int) bool {
+		idir, ielem := path.Split(files[i].name)
+		return idir > dir || idir == dir && ielem >= elem
+	})
+	if i < len(files) && files[i].name == name {
+		return &files[i]
+	}
+	return nil
+}
+
+// readDir returns the entries contained in the directory dir.
+func (fsys *FS) readDir(dir string) []fsEntry {
+	prefix := dir + "/"
+	if dir == "." {
+		prefix = ""
+	}
+	files := fsys.files
+	i := sort.Search(len(files), func(i int) bool {
+		idir, _ := path.Split(files[i].name)
+		return idir >= prefix
+	})
+	j := sort.Search(len(files), func(j int) bool {
+		jdir, _ := path.Split(files[j].name)
+		return jdir > prefix
+	})
+	return files[i:j]
+}
+
+// resolve looks up name, following symbolic links in its final element.
+func (fsys *FS) resolve(op, name string) (*fsEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	fsys.index()
+	if fsys.err != nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fsys.err}
+	}
+	target := name
+	for n := 0; ; n++ {
+		e := fsys.lookup(target)
+		if e == nil {
+			return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		}
+		if e.hdr == nil || e.hdr.Typeflag != TypeSymlink {
+			if target != name {
+				l := *e
+				l.linkName = name
+				e = &l
+			}
+			return e, nil
+		}
+		if n == maxSymlinks {
+			return nil, &fs.PathError{Op: op, Path: name, Err: errors.New("too many links")}
+		}
+		link := e.hdr.Linkname
+		if !strings.HasPrefix(link, "/") {
+			link = path.Join(path.Dir(target), link)
+		}
+		target = toValidName(link)
+		if target == "" {
+			return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		}
+	}
+}
+
+// Open opens the named file in the archive,
+// using the semantics of fs.FS.Open:
+// paths are always slash separated, with no
+// leading / or ../ elements.
+func (fsys *FS) Open(name string) (fs.File, error) {
+	e, err := fsys.resolve("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if e.isDup {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: errDupEntry}
+	}
+	if e.isDir {
+		return &openFSDir{e: e, files: fsys.readDir(e.name)}, nil
+	}
+	var fr fileReader = &regFileReader{r: io.NewSectionReader(fsys.r, e.offset, e.size), nb: e.size}
+	if e.sp != nil {
+		fr = &sparseFileReader{fr, append(sparseHoles{}, e.sp...), 0}
+	}
+	return &openFSFile{e: e, fr: fr}, nil
+}
+
+// Stat returns a FileInfo describing the named file.
+func (fsys *FS) Stat(name string) (fs.FileInfo, error) {
+	e, err := fsys.resolve("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := e.stat()
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+	}
+	return fi, nil
+}
+
+// ReadDir reads the named directory
+// and returns a list of directory entries sorted by filename.
+func (fsys *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	e, err := fsys.resolve("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !e.isDir || e.isDup {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+	d := &openFSDir{e: e, files: fsys.readDir(e.name)}
+	list, err := d.ReadDir(-1)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	return list, nil
+}
+
+var errDupEntry = errors.New("archive/tar: conflicting file and directory entries")
+
+type fsFileInfo interface {
+	fs.FileInfo
+	fs.DirEntry
+}
+
+func (e *fsEntry) stat() (fsFileInfo, error) {
+	if e.isDup {
+		return nil, errDupEntry
+	}
+	if e.hdr == nil {
+		return e, nil
+	}
+	hdr := e.hdr
+	if e.linkName != "" {
+		h := *hdr
+		h.Name = e.linkName
+		hdr = &h
+	}
+	return fsHeaderInfo{headerFileInfo{hdr}}, nil
+}
+
+// Only used for implied directories.
+func (e *fsEntry) Name() string {
+	if e.linkName != "" {
+		return path.Base(e.linkName)
+	}
+	return path.Base(e.name)
+}
+
+func (e *fsEntry) Size() int64                { return 0 }
+func (e *fsEntry) Mode() fs.FileMode          { return fs.ModeDir | 0555 }
+func (e *fsEntry) Type() fs.FileMode          { return fs.ModeDir }
+func (e *fsEntry) IsDir() bool                { return true }
+func (e *fsEntry) ModTime() time.Time         { return time.Time{} }
+func (e *fsEntry) Sys() any                   { return nil }
+func (e *fsEntry) Info() (fs.FileInfo, error) { return e, nil }
+
+// fsHeaderInfo extends headerFileInfo to implement fs.DirEntry.
+type fsHeaderInfo struct {
+	headerFileInfo
+}
+
+func (fi fsHeaderInfo) Type() fs.FileMode          { return fi.Mode().Type() }
+func (fi fsHeaderInfo) Info() (fs.FileInfo, error) { return fi.headerFileInfo, nil }
+
+// openFSFile is an open regular file in an FS.
+type openFSFile struct {
+	e  *fsEntry
+	fr fileReader
+}
+
+func (f *openFSFile) Stat() (fs.FileInfo, error) { return f.e.stat() }
+func (f *openFSFile) Read(b []byte) (int, error) { return f.fr.Read(b) }
+func (f *openFSFile) Close() error               { return nil }
+
+// openFSDir is an open directory in an FS.
+type openFSDir struct {
+	e      *fsEntry
+	files  []fsEntry
+	offset int
+}
+
+func (d *openFSDir) Close() error               { return nil }
+func (d *openFSDir) Stat() (fs.FileInfo, error) { return d.e.stat() }
+
+func (d *openFSDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.e.name, Err: errors.New("is a directory")}
+}
+
+func (d *openFSDir) ReadDir(count int) ([]fs.DirEntry, error) {
+	n := len(d.files) - d.offset
+	if count > 0 && n > count {
+		n = count
+	}
+	if n == 0 {
+		if count <= 0 {
+			return nil, nil
+		}
+		return nil, io.EOF
+	}
+	list := make([]fs.DirEntry, n)
+	for i := range list {
+		s, err := d.files[d.offset+i].stat()
+		if err != nil {
+			return nil, err
+		}
+		list[i] = s
+	}
+	d.offset += n
+	return list, nil
+}
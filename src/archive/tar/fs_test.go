@@ -0,0 +1,221 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tar
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// onlyReadSeeker hides any methods of r other than Read and Seek.
+type onlyReadSeeker struct{ io.ReadSeeker }
+
+func TestFS(t *testing.T) {
+	for _, test := range []struct {
+		file string
+		want []string
+	}{
+		{"testdata/file-and-dir.tar", []string{"small.txt", "dir"}},
+		{"testdata/hardlink.tar", []string{"file.txt", "hard.txt"}},
+		{"testdata/sparse-formats.tar", []string{"sparse-gnu", "sparse-posix-0.0", "sparse-posix-0.1", "sparse-posix-1.0", "end"}},
+		{"testdata/gnu.tar", []string{"small.txt", "small2.txt"}},
+		{"testdata/pax.tar", []string{"a/b"}},
+		{"testdata/writer.tar", []string{"small.txt", "small2.txt", "link.txt"}},
+	} {
+		t.Run(test.file, func(t *testing.T) {
+			f, err := os.Open(test.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			if err := fstest.TestFS(NewFS(f), test.want...); err != nil {
+				t.Error(err)
+			}
+			if err := fstest.TestFS(NewFS(onlyReadSeeker{f}), test.want...); err != nil {
+				t.Error(err)
+			}
+			// Files reached through symbolic links report the
+			// name that was asked for.
+			fsys := NewFS(f)
+			for _, name := range test.want {
+				fi, err := fs.Stat(fsys, name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got, want := fi.Name(), path.Base(name); got != want {
+					t.Errorf("Stat(%q).Name() = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFSContents(t *testing.T) {
+	f, err := os.Open("testdata/sparse-formats.tar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// Compare every file against a sequential read of the archive.
+	fsys := NewFS(f)
+	tr := NewReader(io.NewSectionReader(f, 0, 1<<62))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := fs.ReadFile(fsys, hdr.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("ReadFile(%q) = %q, want %q", hdr.Name, got, want)
+		}
+	}
+
+	hf, err := os.Open("testdata/hardlink.tar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer hf.Close()
+	hfs := NewFS(hf)
+	file, err := fs.ReadFile(hfs, "file.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hard, err := fs.ReadFile(hfs, "hard.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(file, hard) {
+		t.Errorf("hard link contents = %q, want %q", hard, file)
+	}
+}
+
+func TestFSLinksAndDups(t *testing.T) {
+	var buf bytes.Buffer
+	tw := NewWriter(&buf)
+	for _, e := range []struct {
+		hdr  Header
+		data string
+	}{
+		{Header{Name: "a/b/old.txt", Typeflag: TypeReg, Mode: 0644}, "old"},
+		{Header{Name: "a/b/old.txt", Typeflag: TypeReg, Mode: 0644}, "new"},
+		{Header{Name: "a/link", Typeflag: TypeSymlink, Linkname: "b/old.txt"}, ""},
+		{Header{Name: "loop", Typeflag: TypeSymlink, Linkname: "loop"}, ""},
+		{Header{Name: "dirlink", Typeflag: TypeSymlink, Linkname: "a/b"}, ""},
+		{Header{Name: "/abs.txt", Typeflag: TypeReg, Mode: 0600}, "abs"},
+	} {
+		e.hdr.Size = int64(len(e.data))
+		if err := tw.WriteHeader(&e.hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(tw, e.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	fsys := NewFS(bytes.NewReader(buf.Bytes()))
+	for name, want := range map[string]string{
+		"a/b/old.txt": "new",
+		"a/link":      "new",
+		"abs.txt":     "abs",
+	} {
+		got, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			t.Errorf("ReadFile(%q): %v", name, err)
+		} else if string(got) != want {
+			t.Errorf("ReadFile(%q) = %q, want %q", name, got, want)
+		}
+	}
+	if _, err := fsys.Open("loop"); err == nil || !strings.Contains(err.Error(), "too many links") {
+		t.Errorf("Open(loop) = %v, want too many links", err)
+	}
+
+	entries, err := fsys.ReadDir("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Name() != "b" || !entries[0].IsDir() ||
+		entries[1].Name() != "link" || entries[1].Type() != fs.ModeSymlink {
+		t.Errorf("ReadDir(a) = %v, want [b link]", entries)
+	}
+
+	fi, err := fs.Stat(fsys, "dirlink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Name() != "dirlink" || !fi.IsDir() {
+		t.Errorf("Stat(dirlink) = %q, IsDir %v; want dirlink, true", fi.Name(), fi.IsDir())
+	}
+	entries, err = fsys.ReadDir("dirlink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "old.txt" {
+		t.Errorf("ReadDir(dirlink) = %v, want [old.txt]", entries)
+	}
+}
+
+func TestFSRoundTrip(t *testing.T) {
+	modTime := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	src := fstest.MapFS{
+		"file.go":         {Data: []byte("package main"), Mode: 0644, ModTime: modTime},
+		"subfolder/a.txt": {Data: []byte("a"), Mode: 0600, ModTime: modTime},
+		"empty":           {Mode: fs.ModeDir | 0755, ModTime: modTime},
+	}
+	var buf bytes.Buffer
+	tw := NewWriter(&buf)
+	if err := tw.AddFS(src); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	fsys := NewFS(bytes.NewReader(buf.Bytes()))
+	if err := fstest.TestFS(fsys, "file.go", "subfolder/a.txt", "empty"); err != nil {
+		t.Fatal(err)
+	}
+	for name, f := range src {
+		fi, err := fs.Stat(fsys, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Mode() != f.Mode {
+			t.Errorf("%s: mode = %v, want %v", name, fi.Mode(), f.Mode)
+		}
+		if !fi.ModTime().Equal(modTime) {
+			t.Errorf("%s: modtime = %v, want %v", name, fi.ModTime(), modTime)
+		}
+		if f.Mode.IsRegular() {
+			data, err := fs.ReadFile(fsys, name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(data, f.Data) {
+				t.Errorf("%s: data = %q, want %q", name, data, f.Data)
+			}
+		}
+	}
+}
@@ -5,8 +5,10 @@
 package tar
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"path"
 	"sort"
 	"strings"
@@ -463,6 +465,52 @@ func (tw *Writer) readFrom(r io.Reader) (int64, error) {
 	return n, err
 }
 
+// AddFS adds the files from fsys to the archive.
+// It walks the directory tree starting at the root of the file system,
+// adding a header for each directory and each regular file, followed by
+// the file's contents. Permission bits and modification times are
+// taken from the files' fs.FileInfo.
+// AddFS returns an error if it encounters any other kind of file,
+// such as a symbolic link or device.
+func (tw *Writer) AddFS(fsys fs.FS) error {
+	return fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name == "." {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && !info.Mode().IsRegular() {
+			return errors.New("archive/tar: cannot add non-regular file " + name)
+		}
+		h, err := FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		h.Name = name
+		if d.IsDir() {
+			h.Name += "/"
+		}
+		if err := tw.WriteHeader(h); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		f, err := fsys.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+}
+
 // Close closes the tar archive by flushing the padding, and writing the footer.
 // If the current file (from a prior call to WriteHeader) is not fully written,
 // then this returns an error.
@@ -9,12 +9,14 @@ import (
 	"encoding/hex"
 	"errors"
 	"io"
+	"io/fs"
 	"os"
 	"path"
 	"reflect"
 	"sort"
 	"strings"
 	"testing"
+	"testing/fstest"
 	"testing/iotest"
 	"time"
 )
@@ -1333,3 +1335,14 @@ func TestFileWriter(t *testing.T) {
 		}
 	}
 }
+
+func TestWriterAddFSNonRegular(t *testing.T) {
+	fsys := fstest.MapFS{
+		"file":    {Data: []byte("hello")},
+		"symlink": {Data: []byte("file"), Mode: fs.ModeSymlink | 0777},
+	}
+	tw := NewWriter(io.Discard)
+	if err := tw.AddFS(fsys); err == nil {
+		t.Fatal("AddFS succeeded with a symbolic link in the file system")
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"hash"
 	"hash/crc32"
 	"io"
+	"io/fs"
 	"strings"
 	"unicode/utf8"
 )
@@ -485,6 +486,56 @@ func (w *Writer) Copy(f *File) error {
 	return err
 }
 
+// AddFS adds the files from fsys to the archive.
+// It walks the directory tree starting at the root of the file system,
+// adding an entry for each directory and each regular file while
+// preserving the directory structure. Permission bits and modification
+// times are taken from the files' fs.FileInfo, and file contents are
+// compressed using the Deflate method.
+// AddFS returns an error if it encounters any other kind of file,
+// such as a symbolic link or device.
+func (w *Writer) AddFS(fsys fs.FS) error {
+	return fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name == "." {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && !info.Mode().IsRegular() {
+			return errors.New("zip: cannot add non-regular file " + name)
+		}
+		h, err := FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		h.Name = name
+		if d.IsDir() {
+			h.Name += "/"
+		} else {
+			h.Method = Deflate
+		}
+		fw, err := w.CreateHeader(h)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		f, err := fsys.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(fw, f)
+		return err
+	})
+}
+
 // RegisterCompressor registers or overrides a custom compressor for a specific
 // method ID. If a compressor for a given method is not found, Writer will
 // default to looking up the compressor at the package level.
@@ -16,6 +16,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"testing/fstest"
 	"time"
 )
 
@@ -532,6 +533,51 @@ func TestWriterCreateRaw(t *testing.T) {
 	}
 }
 
+func TestWriterAddFS(t *testing.T) {
+	modTime := time.Date(2022, 1, 2, 3, 4, 6, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"file.go":         {Data: []byte("package main"), Mode: 0644, ModTime: modTime},
+		"subfolder/a.txt": {Data: []byte("a"), Mode: 0600, ModTime: modTime},
+		"empty":           {Mode: fs.ModeDir | 0755, ModTime: modTime},
+	}
+	buf := new(bytes.Buffer)
+	w := NewWriter(buf)
+	if err := w.AddFS(fsys); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fstest.TestFS(r, "file.go", "subfolder/a.txt", "empty"); err != nil {
+		t.Fatal(err)
+	}
+	for name, f := range fsys {
+		fi, err := fs.Stat(r, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.IsDir() != f.Mode.IsDir() {
+			t.Errorf("%s: IsDir = %v, want %v", name, fi.IsDir(), f.Mode.IsDir())
+		}
+		if f.Mode.IsRegular() && fi.Mode() != f.Mode {
+			t.Errorf("%s: mode = %v, want %v", name, fi.Mode(), f.Mode)
+		}
+		if !fi.ModTime().Equal(modTime) {
+			t.Errorf("%s: modtime = %v, want %v", name, fi.ModTime(), modTime)
+		}
+	}
+
+	fsys["symlink"] = &fstest.MapFile{Data: []byte("file.go"), Mode: fs.ModeSymlink | 0777}
+	if err := NewWriter(io.Discard).AddFS(fsys); err == nil {
+		t.Error("AddFS succeeded with a symbolic link in the file system")
+	}
+}
+
 func testCreate(t *testing.T, w *Writer, wt *WriteTest) {
 	header := &FileHeader{
 		Name:   wt.Name,
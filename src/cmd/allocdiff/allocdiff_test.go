@@ -0,0 +1,121 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"internal/testenv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	mk := func(file string, line uint, msg string) *site {
+		return &site{Package: "p", File: file, Line: line, Message: msg}
+	}
+	oldSites := []*site{
+		mk("/old/a.go", 10, "moved to heap: x"),
+		mk("/old/a.go", 20, "new(int) escapes to heap"),
+		mk("/old/a.go", 30, "new(int) escapes to heap"),
+		mk("/old/b.go", 5, "moved to heap: y"),
+	}
+	newSites := []*site{
+		mk("/new/a.go", 12, "moved to heap: x"),         // moved down
+		mk("/new/a.go", 20, "new(int) escapes to heap"), // unchanged
+		mk("/new/a.go", 25, "new(int) escapes to heap"), // added
+		mk("/new/a.go", 30, "new(int) escapes to heap"), // unchanged
+		mk("/new/a.go", 40, "make([]int, n) escapes to heap"),
+	}
+	added, removed := diff(oldSites, newSites)
+
+	var got []string
+	for _, s := range removed {
+		got = append(got, "- "+s.String())
+	}
+	for _, s := range added {
+		got = append(got, "+ "+s.String())
+	}
+	want := []string{
+		"- /old/b.go:5:0: moved to heap: y",
+		"+ /new/a.go:25:0: new(int) escapes to heap",
+		"+ /new/a.go:40:0: make([]int, n) escapes to heap",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("diff:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// TestBuilds compares the logs of two compilations of a package.
+func TestBuilds(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+
+	const oldSrc = `package p
+
+var sink *int
+
+func f() *int {
+	x := 0
+	return &x
+}
+`
+	const newSrc = `package p
+
+var sink *int
+
+// g is new.
+func g() {
+	sink = new(int)
+}
+
+func f() *int {
+	x := 0
+	return &x
+}
+`
+	dir := t.TempDir()
+	compile := func(name, src string) string {
+		d := filepath.Join(dir, name)
+		if err := os.Mkdir(d, 0777); err != nil {
+			t.Fatal(err)
+		}
+		file := filepath.Join(d, "p.go")
+		if err := os.WriteFile(file, []byte(src), 0666); err != nil {
+			t.Fatal(err)
+		}
+		cmd := testenv.Command(t, testenv.GoToolPath(t), "tool", "compile", "-p=p", "-json=0,file://log", "-o", "p.o", file)
+		cmd.Dir = d
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("%v: %v\n%s", cmd, err, out)
+		}
+		return filepath.Join(d, "log")
+	}
+	oldSites, err := readSites(compile("old", oldSrc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	newSites, err := readSites(compile("new", newSrc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(oldSites) != 1 || len(newSites) != 2 {
+		t.Fatalf("got %d old and %d new sites, want 1 and 2", len(oldSites), len(newSites))
+	}
+
+	added, removed := diff(oldSites, newSites)
+	if len(removed) != 0 {
+		t.Errorf("removed = %v, want none", removed)
+	}
+	if len(added) != 1 {
+		t.Fatalf("added = %v, want 1 site", added)
+	}
+	s := added[0]
+	if s.Line != 7 || s.Message != "new(int) escapes to heap" {
+		t.Errorf("added %v, want line 7: new(int) escapes to heap", s)
+	}
+	if len(s.Why) == 0 || !strings.HasSuffix(s.Why[len(s.Why)-1], "escdest: {heap}") {
+		t.Errorf("explanation %q does not end at the heap", s.Why)
+	}
+}
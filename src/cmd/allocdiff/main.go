@@ -0,0 +1,279 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Allocdiff compares the heap allocation sites of two builds.
+
+Usage:
+
+	go tool allocdiff [-v] old new
+
+Old and new are directories holding the optimization logs of the two
+builds, written by the compiler's -json flag, for example by
+
+	go build -gcflags=all=-json=0,file:///tmp/old ./...
+
+The compiler logs an "escape" record for each expression or variable
+that escape analysis decides to allocate on the heap. Allocdiff prints
+the sites that are only in the new build, prefixed with "+", and those
+that are only in the old build, prefixed with "-". Sites are matched by
+package, file name and message, such as "moved to heap: x", so that
+moving code around does not make it look new. With -v, allocdiff also
+prints why each new allocation escapes.
+
+The exit status is 1 if the new build has allocation sites that the old
+one does not, 0 if it does not, and 2 if there was an error.
+*/
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: go tool allocdiff [-v] old new\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+var verbose = flag.Bool("v", false, "print why new allocations escape")
+
+func main() {
+	log.SetPrefix("allocdiff: ")
+	log.SetFlags(0)
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() != 2 {
+		usage()
+	}
+
+	oldSites, err := readSites(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	newSites, err := readSites(flag.Arg(1))
+	if err != nil {
+		log.Fatal(err)
+	}
+	added, removed := diff(oldSites, newSites)
+
+	w := bufio.NewWriter(os.Stdout)
+	for _, s := range removed {
+		fmt.Fprintf(w, "- %s\n", s)
+	}
+	for _, s := range added {
+		fmt.Fprintf(w, "+ %s\n", s)
+		if *verbose {
+			for _, why := range s.Why {
+				fmt.Fprintf(w, "\t%s\n", why)
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		log.Fatal(err)
+	}
+	if len(added) > 0 {
+		os.Exit(1)
+	}
+}
+
+// A site is a heap allocation in a build.
+type site struct {
+	Package string
+	File    string // as logged by the compiler
+	Line    uint
+	Col     uint
+	Message string   // e.g. "moved to heap: x"
+	Why     []string // the explanation, one line per element
+}
+
+func (s *site) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", s.File, s.Line, s.Col, s.Message)
+}
+
+// key returns the key that matches s with the same allocation in
+// another build.
+func (s *site) key() string {
+	return s.Package + "\x00" + filepath.Base(s.File) + "\x00" + s.Message
+}
+
+// The records of the compiler's -json log that allocdiff reads. See
+// cmd/compile/internal/logopt for their full definitions.
+
+type header struct {
+	Version int    `json:"version"`
+	Package string `json:"package"`
+	File    string `json:"file"`
+}
+
+type position struct {
+	Line      uint `json:"line"`
+	Character uint `json:"character"`
+}
+
+type rng struct {
+	Start position `json:"start"`
+}
+
+type location struct {
+	URI   string `json:"uri"`
+	Range rng    `json:"range"`
+}
+
+type diagnostic struct {
+	Range              rng    `json:"range"`
+	Code               string `json:"code"`
+	Message            string `json:"message"`
+	RelatedInformation []struct {
+		Location location `json:"location"`
+		Message  string   `json:"message"`
+	} `json:"relatedInformation"`
+}
+
+// readSites returns the allocation sites logged in the .json files in
+// the directory tree dir.
+func readSites(dir string) ([]*site, error) {
+	var sites []*site
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".json") {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		s, err := readLog(f)
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		sites = append(sites, s...)
+		return nil
+	})
+	return sites, err
+}
+
+// readLog returns the allocation sites in a log file for one source
+// file: a header followed by diagnostics.
+func readLog(r io.Reader) ([]*site, error) {
+	dec := json.NewDecoder(r)
+	var h header
+	if err := dec.Decode(&h); err != nil {
+		return nil, err
+	}
+	if h.Version != 0 {
+		return nil, fmt.Errorf("unsupported log version %d", h.Version)
+	}
+	var sites []*site
+	for {
+		var d diagnostic
+		if err := dec.Decode(&d); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if d.Code != "escape" {
+			continue
+		}
+		s := &site{
+			Package: h.Package,
+			File:    h.File,
+			Line:    d.Range.Start.Line,
+			Col:     d.Range.Start.Character,
+			Message: d.Message,
+		}
+		for _, ri := range d.RelatedInformation {
+			file := strings.TrimPrefix(ri.Location.URI, "file://")
+			s.Why = append(s.Why, fmt.Sprintf("%s:%d:%d: %s", file, ri.Location.Range.Start.Line, ri.Location.Range.Start.Character, ri.Message))
+		}
+		sites = append(sites, s)
+	}
+	return sites, nil
+}
+
+// diff returns the sites of newSites that have no match in oldSites,
+// and those of oldSites that have no match in newSites, each sorted by
+// position.
+//
+// Sites match if they have the same key. Among sites with the same
+// key, those on the same line match first, and then the rest pair up
+// in order of position.
+func diff(oldSites, newSites []*site) (added, removed []*site) {
+	byKey := func(sites []*site) map[string][]*site {
+		m := make(map[string][]*site)
+		for _, s := range sites {
+			m[s.key()] = append(m[s.key()], s)
+		}
+		return m
+	}
+	oldByKey, newByKey := byKey(oldSites), byKey(newSites)
+
+	for k, news := range newByKey {
+		olds := oldByKey[k]
+		oldLines := make(map[uint]int)
+		for _, s := range olds {
+			oldLines[s.Line]++
+		}
+		newLines := make(map[uint]int)
+		for _, s := range news {
+			newLines[s.Line]++
+		}
+		var restOld, restNew []*site
+		for _, s := range olds {
+			if newLines[s.Line] > 0 {
+				newLines[s.Line]--
+				continue
+			}
+			restOld = append(restOld, s)
+		}
+		for _, s := range news {
+			if oldLines[s.Line] > 0 {
+				oldLines[s.Line]--
+				continue
+			}
+			restNew = append(restNew, s)
+		}
+		sortSites(restOld)
+		sortSites(restNew)
+		for len(restOld) > 0 && len(restNew) > 0 {
+			restOld, restNew = restOld[1:], restNew[1:]
+		}
+		added = append(added, restNew...)
+		removed = append(removed, restOld...)
+	}
+	for k, olds := range oldByKey {
+		if _, ok := newByKey[k]; !ok {
+			removed = append(removed, olds...)
+		}
+	}
+	sortSites(added)
+	sortSites(removed)
+	return added, removed
+}
+
+func sortSites(sites []*site) {
+	sort.Slice(sites, func(i, j int) bool {
+		a, b := sites[i], sites[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Col != b.Col {
+			return a.Col < b.Col
+		}
+		return a.Message < b.Message
+	})
+}
@@ -0,0 +1,182 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"internal/testenv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSymPackage(t *testing.T) {
+	for _, tt := range []struct {
+		sym, pkg string
+	}{
+		{"main.main", "main"},
+		{"fmt.(*pp).printArg", "fmt"},
+		{"net/http.(*Server).Serve.func1", "net/http"},
+		{"gopkg.in/yaml%2ev3.Marshal", "gopkg.in/yaml.v3"},
+		{"main.F[go.shape.*example.com/p.T]", "main"},
+		{"type:*", pkgTypes},
+		{"type:*main.T", "main"},
+		{"type:[]encoding/json.Number", "encoding/json"},
+		{"type:map[string]int", pkgTypes},
+		{"type:.eq.io/fs.PathError", "io/fs"},
+		{"go:itab.*os.File,io.Writer", "os"},
+		{"go:string.*", pkgStrings},
+		{"go:func.*", pkgGo},
+		{"$f64.3ff0000000000000", pkgGo},
+		{"x_cgo_init", pkgOther},
+		{"_rt0_amd64_linux", pkgOther},
+	} {
+		if got := symPackage(tt.sym); got != tt.pkg {
+			t.Errorf("symPackage(%q) = %q, want %q", tt.sym, got, tt.pkg)
+		}
+	}
+}
+
+func TestDeps(t *testing.T) {
+	const dump = `# command-line-arguments
+_ -> main.main
+main.main -> type:main.T <UsedInIface>
+type:main.T <UsedInIface> -> main.T.M
+main.main -> main.T.M <named>
+main.main -> main.f
+main.f -> main.g
+main.g -> main.f
+`
+	g, err := readDeps(strings.NewReader(dump))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		sym  string
+		want string
+	}{
+		{"main.main", "main.main"},
+		{"type:main.T", "main.main type:main.T"},
+		{"main.T.M", "main.main main.T.M<named>"},
+		{"main.g", "main.main main.f main.g"},
+		{"main.h", ""},
+	} {
+		var got []string
+		for _, e := range g.why(tt.sym) {
+			s := e.Sym
+			if e.Why != "" {
+				s += "<" + e.Why + ">"
+			}
+			got = append(got, s)
+		}
+		if strings.Join(got, " ") != tt.want {
+			t.Errorf("why(%s) = %q, want %q", tt.sym, got, tt.want)
+		}
+	}
+}
+
+// TestBuilds reports on and compares two builds of a program.
+func TestBuilds(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+
+	const oldSrc = `package main
+
+func main() { println("hello") }
+`
+	const newSrc = `package main
+
+var table = [1000]int{1: 1}
+
+//go:noinline
+func lookup(i int) int { return table[i] }
+
+func main() { println("hello", lookup(1)) }
+`
+	dir := t.TempDir()
+	build := func(name, src string) (exe, deps string) {
+		file := filepath.Join(dir, name+".go")
+		if err := os.WriteFile(file, []byte(src), 0666); err != nil {
+			t.Fatal(err)
+		}
+		exe = filepath.Join(dir, name+".exe")
+		cmd := testenv.Command(t, testenv.GoToolPath(t), "build", "-ldflags=-dumpdep", "-o", exe, file)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("%v: %v\n%s", cmd, err, out)
+		}
+		deps = filepath.Join(dir, name+".deps")
+		if err := os.WriteFile(deps, out, 0666); err != nil {
+			t.Fatal(err)
+		}
+		return exe, deps
+	}
+	oldExe, _ := build("old", oldSrc)
+	newExe, newDeps := build("new", newSrc)
+
+	oldr, err := readReport(oldExe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newr, err := readReport(newExe)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sum int64
+	for _, c := range newr.Categories {
+		if c.Size < 0 {
+			t.Errorf("category %s has size %d", c.Name, c.Size)
+		}
+		sum += c.Size
+	}
+	if sum != newr.Size {
+		t.Errorf("categories add up to %d bytes, want %d", sum, newr.Size)
+	}
+	var found bool
+	for _, p := range newr.Packages {
+		if p.Name == "main" {
+			found = true
+			if p.Code == 0 || p.Data < 8000 {
+				t.Errorf("package main has %d bytes of code and %d of data, want some code and at least 8000 of data", p.Code, p.Data)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("package main not found in %v", newr.Packages)
+	}
+
+	f, err := os.Open(newDeps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	deps, err := readDeps(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newr.trim(-1, deps)
+	for _, s := range newr.Symbols {
+		if s.Name == "main.table" {
+			if s.KeptBy != "main.lookup" {
+				t.Errorf("main.table kept by %q, want main.lookup", s.KeptBy)
+			}
+			break
+		}
+	}
+
+	d := diff(oldr, newr, -1)
+	for _, want := range []string{"main.table", "main.lookup"} {
+		found := false
+		for _, e := range d.Symbols {
+			if e.Name == want {
+				found = e.Old == 0 && e.New > 0
+				break
+			}
+		}
+		if !found {
+			t.Errorf("diff does not report %s as added: %v", want, d.Symbols)
+		}
+	}
+}
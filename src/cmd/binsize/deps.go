@@ -0,0 +1,99 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// A depGraph records why the linker kept each symbol.
+type depGraph struct {
+	edges map[string]depEdge // keyed by the symbol kept
+}
+
+// A depEdge records that From caused a symbol to be kept.
+type depEdge struct {
+	From string
+	Why  string // reason reported by -dumpmethods, if any
+}
+
+// A PathElem is an element of the chain of references to a symbol.
+type PathElem struct {
+	Sym string
+	Why string `json:",omitempty"`
+}
+
+// readDeps reads the output of the linker's -dumpdep and -dumpmethods
+// flags. Each line has the form
+//
+//	from -> to
+//
+// where "_" stands for a root, either symbol may be followed by an
+// annotation in angle brackets, and methods kept by -dumpmethods have the
+// reason as annotation. Other lines, such as the go command's output,
+// are ignored.
+func readDeps(r io.Reader) (*depGraph, error) {
+	g := &depGraph{edges: make(map[string]depEdge)}
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<20)
+	for s.Scan() {
+		from, to, ok := strings.Cut(s.Text(), " -> ")
+		if !ok {
+			continue
+		}
+		from, _ = splitAnnotation(from)
+		to, why := splitAnnotation(to)
+		if why == "UsedInIface" {
+			why = ""
+		}
+		// The linker reports a symbol when it first reaches it, but a
+		// method may be reported both by -dumpdep, as reached from its
+		// type, and by -dumpmethods, with the reason. Prefer the latter.
+		if old, ok := g.edges[to]; ok && (old.Why != "" || why == "") {
+			continue
+		}
+		g.edges[to] = depEdge{From: from, Why: why}
+	}
+	return g, s.Err()
+}
+
+// splitAnnotation splits "sym <annotation>" into its parts.
+func splitAnnotation(s string) (sym, annotation string) {
+	if strings.HasSuffix(s, ">") {
+		if i := strings.LastIndex(s, " <"); i >= 0 {
+			return s[:i], s[i+2 : len(s)-1]
+		}
+	}
+	return s, ""
+}
+
+// why returns the chain of references from a root to sym,
+// or nil if sym is not in g.
+func (g *depGraph) why(sym string) []PathElem {
+	e, ok := g.edges[sym]
+	if !ok {
+		return nil
+	}
+	path := []PathElem{{Sym: sym, Why: e.Why}}
+	seen := map[string]bool{sym: true}
+	for {
+		from := e.From
+		if from == "_" || seen[from] {
+			break
+		}
+		seen[from] = true
+		e, ok = g.edges[from]
+		path = append(path, PathElem{Sym: from, Why: e.Why})
+		if !ok {
+			break
+		}
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import "sort"
+
+// A Diff describes how the contents of a binary changed.
+type Diff struct {
+	Old, New         string
+	OldSize, NewSize int64
+	Categories       []Delta
+	Packages         []Delta
+	Symbols          []Delta // largest changes first
+}
+
+// A Delta is the change in size of a category, package or symbol.
+type Delta struct {
+	Name     string
+	Old, New int64
+	Delta    int64
+}
+
+// diff compares the reports oldr and newr. It lists the categories and
+// packages that changed in size and the n symbols that changed most.
+func diff(oldr, newr *Report, n int) *Diff {
+	d := &Diff{Old: oldr.File, New: newr.File, OldSize: oldr.Size, NewSize: newr.Size}
+
+	oldc, newc := make(map[string]int64), make(map[string]int64)
+	for _, c := range oldr.Categories {
+		oldc[c.Name] = c.Size
+	}
+	for _, c := range newr.Categories {
+		newc[c.Name] = c.Size
+	}
+	for _, c := range categories {
+		if oldc[c] != newc[c] {
+			d.Categories = append(d.Categories, Delta{c, oldc[c], newc[c], newc[c] - oldc[c]})
+		}
+	}
+
+	oldp, newp := make(map[string]int64), make(map[string]int64)
+	for _, p := range oldr.Packages {
+		oldp[p.Name] = p.Total
+	}
+	for _, p := range newr.Packages {
+		newp[p.Name] = p.Total
+	}
+	d.Packages = deltas(oldp, newp)
+
+	olds, news := make(map[string]int64), make(map[string]int64)
+	for _, s := range oldr.Symbols {
+		if s.Kind != "bss" {
+			olds[s.Name] += s.Size
+		}
+	}
+	for _, s := range newr.Symbols {
+		if s.Kind != "bss" {
+			news[s.Name] += s.Size
+		}
+	}
+	d.Symbols = deltas(olds, news)
+	if n >= 0 && len(d.Symbols) > n {
+		d.Symbols = d.Symbols[:n]
+	}
+	return d
+}
+
+// deltas returns the changes between the sizes in old and new,
+// largest first.
+func deltas(old, new map[string]int64) []Delta {
+	var list []Delta
+	for name, o := range old {
+		if n := new[name]; n != o {
+			list = append(list, Delta{name, o, n, n - o})
+		}
+	}
+	for name, n := range new {
+		if _, ok := old[name]; !ok {
+			list = append(list, Delta{name, 0, n, n})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := abs(list[i].Delta), abs(list[j].Delta)
+		if a != b {
+			return a > b
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+func abs(x int64) int64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
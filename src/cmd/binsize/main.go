@@ -0,0 +1,234 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Binsize reports what the bytes of a Go binary are spent on.
+
+Usage:
+
+	go tool binsize [-json] [-n count] [-deps file] binary
+	go tool binsize [-json] [-n count] -diff old new
+	go tool binsize -deps file -why symbol
+
+Binsize reads ELF, Mach-O and PE binaries. It prints the size of each
+section and splits the file into categories: machine code, the pclntab
+(the function and line tables used by the runtime), type metadata,
+other read-only data, data, DWARF debug information, and everything
+else, such as headers and symbol tables. It then attributes code, data
+and type metadata to packages and lists the largest symbols.
+
+Symbols are read from the binary's symbol table. Mach-O and PE symbol
+tables do not record sizes, so they are inferred from the address of
+the next symbol, and padding is attributed to the preceding symbol.
+The linker lists type descriptors as a single symbol, "type:*", so they
+are attributed to the pseudo-package "(types)", except for itabs and the
+equality and hash functions of types. If the binary was stripped, code
+is attributed using the function table in the pclntab, and data and
+type metadata are not attributed.
+
+The -diff flag compares two binaries, printing how each category,
+package and symbol changed.
+
+The -deps flag names a file holding the output of the linker's -dumpdep
+and -dumpmethods flags, for example written by
+
+	go build -ldflags='-dumpdep -dumpmethods' >deps.txt 2>&1
+
+With it, binsize shows which symbol caused each listed symbol to be kept,
+and -why prints the whole chain of references from the entry point to
+the given symbol. For methods kept by -dumpmethods lines, the reason is
+shown as well: "interface" for interface calls, "named" for generic
+interface calls and reflect lookups by constant name, and "reflect" if
+the program may look up any method by reflection.
+
+With -json, the report is printed as JSON.
+*/
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"text/tabwriter"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: go tool binsize [-json] [-n count] [-deps file] binary\n")
+	fmt.Fprintf(os.Stderr, "       go tool binsize [-json] [-n count] -diff old new\n")
+	fmt.Fprintf(os.Stderr, "       go tool binsize -deps file -why symbol\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+var (
+	diffFlag = flag.Bool("diff", false, "compare two binaries")
+	jsonFlag = flag.Bool("json", false, "print the report as JSON")
+	numFlag  = flag.Int("n", 20, "print the `count` largest symbols")
+	depsFlag = flag.String("deps", "", "read linker -dumpdep output from `file`")
+	whyFlag  = flag.String("why", "", "print why `symbol` is kept (requires -deps)")
+)
+
+func main() {
+	log.SetPrefix("binsize: ")
+	log.SetFlags(0)
+	flag.Usage = usage
+	flag.Parse()
+
+	var deps *depGraph
+	if *depsFlag != "" {
+		f, err := os.Open(*depsFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		deps, err = readDeps(f)
+		f.Close()
+		if err != nil {
+			log.Fatalf("reading %s: %v", *depsFlag, err)
+		}
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	switch {
+	case *whyFlag != "":
+		if deps == nil || flag.NArg() != 0 || *diffFlag {
+			usage()
+		}
+		path := deps.why(*whyFlag)
+		if path == nil {
+			log.Fatalf("%s is not in the dependency graph", *whyFlag)
+		}
+		if *jsonFlag {
+			writeJSON(w, path)
+			return
+		}
+		for i, e := range path {
+			if i > 0 {
+				fmt.Fprint(w, "  -> ")
+			}
+			fmt.Fprint(w, e.Sym)
+			if e.Why != "" {
+				fmt.Fprintf(w, " <%s>", e.Why)
+			}
+			fmt.Fprintln(w)
+		}
+
+	case *diffFlag:
+		if flag.NArg() != 2 {
+			usage()
+		}
+		oldr, err := readReport(flag.Arg(0))
+		if err != nil {
+			log.Fatal(err)
+		}
+		newr, err := readReport(flag.Arg(1))
+		if err != nil {
+			log.Fatal(err)
+		}
+		d := diff(oldr, newr, *numFlag)
+		if *jsonFlag {
+			writeJSON(w, d)
+			return
+		}
+		writeDiff(w, d)
+
+	default:
+		if flag.NArg() != 1 {
+			usage()
+		}
+		r, err := readReport(flag.Arg(0))
+		if err != nil {
+			log.Fatal(err)
+		}
+		r.trim(*numFlag, deps)
+		if *jsonFlag {
+			writeJSON(w, r)
+			return
+		}
+		writeReport(w, r)
+	}
+}
+
+func writeJSON(w io.Writer, v any) {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(v); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// percent formats n as a percentage of total.
+func percent(n, total int64) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", 100*float64(n)/float64(total))
+}
+
+func writeReport(w io.Writer, r *Report) {
+	fmt.Fprintf(w, "%s: %s/%s, %d bytes\n", r.File, r.Format, r.Arch, r.Size)
+	if r.Stripped {
+		fmt.Fprintf(w, "no symbol table; only code is attributed to packages\n")
+	}
+	fmt.Fprintln(w)
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "SECTION\tSIZE\tMEMORY\n")
+	for _, s := range r.Sections {
+		fmt.Fprintf(tw, "%s\t%d\t%d\n", s.Name, s.Size, s.MemSize)
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
+
+	tw = tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "CATEGORY\tSIZE\t\n")
+	for _, c := range r.Categories {
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", c.Name, c.Size, percent(c.Size, r.Size))
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
+
+	tw = tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "PACKAGE\tCODE\tDATA\tTYPES\tTOTAL\n")
+	for _, p := range r.Packages {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\n", p.Name, p.Code, p.Data, p.Types, p.Total)
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
+
+	tw = tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "SYMBOL\tSIZE\tKIND\tKEPT BY\n")
+	for _, s := range r.Symbols {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", s.Name, s.Size, s.Kind, s.KeptBy)
+	}
+	tw.Flush()
+}
+
+func writeDiff(w io.Writer, d *Diff) {
+	fmt.Fprintf(w, "%s -> %s: %d -> %d bytes (%+d)\n", d.Old, d.New, d.OldSize, d.NewSize, d.NewSize-d.OldSize)
+	for _, t := range []struct {
+		title string
+		list  []Delta
+	}{
+		{"CATEGORY", d.Categories},
+		{"PACKAGE", d.Packages},
+		{"SYMBOL", d.Symbols},
+	} {
+		if len(t.list) == 0 {
+			continue
+		}
+		fmt.Fprintln(w)
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		fmt.Fprintf(tw, "%s\tOLD\tNEW\tDELTA\n", t.title)
+		for _, e := range t.list {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%+d\n", e.Name, e.Old, e.New, e.Delta)
+		}
+		tw.Flush()
+	}
+}
@@ -0,0 +1,498 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"debug/elf"
+	"debug/gosym"
+	"debug/macho"
+	"debug/pe"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"cmd/internal/objfile"
+)
+
+// A Report describes what the bytes of a binary are spent on.
+type Report struct {
+	File       string
+	Format     string // "elf", "macho" or "pe"
+	Arch       string
+	Size       int64 // size of the file
+	Sections   []Section
+	Categories []Category
+	Packages   []Package
+	Symbols    []Symbol // largest first
+
+	// Stripped reports that the binary has no symbol table, so
+	// only code is attributed to packages.
+	Stripped bool `json:",omitempty"`
+}
+
+// A Section is a section of the binary.
+type Section struct {
+	Name    string
+	Addr    uint64 `json:"-"`
+	Size    int64  // bytes in the file; compressed size for compressed sections
+	MemSize int64  // bytes in memory; 0 for sections that are not loaded
+	Kind    string // category the section's bytes are counted in
+}
+
+// A Category is a kind of content, such as code or DWARF.
+type Category struct {
+	Name string
+	Size int64
+}
+
+// The categories, in the order they are reported.
+const (
+	catCode   = "code"
+	catPCLN   = "pclntab"
+	catTypes  = "type metadata"
+	catRodata = "rodata"
+	catData   = "data"
+	catDWARF  = "DWARF"
+	catOther  = "other"
+)
+
+var categories = []string{catCode, catPCLN, catTypes, catRodata, catData, catDWARF, catOther}
+
+// A Package holds the sizes of the symbols attributed to a package.
+type Package struct {
+	Name  string
+	Code  int64
+	Data  int64 // read-only and writable data, except type metadata
+	Types int64 // type descriptors and itabs
+	Total int64
+}
+
+// A Symbol is a symbol of the binary.
+type Symbol struct {
+	Name    string
+	Package string
+	Size    int64
+	Kind    string // "code", "data", "types" or "bss"
+	KeptBy  string `json:",omitempty"` // symbol that caused this one to be linked in, from -deps
+}
+
+// readReport reads the binary named file and reports on its contents.
+func readReport(file string) (*Report, error) {
+	st, err := os.Stat(file)
+	if err != nil {
+		return nil, err
+	}
+	r := &Report{File: file, Size: st.Size()}
+	if err := r.readSections(file); err != nil {
+		return nil, err
+	}
+
+	f, err := objfile.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r.Arch = f.GOARCH()
+	syms, err := f.Symbols()
+	if err != nil || len(syms) == 0 {
+		// Fall back to the function table, which is never stripped.
+		r.Stripped = true
+		syms = nil
+		if tab, err := f.PCLineTable(); err == nil {
+			if t, ok := tab.(*gosym.Table); ok {
+				for _, fn := range t.Funcs {
+					syms = append(syms, objfile.Sym{Name: fn.Name, Addr: fn.Entry, Size: int64(fn.End - fn.Entry), Code: 'T'})
+				}
+			}
+		}
+	}
+	r.fixSymbols(syms)
+	r.attribute(syms)
+	return r, nil
+}
+
+// markers are the symbols the linker defines to mark the bounds of
+// sections and tables. They take no space of their own.
+var markers = map[string]bool{
+	"runtime.text":       true,
+	"runtime.etext":      true,
+	"runtime.rodata":     true,
+	"runtime.erodata":    true,
+	"runtime.types":      true,
+	"runtime.etypes":     true,
+	"runtime.symtab":     true,
+	"runtime.esymtab":    true,
+	"runtime.pclntab":    true,
+	"runtime.epclntab":   true,
+	"runtime.noptrdata":  true,
+	"runtime.enoptrdata": true,
+	"runtime.data":       true,
+	"runtime.edata":      true,
+	"runtime.bss":        true,
+	"runtime.ebss":       true,
+	"runtime.noptrbss":   true,
+	"runtime.enoptrbss":  true,
+	"runtime.end":        true,
+}
+
+// fixSymbols normalizes the symbols syms, sorted by address, read
+// from r's symbol table.
+func (r *Report) fixSymbols(syms []objfile.Sym) {
+	for i := range syms {
+		s := &syms[i]
+		if r.Format == "macho" && (strings.HasPrefix(s.Name, "_type:") || strings.HasPrefix(s.Name, "_go:")) {
+			// The Mach-O linker prefixes all names with "_", but
+			// only names with a dot have it removed.
+			s.Name = s.Name[1:]
+		}
+		if markers[s.Name] {
+			s.Size = 0
+		}
+	}
+	if r.Format == "elf" {
+		return
+	}
+	// Symbol tables of other formats do not record sizes. They are
+	// inferred from the address of the next symbol, so only one of
+	// the symbols at an address may have a size.
+	for i := 1; i < len(syms); i++ {
+		if syms[i].Addr == syms[i-1].Addr && syms[i-1].Size != 0 {
+			syms[i].Size = 0
+		}
+	}
+}
+
+// readSections reads the section headers of file.
+func (r *Report) readSections(file string) error {
+	if f, err := elf.Open(file); err == nil {
+		defer f.Close()
+		r.Format = "elf"
+		for _, s := range f.Sections {
+			if s.Type == elf.SHT_NULL {
+				continue
+			}
+			sect := Section{Name: s.Name, Addr: s.Addr, Size: int64(s.FileSize)}
+			if s.Type == elf.SHT_NOBITS {
+				sect.Size = 0
+			}
+			if s.Flags&elf.SHF_ALLOC != 0 {
+				sect.MemSize = int64(s.Size)
+			}
+			switch {
+			case s.Flags&elf.SHF_EXECINSTR != 0:
+				sect.Kind = catCode
+			case s.Flags&elf.SHF_WRITE != 0:
+				sect.Kind = catData
+			case s.Flags&elf.SHF_ALLOC != 0:
+				sect.Kind = catRodata
+			}
+			r.addSection(sect)
+		}
+		return nil
+	}
+	if f, err := macho.Open(file); err == nil {
+		defer f.Close()
+		r.Format = "macho"
+		for _, s := range f.Sections {
+			sect := Section{Name: s.Seg + "," + s.Name, Addr: s.Addr, Size: int64(s.Size), MemSize: int64(s.Size)}
+			const zerofill = 0x1
+			if s.Flags&0xff == zerofill {
+				sect.Size = 0
+			}
+			switch s.Seg {
+			case "__TEXT":
+				sect.Kind = catRodata
+				if s.Name == "__text" {
+					sect.Kind = catCode
+				}
+			case "__DATA", "__DATA_CONST":
+				sect.Kind = catData
+			case "__DWARF":
+				sect.MemSize = 0
+			}
+			r.addSection(sect)
+		}
+		return nil
+	}
+	if f, err := pe.Open(file); err == nil {
+		defer f.Close()
+		r.Format = "pe"
+		var base uint64
+		switch oh := f.OptionalHeader.(type) {
+		case *pe.OptionalHeader32:
+			base = uint64(oh.ImageBase)
+		case *pe.OptionalHeader64:
+			base = oh.ImageBase
+		}
+		for _, s := range f.Sections {
+			sect := Section{Name: s.Name, Addr: base + uint64(s.VirtualAddress), Size: int64(s.Size), MemSize: int64(s.VirtualSize)}
+			const (
+				memExecute = 0x20000000
+				memWrite   = 0x80000000
+			)
+			switch {
+			case strings.HasPrefix(s.Name, ".debug_") || strings.HasPrefix(s.Name, ".zdebug_"):
+				sect.MemSize = 0
+			case s.Characteristics&memExecute != 0:
+				sect.Kind = catCode
+			case s.Characteristics&memWrite != 0:
+				sect.Kind = catData
+			default:
+				sect.Kind = catRodata
+			}
+			r.addSection(sect)
+		}
+		return nil
+	}
+	return fmt.Errorf("%s: not an ELF, Mach-O or PE file", file)
+}
+
+// addSection adds s to r, recognizing the sections that hold
+// the pclntab and DWARF whatever their flags.
+func (r *Report) addSection(s Section) {
+	name := s.Name
+	if i := strings.LastIndex(name, ","); i >= 0 {
+		name = name[i+1:]
+	}
+	switch {
+	case strings.HasPrefix(name, ".debug_"), strings.HasPrefix(name, ".zdebug_"),
+		strings.HasPrefix(s.Name, "__DWARF,"):
+		s.Kind = catDWARF
+	case strings.HasSuffix(name, "gopclntab"):
+		s.Kind = catPCLN
+	case s.Kind == "":
+		s.Kind = catOther
+	}
+	r.Sections = append(r.Sections, s)
+}
+
+// attribute computes the categories and packages of r from its
+// sections and the symbols syms.
+func (r *Report) attribute(syms []objfile.Sym) {
+	sizes := make(map[string]int64)
+	for _, s := range r.Sections {
+		sizes[s.Kind] += s.Size
+	}
+
+	// Sections loaded in memory, for finding the section of a symbol.
+	var loaded []Section
+	for _, s := range r.Sections {
+		if s.MemSize > 0 {
+			loaded = append(loaded, s)
+		}
+	}
+	sort.Slice(loaded, func(i, j int) bool { return loaded[i].Addr < loaded[j].Addr })
+	sectionAt := func(addr uint64) *Section {
+		i := sort.Search(len(loaded), func(i int) bool { return loaded[i].Addr > addr }) - 1
+		if i < 0 || addr >= loaded[i].Addr+uint64(loaded[i].MemSize) {
+			return nil
+		}
+		return &loaded[i]
+	}
+
+	// The pclntab is not a section of its own in all formats.
+	// Move its bytes to its own category.
+	if sizes[catPCLN] == 0 {
+		var lo, hi uint64
+		for _, s := range syms {
+			switch s.Name {
+			case "runtime.pclntab":
+				lo = s.Addr
+			case "runtime.epclntab":
+				hi = s.Addr
+			}
+		}
+		if sect := sectionAt(lo); sect != nil && lo < hi && hi <= sect.Addr+uint64(sect.Size) {
+			sizes[sect.Kind] -= int64(hi - lo)
+			sizes[catPCLN] += int64(hi - lo)
+		}
+	}
+
+	// The linker lists some groups of symbols, like the type descriptors
+	// ("type:*") and string data ("go:string.*"), as a single symbol of
+	// size 0. Their size extends to the next symbol.
+	for i := range syms {
+		s := &syms[i]
+		if s.Size != 0 || !strings.HasSuffix(s.Name, "*") {
+			continue
+		}
+		sect := sectionAt(s.Addr)
+		if sect == nil {
+			continue
+		}
+		end := sect.Addr + uint64(sect.MemSize)
+		for _, t := range syms[i+1:] {
+			if t.Addr > s.Addr {
+				if t.Addr < end {
+					end = t.Addr
+				}
+				break
+			}
+		}
+		s.Size = int64(end - s.Addr)
+	}
+
+	pkgs := make(map[string]*Package)
+	for _, s := range syms {
+		if s.Size <= 0 {
+			continue
+		}
+		sym := Symbol{Name: s.Name, Package: symPackage(s.Name), Size: s.Size}
+		sect := sectionAt(s.Addr)
+		switch {
+		case s.Code == 'T' || s.Code == 't':
+			sym.Kind = "code"
+		case sect == nil:
+			continue
+		case s.Addr >= sect.Addr+uint64(sect.Size):
+			// Beyond the part of the section stored in the file,
+			// like the .bss section, which PE puts after .data.
+			sym.Kind = "bss"
+		case strings.HasPrefix(s.Name, "type:") || strings.HasPrefix(s.Name, "go:itab."):
+			// Type metadata is part of the data sections.
+			// Move its bytes to its own category.
+			sym.Kind = "types"
+			sizes[sect.Kind] -= s.Size
+			sizes[catTypes] += s.Size
+		default:
+			sym.Kind = "data"
+		}
+		r.Symbols = append(r.Symbols, sym)
+
+		p := pkgs[sym.Package]
+		if p == nil {
+			p = &Package{Name: sym.Package}
+			pkgs[sym.Package] = p
+		}
+		switch sym.Kind {
+		case "code":
+			p.Code += s.Size
+		case "data":
+			p.Data += s.Size
+		case "types":
+			p.Types += s.Size
+		default:
+			continue // bss takes no space in the file
+		}
+		p.Total += s.Size
+	}
+	for _, p := range pkgs {
+		if p.Total > 0 {
+			r.Packages = append(r.Packages, *p)
+		}
+	}
+	sort.Slice(r.Packages, func(i, j int) bool {
+		p, q := r.Packages[i], r.Packages[j]
+		if p.Total != q.Total {
+			return p.Total > q.Total
+		}
+		return p.Name < q.Name
+	})
+	sort.Slice(r.Symbols, func(i, j int) bool {
+		s, t := r.Symbols[i], r.Symbols[j]
+		if s.Size != t.Size {
+			return s.Size > t.Size
+		}
+		return s.Name < t.Name
+	})
+
+	var known int64
+	for _, c := range categories {
+		if c != catOther {
+			known += sizes[c]
+		}
+	}
+	sizes[catOther] = r.Size - known
+	for _, c := range categories {
+		r.Categories = append(r.Categories, Category{c, sizes[c]})
+	}
+}
+
+// trim keeps only the n largest symbols of r,
+// filling in why they were kept from deps if it is not nil.
+func (r *Report) trim(n int, deps *depGraph) {
+	if n >= 0 && len(r.Symbols) > n {
+		r.Symbols = r.Symbols[:n]
+	}
+	if deps == nil {
+		return
+	}
+	for i := range r.Symbols {
+		if e, ok := deps.edges[r.Symbols[i].Name]; ok {
+			r.Symbols[i].KeptBy = e.From
+			if e.Why != "" {
+				r.Symbols[i].KeptBy += " <" + e.Why + ">"
+			}
+		}
+	}
+}
+
+// Names of pseudo-packages for symbols that belong to no package.
+const (
+	pkgTypes   = "(types)"   // type descriptors of unnamed types
+	pkgStrings = "(strings)" // string data
+	pkgGo      = "(go)"      // other symbols generated by the toolchain, like constants
+	pkgOther   = "(other)"   // symbols not written in Go, like C functions
+)
+
+// symPackage returns the package path of the Go symbol name.
+// Type descriptors are attributed to the package of the type and
+// itabs to the package of the concrete type.
+func symPackage(name string) string {
+	switch {
+	case strings.HasPrefix(name, "type:"):
+		// Equality and hash functions are named after their type.
+		t := strings.TrimPrefix(name, "type:")
+		t = strings.TrimPrefix(strings.TrimPrefix(t, ".eq."), ".hash.")
+		t = strings.TrimLeft(t, "*[]0123456789")
+		if strings.HasPrefix(t, "map[") || strings.HasPrefix(t, "func(") || strings.HasPrefix(t, "chan ") ||
+			strings.HasPrefix(t, "struct ") || strings.HasPrefix(t, "interface ") {
+			return pkgTypes
+		}
+		if p := goPackage(t); p != "" {
+			return p
+		}
+		return pkgTypes
+	case strings.HasPrefix(name, "go:itab."):
+		t := strings.TrimLeft(name[len("go:itab."):], "*")
+		if i := strings.Index(t, ","); i >= 0 {
+			t = t[:i]
+		}
+		if p := goPackage(t); p != "" {
+			return p
+		}
+		return pkgTypes
+	case strings.HasPrefix(name, "go:string."), strings.HasPrefix(name, "go:str."):
+		return pkgStrings
+	case strings.HasPrefix(name, "go:"), strings.HasPrefix(name, "$"):
+		return pkgGo
+	}
+	if p := goPackage(name); p != "" {
+		return p
+	}
+	return pkgOther
+}
+
+// goPackage returns the package path of a name of the form
+// path.Name, or "" if name does not have that form.
+func goPackage(name string) string {
+	// Instantiated generic functions and types have type arguments,
+	// which may contain paths, in brackets.
+	if i := strings.Index(name, "["); i >= 0 {
+		name = name[:i]
+	}
+	slash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[slash+1:], ".")
+	if dot <= 0 {
+		return ""
+	}
+	pkg := name[:slash+1+dot]
+	if strings.ContainsAny(pkg, " ()*,") {
+		return ""
+	}
+	// The linker escapes dots in the last element of a path.
+	return strings.ReplaceAll(pkg, "%2e", ".")
+}
@@ -15,6 +15,7 @@ func Init(arch *ssagen.ArchInfo) {
 	arch.LinkArch = &x86.Linkamd64
 	arch.REGSP = x86.REGSP
 	arch.MAXWIDTH = 1 << 50
+	arch.LoopAlign = 32
 
 	arch.ZeroRange = zerorange
 	arch.Ginsnop = ginsnop
@@ -80,6 +80,8 @@ func storeByType(t *types.Type) obj.As {
 			return x86.AMOVQ
 		case 16:
 			return x86.AMOVUPS
+		case 32:
+			return x86.AVMOVDQU // 256-bit vectors, in Y registers
 		}
 	}
 	panic(fmt.Sprintf("bad store type %v", t))
@@ -106,12 +108,55 @@ func moveByType(t *types.Type) obj.As {
 			return x86.AMOVQ
 		case 16:
 			return x86.AMOVUPS // int128s are in SSE registers
+		case 32:
+			return x86.AVMOVDQU // 256-bit vectors, in Y registers
 		default:
 			panic(fmt.Sprintf("bad int register width %d:%v", t.Size(), t))
 		}
 	}
 }
 
+// vecReg returns the register holding a value of type t that is
+// allocated to register r: for 256-bit vectors, the Y register that
+// extends X register r.
+func vecReg(t *types.Type, r int16) int16 {
+	if t.IsSIMD() && t.Size() == 32 {
+		return yreg(r)
+	}
+	return r
+}
+
+// yreg returns the Y register that extends X register r.
+func yreg(r int16) int16 {
+	return r - x86.REG_X0 + x86.REG_Y0
+}
+
+// zeroUpper emits a VZEROUPPER if the function may have used the upper
+// halves of the Y registers, to avoid the penalty for SSE instructions
+// that follow AVX instructions in the code after a call or return.
+// Unless GOAMD64 guarantees AVX2, the 256-bit vector instructions only
+// run if the CPU has it, so the VZEROUPPER is guarded by the same check.
+func zeroUpper(s *ssagen.State, f *ssa.Func) {
+	if f.VecSize <= 16 {
+		return
+	}
+	if buildcfg.GOAMD64 >= 3 {
+		s.Prog(x86.AVZEROUPPER)
+		return
+	}
+	// CMPB runtime.x86HasAVX2(SB), $0; JEQ skip; VZEROUPPER; skip:
+	p := s.Prog(x86.ACMPB)
+	p.From.Type = obj.TYPE_MEM
+	p.From.Name = obj.NAME_EXTERN
+	p.From.Sym = ir.Syms.X86HasAVX2
+	p.To.Type = obj.TYPE_CONST
+	p.To.Offset = 0
+	j := s.Prog(x86.AJEQ)
+	j.To.Type = obj.TYPE_BRANCH
+	s.Prog(x86.AVZEROUPPER)
+	j.To.SetTarget(s.Pc())
+}
+
 // opregreg emits instructions for
 //
 //	dest := dest(To) op src(From)
@@ -253,11 +298,73 @@ func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 		ssa.OpAMD64ADDSS, ssa.OpAMD64ADDSD, ssa.OpAMD64SUBSS, ssa.OpAMD64SUBSD,
 		ssa.OpAMD64MULSS, ssa.OpAMD64MULSD, ssa.OpAMD64DIVSS, ssa.OpAMD64DIVSD,
 		ssa.OpAMD64PXOR,
+		ssa.OpAMD64PADDB, ssa.OpAMD64PADDW, ssa.OpAMD64PADDL, ssa.OpAMD64PADDQ, ssa.OpAMD64ADDPS, ssa.OpAMD64ADDPD,
+		ssa.OpAMD64PSUBB, ssa.OpAMD64PSUBW, ssa.OpAMD64PSUBL, ssa.OpAMD64PSUBQ, ssa.OpAMD64SUBPS, ssa.OpAMD64SUBPD,
+		ssa.OpAMD64PMULLW, ssa.OpAMD64PMULLD, ssa.OpAMD64MULPS, ssa.OpAMD64MULPD, ssa.OpAMD64DIVPS, ssa.OpAMD64DIVPD,
+		ssa.OpAMD64PAND, ssa.OpAMD64POR, ssa.OpAMD64PANDN,
+		ssa.OpAMD64PCMPEQB, ssa.OpAMD64PCMPEQW, ssa.OpAMD64PCMPEQL, ssa.OpAMD64PCMPEQQ,
 		ssa.OpAMD64BTSL, ssa.OpAMD64BTSQ,
 		ssa.OpAMD64BTCL, ssa.OpAMD64BTCQ,
 		ssa.OpAMD64BTRL, ssa.OpAMD64BTRQ:
 		opregreg(s, v.Op.Asm(), v.Reg(), v.Args[1].Reg())
 
+	// 3-operand AVX2 opcode arithmetic, on Y registers
+	case ssa.OpAMD64VPADDB256, ssa.OpAMD64VPADDW256, ssa.OpAMD64VPADDD256, ssa.OpAMD64VPADDQ256, ssa.OpAMD64VADDPS256, ssa.OpAMD64VADDPD256,
+		ssa.OpAMD64VPSUBB256, ssa.OpAMD64VPSUBW256, ssa.OpAMD64VPSUBD256, ssa.OpAMD64VPSUBQ256, ssa.OpAMD64VSUBPS256, ssa.OpAMD64VSUBPD256,
+		ssa.OpAMD64VPMULLW256, ssa.OpAMD64VPMULLD256, ssa.OpAMD64VMULPS256, ssa.OpAMD64VMULPD256, ssa.OpAMD64VDIVPS256, ssa.OpAMD64VDIVPD256,
+		ssa.OpAMD64VPAND256, ssa.OpAMD64VPOR256, ssa.OpAMD64VPXOR256, ssa.OpAMD64VPANDN256,
+		ssa.OpAMD64VPCMPEQB256, ssa.OpAMD64VPCMPEQW256, ssa.OpAMD64VPCMPEQD256, ssa.OpAMD64VPCMPEQQ256:
+		p := s.Prog(v.Op.Asm())
+		p.From = obj.Addr{Type: obj.TYPE_REG, Reg: yreg(v.Args[1].Reg())}
+		p.SetFrom3Reg(yreg(v.Args[0].Reg()))
+		p.To = obj.Addr{Type: obj.TYPE_REG, Reg: yreg(v.Reg())}
+
+	case ssa.OpAMD64PMOVMSKB, ssa.OpAMD64VPMOVMSKB256:
+		p := s.Prog(v.Op.Asm())
+		p.From = obj.Addr{Type: obj.TYPE_REG, Reg: v.Args[0].Reg()}
+		if v.Op == ssa.OpAMD64VPMOVMSKB256 {
+			p.From.Reg = yreg(p.From.Reg)
+		}
+		p.To = obj.Addr{Type: obj.TYPE_REG, Reg: v.Reg()}
+
+	case ssa.OpAMD64LoweredBroadcastB, ssa.OpAMD64LoweredBroadcastW, ssa.OpAMD64LoweredBroadcastL, ssa.OpAMD64LoweredBroadcastQ,
+		ssa.OpAMD64LoweredBroadcastB256, ssa.OpAMD64LoweredBroadcastW256, ssa.OpAMD64LoweredBroadcastL256, ssa.OpAMD64LoweredBroadcastQ256:
+		x := v.Reg()
+		mov := x86.AMOVL
+		if v.Op == ssa.OpAMD64LoweredBroadcastQ || v.Op == ssa.OpAMD64LoweredBroadcastQ256 {
+			mov = x86.AMOVQ
+		}
+		opregreg(s, mov, x, v.Args[0].Reg())
+		// shuffle copies the dword selected by imm8=0 (or the word,
+		// for PSHUFLW) to all lanes of x.
+		shuffle := func(as obj.As) {
+			p := s.Prog(as)
+			p.From = obj.Addr{Type: obj.TYPE_CONST, Offset: 0}
+			p.SetFrom3Reg(x)
+			p.To = obj.Addr{Type: obj.TYPE_REG, Reg: x}
+		}
+		switch v.Op {
+		case ssa.OpAMD64LoweredBroadcastB:
+			opregreg(s, x86.APUNPCKLBW, x, x) // byte 0 to word 0
+			shuffle(x86.APSHUFLW)
+			shuffle(x86.APSHUFD)
+		case ssa.OpAMD64LoweredBroadcastW:
+			shuffle(x86.APSHUFLW)
+			shuffle(x86.APSHUFD)
+		case ssa.OpAMD64LoweredBroadcastL:
+			shuffle(x86.APSHUFD)
+		case ssa.OpAMD64LoweredBroadcastQ:
+			opregreg(s, x86.APUNPCKLQDQ, x, x)
+		default:
+			as := map[ssa.Op]obj.As{
+				ssa.OpAMD64LoweredBroadcastB256: x86.AVPBROADCASTB,
+				ssa.OpAMD64LoweredBroadcastW256: x86.AVPBROADCASTW,
+				ssa.OpAMD64LoweredBroadcastL256: x86.AVPBROADCASTD,
+				ssa.OpAMD64LoweredBroadcastQ256: x86.AVPBROADCASTQ,
+			}[v.Op]
+			opregreg(s, as, yreg(x), x)
+		}
+
 	case ssa.OpAMD64SHRDQ, ssa.OpAMD64SHLDQ:
 		p := s.Prog(v.Op.Asm())
 		lo, hi, bits := v.Args[0].Reg(), v.Args[1].Reg(), v.Args[2].Reg()
@@ -798,6 +905,20 @@ func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 		ssagen.AddAux(&p.From, v)
 		p.To.Type = obj.TYPE_REG
 		p.To.Reg = v.Reg()
+	case ssa.OpAMD64VMOVDQUload256:
+		p := s.Prog(v.Op.Asm())
+		p.From.Type = obj.TYPE_MEM
+		p.From.Reg = v.Args[0].Reg()
+		ssagen.AddAux(&p.From, v)
+		p.To.Type = obj.TYPE_REG
+		p.To.Reg = yreg(v.Reg())
+	case ssa.OpAMD64VMOVDQUstore256:
+		p := s.Prog(v.Op.Asm())
+		p.From.Type = obj.TYPE_REG
+		p.From.Reg = yreg(v.Args[1].Reg())
+		p.To.Type = obj.TYPE_MEM
+		p.To.Reg = v.Args[0].Reg()
+		ssagen.AddAux(&p.To, v)
 	case ssa.OpAMD64MOVBloadidx1, ssa.OpAMD64MOVWloadidx1, ssa.OpAMD64MOVLloadidx1, ssa.OpAMD64MOVQloadidx1, ssa.OpAMD64MOVSSloadidx1, ssa.OpAMD64MOVSDloadidx1,
 		ssa.OpAMD64MOVQloadidx8, ssa.OpAMD64MOVSDloadidx8, ssa.OpAMD64MOVLloadidx8, ssa.OpAMD64MOVLloadidx4, ssa.OpAMD64MOVSSloadidx4, ssa.OpAMD64MOVWloadidx2,
 		ssa.OpAMD64MOVBELloadidx1, ssa.OpAMD64MOVBELloadidx4, ssa.OpAMD64MOVBELloadidx8, ssa.OpAMD64MOVBEQloadidx1, ssa.OpAMD64MOVBEQloadidx8:
@@ -1014,7 +1135,7 @@ func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 		x := v.Args[0].Reg()
 		y := v.Reg()
 		if x != y {
-			opregreg(s, moveByType(v.Type), y, x)
+			opregreg(s, moveByType(v.Type), vecReg(v.Type, y), vecReg(v.Type, x))
 		}
 	case ssa.OpLoadReg:
 		if v.Type.IsFlags() {
@@ -1024,7 +1145,7 @@ func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 		p := s.Prog(loadByType(v.Type))
 		ssagen.AddrAuto(&p.From, v.Args[0])
 		p.To.Type = obj.TYPE_REG
-		p.To.Reg = v.Reg()
+		p.To.Reg = vecReg(v.Type, v.Reg())
 
 	case ssa.OpStoreReg:
 		if v.Type.IsFlags() {
@@ -1033,7 +1154,7 @@ func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 		}
 		p := s.Prog(storeByType(v.Type))
 		p.From.Type = obj.TYPE_REG
-		p.From.Reg = v.Args[0].Reg()
+		p.From.Reg = vecReg(v.Type, v.Args[0].Reg())
 		ssagen.AddrAuto(&p.To, v)
 	case ssa.OpAMD64LoweredHasCPUFeature:
 		p := s.Prog(x86.AMOVBQZX)
@@ -1070,6 +1191,7 @@ func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 			// set G register from TLS
 			getgFromTLS(s, x86.REG_R14)
 		}
+		zeroUpper(s, v.Block.Func)
 		if v.Op == ssa.OpAMD64CALLtail {
 			s.TailCall(v)
 			break
@@ -1084,6 +1206,7 @@ func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 			getgFromTLS(s, x86.REG_R14)
 		}
 	case ssa.OpAMD64CALLclosure, ssa.OpAMD64CALLinter:
+		zeroUpper(s, v.Block.Func)
 		s.Call(v)
 
 	case ssa.OpAMD64LoweredGetCallerPC:
@@ -1374,6 +1497,7 @@ func ssaGenBlock(s *ssagen.State, b, next *ssa.Block) {
 		}
 	case ssa.BlockExit, ssa.BlockRetJmp:
 	case ssa.BlockRet:
+		zeroUpper(s, b.Func)
 		s.Prog(obj.ARET)
 
 	case ssa.BlockAMD64EQF:
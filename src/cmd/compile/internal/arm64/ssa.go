@@ -27,6 +27,8 @@ func loadByType(t *types.Type) obj.As {
 		case 8:
 			return arm64.AFMOVD
 		}
+	} else if t.IsSIMD() && t.Size() == 16 {
+		return arm64.AFMOVQ
 	} else {
 		switch t.Size() {
 		case 1:
@@ -63,6 +65,8 @@ func storeByType(t *types.Type) obj.As {
 		case 8:
 			return arm64.AFMOVD
 		}
+	} else if t.IsSIMD() && t.Size() == 16 {
+		return arm64.AFMOVQ
 	} else {
 		switch t.Size() {
 		case 1:
@@ -78,6 +82,12 @@ func storeByType(t *types.Type) obj.As {
 	panic("bad store type")
 }
 
+// vreg returns the operand register for the vector register that
+// extends floating-point register r, with arrangement arng.
+func vreg(r int16, arng int16) int16 {
+	return (r-arm64.REG_F0)&31 + arm64.REG_ARNG + ((arng & 15) << 5)
+}
+
 // makeshift encodes a register shifted by a constant, used as an Offset in Prog.
 func makeshift(v *ssa.Value, reg int16, typ int64, s int64) int64 {
 	if s < 0 || s >= 64 {
@@ -119,6 +129,19 @@ func genIndexedOperand(op ssa.Op, base, idx int16) obj.Addr {
 	return mop
 }
 
+// vecArng returns the arrangement of the vector registers of op.
+func vecArng(op ssa.Op) int16 {
+	switch op {
+	case ssa.OpARM64VADD8H, ssa.OpARM64VSUB8H, ssa.OpARM64VCMEQ8H, ssa.OpARM64VDUP8H:
+		return arm64.ARNG_8H
+	case ssa.OpARM64VADD4S, ssa.OpARM64VSUB4S, ssa.OpARM64VCMEQ4S, ssa.OpARM64VDUP4S:
+		return arm64.ARNG_4S
+	case ssa.OpARM64VADD2D, ssa.OpARM64VSUB2D, ssa.OpARM64VCMEQ2D, ssa.OpARM64VDUP2D:
+		return arm64.ARNG_2D
+	}
+	return arm64.ARNG_16B
+}
+
 func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 	switch v.Op {
 	case ssa.OpCopy, ssa.OpARM64MOVDreg:
@@ -130,6 +153,14 @@ func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 		if x == y {
 			return
 		}
+		if v.Type.IsSIMD() {
+			p := s.Prog(arm64.AVMOV)
+			p.From.Type = obj.TYPE_REG
+			p.From.Reg = vreg(x, arm64.ARNG_16B)
+			p.To.Type = obj.TYPE_REG
+			p.To.Reg = vreg(y, arm64.ARNG_16B)
+			return
+		}
 		as := arm64.AMOVD
 		if v.Type.IsFloat() {
 			switch v.Type.Size() {
@@ -442,7 +473,8 @@ func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 		ssa.OpARM64MOVWUload,
 		ssa.OpARM64MOVDload,
 		ssa.OpARM64FMOVSload,
-		ssa.OpARM64FMOVDload:
+		ssa.OpARM64FMOVDload,
+		ssa.OpARM64FMOVQload:
 		p := s.Prog(v.Op.Asm())
 		p.From.Type = obj.TYPE_MEM
 		p.From.Reg = v.Args[0].Reg()
@@ -492,6 +524,7 @@ func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 		ssa.OpARM64MOVDstore,
 		ssa.OpARM64FMOVSstore,
 		ssa.OpARM64FMOVDstore,
+		ssa.OpARM64FMOVQstore,
 		ssa.OpARM64STLRB,
 		ssa.OpARM64STLR,
 		ssa.OpARM64STLRW:
@@ -960,6 +993,23 @@ func ssaGenValue(s *ssagen.State, v *ssa.Value) {
 		p.From.Reg = (v.Args[0].Reg()-arm64.REG_F0)&31 + arm64.REG_ARNG + ((arm64.ARNG_8B & 15) << 5)
 		p.To.Type = obj.TYPE_REG
 		p.To.Reg = (v.Reg()-arm64.REG_F0)&31 + arm64.REG_ARNG + ((arm64.ARNG_8B & 15) << 5)
+	case ssa.OpARM64VADD16B, ssa.OpARM64VADD8H, ssa.OpARM64VADD4S, ssa.OpARM64VADD2D,
+		ssa.OpARM64VSUB16B, ssa.OpARM64VSUB8H, ssa.OpARM64VSUB4S, ssa.OpARM64VSUB2D,
+		ssa.OpARM64VCMEQ16B, ssa.OpARM64VCMEQ8H, ssa.OpARM64VCMEQ4S, ssa.OpARM64VCMEQ2D,
+		ssa.OpARM64VAND16B, ssa.OpARM64VORR16B, ssa.OpARM64VEOR16B:
+		arng := vecArng(v.Op)
+		p := s.Prog(v.Op.Asm())
+		p.From.Type = obj.TYPE_REG
+		p.From.Reg = vreg(v.Args[1].Reg(), arng)
+		p.Reg = vreg(v.Args[0].Reg(), arng)
+		p.To.Type = obj.TYPE_REG
+		p.To.Reg = vreg(v.Reg(), arng)
+	case ssa.OpARM64VDUP16B, ssa.OpARM64VDUP8H, ssa.OpARM64VDUP4S, ssa.OpARM64VDUP2D:
+		p := s.Prog(v.Op.Asm())
+		p.From.Type = obj.TYPE_REG
+		p.From.Reg = v.Args[0].Reg()
+		p.To.Type = obj.TYPE_REG
+		p.To.Reg = vreg(v.Reg(), vecArng(v.Op))
 	case ssa.OpARM64VUADDLV:
 		p := s.Prog(v.Op.Asm())
 		p.From.Type = obj.TYPE_REG
@@ -46,12 +46,15 @@ type DebugFlags struct {
 	TypeAssert            int    `help:"print information about type assertion inlining"`
 	TypecheckInl          int    `help:"eager typechecking of inline function bodies" concurrent:"ok"`
 	Unified               int    `help:"enable unified IR construction"`
+	VariableMakeThreshold int    `help:"size in bytes of the stack buffer for non-escaping make and append with variable size; 0 to disable" concurrent:"ok"`
 	WB                    int    `help:"print information about write barriers"`
 	ABIWrap               int    `help:"print information about ABI wrapper generation"`
 	MayMoreStack          string `help:"call named function before all stack growth checks" concurrent:"ok"`
 	PGOInlineCDFThreshold string `help:"cummulative threshold percentage for determining call sites as hot candidates for inlining" concurrent:"ok"`
 	PGOInlineBudget       int    `help:"inline budget for hot functions" concurrent:"ok"`
 	PGOInline             int    `help:"debug profile-guided inlining"`
+	PGODebug              int    `help:"debug profile-guided block layout, loop alignment and spill placement; 2 for more detail" concurrent:"ok"`
+	PGODevirtualize       int    `help:"enable profile-guided devirtualization; 2 to also report call sites not devirtualized" concurrent:"ok"`
 
 	ConcurrentOk bool // true if only concurrentOk flags seen
 }
@@ -167,6 +167,8 @@ func ParseFlags() {
 
 	Debug.ConcurrentOk = true
 	Debug.InlFuncsWithClosures = 1
+	Debug.PGODevirtualize = 1
+	Debug.VariableMakeThreshold = 32
 	if buildcfg.Experiment.Unified {
 		Debug.Unified = 1
 	}
@@ -0,0 +1,357 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package devirtualize
+
+import (
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/ir"
+	"cmd/compile/internal/pgo"
+	"cmd/compile/internal/typecheck"
+	"cmd/compile/internal/types"
+	"cmd/internal/obj"
+	"cmd/internal/src"
+	"fmt"
+)
+
+// ProfileGuided performs call devirtualization of indirect calls based on
+// profile information.
+//
+// Specifically, it performs conditional devirtualization of interface calls
+// and of calls through function values for the hottest callee observed at
+// the call site in the profile. That is, it performs a transformation like:
+//
+//	type Iface interface {
+//		Foo()
+//	}
+//
+//	type Concrete struct{}
+//
+//	func (Concrete) Foo() {}
+//
+//	func foo(i Iface) {
+//		i.Foo()
+//	}
+//
+// to:
+//
+//	func foo(i Iface) {
+//		if c, ok := i.(Concrete); ok {
+//			c.Foo()
+//		} else {
+//			i.Foo()
+//		}
+//	}
+//
+// A call through a function value is guarded by comparing the code pointer
+// of the function value against the address of the hottest callee instead.
+//
+// The primary benefit of this transformation is enabling inlining of the
+// direct call.
+func ProfileGuided(fn *ir.Func, p *pgo.Profile) {
+	ir.CurFunc = fn
+
+	name := ir.PkgFuncName(fn)
+
+	// Can't devirtualize go/defer calls. See comment in Func.
+	goDeferCall := make(map[*ir.CallExpr]bool)
+
+	var edit func(n ir.Node) ir.Node
+	edit = func(n ir.Node) ir.Node {
+		if n == nil {
+			return n
+		}
+
+		if gds, ok := n.(*ir.GoDeferStmt); ok {
+			if call, ok := gds.Call.(*ir.CallExpr); ok {
+				goDeferCall[call] = true
+			}
+		}
+
+		ir.EditChildren(n, edit)
+
+		call, ok := n.(*ir.CallExpr)
+		if !ok {
+			return n
+		}
+		switch call.Op() {
+		case ir.OCALLINTER:
+		case ir.OCALLFUNC:
+			if !isIndirectCall(call) {
+				return n
+			}
+		default:
+			return n
+		}
+
+		if goDeferCall[call] {
+			if base.Debug.PGODevirtualize >= 2 {
+				fmt.Printf("%v: not devirtualizing %v: go/defer call\n", ir.Line(call), call.X)
+			}
+			return n
+		}
+
+		site := pgo.CallSiteKey{CallerName: name, CallSiteOffset: pgo.NodeLineOffset(call, fn)}
+		edges := p.CallSites[site]
+		if len(edges) == 0 {
+			// Not in the profile; the call site is cold.
+			return n
+		}
+
+		if call.Op() == ir.OCALLINTER {
+			callee, ctyp := findHotConcreteCallee(p, call, edges)
+			if callee == nil {
+				return n
+			}
+			return rewriteInterfaceCall(call, callee, ctyp)
+		}
+		callee := findHotFuncCallee(p, call, edges)
+		if callee == nil {
+			return n
+		}
+		return rewriteFuncCall(call, callee)
+	}
+
+	ir.EditChildren(fn, edit)
+}
+
+// isIndirectCall reports whether the OCALLFUNC call is a call through a
+// function value whose target is not statically known.
+func isIndirectCall(call *ir.CallExpr) bool {
+	if ir.IsIntrinsicCall(call) {
+		return false
+	}
+	switch fn := ir.StaticValue(call.X); fn.Op() {
+	case ir.OMETHEXPR, ir.OCLOSURE:
+		return false
+	case ir.ONAME:
+		return fn.(*ir.Name).Class != ir.PFUNC
+	}
+	return true
+}
+
+// findHotConcreteCallee returns the hottest method, among the profile edges
+// of the call site of the interface call, that can be called directly
+// instead, and its receiver type. It returns nil if there is none.
+func findHotConcreteCallee(p *pgo.Profile, call *ir.CallExpr, edges []pgo.NodeMapKey) (*ir.Func, *types.Type) {
+	sel := call.X.(*ir.SelectorExpr)
+	inter := sel.X.Type()
+	if inter.HasShape() {
+		// See the comment about shaped interfaces in Call.
+		if base.Debug.PGODevirtualize >= 2 {
+			fmt.Printf("%v: not devirtualizing %v: shaped interface %v\n", ir.Line(call), call.X, inter)
+		}
+		return nil, nil
+	}
+
+	for _, e := range edges {
+		if p.NodeMap[e].EWeight == 0 {
+			break
+		}
+		callee := p.Callee(e.CalleeName)
+		if callee == nil {
+			if base.Debug.PGODevirtualize >= 2 {
+				fmt.Printf("%v: not devirtualizing %v to %s: callee not available\n", ir.Line(call), call.X, e.CalleeName)
+			}
+			continue
+		}
+		recv := callee.Type().Recv()
+		if recv == nil || ir.MethodSym(recv.Type, sel.Sel) != callee.Sym() {
+			// Some other call on the same line.
+			continue
+		}
+		ctyp := recv.Type
+		if ctyp.IsInterface() || ctyp.HasShape() {
+			continue
+		}
+		if !typecheck.Implements(ctyp, inter) {
+			if base.Debug.PGODevirtualize >= 2 {
+				fmt.Printf("%v: not devirtualizing %v to %s: %v does not implement %v\n", ir.Line(call), call.X, e.CalleeName, ctyp, inter)
+			}
+			continue
+		}
+		return callee, ctyp
+	}
+	return nil, nil
+}
+
+// findHotFuncCallee returns the hottest top-level function, among the
+// profile edges of the call site of the indirect call, that can be called
+// directly instead. It returns nil if there is none.
+func findHotFuncCallee(p *pgo.Profile, call *ir.CallExpr, edges []pgo.NodeMapKey) *ir.Func {
+	for _, e := range edges {
+		if p.NodeMap[e].EWeight == 0 {
+			break
+		}
+		callee := p.Callee(e.CalleeName)
+		if callee == nil {
+			if base.Debug.PGODevirtualize >= 2 {
+				fmt.Printf("%v: not devirtualizing %v to %s: callee not available\n", ir.Line(call), call.X, e.CalleeName)
+			}
+			continue
+		}
+		if callee.OClosure != nil || callee.Type().Recv() != nil || callee.Type().HasShape() {
+			// Function values of closures and methods do not point
+			// directly at the function.
+			continue
+		}
+		if callee.ABI != obj.ABIInternal {
+			continue
+		}
+		if !types.Identical(callee.Type(), call.X.Type()) {
+			// Some other call on the same line.
+			continue
+		}
+		return callee
+	}
+	return nil
+}
+
+// rewriteInterfaceCall rewrites the interface call to call callee, a method
+// of ctyp, directly when the dynamic type of the receiver is ctyp. It
+// returns the replacement for call.
+func rewriteInterfaceCall(call *ir.CallExpr, callee *ir.Func, ctyp *types.Type) ir.Node {
+	lno := ir.SetPos(call)
+	defer func() { base.Pos = lno }()
+
+	if base.Flag.LowerM != 0 {
+		base.WarnfAt(call.Pos(), "PGO devirtualizing %v to %v", call.X, ir.PkgFuncName(callee))
+	}
+
+	// We generate an OINLCALL of:
+	//
+	//	recv, arg1, ..., argN = recv expr, arg1 expr, ..., argN expr
+	//	t, ok := recv.(Concrete)
+	//	if ok {
+	//		ret1, ..., retN = t.Method(arg1, ..., argN)
+	//	} else {
+	//		ret1, ..., retN = recv.Method(arg1, ..., argN)
+	//	}
+	//
+	// with ret1, ..., retN as its results.
+	//
+	// This isn't really an inlined call of course, but InlinedCallExpr
+	// makes handling reassignment of return values easier.
+	pos := call.Pos()
+	sel := call.X.(*ir.SelectorExpr)
+	init, recv, args := evalCallOperands(call, sel.X)
+	sel.X = recv
+
+	tmp := typecheck.Temp(ctyp)
+	ok := typecheck.Temp(types.Types[types.TBOOL])
+	assert := ir.NewTypeAssertExpr(pos, recv, ctyp)
+	as := ir.NewAssignListStmt(pos, ir.OAS2, []ir.Node{tmp, ok}, []ir.Node{typecheck.Expr(assert)})
+	init.Append(typecheck.Stmt(as))
+
+	x := typecheck.Callee(ir.NewSelectorExpr(pos, ir.OXDOT, tmp, sel.Sel))
+	direct := typecheck.Call(pos, x, args, call.IsDDD).(*ir.CallExpr)
+
+	return condCall(call, direct, ok, init)
+}
+
+// rewriteFuncCall rewrites the call through a function value to call
+// callee directly when the function value refers to callee. It returns the
+// replacement for call.
+func rewriteFuncCall(call *ir.CallExpr, callee *ir.Func) ir.Node {
+	lno := ir.SetPos(call)
+	defer func() { base.Pos = lno }()
+
+	if base.Flag.LowerM != 0 {
+		base.WarnfAt(call.Pos(), "PGO devirtualizing %v to %v", call.X, ir.PkgFuncName(callee))
+	}
+
+	// We generate an OINLCALL of:
+	//
+	//	f, arg1, ..., argN = f expr, arg1 expr, ..., argN expr
+	//	if funcPC(f) == funcPC(callee) {
+	//		ret1, ..., retN = callee(arg1, ..., argN)
+	//	} else {
+	//		ret1, ..., retN = f(arg1, ..., argN)
+	//	}
+	//
+	// with ret1, ..., retN as its results. A nil f still panics when it
+	// is called: loading its code pointer faults the same way.
+	pos := call.Pos()
+	init, f, args := evalCallOperands(call, call.X)
+	call.X = f
+
+	fpc := funcValuePC(pos, f)
+	calleepc := ir.FuncPC(pos, typecheck.Conv(callee.Nname, types.Types[types.TINTER]), obj.ABIInternal)
+	cond := typecheck.Expr(ir.NewBinaryExpr(pos, ir.OEQ, fpc, calleepc))
+
+	direct := typecheck.Call(pos, callee.Nname, args, call.IsDDD).(*ir.CallExpr)
+
+	return condCall(call, direct, cond, init)
+}
+
+// funcValuePC returns an expression that evaluates to the PC of the
+// function the function value f refers to, that is, *(*uintptr)(f).
+func funcValuePC(pos src.XPos, f ir.Node) ir.Node {
+	var e ir.Node = ir.NewConvExpr(pos, ir.OCONVNOP, types.Types[types.TUNSAFEPTR], f)
+	e.SetTypecheck(1)
+	e = ir.NewConvExpr(pos, ir.OCONVNOP, types.Types[types.TUINTPTR].PtrTo(), e)
+	e.SetTypecheck(1)
+	e = ir.NewStarExpr(pos, e)
+	e.SetType(types.Types[types.TUINTPTR])
+	e.SetTypecheck(1)
+	return e
+}
+
+// evalCallOperands appends to the init statements of call an assignment
+// of x, the callee operand of call, and of the arguments of call to
+// temporaries. It returns the init statements, the temporary for x, and
+// the temporaries for the arguments, with which the arguments of call
+// are replaced.
+//
+// x is used twice but its side effects must happen once, and the
+// arguments are used in two calls but cannot be trivially copied. x must
+// be first as its side effects are ordered before those of the
+// arguments.
+func evalCallOperands(call *ir.CallExpr, x ir.Node) (init ir.Nodes, xtmp *ir.Name, args []ir.Node) {
+	init = ir.TakeInit(call)
+
+	xtmp = typecheck.Temp(x.Type())
+	lhs := []ir.Node{xtmp}
+	rhs := []ir.Node{x}
+	for _, arg := range call.Args.Take() {
+		lhs = append(lhs, typecheck.Temp(arg.Type()))
+		rhs = append(rhs, arg)
+	}
+	init.Append(typecheck.Stmt(ir.NewAssignListStmt(call.Pos(), ir.OAS2, lhs, rhs)))
+
+	// Copy slices so edits in one call don't affect the other.
+	call.Args = append([]ir.Node(nil), lhs[1:]...)
+	return init, xtmp, append([]ir.Node(nil), lhs[1:]...)
+}
+
+// condCall returns an OINLCALL that runs init, then calls direct if cond
+// is true and call otherwise, and has the results of the call as its
+// results.
+func condCall(call, direct *ir.CallExpr, cond ir.Node, init ir.Nodes) ir.Node {
+	pos := call.Pos()
+
+	var retvars []ir.Node
+	for _, ret := range call.X.Type().Results().FieldSlice() {
+		retvars = append(retvars, typecheck.Temp(ret.Type))
+	}
+
+	var thenBlock, elseBlock ir.Nodes
+	if len(retvars) == 0 {
+		thenBlock.Append(direct)
+		elseBlock.Append(call)
+	} else {
+		thenRet := append([]ir.Node(nil), retvars...)
+		thenBlock.Append(typecheck.Stmt(ir.NewAssignListStmt(pos, ir.OAS2, thenRet, []ir.Node{direct})))
+		elseRet := append([]ir.Node(nil), retvars...)
+		elseBlock.Append(typecheck.Stmt(ir.NewAssignListStmt(pos, ir.OAS2, elseRet, []ir.Node{call})))
+	}
+
+	nif := ir.NewIfStmt(pos, cond, thenBlock, elseBlock)
+	nif.SetInit(init)
+
+	res := ir.NewInlinedCallExpr(pos, []ir.Node{typecheck.Stmt(nif)}, retvars)
+	res.SetType(call.Type())
+	res.SetTypecheck(1)
+	return res
+}
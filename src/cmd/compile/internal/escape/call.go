@@ -154,6 +154,18 @@ func (e *escape) callCommon(ks []hole, call ir.Node, init *ir.Nodes, wrapper *ir
 			}
 		}
 
+		// If the slice has to grow, the new backing store may
+		// come from a stack buffer instead of the heap (see
+		// appendStackBuf in walk). Model it as a location of
+		// its own whose address flows to the result. The
+		// buffer is handed out at most once per call, so it
+		// lives as long as the function, even in a loop.
+		if StackBufLen(call.Type().Elem()) > 0 {
+			loc := e.newLoc(call, false)
+			loc.loopDepth = 1
+			e.flow(ks[0].addr(call, "append"), loc)
+		}
+
 	case ir.OCOPY:
 		call := call.(*ir.BinaryExpr)
 		argument(e.discardHole(), &call.X)
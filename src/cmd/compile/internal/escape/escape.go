@@ -300,26 +300,34 @@ func (b *batch) finish(fns []*ir.Func) {
 		// TODO(mdempsky): Update tests to expect this.
 		goDeferWrapper := n.Op() == ir.OCLOSURE && n.(*ir.ClosureExpr).Func.Wrapper()
 
+		// Likewise for the backing stores of append, which are only
+		// allocated if the slice grows.
+		quiet := goDeferWrapper || n.Op() == ir.OAPPEND
+
 		if loc.escapes {
+			var msg string
 			if n.Op() == ir.ONAME {
 				if base.Flag.CompilingRuntime {
 					base.ErrorfAt(n.Pos(), "%v escapes to heap, not allowed in runtime", n)
 				}
+				msg = fmt.Sprintf("moved to heap: %v", n)
 				if base.Flag.LowerM != 0 {
-					base.WarnfAt(n.Pos(), "moved to heap: %v", n)
+					base.WarnfAt(n.Pos(), "%s", msg)
 				}
 			} else {
-				if base.Flag.LowerM != 0 && !goDeferWrapper {
-					base.WarnfAt(n.Pos(), "%v escapes to heap", n)
-				}
-				if logopt.Enabled() {
-					var e_curfn *ir.Func // TODO(mdempsky): Fix.
-					logopt.LogOpt(n.Pos(), "escape", "escape", ir.FuncName(e_curfn))
+				msg = fmt.Sprintf("%v escapes to heap", n)
+				if base.Flag.LowerM != 0 && !quiet {
+					base.WarnfAt(n.Pos(), "%s", msg)
 				}
 			}
+			if logopt.Enabled() && n.Op() != ir.OAPPEND {
+				// One record per heap allocation, explaining why
+				// it is needed.
+				logopt.LogOpt(n.Pos(), "escape", "escape", ir.FuncName(loc.curfn), msg, loc.escapeWhy)
+			}
 			n.SetEsc(ir.EscHeap)
 		} else {
-			if base.Flag.LowerM != 0 && n.Op() != ir.ONAME && !goDeferWrapper {
+			if base.Flag.LowerM != 0 && n.Op() != ir.ONAME && !quiet {
 				base.WarnfAt(n.Pos(), "%v does not escape", n)
 			}
 			n.SetEsc(ir.EscNone)
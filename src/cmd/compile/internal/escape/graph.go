@@ -71,6 +71,11 @@ type location struct {
 	// allocated.
 	escapes bool
 
+	// escapeWhy explains why the location escapes, for the -json
+	// optimization log: the assignment path that its address flows
+	// along, followed by the location it flows to.
+	escapeWhy []*logopt.LoggedOpt
+
 	// transient reports whether the represented expression's
 	// address does not outlive the statement; that is, whether
 	// its storage can be immediately reused.
@@ -190,10 +195,8 @@ func (b *batch) flow(k hole, src *location) {
 			}
 			explanation := b.explainFlow(pos, dst, src, k.derefs, k.notes, []*logopt.LoggedOpt{})
 			if logopt.Enabled() {
-				var e_curfn *ir.Func // TODO(mdempsky): Fix.
-				logopt.LogOpt(src.n.Pos(), "escapes", "escape", ir.FuncName(e_curfn), fmt.Sprintf("%v escapes to heap", src.n), explanation)
+				src.escapeWhy = b.explainDest(dst, src, explanation)
 			}
-
 		}
 		src.escapes = true
 		return
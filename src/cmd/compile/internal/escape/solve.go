@@ -99,8 +99,7 @@ func (b *batch) walkOne(root *location, walkgen uint32, enqueue func(*location))
 					}
 					explanation := b.explainPath(root, l)
 					if logopt.Enabled() {
-						var e_curfn *ir.Func // TODO(mdempsky): Fix.
-						logopt.LogOpt(l.n.Pos(), "leak", "escape", ir.FuncName(e_curfn),
+						logopt.LogOpt(l.n.Pos(), "leak", "escape", ir.FuncName(l.curfn),
 							fmt.Sprintf("parameter %v leaks to %s with derefs=%d", l.n, b.explainLoc(root), derefs), explanation)
 					}
 				}
@@ -117,8 +116,7 @@ func (b *batch) walkOne(root *location, walkgen uint32, enqueue func(*location))
 					}
 					explanation := b.explainPath(root, l)
 					if logopt.Enabled() {
-						var e_curfn *ir.Func // TODO(mdempsky): Fix.
-						logopt.LogOpt(l.n.Pos(), "escape", "escape", ir.FuncName(e_curfn), fmt.Sprintf("%v escapes to heap", l.n), explanation)
+						l.escapeWhy = b.explainDest(root, l, explanation)
 					}
 				}
 				l.escapes = true
@@ -185,6 +183,10 @@ func (b *batch) explainFlow(pos string, dst, srcloc *location, derefs int, notes
 	if print {
 		fmt.Printf("%s:%s\n", pos, flow)
 	}
+	var curfn *ir.Func
+	if srcloc != nil {
+		curfn = srcloc.curfn
+	}
 	if logopt.Enabled() {
 		var epos src.XPos
 		if notes != nil {
@@ -192,8 +194,7 @@ func (b *batch) explainFlow(pos string, dst, srcloc *location, derefs int, notes
 		} else if srcloc != nil && srcloc.n != nil {
 			epos = srcloc.n.Pos()
 		}
-		var e_curfn *ir.Func // TODO(mdempsky): Fix.
-		explanation = append(explanation, logopt.NewLoggedOpt(epos, "escflow", "escape", ir.FuncName(e_curfn), flow))
+		explanation = append(explanation, logopt.NewLoggedOpt(epos, "escflow", "escape", ir.FuncName(curfn), flow))
 	}
 
 	for note := notes; note != nil; note = note.next {
@@ -201,14 +202,25 @@ func (b *batch) explainFlow(pos string, dst, srcloc *location, derefs int, notes
 			fmt.Printf("%s:     from %v (%v) at %s\n", pos, note.where, note.why, base.FmtPos(note.where.Pos()))
 		}
 		if logopt.Enabled() {
-			var e_curfn *ir.Func // TODO(mdempsky): Fix.
-			explanation = append(explanation, logopt.NewLoggedOpt(note.where.Pos(), "escflow", "escape", ir.FuncName(e_curfn),
+			explanation = append(explanation, logopt.NewLoggedOpt(note.where.Pos(), "escflow", "escape", ir.FuncName(curfn),
 				fmt.Sprintf("     from %v (%v)", note.where, note.why)))
 		}
 	}
 	return explanation
 }
 
+// explainDest completes explanation, the flow path of src's address
+// to dst, with the location that the path ends at: the heap, or a
+// location that outlives src, like a result parameter or a variable
+// of an enclosing function.
+func (b *batch) explainDest(dst, src *location, explanation []*logopt.LoggedOpt) []*logopt.LoggedOpt {
+	pos := src.n.Pos()
+	if dst.n != nil {
+		pos = dst.n.Pos()
+	}
+	return append(explanation, logopt.NewLoggedOpt(pos, "escdest", "escape", ir.FuncName(src.curfn), b.explainLoc(dst)))
+}
+
 func (b *batch) explainLoc(l *location) string {
 	if l == &b.heapLoc {
 		return "{heap}"
@@ -5,6 +5,7 @@
 package escape
 
 import (
+	"cmd/compile/internal/base"
 	"cmd/compile/internal/ir"
 	"cmd/compile/internal/typecheck"
 	"cmd/compile/internal/types"
@@ -211,7 +212,11 @@ func HeapAllocReason(n ir.Node) string {
 			r = n.Len
 		}
 		if !ir.IsSmallIntConst(r) {
-			return "non-constant size"
+			// Small enough sizes use a stack buffer; see walkMakeSlice.
+			if StackBufLen(n.Type().Elem()) == 0 {
+				return "non-constant size"
+			}
+			return ""
 		}
 		if t := n.Type(); t.Elem().Size() != 0 && ir.Int64Val(r) > ir.MaxImplicitStackVarSize/t.Elem().Size() {
 			return "too large for stack"
@@ -220,3 +225,18 @@ func HeapAllocReason(n ir.Node) string {
 
 	return ""
 }
+
+// StackBufLen returns the length of the stack array that can back a
+// non-escaping make or append of a slice with element type elem whose
+// size is only known at run time, or 0 if there is no such array.
+// Sizes that do not fit in the array are still allocated on the heap.
+func StackBufLen(elem *types.Type) int64 {
+	size := elem.Size()
+	if size == 0 || size > int64(base.Debug.VariableMakeThreshold) {
+		return 0
+	}
+	if elem.Alignment() > int64(types.PtrSize) || elem.NotInHeap() {
+		return 0
+	}
+	return int64(base.Debug.VariableMakeThreshold) / size
+}
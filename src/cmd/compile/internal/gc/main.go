@@ -176,6 +176,7 @@ func Main(archInit func(*ssagen.ArchInfo)) {
 	ir.EscFmt = escape.Fmt
 	ir.IsIntrinsicCall = ssagen.IsIntrinsicCall
 	inline.SSADumpInline = ssagen.DumpInline
+	pgo.LookupFunc = noder.LookupFunc
 	ssagen.InitEnv()
 	ssagen.InitTables()
 
@@ -261,6 +262,20 @@ func Main(archInit func(*ssagen.ArchInfo)) {
 	var profile *pgo.Profile
 	if base.Flag.PgoProfile != "" {
 		profile = pgo.New(base.Flag.PgoProfile)
+		ssagen.PGOProfile = profile
+	}
+
+	// Profile-guided devirtualization of interface and indirect
+	// function calls. This must happen before inlining, so that the
+	// direct calls it introduces can be inlined.
+	if profile != nil && base.Debug.PGODevirtualize > 0 {
+		base.Timer.Start("fe", "pgodevirtualize")
+		for _, n := range typecheck.Target.Decls {
+			if n.Op() == ir.ODCLFUNC {
+				devirtualize.ProfileGuided(n.(*ir.Func), profile)
+			}
+		}
+		ir.CurFunc = nil
 	}
 
 	// Inlining
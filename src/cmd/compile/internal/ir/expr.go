@@ -153,6 +153,12 @@ type CallExpr struct {
 	KeepAlive []*Name // vars to be kept alive until call returns
 	IsDDD     bool
 	NoInline  bool
+
+	// For a non-escaping OAPPEND, a stack array the slice can grow
+	// into instead of the heap, and a flag set once it has been used
+	// in the current call. See walk.appendStackBuf.
+	StackBuf     *Name
+	StackBufUsed *Name
 }
 
 func NewCallExpr(pos src.XPos, op Op, fun Node, args []Node) *CallExpr {
@@ -243,6 +243,12 @@ func (n *CallExpr) doChildren(do func(Node) bool) bool {
 	if doNames(n.KeepAlive, do) {
 		return true
 	}
+	if n.StackBuf != nil && do(n.StackBuf) {
+		return true
+	}
+	if n.StackBufUsed != nil && do(n.StackBufUsed) {
+		return true
+	}
 	return false
 }
 func (n *CallExpr) editChildren(edit func(Node) Node) {
@@ -252,6 +258,12 @@ func (n *CallExpr) editChildren(edit func(Node) Node) {
 	}
 	editNodes(n.Args, edit)
 	editNames(n.KeepAlive, edit)
+	if n.StackBuf != nil {
+		n.StackBuf = edit(n.StackBuf).(*Name)
+	}
+	if n.StackBufUsed != nil {
+		n.StackBufUsed = edit(n.StackBufUsed).(*Name)
+	}
 }
 
 func (n *CaseClause) Format(s fmt.State, verb rune) { fmtNode(n, s, verb) }
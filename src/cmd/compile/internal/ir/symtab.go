@@ -52,6 +52,7 @@ var Syms struct {
 	Zerobase        *obj.LSym
 	ARM64HasATOMICS *obj.LSym
 	ARMHasVFPv4     *obj.LSym
+	X86HasAVX2      *obj.LSym
 	X86HasFMA       *obj.LSym
 	X86HasPOPCNT    *obj.LSym
 	X86HasSSE41     *obj.LSym
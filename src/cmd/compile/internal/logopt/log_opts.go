@@ -67,6 +67,12 @@ import (
 //    the lines of the explanation appear, each potentially followed with its own inlining
 //    location if the escape flow occurred within an inlined function.
 //
+//    Escape analysis logs one "escape" diagnostic for each heap allocation it decides on,
+//    with message "moved to heap: x" for a variable x, or "e escapes to heap" for an
+//    allocating expression e, the same as -m. Its explanation is the chain of "escflow"
+//    lines of the assignment path along which the address escapes, followed by a single
+//    "escdest" line naming where the path ends, such as "{heap}" or a result parameter.
+//
 // For example <destination>/cmd%2Fcompile%2Finternal%2Fssa/prove.json
 // might begin with the following line (wrapped for legibility):
 //
@@ -221,6 +221,43 @@ func s15a8(x *[15]int64) [15]int64 {
 			`{"location":{"uri":"file://tmpdir/file.go","range":{"start":{"line":9,"character":3},"end":{"line":9,"character":3}}},"message":"escflow:    flow: ~r0 = ~R0:"},`+
 			`{"location":{"uri":"file://tmpdir/file.go","range":{"start":{"line":9,"character":3},"end":{"line":9,"character":3}}},"message":"escflow:      from return ~R0 (return)"}]}`)
 	})
+
+	// Each heap allocation is logged once, with its reason chain.
+	t.Run("Escape", func(t *testing.T) {
+		const escapeCode = `package x
+var sink *int
+func f(n int) *int {
+	x := 0
+	sink = new(int)
+	_ = make([][64]byte, n)
+	return &x
+}
+`
+		escape := filepath.Join(dir, "escape.go")
+		if err := os.WriteFile(escape, []byte(escapeCode), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := testLogOptDir(t, dir, "-json=0,file://log/opt", escape, filepath.Join(dir, "escape.o"))
+		if err != nil {
+			t.Error("-json=0,file://log/opt should have succeeded")
+		}
+		logged, err := os.ReadFile(filepath.Join(dir, "log", "opt", "x", "escape.json"))
+		if err != nil {
+			t.Error("-json=0,file://log/opt missing expected log file")
+		}
+		slogged := normalize(logged, string(uriIfy(dir)), string(uriIfy("tmpdir")))
+		t.Logf("%s", slogged)
+		want(t, slogged, `{"range":{"start":{"line":4,"character":2},"end":{"line":4,"character":2}},"severity":3,"code":"escape","source":"go compiler","message":"moved to heap: x",`+
+			`"relatedInformation":[`+
+			`{"location":{"uri":"file://tmpdir/escape.go","range":{"start":{"line":7,"character":9},"end":{"line":7,"character":9}}},"message":"escflow:    flow: ~r0 = \u0026x:"},`+
+			`{"location":{"uri":"file://tmpdir/escape.go","range":{"start":{"line":7,"character":9},"end":{"line":7,"character":9}}},"message":"escflow:      from \u0026x (address-of)"},`+
+			`{"location":{"uri":"file://tmpdir/escape.go","range":{"start":{"line":7,"character":2},"end":{"line":7,"character":2}}},"message":"escflow:      from return \u0026x (return)"},`+
+			`{"location":{"uri":"file://tmpdir/escape.go","range":{"start":{"line":3,"character":15},"end":{"line":3,"character":15}}},"message":"escdest: ~r0"}]}`)
+		want(t, slogged, `"code":"escape","source":"go compiler","message":"new(int) escapes to heap",`)
+		want(t, slogged, `"message":"escflow:      from make([][64]byte, n) (non-constant size)"},`+
+			`{"location":{"uri":"file://tmpdir/escape.go","range":{"start":{"line":6,"character":10},"end":{"line":6,"character":10}}},"message":"escdest: {heap}"}]}`)
+		wantN(t, slogged, `"code":"escape"`, 3)
+	})
 }
 
 func testLogOpt(t *testing.T, flag, src, outfile string) (string, error) {
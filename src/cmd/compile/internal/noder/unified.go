@@ -435,3 +435,90 @@ func writeUnifiedExport(out io.Writer) {
 
 	base.Ctxt.Fingerprint = l.pw.DumpTo(out)
 }
+
+// LookupFunc returns the function or method named fullName, such as
+// "pkg/path.Func", "pkg/path.Type.Method" or "pkg/path.(*Type).Method",
+// if it is declared in the export data read so far. It implements
+// pgo.LookupFunc.
+//
+// Closures, and functions and methods of generic types, cannot be
+// looked up: their names in profiles do not carry type arguments.
+func LookupFunc(fullName string) (*ir.Func, error) {
+	// The package path ends at the first dot after the last slash.
+	dot := strings.Index(fullName[strings.LastIndex(fullName, "/")+1:], ".")
+	if dot < 0 {
+		return nil, fmt.Errorf("%q is not a qualified name", fullName)
+	}
+	dot += strings.LastIndex(fullName, "/") + 1
+	pkgPath, name := fullName[:dot], fullName[dot+1:]
+	if strings.Contains(name, "[") {
+		return nil, fmt.Errorf("%q is generic", fullName)
+	}
+	pkg, ok := types.PkgMap()[pkgPath]
+	if !ok {
+		return nil, fmt.Errorf("package %q is not imported", pkgPath)
+	}
+
+	typName, methName, ptr := "", "", false
+	if strings.HasPrefix(name, "(*") {
+		i := strings.Index(name, ").")
+		if i < 0 {
+			return nil, fmt.Errorf("malformed method name %q", fullName)
+		}
+		typName, methName, ptr = name[len("(*"):i], name[i+len(")."):], true
+	} else if i := strings.Index(name, "."); i >= 0 {
+		typName, methName = name[:i], name[i+1:]
+	}
+
+	if typName == "" {
+		n, err := lookupPkgObj(pkg, name)
+		if err != nil {
+			return nil, err
+		}
+		if n.Op() != ir.ONAME || n.Class != ir.PFUNC {
+			return nil, fmt.Errorf("%q is not a function", fullName)
+		}
+		return n.Func, nil
+	}
+
+	n, err := lookupPkgObj(pkg, typName)
+	if err != nil {
+		return nil, err
+	}
+	if n.Op() != ir.OTYPE || n.Alias() || n.Type().IsInterface() {
+		return nil, fmt.Errorf("%s.%s is not a concrete type", pkgPath, typName)
+	}
+	for _, m := range n.Type().Methods().Slice() {
+		if m.Sym.Name != methName {
+			continue
+		}
+		if m.Type.Recv().Type.IsPtr() != ptr {
+			// The method of *T of a method declared on T is a
+			// wrapper, which has no IR.
+			return nil, fmt.Errorf("%q is a wrapper", fullName)
+		}
+		return m.Nname.(*ir.Name).Func, nil
+	}
+	return nil, fmt.Errorf("%q is not a method", fullName)
+}
+
+// lookupPkgObj returns the package-level object name of pkg, reading it
+// from export data if need be.
+func lookupPkgObj(pkg *types.Pkg, name string) (*ir.Name, error) {
+	sym, ok := pkg.LookupOK(name)
+	if !ok {
+		return nil, fmt.Errorf("%s.%s is not declared", pkg.Path, name)
+	}
+	if sym.Def != nil {
+		n, ok := sym.Def.(*ir.Name)
+		if !ok {
+			return nil, fmt.Errorf("%v is not a declared object", sym)
+		}
+		return n, nil
+	}
+	pri, ok := objReader[sym]
+	if !ok {
+		return nil, fmt.Errorf("%v is not in the export data read", sym)
+	}
+	return pri.pr.objIdx(pri.idx, nil, nil, false).(*ir.Name), nil
+}
@@ -51,6 +51,7 @@ import (
 	"internal/profile"
 	"log"
 	"os"
+	"sort"
 )
 
 // IRGraph is the key datastrcture that is built from profile. It is
@@ -108,6 +109,13 @@ type CallSiteInfo struct {
 	Callee     *ir.Func
 }
 
+// CallSiteKey identifies a call site in the profile: the caller, and the
+// line offset of the call from the start line of the caller.
+type CallSiteKey struct {
+	CallerName     string
+	CallSiteOffset int // Line offset from function start line.
+}
+
 // Profile contains the processed PGO profile and weighted call graph used for
 // PGO optimizations.
 type Profile struct {
@@ -121,9 +129,46 @@ type Profile struct {
 	// aggregated weight.
 	NodeMap map[NodeMapKey]*Weights
 
+	// CallSites maps each call site in the profile to its call-edges,
+	// heaviest first. A call site has several edges when it is an
+	// interface or indirect function call, or when several calls share
+	// its line.
+	CallSites map[CallSiteKey][]NodeMapKey
+
 	// WeightedCG represents the IRGraph built from profile, which we will
 	// update as part of inlining.
 	WeightedCG *IRGraph
+
+	// LineWeights maps each function in the profile to the sample
+	// weight of each of its lines, as line offsets from the function
+	// start line. Used to weigh basic blocks in the backend.
+	LineWeights map[string]map[int]int64
+
+	// HotLineWeight is the weight at and above which a line is
+	// considered hot.
+	HotLineWeight int64
+}
+
+// LookupFunc returns the function or method named fullName, such as
+// "pkg/path.Func" or "pkg/path.(*Type).Method", reading it from the
+// export data of the imported packages if need be. It is set by the
+// noder.
+var LookupFunc = func(fullName string) (*ir.Func, error) {
+	base.Fatalf("pgo.LookupFunc not overridden")
+	panic("unreachable")
+}
+
+// Callee returns the function named name, from the package being
+// compiled or from export data, or nil if it is not available.
+func (p *Profile) Callee(name string) *ir.Func {
+	if n := p.WeightedCG.IRNodes[name]; n != nil && n.AST != nil {
+		return n.AST
+	}
+	fn, err := LookupFunc(name)
+	if err != nil {
+		return nil
+	}
+	return fn
 }
 
 // New generates a profile-graph from the profile.
@@ -157,6 +202,8 @@ func New(profileFile string) *Profile {
 		return nil
 	}
 
+	p.processLines(profile)
+
 	// Create package-level call graph with weights from profile and IR.
 	p.initializeIRGraph()
 
@@ -213,6 +260,21 @@ func (p *Profile) processprofileGraph(g *Graph) bool {
 		return false // accept but ignore profile with no sample
 	}
 
+	p.CallSites = make(map[CallSiteKey][]NodeMapKey)
+	for key := range p.NodeMap {
+		site := CallSiteKey{CallerName: key.CallerName, CallSiteOffset: key.CallSiteOffset}
+		p.CallSites[site] = append(p.CallSites[site], key)
+	}
+	for _, edges := range p.CallSites {
+		sort.Slice(edges, func(i, j int) bool {
+			ei, ej := edges[i], edges[j]
+			if wi, wj := p.NodeMap[ei].EWeight, p.NodeMap[ej].EWeight; wi != wj {
+				return wi > wj // want larger weight first
+			}
+			return ei.CalleeName < ej.CalleeName
+		})
+	}
+
 	if !seenStartLine {
 		// TODO(prattic): If Function.start_line is missing we could
 		// fall back to using absolute line numbers, which is better
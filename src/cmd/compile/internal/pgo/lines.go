@@ -0,0 +1,99 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pgo
+
+import (
+	"cmd/compile/internal/base"
+	"cmd/compile/internal/ir"
+	"internal/profile"
+	"sort"
+)
+
+// hotLineCDFPercent is the share of the samples covered by hot lines:
+// the hottest lines that together account for this percentage of the
+// total weight are considered hot.
+const hotLineCDFPercent = 90
+
+// lineKey identifies a line of a function in the profile.
+type lineKey struct {
+	name   string
+	offset int // Line offset from function start line.
+}
+
+// processLines initializes LineWeights and HotLineWeight from the
+// samples of the profile.
+//
+// A sample is attributed to the lines of its leaf location only, that
+// is, to the code that was executing rather than to the calls on the
+// stack. A leaf location has a line for each inlined frame; the sample
+// is attributed to all of them, so that it is found both through the
+// caller and through the callee, whatever the inlining decisions of the
+// build being compiled.
+func (p *Profile) processLines(prof *profile.Profile) {
+	p.LineWeights = make(map[string]map[int]int64)
+
+	// Weights of the lines of physical frames, for the CDF.
+	phys := make(map[lineKey]int64)
+	var total int64
+
+	for _, s := range prof.Sample {
+		if len(s.Location) == 0 || len(s.Value) < 2 || s.Value[1] == 0 {
+			continue
+		}
+		w := s.Value[1]
+		lines := s.Location[0].Line
+		for i, l := range lines {
+			if l.Function == nil {
+				continue
+			}
+			k := lineKey{l.Function.Name, int(l.Line - l.Function.StartLine)}
+			weights := p.LineWeights[k.name]
+			if weights == nil {
+				weights = make(map[int]int64)
+				p.LineWeights[k.name] = weights
+			}
+			weights[k.offset] += w
+			if i == len(lines)-1 {
+				phys[k] += w
+				total += w
+			}
+		}
+	}
+
+	weights := make([]int64, 0, len(phys))
+	for _, w := range phys {
+		weights = append(weights, w)
+	}
+	sort.Slice(weights, func(i, j int) bool { return weights[i] > weights[j] })
+	var cum int64
+	for _, w := range weights {
+		cum += w
+		p.HotLineWeight = w
+		if WeightInPercentage(cum, total) >= hotLineCDFPercent {
+			break
+		}
+	}
+}
+
+// FuncLines returns the sample weight of each line of fn, or nil if fn
+// is not in the profile. The lines are absolute line numbers in fn's
+// own source, as reported by base.Ctxt.OutermostPos(pos).RelLine() for
+// the positions of fn's code. Code inlined into fn is attributed to the
+// line of its call site in fn, where the profile attributes it too.
+func (p *Profile) FuncLines(fn *ir.Func) map[uint]int64 {
+	weights := p.LineWeights[ir.PkgFuncName(fn)]
+	if weights == nil {
+		return nil
+	}
+	// See "A note on line numbers" at the top of irgraph.go.
+	start := int(base.Ctxt.OutermostPos(fn.Pos()).RelLine())
+	lines := make(map[uint]int64, len(weights))
+	for off, w := range weights {
+		if line := start + off; line > 0 {
+			lines[uint(line)] = w
+		}
+	}
+	return lines
+}
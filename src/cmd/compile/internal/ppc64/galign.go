@@ -17,6 +17,7 @@ func Init(arch *ssagen.ArchInfo) {
 	}
 	arch.REGSP = ppc64.REGSP
 	arch.MAXWIDTH = 1 << 50
+	arch.LoopAlign = 32
 
 	arch.ZeroRange = zerorange
 	arch.Ginsnop = ginsnop
@@ -2108,18 +2108,7 @@ func MarkUsedIfaceMethod(n *ir.CallExpr) {
 		// type, and the linker could do more complicated matching using
 		// some sort of fuzzy shape matching. For now, only use the name
 		// of the method for matching.
-		r := obj.Addrel(ir.CurFunc.LSym)
-		// We use a separate symbol just to tell the linker the method name.
-		// (The symbol itself is not needed in the final binary. Do not use
-		// staticdata.StringSym, which creates a content addessable symbol,
-		// which may have trailing zero bytes. This symbol doesn't need to
-		// be deduplicated anyway.)
-		name := dot.Sel.Name
-		var nameSym obj.LSym
-		nameSym.WriteString(base.Ctxt, 0, len(name), name)
-		objw.Global(&nameSym, int32(len(name)), obj.RODATA)
-		r.Sym = &nameSym
-		r.Type = objabi.R_USEGENERICIFACEMETHOD
+		markUsedMethodName(dot.Sel.Name)
 		return
 	}
 
@@ -2133,6 +2122,33 @@ func MarkUsedIfaceMethod(n *ir.CallExpr) {
 	r.Type = objabi.R_USEIFACEMETHOD
 }
 
+// MarkUsedMethodName marks that methods named name may be looked up by
+// reflection in the current function, e.g. through a call of
+// reflect.Value.MethodByName with a constant argument.
+func MarkUsedMethodName(name string) {
+	// skip unnamed functions (func _())
+	if ir.CurFunc.LSym == nil {
+		return
+	}
+	markUsedMethodName(name)
+}
+
+// markUsedMethodName emits a relocation telling the linker that methods
+// named name are used in the current function.
+func markUsedMethodName(name string) {
+	r := obj.Addrel(ir.CurFunc.LSym)
+	// We use a separate symbol just to tell the linker the method name.
+	// (The symbol itself is not needed in the final binary. Do not use
+	// staticdata.StringSym, which creates a content addessable symbol,
+	// which may have trailing zero bytes. This symbol doesn't need to
+	// be deduplicated anyway.)
+	var nameSym obj.LSym
+	nameSym.WriteString(base.Ctxt, 0, len(name), name)
+	objw.Global(&nameSym, int32(len(name)), obj.RODATA)
+	r.Sym = &nameSym
+	r.Type = objabi.R_USENAMEDMETHOD
+}
+
 // getDictionary returns the dictionary for the given named generic function
 // or method, with the given type arguments.
 func getDictionary(gf *types.Sym, targs []*types.Type) ir.Node {
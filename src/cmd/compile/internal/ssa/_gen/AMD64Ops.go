@@ -593,6 +593,80 @@ func init() {
 		// Any use must be preceded by a successful check of runtime.support_fma.
 		{name: "VFMADD231SD", argLength: 3, reg: fp31, resultInArg0: true, asm: "VFMADD231SD"},
 
+		// SIMD vector ops on 128-bit vectors, in X registers. PXOR is above.
+		// Ops marked SSE4.1 must be preceded by a check of runtime.x86HasSSE41.
+		{name: "PADDB", argLength: 2, reg: fp21, asm: "PADDB", commutative: true, resultInArg0: true},     // arg0 + arg1, bytes
+		{name: "PADDW", argLength: 2, reg: fp21, asm: "PADDW", commutative: true, resultInArg0: true},     // arg0 + arg1, words
+		{name: "PADDL", argLength: 2, reg: fp21, asm: "PADDL", commutative: true, resultInArg0: true},     // arg0 + arg1, dwords
+		{name: "PADDQ", argLength: 2, reg: fp21, asm: "PADDQ", commutative: true, resultInArg0: true},     // arg0 + arg1, qwords
+		{name: "ADDPS", argLength: 2, reg: fp21, asm: "ADDPS", commutative: true, resultInArg0: true},     // arg0 + arg1, float32s
+		{name: "ADDPD", argLength: 2, reg: fp21, asm: "ADDPD", commutative: true, resultInArg0: true},     // arg0 + arg1, float64s
+		{name: "PSUBB", argLength: 2, reg: fp21, asm: "PSUBB", resultInArg0: true},                        // arg0 - arg1, bytes
+		{name: "PSUBW", argLength: 2, reg: fp21, asm: "PSUBW", resultInArg0: true},                        // arg0 - arg1, words
+		{name: "PSUBL", argLength: 2, reg: fp21, asm: "PSUBL", resultInArg0: true},                        // arg0 - arg1, dwords
+		{name: "PSUBQ", argLength: 2, reg: fp21, asm: "PSUBQ", resultInArg0: true},                        // arg0 - arg1, qwords
+		{name: "SUBPS", argLength: 2, reg: fp21, asm: "SUBPS", resultInArg0: true},                        // arg0 - arg1, float32s
+		{name: "SUBPD", argLength: 2, reg: fp21, asm: "SUBPD", resultInArg0: true},                        // arg0 - arg1, float64s
+		{name: "PMULLW", argLength: 2, reg: fp21, asm: "PMULLW", commutative: true, resultInArg0: true},   // arg0 * arg1, words, low 16 bits of each product
+		{name: "PMULLD", argLength: 2, reg: fp21, asm: "PMULLD", commutative: true, resultInArg0: true},   // arg0 * arg1, dwords, low 32 bits of each product (SSE4.1)
+		{name: "MULPS", argLength: 2, reg: fp21, asm: "MULPS", commutative: true, resultInArg0: true},     // arg0 * arg1, float32s
+		{name: "MULPD", argLength: 2, reg: fp21, asm: "MULPD", commutative: true, resultInArg0: true},     // arg0 * arg1, float64s
+		{name: "DIVPS", argLength: 2, reg: fp21, asm: "DIVPS", resultInArg0: true},                        // arg0 / arg1, float32s
+		{name: "DIVPD", argLength: 2, reg: fp21, asm: "DIVPD", resultInArg0: true},                        // arg0 / arg1, float64s
+		{name: "PAND", argLength: 2, reg: fp21, asm: "PAND", commutative: true, resultInArg0: true},       // arg0 & arg1
+		{name: "POR", argLength: 2, reg: fp21, asm: "POR", commutative: true, resultInArg0: true},         // arg0 | arg1
+		{name: "PANDN", argLength: 2, reg: fp21, asm: "PANDN", resultInArg0: true},                        // ^arg0 & arg1
+		{name: "PCMPEQB", argLength: 2, reg: fp21, asm: "PCMPEQB", commutative: true, resultInArg0: true}, // arg0 == arg1, bytes, all ones if true
+		{name: "PCMPEQW", argLength: 2, reg: fp21, asm: "PCMPEQW", commutative: true, resultInArg0: true}, // arg0 == arg1, words, all ones if true
+		{name: "PCMPEQL", argLength: 2, reg: fp21, asm: "PCMPEQL", commutative: true, resultInArg0: true}, // arg0 == arg1, dwords, all ones if true
+		{name: "PCMPEQQ", argLength: 2, reg: fp21, asm: "PCMPEQQ", commutative: true, resultInArg0: true}, // arg0 == arg1, qwords, all ones if true (SSE4.1)
+		{name: "PMOVMSKB", argLength: 1, reg: fpgp, asm: "PMOVMSKB"},                                      // bit i is the top bit of byte i of arg0
+
+		// Broadcasts, each a sequence of SSE2 instructions.
+		{name: "LoweredBroadcastB", argLength: 1, reg: gpfp}, // all bytes set to the low bits of arg0
+		{name: "LoweredBroadcastW", argLength: 1, reg: gpfp}, // all words set to the low bits of arg0
+		{name: "LoweredBroadcastL", argLength: 1, reg: gpfp}, // all dwords set to the low bits of arg0
+		{name: "LoweredBroadcastQ", argLength: 1, reg: gpfp}, // all qwords set to the low bits of arg0
+
+		// SIMD vector ops on 256-bit vectors, in the Y registers that extend
+		// the X registers the values are allocated to. They are AVX2
+		// instructions, so any use must be preceded by a check of
+		// runtime.x86HasAVX2.
+		{name: "VPADDB256", argLength: 2, reg: fp21, asm: "VPADDB", commutative: true},     // arg0 + arg1, bytes
+		{name: "VPADDW256", argLength: 2, reg: fp21, asm: "VPADDW", commutative: true},     // arg0 + arg1, words
+		{name: "VPADDD256", argLength: 2, reg: fp21, asm: "VPADDD", commutative: true},     // arg0 + arg1, dwords
+		{name: "VPADDQ256", argLength: 2, reg: fp21, asm: "VPADDQ", commutative: true},     // arg0 + arg1, qwords
+		{name: "VADDPS256", argLength: 2, reg: fp21, asm: "VADDPS", commutative: true},     // arg0 + arg1, float32s
+		{name: "VADDPD256", argLength: 2, reg: fp21, asm: "VADDPD", commutative: true},     // arg0 + arg1, float64s
+		{name: "VPSUBB256", argLength: 2, reg: fp21, asm: "VPSUBB"},                        // arg0 - arg1, bytes
+		{name: "VPSUBW256", argLength: 2, reg: fp21, asm: "VPSUBW"},                        // arg0 - arg1, words
+		{name: "VPSUBD256", argLength: 2, reg: fp21, asm: "VPSUBD"},                        // arg0 - arg1, dwords
+		{name: "VPSUBQ256", argLength: 2, reg: fp21, asm: "VPSUBQ"},                        // arg0 - arg1, qwords
+		{name: "VSUBPS256", argLength: 2, reg: fp21, asm: "VSUBPS"},                        // arg0 - arg1, float32s
+		{name: "VSUBPD256", argLength: 2, reg: fp21, asm: "VSUBPD"},                        // arg0 - arg1, float64s
+		{name: "VPMULLW256", argLength: 2, reg: fp21, asm: "VPMULLW", commutative: true},   // arg0 * arg1, words, low 16 bits of each product
+		{name: "VPMULLD256", argLength: 2, reg: fp21, asm: "VPMULLD", commutative: true},   // arg0 * arg1, dwords, low 32 bits of each product
+		{name: "VMULPS256", argLength: 2, reg: fp21, asm: "VMULPS", commutative: true},     // arg0 * arg1, float32s
+		{name: "VMULPD256", argLength: 2, reg: fp21, asm: "VMULPD", commutative: true},     // arg0 * arg1, float64s
+		{name: "VDIVPS256", argLength: 2, reg: fp21, asm: "VDIVPS"},                        // arg0 / arg1, float32s
+		{name: "VDIVPD256", argLength: 2, reg: fp21, asm: "VDIVPD"},                        // arg0 / arg1, float64s
+		{name: "VPAND256", argLength: 2, reg: fp21, asm: "VPAND", commutative: true},       // arg0 & arg1
+		{name: "VPOR256", argLength: 2, reg: fp21, asm: "VPOR", commutative: true},         // arg0 | arg1
+		{name: "VPXOR256", argLength: 2, reg: fp21, asm: "VPXOR", commutative: true},       // arg0 ^ arg1
+		{name: "VPANDN256", argLength: 2, reg: fp21, asm: "VPANDN"},                        // ^arg0 & arg1
+		{name: "VPCMPEQB256", argLength: 2, reg: fp21, asm: "VPCMPEQB", commutative: true}, // arg0 == arg1, bytes, all ones if true
+		{name: "VPCMPEQW256", argLength: 2, reg: fp21, asm: "VPCMPEQW", commutative: true}, // arg0 == arg1, words, all ones if true
+		{name: "VPCMPEQD256", argLength: 2, reg: fp21, asm: "VPCMPEQD", commutative: true}, // arg0 == arg1, dwords, all ones if true
+		{name: "VPCMPEQQ256", argLength: 2, reg: fp21, asm: "VPCMPEQQ", commutative: true}, // arg0 == arg1, qwords, all ones if true
+		{name: "VPMOVMSKB256", argLength: 1, reg: fpgp, asm: "VPMOVMSKB"},                  // bit i is the top bit of byte i of arg0
+		{name: "LoweredBroadcastB256", argLength: 1, reg: gpfp},                            // all bytes set to the low bits of arg0
+		{name: "LoweredBroadcastW256", argLength: 1, reg: gpfp},                            // all words set to the low bits of arg0
+		{name: "LoweredBroadcastL256", argLength: 1, reg: gpfp},                            // all dwords set to the low bits of arg0
+		{name: "LoweredBroadcastQ256", argLength: 1, reg: gpfp},                            // all qwords set to the low bits of arg0
+
+		{name: "VMOVDQUload256", argLength: 2, reg: fpload, asm: "VMOVDQU", aux: "SymOff", faultOnNilArg0: true, symEffect: "Read"},                // load 32 bytes from arg0+auxint+aux. arg1=mem
+		{name: "VMOVDQUstore256", argLength: 3, reg: fpstore, asm: "VMOVDQU", aux: "SymOff", typ: "Mem", faultOnNilArg0: true, symEffect: "Write"}, // store 32 bytes in arg1 to arg0+auxint+aux. arg2=mem
+
 		{name: "SBBQcarrymask", argLength: 1, reg: flagsgp, asm: "SBBQ"}, // (int64)(-1) if carry is set, 0 if carry is clear.
 		{name: "SBBLcarrymask", argLength: 1, reg: flagsgp, asm: "SBBL"}, // (int32)(-1) if carry is set, 0 if carry is clear.
 		// Note: SBBW and SBBB are subsumed by SBBL
@@ -129,7 +129,8 @@ func init() {
 		gpspg      = gpg | buildReg("SP")
 		gpspsbg    = gpspg | buildReg("SB")
 		fp         = buildReg("F0 F1 F2 F3 F4 F5 F6 F7 F8 F9 F10 F11 F12 F13 F14 F15 F16 F17 F18 F19 F20 F21 F22 F23 F24 F25 F26 F27 F28 F29 F30 F31")
-		callerSave = gp | fp | buildReg("g") // runtime.setg (and anything calling it) may clobber g
+		vec        = buildReg("F0 F1 F2 F3 F4 F5 F6 F7 F8 F9 F10 F11 F12 F13 F14 F15") // asyncPreempt saves only these in full
+		callerSave = gp | fp | buildReg("g")                                           // runtime.setg (and anything calling it) may clobber g
 		r0         = buildReg("R0")
 		r1         = buildReg("R1")
 		r2         = buildReg("R2")
@@ -171,6 +172,10 @@ func init() {
 		fp2load        = regInfo{inputs: []regMask{gpspsbg, gpg}, outputs: []regMask{fp}}
 		fpstore        = regInfo{inputs: []regMask{gpspsbg, fp}}
 		fpstore2       = regInfo{inputs: []regMask{gpspsbg, gpg, fp}}
+		vec21          = regInfo{inputs: []regMask{vec, vec}, outputs: []regMask{vec}}
+		gpvec          = regInfo{inputs: []regMask{gp}, outputs: []regMask{vec}}
+		vecload        = regInfo{inputs: []regMask{gpspsbg}, outputs: []regMask{vec}}
+		vecstore       = regInfo{inputs: []regMask{gpspsbg, vec}}
 		readflags      = regInfo{inputs: nil, outputs: []regMask{gp}}
 		prefreg        = regInfo{inputs: []regMask{gpspsbg}}
 	)
@@ -247,6 +252,28 @@ func init() {
 		{name: "LoweredRound32F", argLength: 1, reg: fp11, resultInArg0: true, zeroWidth: true},
 		{name: "LoweredRound64F", argLength: 1, reg: fp11, resultInArg0: true, zeroWidth: true},
 
+		// SIMD vector ops on 128-bit vectors. The arrangement of the
+		// vector registers is part of the op name.
+		{name: "VADD16B", argLength: 2, reg: vec21, asm: "VADD", commutative: true},   // arg0 + arg1, bytes
+		{name: "VADD8H", argLength: 2, reg: vec21, asm: "VADD", commutative: true},    // arg0 + arg1, halfwords
+		{name: "VADD4S", argLength: 2, reg: vec21, asm: "VADD", commutative: true},    // arg0 + arg1, words
+		{name: "VADD2D", argLength: 2, reg: vec21, asm: "VADD", commutative: true},    // arg0 + arg1, doublewords
+		{name: "VSUB16B", argLength: 2, reg: vec21, asm: "VSUB"},                      // arg0 - arg1, bytes
+		{name: "VSUB8H", argLength: 2, reg: vec21, asm: "VSUB"},                       // arg0 - arg1, halfwords
+		{name: "VSUB4S", argLength: 2, reg: vec21, asm: "VSUB"},                       // arg0 - arg1, words
+		{name: "VSUB2D", argLength: 2, reg: vec21, asm: "VSUB"},                       // arg0 - arg1, doublewords
+		{name: "VCMEQ16B", argLength: 2, reg: vec21, asm: "VCMEQ", commutative: true}, // arg0 == arg1, bytes, all ones if true
+		{name: "VCMEQ8H", argLength: 2, reg: vec21, asm: "VCMEQ", commutative: true},  // arg0 == arg1, halfwords, all ones if true
+		{name: "VCMEQ4S", argLength: 2, reg: vec21, asm: "VCMEQ", commutative: true},  // arg0 == arg1, words, all ones if true
+		{name: "VCMEQ2D", argLength: 2, reg: vec21, asm: "VCMEQ", commutative: true},  // arg0 == arg1, doublewords, all ones if true
+		{name: "VAND16B", argLength: 2, reg: vec21, asm: "VAND", commutative: true},   // arg0 & arg1
+		{name: "VORR16B", argLength: 2, reg: vec21, asm: "VORR", commutative: true},   // arg0 | arg1
+		{name: "VEOR16B", argLength: 2, reg: vec21, asm: "VEOR", commutative: true},   // arg0 ^ arg1
+		{name: "VDUP16B", argLength: 1, reg: gpvec, asm: "VDUP"},                      // all bytes set to the low bits of arg0
+		{name: "VDUP8H", argLength: 1, reg: gpvec, asm: "VDUP"},                       // all halfwords set to the low bits of arg0
+		{name: "VDUP4S", argLength: 1, reg: gpvec, asm: "VDUP"},                       // all words set to the low bits of arg0
+		{name: "VDUP2D", argLength: 1, reg: gpvec, asm: "VDUP"},                       // all doublewords set to the low bits of arg0
+
 		// 3-operand, the addend comes first
 		{name: "FMADDS", argLength: 3, reg: fp31, asm: "FMADDS"},   // +arg0 + (arg1 * arg2)
 		{name: "FMADDD", argLength: 3, reg: fp31, asm: "FMADDD"},   // +arg0 + (arg1 * arg2)
@@ -374,6 +401,7 @@ func init() {
 		{name: "LDP", argLength: 2, reg: gpload2, aux: "SymOff", asm: "LDP", typ: "(UInt64,UInt64)", faultOnNilArg0: true, symEffect: "Read"}, // load from ptr = arg0 + auxInt + aux, returns the tuple <*(*uint64)ptr, *(*uint64)(ptr+8)>. arg1=mem.
 		{name: "FMOVSload", argLength: 2, reg: fpload, aux: "SymOff", asm: "FMOVS", typ: "Float32", faultOnNilArg0: true, symEffect: "Read"},  // load from arg0 + auxInt + aux.  arg1=mem.
 		{name: "FMOVDload", argLength: 2, reg: fpload, aux: "SymOff", asm: "FMOVD", typ: "Float64", faultOnNilArg0: true, symEffect: "Read"},  // load from arg0 + auxInt + aux.  arg1=mem.
+		{name: "FMOVQload", argLength: 2, reg: vecload, aux: "SymOff", asm: "FMOVQ", faultOnNilArg0: true, symEffect: "Read"},                 // load 16 bytes from arg0 + auxInt + aux.  arg1=mem.
 
 		// register indexed load
 		{name: "MOVDloadidx", argLength: 3, reg: gp2load, asm: "MOVD", typ: "UInt64"},    // load 64-bit dword from arg0 + arg1, arg2 = mem.
@@ -395,13 +423,14 @@ func init() {
 		{name: "FMOVSloadidx4", argLength: 3, reg: fp2load, asm: "FMOVS", typ: "Float32"}, // load 32-bit float from arg0 + arg1*4, arg2 = mem.
 		{name: "FMOVDloadidx8", argLength: 3, reg: fp2load, asm: "FMOVD", typ: "Float64"}, // load 64-bit float from arg0 + arg1*8, arg2 = mem.
 
-		{name: "MOVBstore", argLength: 3, reg: gpstore, aux: "SymOff", asm: "MOVB", typ: "Mem", faultOnNilArg0: true, symEffect: "Write"},   // store 1 byte of arg1 to arg0 + auxInt + aux.  arg2=mem.
-		{name: "MOVHstore", argLength: 3, reg: gpstore, aux: "SymOff", asm: "MOVH", typ: "Mem", faultOnNilArg0: true, symEffect: "Write"},   // store 2 bytes of arg1 to arg0 + auxInt + aux.  arg2=mem.
-		{name: "MOVWstore", argLength: 3, reg: gpstore, aux: "SymOff", asm: "MOVW", typ: "Mem", faultOnNilArg0: true, symEffect: "Write"},   // store 4 bytes of arg1 to arg0 + auxInt + aux.  arg2=mem.
-		{name: "MOVDstore", argLength: 3, reg: gpstore, aux: "SymOff", asm: "MOVD", typ: "Mem", faultOnNilArg0: true, symEffect: "Write"},   // store 8 bytes of arg1 to arg0 + auxInt + aux.  arg2=mem.
-		{name: "STP", argLength: 4, reg: gpstore2, aux: "SymOff", asm: "STP", typ: "Mem", faultOnNilArg0: true, symEffect: "Write"},         // store 16 bytes of arg1 and arg2 to arg0 + auxInt + aux.  arg3=mem.
-		{name: "FMOVSstore", argLength: 3, reg: fpstore, aux: "SymOff", asm: "FMOVS", typ: "Mem", faultOnNilArg0: true, symEffect: "Write"}, // store 4 bytes of arg1 to arg0 + auxInt + aux.  arg2=mem.
-		{name: "FMOVDstore", argLength: 3, reg: fpstore, aux: "SymOff", asm: "FMOVD", typ: "Mem", faultOnNilArg0: true, symEffect: "Write"}, // store 8 bytes of arg1 to arg0 + auxInt + aux.  arg2=mem.
+		{name: "MOVBstore", argLength: 3, reg: gpstore, aux: "SymOff", asm: "MOVB", typ: "Mem", faultOnNilArg0: true, symEffect: "Write"},    // store 1 byte of arg1 to arg0 + auxInt + aux.  arg2=mem.
+		{name: "MOVHstore", argLength: 3, reg: gpstore, aux: "SymOff", asm: "MOVH", typ: "Mem", faultOnNilArg0: true, symEffect: "Write"},    // store 2 bytes of arg1 to arg0 + auxInt + aux.  arg2=mem.
+		{name: "MOVWstore", argLength: 3, reg: gpstore, aux: "SymOff", asm: "MOVW", typ: "Mem", faultOnNilArg0: true, symEffect: "Write"},    // store 4 bytes of arg1 to arg0 + auxInt + aux.  arg2=mem.
+		{name: "MOVDstore", argLength: 3, reg: gpstore, aux: "SymOff", asm: "MOVD", typ: "Mem", faultOnNilArg0: true, symEffect: "Write"},    // store 8 bytes of arg1 to arg0 + auxInt + aux.  arg2=mem.
+		{name: "STP", argLength: 4, reg: gpstore2, aux: "SymOff", asm: "STP", typ: "Mem", faultOnNilArg0: true, symEffect: "Write"},          // store 16 bytes of arg1 and arg2 to arg0 + auxInt + aux.  arg3=mem.
+		{name: "FMOVSstore", argLength: 3, reg: fpstore, aux: "SymOff", asm: "FMOVS", typ: "Mem", faultOnNilArg0: true, symEffect: "Write"},  // store 4 bytes of arg1 to arg0 + auxInt + aux.  arg2=mem.
+		{name: "FMOVDstore", argLength: 3, reg: fpstore, aux: "SymOff", asm: "FMOVD", typ: "Mem", faultOnNilArg0: true, symEffect: "Write"},  // store 8 bytes of arg1 to arg0 + auxInt + aux.  arg2=mem.
+		{name: "FMOVQstore", argLength: 3, reg: vecstore, aux: "SymOff", asm: "FMOVQ", typ: "Mem", faultOnNilArg0: true, symEffect: "Write"}, // store 16 bytes of arg1 to arg0 + auxInt + aux.  arg2=mem.
 
 		// register indexed store
 		{name: "MOVBstoreidx", argLength: 4, reg: gpstore2, asm: "MOVB", typ: "Mem"},   // store 1 byte of arg2 to arg0 + arg1, arg3 = mem.
@@ -788,6 +817,7 @@ func init() {
 		ParamFloatRegNames: "F0 F1 F2 F3 F4 F5 F6 F7 F8 F9 F10 F11 F12 F13 F14 F15",
 		gpregmask:          gp,
 		fpregmask:          fp,
+		vecregmask:         vec,
 		framepointerreg:    -1, // not used
 		linkreg:            int8(num["R30"]),
 	})
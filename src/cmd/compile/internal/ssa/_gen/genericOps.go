@@ -238,6 +238,7 @@ var genericOps = []opData{
 	{name: "BitLen32", argLength: 1},     // Number of bits in arg[0] (returns 0-32)
 	{name: "BitLen64", argLength: 1},     // Number of bits in arg[0] (returns 0-64)
 
+	{name: "Bswap16", argLength: 1}, // Swap bytes
 	{name: "Bswap32", argLength: 1}, // Swap bytes
 	{name: "Bswap64", argLength: 1}, // Swap bytes
 
@@ -623,6 +624,42 @@ var genericOps = []opData{
 	// Publication barrier
 	{name: "PubBarrier", argLength: 1, hasSideEffects: true}, // Do data barrier. arg0=memory.
 
+	// SIMD vector ops, on values of types vec128 and vec256. The lane
+	// type is part of the op name, the width of the vector is the size
+	// of the result type. Integer ops don't depend on the signedness of
+	// the lanes.
+	{name: "VecAddInt8", argLength: 2, commutative: true},    // arg0 + arg1, lane-wise
+	{name: "VecAddInt16", argLength: 2, commutative: true},   // arg0 + arg1, lane-wise
+	{name: "VecAddInt32", argLength: 2, commutative: true},   // arg0 + arg1, lane-wise
+	{name: "VecAddInt64", argLength: 2, commutative: true},   // arg0 + arg1, lane-wise
+	{name: "VecAddFloat32", argLength: 2, commutative: true}, // arg0 + arg1, lane-wise
+	{name: "VecAddFloat64", argLength: 2, commutative: true}, // arg0 + arg1, lane-wise
+	{name: "VecSubInt8", argLength: 2},                       // arg0 - arg1, lane-wise
+	{name: "VecSubInt16", argLength: 2},                      // arg0 - arg1, lane-wise
+	{name: "VecSubInt32", argLength: 2},                      // arg0 - arg1, lane-wise
+	{name: "VecSubInt64", argLength: 2},                      // arg0 - arg1, lane-wise
+	{name: "VecSubFloat32", argLength: 2},                    // arg0 - arg1, lane-wise
+	{name: "VecSubFloat64", argLength: 2},                    // arg0 - arg1, lane-wise
+	{name: "VecMulInt16", argLength: 2, commutative: true},   // arg0 * arg1, lane-wise
+	{name: "VecMulInt32", argLength: 2, commutative: true},   // arg0 * arg1, lane-wise
+	{name: "VecMulFloat32", argLength: 2, commutative: true}, // arg0 * arg1, lane-wise
+	{name: "VecMulFloat64", argLength: 2, commutative: true}, // arg0 * arg1, lane-wise
+	{name: "VecDivFloat32", argLength: 2},                    // arg0 / arg1, lane-wise
+	{name: "VecDivFloat64", argLength: 2},                    // arg0 / arg1, lane-wise
+	{name: "VecAnd", argLength: 2, commutative: true},        // arg0 & arg1
+	{name: "VecOr", argLength: 2, commutative: true},         // arg0 | arg1
+	{name: "VecXor", argLength: 2, commutative: true},        // arg0 ^ arg1
+	{name: "VecAndNot", argLength: 2},                        // arg0 &^ arg1
+	{name: "VecEqInt8", argLength: 2, commutative: true},     // lanes of all ones where the lanes of arg0 and arg1 are equal, zero elsewhere
+	{name: "VecEqInt16", argLength: 2, commutative: true},    // lanes of all ones where the lanes of arg0 and arg1 are equal, zero elsewhere
+	{name: "VecEqInt32", argLength: 2, commutative: true},    // lanes of all ones where the lanes of arg0 and arg1 are equal, zero elsewhere
+	{name: "VecEqInt64", argLength: 2, commutative: true},    // lanes of all ones where the lanes of arg0 and arg1 are equal, zero elsewhere
+	{name: "VecBroadcastInt8", argLength: 1},                 // all lanes set to the low bits of arg0
+	{name: "VecBroadcastInt16", argLength: 1},                // all lanes set to the low bits of arg0
+	{name: "VecBroadcastInt32", argLength: 1},                // all lanes set to the low bits of arg0
+	{name: "VecBroadcastInt64", argLength: 1},                // all lanes set to the low bits of arg0
+	{name: "VecSignMaskInt8", argLength: 1},                  // bit i is the top bit of byte lane i of arg0
+
 	// Clobber experiment op
 	{name: "Clobber", argLength: 0, typ: "Void", aux: "SymOff", symEffect: "None"}, // write an invalid pointer value to the given pointer slot of a stack variable
 	{name: "ClobberReg", argLength: 0, typ: "Void"},                                // clobber a register
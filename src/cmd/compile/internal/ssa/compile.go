@@ -471,6 +471,8 @@ var passes = [...]pass{
 	{name: "gcse deadcode", fn: deadcode, required: true}, // clean out after cse and phiopt
 	{name: "nilcheckelim", fn: nilcheckelim},
 	{name: "prove", fn: prove},
+	{name: "unroll", fn: unroll}, // fully unroll small constant trip count loops
+	{name: "licm", fn: licm},     // hoist loop-invariant values out of loops
 	{name: "early fuse", fn: fuseEarly},
 	{name: "decompose builtin", fn: decomposeBuiltIn, required: true},
 	{name: "expand calls", fn: expandCalls, required: true},
@@ -481,6 +483,7 @@ var passes = [...]pass{
 	{name: "check bce", fn: checkbce},
 	{name: "branchelim", fn: branchelim},
 	{name: "late fuse", fn: fuseLate},
+	{name: "memcombine", fn: memcombine},
 	{name: "dse", fn: dse},
 	{name: "writebarrier", fn: writebarrier, required: true}, // expand write barrier ops
 	{name: "insert resched checks", fn: insertLoopReschedChecks,
@@ -501,6 +504,7 @@ var passes = [...]pass{
 	{name: "critical", fn: critical, required: true}, // remove critical edges
 	{name: "phi tighten", fn: phiTighten},            // place rematerializable phi args near uses to reduce value lifetimes
 	{name: "likelyadjust", fn: likelyadjust},
+	{name: "pgo likely", fn: pgoLikely},
 	{name: "layout", fn: layout, required: true},     // schedule blocks
 	{name: "schedule", fn: schedule, required: true}, // schedule values
 	{name: "late nilcheck", fn: nilcheckelim2},
@@ -530,6 +534,11 @@ var passOrder = [...]constraint{
 	{"generic cse", "prove"},
 	// deadcode after prove to eliminate all new dead blocks.
 	{"prove", "generic deadcode"},
+	// unroll does not copy loops whose body exits, so bounds checks
+	// must be removed by prove first.
+	{"prove", "unroll"},
+	// licm works on the loops left by unroll.
+	{"unroll", "licm"},
 	// common-subexpression before dead-store elim, so that we recognize
 	// when two address expressions are the same.
 	{"generic cse", "dse"},
@@ -539,6 +548,10 @@ var passOrder = [...]constraint{
 	{"nilcheckelim", "generic deadcode"},
 	// nilcheckelim generates sequences of plain basic blocks
 	{"nilcheckelim", "late fuse"},
+	// memcombine works better if fuse happens first, to help merge stores.
+	{"late fuse", "memcombine"},
+	// memcombine is an arch-independent pass.
+	{"memcombine", "lower"},
 	// nilcheckelim relies on opt to rewrite user nil checks
 	{"opt", "nilcheckelim"},
 	// tighten will be most effective when as many values have been removed as possible
@@ -556,6 +569,8 @@ var passOrder = [...]constraint{
 	{"critical", "phi tighten"},
 	// don't layout blocks until critical edges have been removed
 	{"critical", "layout"},
+	// profile-guided predictions override static ones
+	{"likelyadjust", "pgo likely"},
 	// regalloc requires the removal of all critical edges
 	{"critical", "regalloc"},
 	// regalloc requires all the values in a block to be scheduled
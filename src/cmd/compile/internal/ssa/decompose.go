@@ -97,8 +97,8 @@ func decomposeBuiltIn(f *Func) {
 				f.NamedValues[*dataName] = append(f.NamedValues[*dataName], v.Args[1])
 				toDelete = append(toDelete, namedVal{i, j})
 			}
-		case t.IsFloat():
-			// floats are never decomposed, even ones bigger than RegSize
+		case t.IsFloat(), t.IsSIMD():
+			// floats and vectors are never decomposed, even ones bigger than RegSize
 		case t.Size() > f.Config.RegSize:
 			f.Fatalf("undecomposed named type %s %v", name, t)
 		}
@@ -132,8 +132,8 @@ func decomposeBuiltInPhi(v *Value) {
 		decomposeSlicePhi(v)
 	case v.Type.IsInterface():
 		decomposeInterfacePhi(v)
-	case v.Type.IsFloat():
-		// floats are never decomposed, even ones bigger than RegSize
+	case v.Type.IsFloat(), v.Type.IsSIMD():
+		// floats and vectors are never decomposed, even ones bigger than RegSize
 	case v.Type.Size() > v.Block.Func.Config.RegSize:
 		v.Fatalf("%v undecomposed type %v", v, v.Type)
 	}
@@ -1320,6 +1320,9 @@ func expandCalls(f *Func) {
 				t := v.Aux.(*types.Type)
 				source := v.Args[1]
 				tSrc := source.Type
+				if tSrc.IsSIMD() {
+					continue // a whole vector of package simd, stored as is
+				}
 				iAEATt := x.isAlreadyExpandedAggregateType(t)
 
 				if !iAEATt {
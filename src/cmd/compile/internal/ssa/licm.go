@@ -0,0 +1,231 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"cmd/internal/src"
+	"sort"
+)
+
+// licm hoists loop-invariant values out of loops, into the block that
+// precedes the loop header. A value is loop-invariant if all of its
+// arguments, including its memory argument if any, are defined outside
+// the loop. Only values that are safe to execute when the loop body
+// would not have executed them are hoisted:
+//   - pure values, that is, values without side effects that cannot fault,
+//   - loads that cannot fault: from global or stack addresses, or through
+//     pointers that are nil checked before the loop,
+//   - nil checks in the loop header, which runs whenever the block before
+//     the loop does.
+//
+// Loads are only hoisted from loops that do not modify memory, as any
+// store or call in the loop gives the loop header a memory phi.
+//
+// Values in blocks that only run if the CPU has some feature, such as
+// FMA, are not hoisted out of the code checking for the feature.
+func licm(f *Func) {
+	loops := f.loopnest()
+	if loops.hasIrreducible || len(loops.loops) == 0 {
+		return
+	}
+	loops.calculateDepths()
+	sdom := f.Sdom()
+
+	// Nil checks of each pointer, by pointer ID.
+	nilChecks := map[ID][]*Value{}
+	for _, b := range f.Blocks {
+		for _, v := range b.Values {
+			if v.Op == OpNilCheck {
+				nilChecks[v.Args[0].ID] = append(nilChecks[v.Args[0].ID], v)
+			}
+		}
+	}
+
+	// Entries of the code that only runs if the CPU has some feature.
+	var guarded []*Block
+	for _, b := range f.Blocks {
+		if b.Kind == BlockIf && b.Controls[0].Op == OpHasCPUFeature {
+			guarded = append(guarded, b.Succs[0].b)
+		}
+	}
+
+	// Visit inner loops first, so that values hoisted out of a loop
+	// can then be hoisted out of the loops that contain it.
+	order := append([]*loop(nil), loops.loops...)
+	sort.SliceStable(order, func(i, j int) bool { return order[i].depth > order[j].depth })
+
+	var blocks []*Block
+	for _, l := range order {
+		inLoop := func(b *Block) bool { return loops.b2l[b.ID].isWithinOrEq(l) }
+
+		pre := l.preheader(inLoop)
+		if pre == nil {
+			continue
+		}
+		blocks = blocks[:0]
+		for _, b := range f.Blocks {
+			if inLoop(b) && !cpuFeatureGuarded(b, pre, guarded, sdom) {
+				blocks = append(blocks, b)
+			}
+		}
+
+		// nonFaulting reports whether ptr is not nil in pre, so that
+		// loads from it cannot fault there.
+		nonFaulting := func(ptr *Value) bool {
+			for ptr.Op == OpOffPtr {
+				ptr = ptr.Args[0]
+			}
+			switch ptr.Op {
+			case OpAddr, OpLocalAddr, OpSP, OpSB:
+				return true
+			}
+			for _, nc := range nilChecks[ptr.ID] {
+				if sdom.IsAncestorEq(nc.Block, pre) {
+					return true
+				}
+			}
+			return false
+		}
+
+		for changed := true; changed; {
+			changed = false
+			for _, b := range blocks {
+				for i := 0; i < len(b.Values); i++ {
+					v := b.Values[i]
+					if !isLoopInvariant(v, inLoop) {
+						continue
+					}
+					switch {
+					case v.Op == OpNilCheck:
+						if b != l.header {
+							continue
+						}
+					case v.Op == OpLoad:
+						if !nonFaulting(v.Args[0]) {
+							continue
+						}
+					case v.Op == OpOffPtr:
+						// Offsetting a nil pointer makes a bad pointer,
+						// which the stack copier rejects.
+						if !nonFaulting(v) {
+							continue
+						}
+					case !isHoistablePure(v):
+						continue
+					}
+					if f.pass.debug > 0 {
+						f.Warnl(v.Pos, "Hoisted %v", v.Op)
+					}
+					last := len(b.Values) - 1
+					b.Values[i] = b.Values[last]
+					b.Values[last] = nil
+					b.Values = b.Values[:last]
+					i--
+					if v.Pos.IsStmt() == src.PosIsStmt && keepStmtMark(v, b) {
+						v.Pos = v.Pos.WithNotStmt()
+					}
+					v.Block = pre
+					pre.Values = append(pre.Values, v)
+					changed = true
+				}
+			}
+		}
+	}
+}
+
+// keepStmtMark moves the statement mark of v, which is leaving b, to
+// another value of b on the same line, or to b itself. It reports
+// whether it found a place for the mark.
+func keepStmtMark(v *Value, b *Block) bool {
+	for _, w := range b.Values {
+		if w != v && w.Pos.IsStmt() != src.PosNotStmt && !isPoorStatementOp(w.Op) && w.Pos.SameFileAndLine(v.Pos) {
+			w.Pos = w.Pos.WithIsStmt()
+			return true
+		}
+	}
+	if b.Pos.IsStmt() != src.PosNotStmt && b.Pos.SameFileAndLine(v.Pos) {
+		b.Pos = b.Pos.WithIsStmt()
+		return true
+	}
+	return false
+}
+
+// preheader returns the single predecessor of the header of l outside
+// of l, if it exists and l's header is its only successor. inLoop
+// reports whether a block is in l.
+func (l *loop) preheader(inLoop func(*Block) bool) *Block {
+	var pre *Block
+	for _, e := range l.header.Preds {
+		if inLoop(e.b) {
+			continue
+		}
+		if pre != nil {
+			return nil
+		}
+		pre = e.b
+	}
+	if pre == nil || pre.Kind != BlockPlain {
+		return nil
+	}
+	return pre
+}
+
+// cpuFeatureGuarded reports whether b only runs if the CPU has some
+// feature that pre may run without, so that the instructions in b, which
+// may need the feature, cannot be moved to pre. guarded holds the true
+// successors of the blocks checking for CPU features, and every block
+// they dominate is treated as guarded by the check.
+func cpuFeatureGuarded(b, pre *Block, guarded []*Block, sdom SparseTree) bool {
+	for _, g := range guarded {
+		if sdom.IsAncestorEq(g, b) && !sdom.IsAncestorEq(g, pre) {
+			return true
+		}
+	}
+	return false
+}
+
+// isLoopInvariant reports whether v is a candidate for hoisting out of
+// the loop whose blocks are reported by inLoop: v is not a phi, and all
+// of its arguments are defined outside of the loop.
+func isLoopInvariant(v *Value, inLoop func(*Block) bool) bool {
+	if v.Op == OpPhi || len(v.Args) == 0 {
+		// Values without arguments, like constants, are cheap to
+		// rematerialize and are best left near their uses.
+		return false
+	}
+	for _, a := range v.Args {
+		if inLoop(a.Block) {
+			return false
+		}
+	}
+	return true
+}
+
+// isHoistablePure reports whether v computes its result from its
+// arguments only, without side effects and without faulting, so that
+// it can be executed whether or not the program would have executed it.
+func isHoistablePure(v *Value) bool {
+	if v.Type.IsMemory() || v.MemoryArg() != nil {
+		return v.Op == OpLocalAddr // the memory argument only orders it after its VarDef
+	}
+	info := &opcodeTable[v.Op]
+	if info.call || info.hasSideEffects || info.nilCheck || info.faultOnNilArg0 || info.faultOnNilArg1 {
+		return false
+	}
+	switch v.Op {
+	case OpDiv8, OpDiv8u, OpDiv16, OpDiv16u, OpDiv32, OpDiv32u, OpDiv64, OpDiv64u, OpDiv128u,
+		OpMod8, OpMod8u, OpMod16, OpMod16u, OpMod32, OpMod32u, OpMod64, OpMod64u:
+		// Integer division by zero faults. The check is a branch
+		// that the division must stay behind.
+		return false
+	case OpAddPtr, OpPtrIndex:
+		// The index may be out of bounds until checked, and the garbage
+		// collector must never see a pointer past the end of an object.
+		return false
+	case OpCopy, OpFwdRef, OpSelectN:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,185 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"cmd/compile/internal/types"
+	"testing"
+)
+
+func TestLICM(t *testing.T) {
+	c := testConfig(t)
+	intType := c.config.Types.Int64
+	ptrType := c.config.Types.Int64.PtrTo()
+	fun := c.Fun("entry",
+		Bloc("entry",
+			Valu("mem", OpInitMem, types.TypeMem, 0, nil),
+			Valu("sb", OpSB, c.config.Types.Uintptr, 0, nil),
+			Valu("zero", OpConst64, intType, 0, nil),
+			Valu("one", OpConst64, intType, 1, nil),
+			Valu("n", OpConst64, intType, 10, nil),
+			Valu("global", OpAddr, ptrType, 0, nil, "sb"),
+			Valu("p", OpLoad, ptrType, 0, nil, "global", "mem"),
+			Valu("x", OpLoad, intType, 0, nil, "sb", "mem"),
+			Goto("header")),
+		Bloc("header",
+			Valu("i", OpPhi, intType, 0, nil, "zero", "inc"),
+			Valu("check", OpNilCheck, types.TypeVoid, 0, nil, "p", "mem"),
+			Valu("cmp", OpLess64, c.config.Types.Bool, 0, nil, "i", "n"),
+			If("cmp", "body", "exit")),
+		Bloc("body",
+			Valu("xx", OpMul64, intType, 0, nil, "x", "x"),
+			Valu("field", OpOffPtr, ptrType, 8, nil, "p"),
+			Valu("load", OpLoad, intType, 0, nil, "field", "mem"),
+			Valu("div", OpDiv64, intType, 0, nil, "xx", "load"),
+			Valu("elem", OpAddPtr, ptrType, 0, nil, "p", "load"),
+			Valu("inc", OpAdd64, intType, 0, nil, "i", "one"),
+			Goto("header")),
+		Bloc("exit",
+			Exit("mem")))
+
+	CheckFunc(fun.f)
+	licm(fun.f)
+	CheckFunc(fun.f)
+
+	for _, name := range []string{"check", "xx", "field", "load"} {
+		if b := fun.values[name].Block; b != fun.blocks["entry"] {
+			t.Errorf("%s is in %s, want it hoisted to entry", name, b)
+		}
+	}
+	for _, name := range []string{"div", "elem", "inc"} {
+		if b := fun.values[name].Block; b != fun.blocks["body"] {
+			t.Errorf("%s is in %s, want it left in body", name, b)
+		}
+	}
+	if b := fun.values["cmp"].Block; b != fun.blocks["header"] {
+		t.Errorf("cmp is in %s, want it left in header", b)
+	}
+}
+
+func TestLICMStores(t *testing.T) {
+	c := testConfig(t)
+	intType := c.config.Types.Int64
+	ptrType := c.config.Types.Int64.PtrTo()
+	fun := c.Fun("entry",
+		Bloc("entry",
+			Valu("mem", OpInitMem, types.TypeMem, 0, nil),
+			Valu("sb", OpSB, c.config.Types.Uintptr, 0, nil),
+			Valu("zero", OpConst64, intType, 0, nil),
+			Valu("one", OpConst64, intType, 1, nil),
+			Valu("n", OpConst64, intType, 10, nil),
+			Valu("global", OpAddr, ptrType, 0, nil, "sb"),
+			Goto("header")),
+		Bloc("header",
+			Valu("i", OpPhi, intType, 0, nil, "zero", "inc"),
+			Valu("m", OpPhi, types.TypeMem, 0, nil, "mem", "store"),
+			Valu("cmp", OpLess64, c.config.Types.Bool, 0, nil, "i", "n"),
+			If("cmp", "body", "exit")),
+		Bloc("body",
+			// The load reads the memory stored by the previous iteration.
+			Valu("load", OpLoad, intType, 0, nil, "global", "m"),
+			Valu("sum", OpAdd64, intType, 0, nil, "load", "one"),
+			Valu("store", OpStore, types.TypeMem, 0, intType, "global", "sum", "m"),
+			Valu("inc", OpAdd64, intType, 0, nil, "i", "one"),
+			Goto("header")),
+		Bloc("exit",
+			Exit("m")))
+
+	CheckFunc(fun.f)
+	licm(fun.f)
+	CheckFunc(fun.f)
+
+	for _, name := range []string{"load", "sum", "store"} {
+		if b := fun.values[name].Block; b != fun.blocks["body"] {
+			t.Errorf("%s is in %s, want it left in body", name, b)
+		}
+	}
+}
+
+func TestLICMNilCheckInBody(t *testing.T) {
+	c := testConfig(t)
+	intType := c.config.Types.Int64
+	ptrType := c.config.Types.Int64.PtrTo()
+	fun := c.Fun("entry",
+		Bloc("entry",
+			Valu("mem", OpInitMem, types.TypeMem, 0, nil),
+			Valu("sb", OpSB, c.config.Types.Uintptr, 0, nil),
+			Valu("zero", OpConst64, intType, 0, nil),
+			Valu("one", OpConst64, intType, 1, nil),
+			Valu("n", OpConst64, intType, 10, nil),
+			Valu("global", OpAddr, ptrType, 0, nil, "sb"),
+			Valu("p", OpLoad, ptrType, 0, nil, "global", "mem"),
+			Goto("header")),
+		Bloc("header",
+			Valu("i", OpPhi, intType, 0, nil, "zero", "inc"),
+			Valu("cmp", OpLess64, c.config.Types.Bool, 0, nil, "i", "n"),
+			If("cmp", "body", "exit")),
+		Bloc("body",
+			// The body may not run, so neither the nil check nor the
+			// load it guards may be hoisted.
+			Valu("check", OpNilCheck, types.TypeVoid, 0, nil, "p", "mem"),
+			Valu("load", OpLoad, intType, 0, nil, "p", "mem"),
+			Valu("inc", OpAdd64, intType, 0, nil, "i", "one"),
+			Goto("header")),
+		Bloc("exit",
+			Exit("mem")))
+
+	CheckFunc(fun.f)
+	licm(fun.f)
+	CheckFunc(fun.f)
+
+	for _, name := range []string{"check", "load"} {
+		if b := fun.values[name].Block; b != fun.blocks["body"] {
+			t.Errorf("%s is in %s, want it left in body", name, b)
+		}
+	}
+}
+
+func TestLICMCPUFeature(t *testing.T) {
+	c := testConfig(t)
+	intType := c.config.Types.Int64
+	floatType := c.config.Types.Float64
+	fun := c.Fun("entry",
+		Bloc("entry",
+			Valu("mem", OpInitMem, types.TypeMem, 0, nil),
+			Valu("sb", OpSB, c.config.Types.Uintptr, 0, nil),
+			Valu("zero", OpConst64, intType, 0, nil),
+			Valu("one", OpConst64, intType, 1, nil),
+			Valu("n", OpConst64, intType, 10, nil),
+			Valu("x", OpLoad, floatType, 0, nil, "sb", "mem"),
+			Goto("header")),
+		Bloc("header",
+			Valu("i", OpPhi, intType, 0, nil, "zero", "inc"),
+			Valu("cmp", OpLess64, c.config.Types.Bool, 0, nil, "i", "n"),
+			If("cmp", "body", "exit")),
+		Bloc("body",
+			Valu("hasFMA", OpHasCPUFeature, c.config.Types.Bool, 0, nil),
+			If("hasFMA", "fma", "nofma")),
+		Bloc("fma",
+			Goto("fma2")),
+		Bloc("fma2",
+			// fma2 only runs after the check, though not right after it.
+			Valu("fmax", OpFMA, floatType, 0, nil, "x", "x", "x"),
+			Goto("latch")),
+		Bloc("nofma",
+			Valu("mul", OpMul64F, floatType, 0, nil, "x", "x"),
+			Goto("latch")),
+		Bloc("latch",
+			Valu("inc", OpAdd64, intType, 0, nil, "i", "one"),
+			Goto("header")),
+		Bloc("exit",
+			Exit("mem")))
+
+	CheckFunc(fun.f)
+	licm(fun.f)
+	CheckFunc(fun.f)
+
+	if b := fun.values["fmax"].Block; b != fun.blocks["fma2"] {
+		t.Errorf("fmax is in %s, want it left in fma2", b)
+	}
+	if b := fun.values["mul"].Block; b != fun.blocks["entry"] {
+		t.Errorf("mul is in %s, want it hoisted to entry", b)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa_test
+
+import (
+	"internal/testenv"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+// TestLoopOpt checks that the licm and unroll passes transform the loops
+// of testdata/loopopt.go, and that the program still behaves.
+func TestLoopOpt(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	source := filepath.Join("testdata", "loopopt.go")
+	output := filepath.Join(t.TempDir(), "loopopt.exe")
+	cmd := testenv.Command(t, testenv.GoToolPath(t), "build", "-o", output, "-gcflags=-d=ssa/licm/debug=1,ssa/unroll/debug=1", source)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("build failed: %v\n%s", err, out)
+	}
+
+	want := []string{
+		`loopopt.go:15:\d+: Hoisted Load`,
+		`loopopt.go:22:\d+: Hoisted NilCheck`,
+		`loopopt.go:22:\d+: Hoisted Load`,
+		`loopopt.go:31:\d+: Hoisted Mul64`,
+		`loopopt.go:45:\d+: Unrolled loop 4 times`,
+		`loopopt.go:53:\d+: Unrolled loop 3 times`,
+	}
+	for _, re := range want {
+		if !regexp.MustCompile(re).Match(out) {
+			t.Errorf("missing %q in output", re)
+		}
+	}
+	if regexp.MustCompile(`loopopt.go:38:\d+: Hoisted Load`).Match(out) {
+		t.Errorf("the load in sumN was hoisted, but it may fault")
+	}
+	if t.Failed() {
+		t.Logf("output:\n%s", out)
+	}
+
+	cmd = testenv.Command(t, output)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("%s failed: %v\n%s", output, err, out)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"cmd/compile/internal/base"
+	"cmd/internal/src"
+)
+
+// PGOInfo is the profile information of a function, used to order
+// blocks, align hot loops and place spills.
+type PGOInfo struct {
+	// Lines maps lines of the function to their CPU sample weight.
+	// A value is on the line of its outermost position, that is, code
+	// inlined into the function is on the line of the call.
+	Lines map[uint]int64
+
+	// HotWeight is the weight at and above which a line is hot.
+	HotWeight int64
+}
+
+// pgoBlockWeights returns the profile weight of each block of f, indexed
+// by block ID: the weight of the hottest line among its values. Blocks
+// without any positioned value have weight -1. It returns nil if f has
+// no profile information.
+func (f *Func) pgoBlockWeights() []int64 {
+	if f.PGO == nil {
+		return nil
+	}
+	weights := make([]int64, f.NumBlocks())
+	for _, b := range f.Blocks {
+		weights[b.ID] = -1
+		f.pgoWeighPos(&weights[b.ID], b.Pos)
+		for _, v := range b.Values {
+			f.pgoWeighPos(&weights[b.ID], v.Pos)
+		}
+	}
+	return weights
+}
+
+// pgoWeighPos raises *w to the weight of the line of pos.
+func (f *Func) pgoWeighPos(w *int64, pos src.XPos) {
+	if !pos.IsKnown() {
+		return
+	}
+	line := base.Ctxt.OutermostPos(pos).RelLine()
+	if line == 0 {
+		return
+	}
+	if lw := f.PGO.Lines[line]; lw > *w {
+		*w = lw
+	} else if *w < 0 {
+		*w = 0
+	}
+}
+
+// pgoSuccWeight returns the weight of the successor s, looking through
+// blocks without positioned values that jump unconditionally.
+func pgoSuccWeight(weights []int64, s *Block) int64 {
+	for i := 0; i < 4 && weights[s.ID] < 0 && len(s.Succs) == 1; i++ {
+		s = s.Succs[0].b
+	}
+	return weights[s.ID]
+}
+
+// pgoLikely sets the likely direction of branches to the successor that
+// is hotter in the profile, overriding the static predictions of
+// likelyadjust and of the frontend. Branches between successors of
+// similar weight are left alone.
+func pgoLikely(f *Func) {
+	weights := f.pgoBlockWeights()
+	if weights == nil {
+		return
+	}
+	for _, b := range f.Blocks {
+		if len(b.Succs) != 2 {
+			continue
+		}
+		w0 := pgoSuccWeight(weights, b.Succs[0].b)
+		w1 := pgoSuccWeight(weights, b.Succs[1].b)
+		if w0 < 0 || w1 < 0 {
+			continue
+		}
+		var prediction BranchPrediction
+		switch {
+		case w0 > 2*w1:
+			prediction = BranchLikely
+		case w1 > 2*w0:
+			prediction = BranchUnlikely
+		default:
+			continue
+		}
+		if base.Debug.PGODebug > 0 {
+			hot, hw, cw := b.Succs[0].b, w0, w1
+			if prediction == BranchUnlikely {
+				hot, hw, cw = b.Succs[1].b, w1, w0
+			}
+			overrides := ""
+			if b.Likely == -prediction {
+				overrides = ", overriding static prediction"
+			}
+			f.Warnl(b.Pos, "PGO: %v: %v likely (weight %d vs %d)%s", b, hot, hw, cw, overrides)
+		}
+		b.Likely = prediction
+	}
+}
+
+// HotLoopStarts returns the blocks of f, which must be laid out, that
+// start a hot loop: the blocks that are the target of a backward branch
+// and such that the hottest block between them and the branch is hot.
+// These are the blocks worth aligning. It returns nil if f has no
+// profile information.
+func (f *Func) HotLoopStarts() []*Block {
+	weights := f.pgoBlockWeights()
+	if weights == nil {
+		return nil
+	}
+	idx := make([]int, f.NumBlocks())
+	for i, b := range f.Blocks {
+		idx[b.ID] = i
+	}
+	var starts []*Block
+	for i, b := range f.Blocks {
+		end := -1
+		for _, e := range b.Preds {
+			if j := idx[e.b.ID]; j >= i && j > end {
+				end = j
+			}
+		}
+		if end < 0 {
+			continue
+		}
+		var w int64
+		for _, c := range f.Blocks[i : end+1] {
+			if weights[c.ID] > w {
+				w = weights[c.ID]
+			}
+		}
+		if w > 0 && w >= f.PGO.HotWeight {
+			starts = append(starts, b)
+			if base.Debug.PGODebug > 0 {
+				f.Warnl(b.Pos, "PGO: aligning hot loop starting at %v (weight %d)", b, w)
+			}
+		}
+	}
+	return starts
+}
@@ -805,8 +805,10 @@ func (s *regAllocState) compatRegs(t *types.Type) regMask {
 	if t.IsTuple() || t.IsFlags() {
 		return 0
 	}
-	if t.IsFloat() || t == types.TypeInt128 {
-		if t.Kind() == types.TFLOAT32 && s.f.Config.fp32RegMask != 0 {
+	if t.IsFloat() || t == types.TypeInt128 || t.IsSIMD() {
+		if t.IsSIMD() && s.f.Config.vecRegMask != 0 {
+			m = s.f.Config.vecRegMask
+		} else if t.Kind() == types.TFLOAT32 && s.f.Config.fp32RegMask != 0 {
 			m = s.f.Config.fp32RegMask
 		} else if t.Kind() == types.TFLOAT64 && s.f.Config.fp64RegMask != 0 {
 			m = s.f.Config.fp64RegMask
@@ -1920,6 +1922,9 @@ func (s *regAllocState) placeSpills() {
 	// that go immediately after that value ID.
 	after := map[ID][]*Value{}
 
+	// Profile weights of the blocks, if any.
+	weights := s.f.pgoBlockWeights()
+
 	for i := range s.values {
 		vi := s.values[i]
 		spill := vi.spill
@@ -1973,6 +1978,14 @@ func (s *regAllocState) placeSpills() {
 				// Don't push the spill into a deeper loop.
 				continue
 			}
+			if weights != nil && weights[best.ID] >= 0 && weights[b.ID] > 2*weights[best.ID] {
+				// Don't push the spill into a block that is
+				// hotter in the profile.
+				if base.Debug.PGODebug > 1 {
+					s.f.Warnl(v.Pos, "PGO: not moving spill of %v from %v into hotter %v (weight %d vs %d)", v, best, b, weights[b.ID], weights[best.ID])
+				}
+				continue
+			}
 
 			// If v is in a register at the start of b, we can
 			// place the spill here (after the phis).
@@ -279,6 +279,8 @@ func rewriteValue386(v *Value) bool {
 	case OpAvg32u:
 		v.Op = Op386AVGLU
 		return true
+	case OpBswap16:
+		return rewriteValue386_OpBswap16(v)
 	case OpBswap32:
 		v.Op = Op386BSWAPL
 		return true
@@ -8468,6 +8470,18 @@ func rewriteValue386_OpAddr(v *Value) bool {
 		return true
 	}
 }
+func rewriteValue386_OpBswap16(v *Value) bool {
+	v_0 := v.Args[0]
+	// match: (Bswap16 x)
+	// result: (ROLWconst [8] x)
+	for {
+		x := v_0
+		v.reset(Op386ROLWconst)
+		v.AuxInt = int16ToAuxInt(8)
+		v.AddArg(x)
+		return true
+	}
+}
 func rewriteValue386_OpConst16(v *Value) bool {
 	// match: (Const16 [c])
 	// result: (MOVLconst [int32(c)])
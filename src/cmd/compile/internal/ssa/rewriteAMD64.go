@@ -508,6 +508,10 @@ func rewriteValueAMD64(v *Value) bool {
 		return rewriteValueAMD64_OpAMD64TESTW(v)
 	case OpAMD64TESTWconst:
 		return rewriteValueAMD64_OpAMD64TESTWconst(v)
+	case OpAMD64VMOVDQUload256:
+		return rewriteValueAMD64_OpAMD64VMOVDQUload256(v)
+	case OpAMD64VMOVDQUstore256:
+		return rewriteValueAMD64_OpAMD64VMOVDQUstore256(v)
 	case OpAMD64XADDLlock:
 		return rewriteValueAMD64_OpAMD64XADDLlock(v)
 	case OpAMD64XADDQlock:
@@ -621,6 +625,8 @@ func rewriteValueAMD64(v *Value) bool {
 		return rewriteValueAMD64_OpBitLen64(v)
 	case OpBitLen8:
 		return rewriteValueAMD64_OpBitLen8(v)
+	case OpBswap16:
+		return rewriteValueAMD64_OpBswap16(v)
 	case OpBswap32:
 		v.Op = OpAMD64BSWAPL
 		return true
@@ -1150,6 +1156,68 @@ func rewriteValueAMD64(v *Value) bool {
 	case OpTrunc64to8:
 		v.Op = OpCopy
 		return true
+	case OpVecAddFloat32:
+		return rewriteValueAMD64_OpVecAddFloat32(v)
+	case OpVecAddFloat64:
+		return rewriteValueAMD64_OpVecAddFloat64(v)
+	case OpVecAddInt16:
+		return rewriteValueAMD64_OpVecAddInt16(v)
+	case OpVecAddInt32:
+		return rewriteValueAMD64_OpVecAddInt32(v)
+	case OpVecAddInt64:
+		return rewriteValueAMD64_OpVecAddInt64(v)
+	case OpVecAddInt8:
+		return rewriteValueAMD64_OpVecAddInt8(v)
+	case OpVecAnd:
+		return rewriteValueAMD64_OpVecAnd(v)
+	case OpVecAndNot:
+		return rewriteValueAMD64_OpVecAndNot(v)
+	case OpVecBroadcastInt16:
+		return rewriteValueAMD64_OpVecBroadcastInt16(v)
+	case OpVecBroadcastInt32:
+		return rewriteValueAMD64_OpVecBroadcastInt32(v)
+	case OpVecBroadcastInt64:
+		return rewriteValueAMD64_OpVecBroadcastInt64(v)
+	case OpVecBroadcastInt8:
+		return rewriteValueAMD64_OpVecBroadcastInt8(v)
+	case OpVecDivFloat32:
+		return rewriteValueAMD64_OpVecDivFloat32(v)
+	case OpVecDivFloat64:
+		return rewriteValueAMD64_OpVecDivFloat64(v)
+	case OpVecEqInt16:
+		return rewriteValueAMD64_OpVecEqInt16(v)
+	case OpVecEqInt32:
+		return rewriteValueAMD64_OpVecEqInt32(v)
+	case OpVecEqInt64:
+		return rewriteValueAMD64_OpVecEqInt64(v)
+	case OpVecEqInt8:
+		return rewriteValueAMD64_OpVecEqInt8(v)
+	case OpVecMulFloat32:
+		return rewriteValueAMD64_OpVecMulFloat32(v)
+	case OpVecMulFloat64:
+		return rewriteValueAMD64_OpVecMulFloat64(v)
+	case OpVecMulInt16:
+		return rewriteValueAMD64_OpVecMulInt16(v)
+	case OpVecMulInt32:
+		return rewriteValueAMD64_OpVecMulInt32(v)
+	case OpVecOr:
+		return rewriteValueAMD64_OpVecOr(v)
+	case OpVecSignMaskInt8:
+		return rewriteValueAMD64_OpVecSignMaskInt8(v)
+	case OpVecSubFloat32:
+		return rewriteValueAMD64_OpVecSubFloat32(v)
+	case OpVecSubFloat64:
+		return rewriteValueAMD64_OpVecSubFloat64(v)
+	case OpVecSubInt16:
+		return rewriteValueAMD64_OpVecSubInt16(v)
+	case OpVecSubInt32:
+		return rewriteValueAMD64_OpVecSubInt32(v)
+	case OpVecSubInt64:
+		return rewriteValueAMD64_OpVecSubInt64(v)
+	case OpVecSubInt8:
+		return rewriteValueAMD64_OpVecSubInt8(v)
+	case OpVecXor:
+		return rewriteValueAMD64_OpVecXor(v)
 	case OpWB:
 		v.Op = OpAMD64LoweredWB
 		return true
@@ -25990,6 +26058,105 @@ func rewriteValueAMD64_OpAMD64TESTWconst(v *Value) bool {
 	}
 	return false
 }
+func rewriteValueAMD64_OpAMD64VMOVDQUload256(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VMOVDQUload256 [off1] {sym} (ADDQconst [off2] ptr) mem)
+	// cond: is32Bit(int64(off1)+int64(off2))
+	// result: (VMOVDQUload256 [off1+off2] {sym} ptr mem)
+	for {
+		off1 := auxIntToInt32(v.AuxInt)
+		sym := auxToSym(v.Aux)
+		if v_0.Op != OpAMD64ADDQconst {
+			break
+		}
+		off2 := auxIntToInt32(v_0.AuxInt)
+		ptr := v_0.Args[0]
+		mem := v_1
+		if !(is32Bit(int64(off1) + int64(off2))) {
+			break
+		}
+		v.reset(OpAMD64VMOVDQUload256)
+		v.AuxInt = int32ToAuxInt(off1 + off2)
+		v.Aux = symToAux(sym)
+		v.AddArg2(ptr, mem)
+		return true
+	}
+	// match: (VMOVDQUload256 [off1] {sym1} (LEAQ [off2] {sym2} base) mem)
+	// cond: is32Bit(int64(off1)+int64(off2)) && canMergeSym(sym1, sym2)
+	// result: (VMOVDQUload256 [off1+off2] {mergeSym(sym1,sym2)} base mem)
+	for {
+		off1 := auxIntToInt32(v.AuxInt)
+		sym1 := auxToSym(v.Aux)
+		if v_0.Op != OpAMD64LEAQ {
+			break
+		}
+		off2 := auxIntToInt32(v_0.AuxInt)
+		sym2 := auxToSym(v_0.Aux)
+		base := v_0.Args[0]
+		mem := v_1
+		if !(is32Bit(int64(off1)+int64(off2)) && canMergeSym(sym1, sym2)) {
+			break
+		}
+		v.reset(OpAMD64VMOVDQUload256)
+		v.AuxInt = int32ToAuxInt(off1 + off2)
+		v.Aux = symToAux(mergeSym(sym1, sym2))
+		v.AddArg2(base, mem)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpAMD64VMOVDQUstore256(v *Value) bool {
+	v_2 := v.Args[2]
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VMOVDQUstore256 [off1] {sym} (ADDQconst [off2] ptr) val mem)
+	// cond: is32Bit(int64(off1)+int64(off2))
+	// result: (VMOVDQUstore256 [off1+off2] {sym} ptr val mem)
+	for {
+		off1 := auxIntToInt32(v.AuxInt)
+		sym := auxToSym(v.Aux)
+		if v_0.Op != OpAMD64ADDQconst {
+			break
+		}
+		off2 := auxIntToInt32(v_0.AuxInt)
+		ptr := v_0.Args[0]
+		val := v_1
+		mem := v_2
+		if !(is32Bit(int64(off1) + int64(off2))) {
+			break
+		}
+		v.reset(OpAMD64VMOVDQUstore256)
+		v.AuxInt = int32ToAuxInt(off1 + off2)
+		v.Aux = symToAux(sym)
+		v.AddArg3(ptr, val, mem)
+		return true
+	}
+	// match: (VMOVDQUstore256 [off1] {sym1} (LEAQ [off2] {sym2} base) val mem)
+	// cond: is32Bit(int64(off1)+int64(off2)) && canMergeSym(sym1, sym2)
+	// result: (VMOVDQUstore256 [off1+off2] {mergeSym(sym1,sym2)} base val mem)
+	for {
+		off1 := auxIntToInt32(v.AuxInt)
+		sym1 := auxToSym(v.Aux)
+		if v_0.Op != OpAMD64LEAQ {
+			break
+		}
+		off2 := auxIntToInt32(v_0.AuxInt)
+		sym2 := auxToSym(v_0.Aux)
+		base := v_0.Args[0]
+		val := v_1
+		mem := v_2
+		if !(is32Bit(int64(off1)+int64(off2)) && canMergeSym(sym1, sym2)) {
+			break
+		}
+		v.reset(OpAMD64VMOVDQUstore256)
+		v.AuxInt = int32ToAuxInt(off1 + off2)
+		v.Aux = symToAux(mergeSym(sym1, sym2))
+		v.AddArg3(base, val, mem)
+		return true
+	}
+	return false
+}
 func rewriteValueAMD64_OpAMD64XADDLlock(v *Value) bool {
 	v_2 := v.Args[2]
 	v_1 := v.Args[1]
@@ -27450,6 +27617,18 @@ func rewriteValueAMD64_OpBitLen8(v *Value) bool {
 	}
 	return false
 }
+func rewriteValueAMD64_OpBswap16(v *Value) bool {
+	v_0 := v.Args[0]
+	// match: (Bswap16 x)
+	// result: (ROLWconst [8] x)
+	for {
+		x := v_0
+		v.reset(OpAMD64ROLWconst)
+		v.AuxInt = int8ToAuxInt(8)
+		v.AddArg(x)
+		return true
+	}
+}
 func rewriteValueAMD64_OpCeil(v *Value) bool {
 	v_0 := v.Args[0]
 	// match: (Ceil x)
@@ -29386,6 +29565,34 @@ func rewriteValueAMD64_OpLoad(v *Value) bool {
 		v.AddArg2(ptr, mem)
 		return true
 	}
+	// match: (Load <t> ptr mem)
+	// cond: t.IsSIMD() && t.Size() == 16
+	// result: (MOVOload ptr mem)
+	for {
+		t := v.Type
+		ptr := v_0
+		mem := v_1
+		if !(t.IsSIMD() && t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64MOVOload)
+		v.AddArg2(ptr, mem)
+		return true
+	}
+	// match: (Load <t> ptr mem)
+	// cond: t.IsSIMD() && t.Size() == 32
+	// result: (VMOVDQUload256 ptr mem)
+	for {
+		t := v.Type
+		ptr := v_0
+		mem := v_1
+		if !(t.IsSIMD() && t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VMOVDQUload256)
+		v.AddArg2(ptr, mem)
+		return true
+	}
 	return false
 }
 func rewriteValueAMD64_OpLocalAddr(v *Value) bool {
@@ -32708,6 +32915,36 @@ func rewriteValueAMD64_OpStore(v *Value) bool {
 		return true
 	}
 	// match: (Store {t} ptr val mem)
+	// cond: t.Size() == 16 && val.Type.IsSIMD()
+	// result: (MOVOstore ptr val mem)
+	for {
+		t := auxToType(v.Aux)
+		ptr := v_0
+		val := v_1
+		mem := v_2
+		if !(t.Size() == 16 && val.Type.IsSIMD()) {
+			break
+		}
+		v.reset(OpAMD64MOVOstore)
+		v.AddArg3(ptr, val, mem)
+		return true
+	}
+	// match: (Store {t} ptr val mem)
+	// cond: t.Size() == 32 && val.Type.IsSIMD()
+	// result: (VMOVDQUstore256 ptr val mem)
+	for {
+		t := auxToType(v.Aux)
+		ptr := v_0
+		val := v_1
+		mem := v_2
+		if !(t.Size() == 32 && val.Type.IsSIMD()) {
+			break
+		}
+		v.reset(OpAMD64VMOVDQUstore256)
+		v.AddArg3(ptr, val, mem)
+		return true
+	}
+	// match: (Store {t} ptr val mem)
 	// cond: t.Size() == 8
 	// result: (MOVQstore ptr val mem)
 	for {
@@ -32781,6 +33018,1012 @@ func rewriteValueAMD64_OpTrunc(v *Value) bool {
 		return true
 	}
 }
+func rewriteValueAMD64_OpVecAddFloat32(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecAddFloat32 <t> x y)
+	// cond: t.Size() == 16
+	// result: (ADDPS x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64ADDPS)
+		v.AddArg2(x, y)
+		return true
+	}
+	// match: (VecAddFloat32 <t> x y)
+	// cond: t.Size() == 32
+	// result: (VADDPS256 x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VADDPS256)
+		v.AddArg2(x, y)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecAddFloat64(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecAddFloat64 <t> x y)
+	// cond: t.Size() == 16
+	// result: (ADDPD x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64ADDPD)
+		v.AddArg2(x, y)
+		return true
+	}
+	// match: (VecAddFloat64 <t> x y)
+	// cond: t.Size() == 32
+	// result: (VADDPD256 x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VADDPD256)
+		v.AddArg2(x, y)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecAddInt16(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecAddInt16 <t> x y)
+	// cond: t.Size() == 16
+	// result: (PADDW x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64PADDW)
+		v.AddArg2(x, y)
+		return true
+	}
+	// match: (VecAddInt16 <t> x y)
+	// cond: t.Size() == 32
+	// result: (VPADDW256 x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VPADDW256)
+		v.AddArg2(x, y)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecAddInt32(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecAddInt32 <t> x y)
+	// cond: t.Size() == 16
+	// result: (PADDL x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64PADDL)
+		v.AddArg2(x, y)
+		return true
+	}
+	// match: (VecAddInt32 <t> x y)
+	// cond: t.Size() == 32
+	// result: (VPADDD256 x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VPADDD256)
+		v.AddArg2(x, y)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecAddInt64(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecAddInt64 <t> x y)
+	// cond: t.Size() == 16
+	// result: (PADDQ x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64PADDQ)
+		v.AddArg2(x, y)
+		return true
+	}
+	// match: (VecAddInt64 <t> x y)
+	// cond: t.Size() == 32
+	// result: (VPADDQ256 x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VPADDQ256)
+		v.AddArg2(x, y)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecAddInt8(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecAddInt8 <t> x y)
+	// cond: t.Size() == 16
+	// result: (PADDB x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64PADDB)
+		v.AddArg2(x, y)
+		return true
+	}
+	// match: (VecAddInt8 <t> x y)
+	// cond: t.Size() == 32
+	// result: (VPADDB256 x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VPADDB256)
+		v.AddArg2(x, y)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecAnd(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecAnd <t> x y)
+	// cond: t.Size() == 16
+	// result: (PAND x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64PAND)
+		v.AddArg2(x, y)
+		return true
+	}
+	// match: (VecAnd <t> x y)
+	// cond: t.Size() == 32
+	// result: (VPAND256 x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VPAND256)
+		v.AddArg2(x, y)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecAndNot(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecAndNot <t> x y)
+	// cond: t.Size() == 16
+	// result: (PANDN y x)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64PANDN)
+		v.AddArg2(y, x)
+		return true
+	}
+	// match: (VecAndNot <t> x y)
+	// cond: t.Size() == 32
+	// result: (VPANDN256 y x)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VPANDN256)
+		v.AddArg2(y, x)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecBroadcastInt16(v *Value) bool {
+	v_0 := v.Args[0]
+	// match: (VecBroadcastInt16 <t> x)
+	// cond: t.Size() == 16
+	// result: (LoweredBroadcastW x)
+	for {
+		t := v.Type
+		x := v_0
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64LoweredBroadcastW)
+		v.AddArg(x)
+		return true
+	}
+	// match: (VecBroadcastInt16 <t> x)
+	// cond: t.Size() == 32
+	// result: (LoweredBroadcastW256 x)
+	for {
+		t := v.Type
+		x := v_0
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64LoweredBroadcastW256)
+		v.AddArg(x)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecBroadcastInt32(v *Value) bool {
+	v_0 := v.Args[0]
+	// match: (VecBroadcastInt32 <t> x)
+	// cond: t.Size() == 16
+	// result: (LoweredBroadcastL x)
+	for {
+		t := v.Type
+		x := v_0
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64LoweredBroadcastL)
+		v.AddArg(x)
+		return true
+	}
+	// match: (VecBroadcastInt32 <t> x)
+	// cond: t.Size() == 32
+	// result: (LoweredBroadcastL256 x)
+	for {
+		t := v.Type
+		x := v_0
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64LoweredBroadcastL256)
+		v.AddArg(x)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecBroadcastInt64(v *Value) bool {
+	v_0 := v.Args[0]
+	// match: (VecBroadcastInt64 <t> x)
+	// cond: t.Size() == 16
+	// result: (LoweredBroadcastQ x)
+	for {
+		t := v.Type
+		x := v_0
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64LoweredBroadcastQ)
+		v.AddArg(x)
+		return true
+	}
+	// match: (VecBroadcastInt64 <t> x)
+	// cond: t.Size() == 32
+	// result: (LoweredBroadcastQ256 x)
+	for {
+		t := v.Type
+		x := v_0
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64LoweredBroadcastQ256)
+		v.AddArg(x)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecBroadcastInt8(v *Value) bool {
+	v_0 := v.Args[0]
+	// match: (VecBroadcastInt8 <t> x)
+	// cond: t.Size() == 16
+	// result: (LoweredBroadcastB x)
+	for {
+		t := v.Type
+		x := v_0
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64LoweredBroadcastB)
+		v.AddArg(x)
+		return true
+	}
+	// match: (VecBroadcastInt8 <t> x)
+	// cond: t.Size() == 32
+	// result: (LoweredBroadcastB256 x)
+	for {
+		t := v.Type
+		x := v_0
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64LoweredBroadcastB256)
+		v.AddArg(x)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecDivFloat32(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecDivFloat32 <t> x y)
+	// cond: t.Size() == 16
+	// result: (DIVPS x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64DIVPS)
+		v.AddArg2(x, y)
+		return true
+	}
+	// match: (VecDivFloat32 <t> x y)
+	// cond: t.Size() == 32
+	// result: (VDIVPS256 x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VDIVPS256)
+		v.AddArg2(x, y)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecDivFloat64(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecDivFloat64 <t> x y)
+	// cond: t.Size() == 16
+	// result: (DIVPD x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64DIVPD)
+		v.AddArg2(x, y)
+		return true
+	}
+	// match: (VecDivFloat64 <t> x y)
+	// cond: t.Size() == 32
+	// result: (VDIVPD256 x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VDIVPD256)
+		v.AddArg2(x, y)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecEqInt16(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecEqInt16 <t> x y)
+	// cond: t.Size() == 16
+	// result: (PCMPEQW x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64PCMPEQW)
+		v.AddArg2(x, y)
+		return true
+	}
+	// match: (VecEqInt16 <t> x y)
+	// cond: t.Size() == 32
+	// result: (VPCMPEQW256 x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VPCMPEQW256)
+		v.AddArg2(x, y)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecEqInt32(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecEqInt32 <t> x y)
+	// cond: t.Size() == 16
+	// result: (PCMPEQL x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64PCMPEQL)
+		v.AddArg2(x, y)
+		return true
+	}
+	// match: (VecEqInt32 <t> x y)
+	// cond: t.Size() == 32
+	// result: (VPCMPEQD256 x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VPCMPEQD256)
+		v.AddArg2(x, y)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecEqInt64(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecEqInt64 <t> x y)
+	// cond: t.Size() == 16
+	// result: (PCMPEQQ x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64PCMPEQQ)
+		v.AddArg2(x, y)
+		return true
+	}
+	// match: (VecEqInt64 <t> x y)
+	// cond: t.Size() == 32
+	// result: (VPCMPEQQ256 x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VPCMPEQQ256)
+		v.AddArg2(x, y)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecEqInt8(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecEqInt8 <t> x y)
+	// cond: t.Size() == 16
+	// result: (PCMPEQB x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64PCMPEQB)
+		v.AddArg2(x, y)
+		return true
+	}
+	// match: (VecEqInt8 <t> x y)
+	// cond: t.Size() == 32
+	// result: (VPCMPEQB256 x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VPCMPEQB256)
+		v.AddArg2(x, y)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecMulFloat32(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecMulFloat32 <t> x y)
+	// cond: t.Size() == 16
+	// result: (MULPS x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64MULPS)
+		v.AddArg2(x, y)
+		return true
+	}
+	// match: (VecMulFloat32 <t> x y)
+	// cond: t.Size() == 32
+	// result: (VMULPS256 x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VMULPS256)
+		v.AddArg2(x, y)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecMulFloat64(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecMulFloat64 <t> x y)
+	// cond: t.Size() == 16
+	// result: (MULPD x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64MULPD)
+		v.AddArg2(x, y)
+		return true
+	}
+	// match: (VecMulFloat64 <t> x y)
+	// cond: t.Size() == 32
+	// result: (VMULPD256 x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VMULPD256)
+		v.AddArg2(x, y)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecMulInt16(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecMulInt16 <t> x y)
+	// cond: t.Size() == 16
+	// result: (PMULLW x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64PMULLW)
+		v.AddArg2(x, y)
+		return true
+	}
+	// match: (VecMulInt16 <t> x y)
+	// cond: t.Size() == 32
+	// result: (VPMULLW256 x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VPMULLW256)
+		v.AddArg2(x, y)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecMulInt32(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecMulInt32 <t> x y)
+	// cond: t.Size() == 16
+	// result: (PMULLD x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64PMULLD)
+		v.AddArg2(x, y)
+		return true
+	}
+	// match: (VecMulInt32 <t> x y)
+	// cond: t.Size() == 32
+	// result: (VPMULLD256 x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VPMULLD256)
+		v.AddArg2(x, y)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecOr(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecOr <t> x y)
+	// cond: t.Size() == 16
+	// result: (POR x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64POR)
+		v.AddArg2(x, y)
+		return true
+	}
+	// match: (VecOr <t> x y)
+	// cond: t.Size() == 32
+	// result: (VPOR256 x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VPOR256)
+		v.AddArg2(x, y)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecSignMaskInt8(v *Value) bool {
+	v_0 := v.Args[0]
+	// match: (VecSignMaskInt8 x)
+	// cond: x.Type.Size() == 16
+	// result: (PMOVMSKB x)
+	for {
+		x := v_0
+		if !(x.Type.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64PMOVMSKB)
+		v.AddArg(x)
+		return true
+	}
+	// match: (VecSignMaskInt8 x)
+	// cond: x.Type.Size() == 32
+	// result: (VPMOVMSKB256 x)
+	for {
+		x := v_0
+		if !(x.Type.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VPMOVMSKB256)
+		v.AddArg(x)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecSubFloat32(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecSubFloat32 <t> x y)
+	// cond: t.Size() == 16
+	// result: (SUBPS x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64SUBPS)
+		v.AddArg2(x, y)
+		return true
+	}
+	// match: (VecSubFloat32 <t> x y)
+	// cond: t.Size() == 32
+	// result: (VSUBPS256 x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VSUBPS256)
+		v.AddArg2(x, y)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecSubFloat64(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecSubFloat64 <t> x y)
+	// cond: t.Size() == 16
+	// result: (SUBPD x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64SUBPD)
+		v.AddArg2(x, y)
+		return true
+	}
+	// match: (VecSubFloat64 <t> x y)
+	// cond: t.Size() == 32
+	// result: (VSUBPD256 x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VSUBPD256)
+		v.AddArg2(x, y)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecSubInt16(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecSubInt16 <t> x y)
+	// cond: t.Size() == 16
+	// result: (PSUBW x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64PSUBW)
+		v.AddArg2(x, y)
+		return true
+	}
+	// match: (VecSubInt16 <t> x y)
+	// cond: t.Size() == 32
+	// result: (VPSUBW256 x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VPSUBW256)
+		v.AddArg2(x, y)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecSubInt32(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecSubInt32 <t> x y)
+	// cond: t.Size() == 16
+	// result: (PSUBL x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64PSUBL)
+		v.AddArg2(x, y)
+		return true
+	}
+	// match: (VecSubInt32 <t> x y)
+	// cond: t.Size() == 32
+	// result: (VPSUBD256 x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VPSUBD256)
+		v.AddArg2(x, y)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecSubInt64(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecSubInt64 <t> x y)
+	// cond: t.Size() == 16
+	// result: (PSUBQ x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64PSUBQ)
+		v.AddArg2(x, y)
+		return true
+	}
+	// match: (VecSubInt64 <t> x y)
+	// cond: t.Size() == 32
+	// result: (VPSUBQ256 x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VPSUBQ256)
+		v.AddArg2(x, y)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecSubInt8(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecSubInt8 <t> x y)
+	// cond: t.Size() == 16
+	// result: (PSUBB x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64PSUBB)
+		v.AddArg2(x, y)
+		return true
+	}
+	// match: (VecSubInt8 <t> x y)
+	// cond: t.Size() == 32
+	// result: (VPSUBB256 x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VPSUBB256)
+		v.AddArg2(x, y)
+		return true
+	}
+	return false
+}
+func rewriteValueAMD64_OpVecXor(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (VecXor <t> x y)
+	// cond: t.Size() == 16
+	// result: (PXOR x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 16) {
+			break
+		}
+		v.reset(OpAMD64PXOR)
+		v.AddArg2(x, y)
+		return true
+	}
+	// match: (VecXor <t> x y)
+	// cond: t.Size() == 32
+	// result: (VPXOR256 x y)
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		if !(t.Size() == 32) {
+			break
+		}
+		v.reset(OpAMD64VPXOR256)
+		v.AddArg2(x, y)
+		return true
+	}
+	return false
+}
 func rewriteValueAMD64_OpZero(v *Value) bool {
 	v_1 := v.Args[1]
 	v_0 := v.Args[0]
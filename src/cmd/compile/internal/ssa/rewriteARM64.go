@@ -123,6 +123,10 @@ func rewriteValueARM64(v *Value) bool {
 		return rewriteValueARM64_OpARM64FMOVDstoreidx(v)
 	case OpARM64FMOVDstoreidx8:
 		return rewriteValueARM64_OpARM64FMOVDstoreidx8(v)
+	case OpARM64FMOVQload:
+		return rewriteValueARM64_OpARM64FMOVQload(v)
+	case OpARM64FMOVQstore:
+		return rewriteValueARM64_OpARM64FMOVQstore(v)
 	case OpARM64FMOVSload:
 		return rewriteValueARM64_OpARM64FMOVSload(v)
 	case OpARM64FMOVSloadidx:
@@ -546,6 +550,9 @@ func rewriteValueARM64(v *Value) bool {
 		return true
 	case OpBitRev8:
 		return rewriteValueARM64_OpBitRev8(v)
+	case OpBswap16:
+		v.Op = OpARM64REV16W
+		return true
 	case OpBswap32:
 		v.Op = OpARM64REVW
 		return true
@@ -1089,6 +1096,65 @@ func rewriteValueARM64(v *Value) bool {
 	case OpTrunc64to8:
 		v.Op = OpCopy
 		return true
+	case OpVecAddInt16:
+		v.Op = OpARM64VADD8H
+		return true
+	case OpVecAddInt32:
+		v.Op = OpARM64VADD4S
+		return true
+	case OpVecAddInt64:
+		v.Op = OpARM64VADD2D
+		return true
+	case OpVecAddInt8:
+		v.Op = OpARM64VADD16B
+		return true
+	case OpVecAnd:
+		v.Op = OpARM64VAND16B
+		return true
+	case OpVecAndNot:
+		return rewriteValueARM64_OpVecAndNot(v)
+	case OpVecBroadcastInt16:
+		v.Op = OpARM64VDUP8H
+		return true
+	case OpVecBroadcastInt32:
+		v.Op = OpARM64VDUP4S
+		return true
+	case OpVecBroadcastInt64:
+		v.Op = OpARM64VDUP2D
+		return true
+	case OpVecBroadcastInt8:
+		v.Op = OpARM64VDUP16B
+		return true
+	case OpVecEqInt16:
+		v.Op = OpARM64VCMEQ8H
+		return true
+	case OpVecEqInt32:
+		v.Op = OpARM64VCMEQ4S
+		return true
+	case OpVecEqInt64:
+		v.Op = OpARM64VCMEQ2D
+		return true
+	case OpVecEqInt8:
+		v.Op = OpARM64VCMEQ16B
+		return true
+	case OpVecOr:
+		v.Op = OpARM64VORR16B
+		return true
+	case OpVecSubInt16:
+		v.Op = OpARM64VSUB8H
+		return true
+	case OpVecSubInt32:
+		v.Op = OpARM64VSUB4S
+		return true
+	case OpVecSubInt64:
+		v.Op = OpARM64VSUB2D
+		return true
+	case OpVecSubInt8:
+		v.Op = OpARM64VSUB16B
+		return true
+	case OpVecXor:
+		v.Op = OpARM64VEOR16B
+		return true
 	case OpWB:
 		v.Op = OpARM64LoweredWB
 		return true
@@ -4932,6 +4998,105 @@ func rewriteValueARM64_OpARM64FMOVDstoreidx8(v *Value) bool {
 	}
 	return false
 }
+func rewriteValueARM64_OpARM64FMOVQload(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (FMOVQload [off1] {sym} (ADDconst [off2] ptr) mem)
+	// cond: is32Bit(int64(off1)+off2)
+	// result: (FMOVQload [off1+int32(off2)] {sym} ptr mem)
+	for {
+		off1 := auxIntToInt32(v.AuxInt)
+		sym := auxToSym(v.Aux)
+		if v_0.Op != OpARM64ADDconst {
+			break
+		}
+		off2 := auxIntToInt64(v_0.AuxInt)
+		ptr := v_0.Args[0]
+		mem := v_1
+		if !(is32Bit(int64(off1) + off2)) {
+			break
+		}
+		v.reset(OpARM64FMOVQload)
+		v.AuxInt = int32ToAuxInt(off1 + int32(off2))
+		v.Aux = symToAux(sym)
+		v.AddArg2(ptr, mem)
+		return true
+	}
+	// match: (FMOVQload [off1] {sym1} (MOVDaddr [off2] {sym2} ptr) mem)
+	// cond: canMergeSym(sym1,sym2) && is32Bit(int64(off1)+int64(off2))
+	// result: (FMOVQload [off1+off2] {mergeSym(sym1,sym2)} ptr mem)
+	for {
+		off1 := auxIntToInt32(v.AuxInt)
+		sym1 := auxToSym(v.Aux)
+		if v_0.Op != OpARM64MOVDaddr {
+			break
+		}
+		off2 := auxIntToInt32(v_0.AuxInt)
+		sym2 := auxToSym(v_0.Aux)
+		ptr := v_0.Args[0]
+		mem := v_1
+		if !(canMergeSym(sym1, sym2) && is32Bit(int64(off1)+int64(off2))) {
+			break
+		}
+		v.reset(OpARM64FMOVQload)
+		v.AuxInt = int32ToAuxInt(off1 + off2)
+		v.Aux = symToAux(mergeSym(sym1, sym2))
+		v.AddArg2(ptr, mem)
+		return true
+	}
+	return false
+}
+func rewriteValueARM64_OpARM64FMOVQstore(v *Value) bool {
+	v_2 := v.Args[2]
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	// match: (FMOVQstore [off1] {sym} (ADDconst [off2] ptr) val mem)
+	// cond: is32Bit(int64(off1)+off2)
+	// result: (FMOVQstore [off1+int32(off2)] {sym} ptr val mem)
+	for {
+		off1 := auxIntToInt32(v.AuxInt)
+		sym := auxToSym(v.Aux)
+		if v_0.Op != OpARM64ADDconst {
+			break
+		}
+		off2 := auxIntToInt64(v_0.AuxInt)
+		ptr := v_0.Args[0]
+		val := v_1
+		mem := v_2
+		if !(is32Bit(int64(off1) + off2)) {
+			break
+		}
+		v.reset(OpARM64FMOVQstore)
+		v.AuxInt = int32ToAuxInt(off1 + int32(off2))
+		v.Aux = symToAux(sym)
+		v.AddArg3(ptr, val, mem)
+		return true
+	}
+	// match: (FMOVQstore [off1] {sym1} (MOVDaddr [off2] {sym2} ptr) val mem)
+	// cond: canMergeSym(sym1,sym2) && is32Bit(int64(off1)+int64(off2))
+	// result: (FMOVQstore [off1+off2] {mergeSym(sym1,sym2)} ptr val mem)
+	for {
+		off1 := auxIntToInt32(v.AuxInt)
+		sym1 := auxToSym(v.Aux)
+		if v_0.Op != OpARM64MOVDaddr {
+			break
+		}
+		off2 := auxIntToInt32(v_0.AuxInt)
+		sym2 := auxToSym(v_0.Aux)
+		ptr := v_0.Args[0]
+		val := v_1
+		mem := v_2
+		if !(canMergeSym(sym1, sym2) && is32Bit(int64(off1)+int64(off2))) {
+			break
+		}
+		v.reset(OpARM64FMOVQstore)
+		v.AuxInt = int32ToAuxInt(off1 + off2)
+		v.Aux = symToAux(mergeSym(sym1, sym2))
+		v.AddArg3(ptr, val, mem)
+		return true
+	}
+	return false
+}
 func rewriteValueARM64_OpARM64FMOVSload(v *Value) bool {
 	v_1 := v.Args[1]
 	v_0 := v.Args[0]
@@ -24788,6 +24953,20 @@ func rewriteValueARM64_OpLoad(v *Value) bool {
 		v.AddArg2(ptr, mem)
 		return true
 	}
+	// match: (Load <t> ptr mem)
+	// cond: t.IsSIMD() && t.Size() == 16
+	// result: (FMOVQload ptr mem)
+	for {
+		t := v.Type
+		ptr := v_0
+		mem := v_1
+		if !(t.IsSIMD() && t.Size() == 16) {
+			break
+		}
+		v.reset(OpARM64FMOVQload)
+		v.AddArg2(ptr, mem)
+		return true
+	}
 	return false
 }
 func rewriteValueARM64_OpLocalAddr(v *Value) bool {
@@ -28486,8 +28665,40 @@ func rewriteValueARM64_OpStore(v *Value) bool {
 		v.AddArg3(ptr, val, mem)
 		return true
 	}
+	// match: (Store {t} ptr val mem)
+	// cond: t.Size() == 16 && val.Type.IsSIMD()
+	// result: (FMOVQstore ptr val mem)
+	for {
+		t := auxToType(v.Aux)
+		ptr := v_0
+		val := v_1
+		mem := v_2
+		if !(t.Size() == 16 && val.Type.IsSIMD()) {
+			break
+		}
+		v.reset(OpARM64FMOVQstore)
+		v.AddArg3(ptr, val, mem)
+		return true
+	}
 	return false
 }
+func rewriteValueARM64_OpVecAndNot(v *Value) bool {
+	v_1 := v.Args[1]
+	v_0 := v.Args[0]
+	b := v.Block
+	// match: (VecAndNot <t> x y)
+	// result: (VEOR16B x (VAND16B <t> x y))
+	for {
+		t := v.Type
+		x := v_0
+		y := v_1
+		v.reset(OpARM64VEOR16B)
+		v0 := b.NewValue0(v.Pos, OpARM64VAND16B, t)
+		v0.AddArg2(x, y)
+		v.AddArg2(x, v0)
+		return true
+	}
+}
 func rewriteValueARM64_OpZero(v *Value) bool {
 	v_1 := v.Args[1]
 	v_0 := v.Args[0]
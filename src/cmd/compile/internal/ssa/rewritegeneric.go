@@ -12069,6 +12069,27 @@ func rewriteValuegeneric_OpLoad(v *Value) bool {
 		v.copyOf(x)
 		return true
 	}
+	// match: (Load <t1> p1 (Move {t2} [n] p2 src mem))
+	// cond: t1.IsSIMD() && isSamePtr(p1, p2) && n == t1.Size()
+	// result: (Load <t1> src mem)
+	for {
+		t1 := v.Type
+		p1 := v_0
+		if v_1.Op != OpMove {
+			break
+		}
+		n := auxIntToInt64(v_1.AuxInt)
+		mem := v_1.Args[2]
+		p2 := v_1.Args[0]
+		src := v_1.Args[1]
+		if !(t1.IsSIMD() && isSamePtr(p1, p2) && n == t1.Size()) {
+			break
+		}
+		v.reset(OpLoad)
+		v.Type = t1
+		v.AddArg2(src, mem)
+		return true
+	}
 	// match: (Load <t1> p1 (Store {t2} p2 (Const64 [x]) _))
 	// cond: isSamePtr(p1,p2) && sizeof(t2) == 8 && is64BitFloat(t1) && !math.IsNaN(math.Float64frombits(uint64(x)))
 	// result: (Const64F [math.Float64frombits(uint64(x))])
@@ -14346,6 +14367,54 @@ func rewriteValuegeneric_OpMove(v *Value) bool {
 	v_0 := v.Args[0]
 	b := v.Block
 	config := b.Func.Config
+	// match: (Move {t1} [n] dst p1 mem:(Store {t2} p2 x _))
+	// cond: x.Type.IsSIMD() && isSamePtr(p1, p2) && n == x.Type.Size()
+	// result: (Store {t1} dst x mem)
+	for {
+		n := auxIntToInt64(v.AuxInt)
+		t1 := auxToType(v.Aux)
+		dst := v_0
+		p1 := v_1
+		mem := v_2
+		if mem.Op != OpStore {
+			break
+		}
+		x := mem.Args[1]
+		p2 := mem.Args[0]
+		if !(x.Type.IsSIMD() && isSamePtr(p1, p2) && n == x.Type.Size()) {
+			break
+		}
+		v.reset(OpStore)
+		v.Aux = typeToAux(t1)
+		v.AddArg3(dst, x, mem)
+		return true
+	}
+	// match: (Move {t1} [n] dst p1 mem:(VarDef (Store {t2} p2 x _)))
+	// cond: x.Type.IsSIMD() && isSamePtr(p1, p2) && n == x.Type.Size()
+	// result: (Store {t1} dst x mem)
+	for {
+		n := auxIntToInt64(v.AuxInt)
+		t1 := auxToType(v.Aux)
+		dst := v_0
+		p1 := v_1
+		mem := v_2
+		if mem.Op != OpVarDef {
+			break
+		}
+		mem_0 := mem.Args[0]
+		if mem_0.Op != OpStore {
+			break
+		}
+		x := mem_0.Args[1]
+		p2 := mem_0.Args[0]
+		if !(x.Type.IsSIMD() && isSamePtr(p1, p2) && n == x.Type.Size()) {
+			break
+		}
+		v.reset(OpStore)
+		v.Aux = typeToAux(t1)
+		v.AddArg3(dst, x, mem)
+		return true
+	}
 	// match: (Move {t} [n] dst1 src mem:(Zero {t} [n] dst2 _))
 	// cond: isSamePtr(src, dst2)
 	// result: (Zero {t} [n] dst1 mem)
@@ -0,0 +1,87 @@
+package main
+
+import "fmt"
+
+var scale = 3
+
+type buffer struct {
+	buf []int
+	n   int
+}
+
+//go:noinline
+func sumScaled(a []int) (s int) {
+	for _, x := range a {
+		s += x * scale // the load of scale is hoisted
+	}
+	return s
+}
+
+//go:noinline
+func sumBuffer(b *buffer) (s int) {
+	for i := 0; i < len(b.buf); i++ { // the nil check of b and the load of len(b.buf) are hoisted
+		s += b.buf[i]
+	}
+	return s
+}
+
+//go:noinline
+func fill(a []int, k, m int) {
+	for i := range a {
+		a[i] = k*m + 1 // k*m+1 is hoisted
+	}
+}
+
+//go:noinline
+func sumN(b *buffer, n int) (s int) {
+	for i := 0; i < n; i++ {
+		s += b.n // not hoisted: the loop may not run, and b may be nil
+	}
+	return s
+}
+
+//go:noinline
+func sum4(a *[4]int) (s int) {
+	for i := 0; i < 4; i++ { // unrolled
+		s += a[i]
+	}
+	return s
+}
+
+//go:noinline
+func oddOrNeg(a *[6]int) (s int) {
+	for i := 5; i >= 0; i -= 2 { // unrolled
+		if a[i] > 2 {
+			s += a[i]
+		} else {
+			s -= i
+		}
+	}
+	return s
+}
+
+func check(what string, got, want int) {
+	if got != want {
+		panic(fmt.Sprintf("%s = %d, want %d", what, got, want))
+	}
+}
+
+func main() {
+	a := []int{1, 2, 3}
+	check("sumScaled", sumScaled(a), 18)
+	check("sumBuffer", sumBuffer(&buffer{buf: a}), 6)
+	check("sumBuffer empty", sumBuffer(&buffer{}), 0)
+	fill(a, 2, 3)
+	check("fill", a[0]+a[1]+a[2], 21)
+	check("sumN", sumN(&buffer{n: 2}, 3), 6)
+	check("sumN nil", sumN(nil, 0), 0)
+	check("sum4", sum4(&[4]int{1, 2, 3, 4}), 10)
+	check("oddOrNeg", oddOrNeg(&[6]int{1, 2, 3, 4, 5, 6}), 9)
+
+	defer func() {
+		if recover() == nil {
+			panic("sumBuffer(nil) did not panic")
+		}
+	}()
+	sumBuffer(nil)
+}
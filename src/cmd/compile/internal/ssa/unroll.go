@@ -0,0 +1,255 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+const (
+	unrollMaxTrips  = 8   // maximum trip count of a loop to unroll
+	unrollMaxValues = 128 // maximum number of values of an unrolled loop
+)
+
+// unroll fully unrolls small innermost loops that run a constant number
+// of times, as found by the induction variable analysis of prove:
+//
+//	loop:
+//	  ind = (Phi init nxt)
+//	  if ind < limit then goto body else goto exit
+//	body:
+//	  ...
+//	  nxt = inc + ind
+//	  goto loop
+//
+// with constant init, inc and limit. The iterations are replaced by
+// straight-line copies of the header and the body, chained from the
+// block before the loop, after which the header always exits. The
+// induction variable is a constant in each copy, for opt and prove to
+// fold. The loop body may branch, but must not exit the loop.
+func unroll(f *Func) {
+	ivs := findIndVar(f)
+	if len(ivs) == 0 {
+		return
+	}
+	loops := f.loopnest()
+	if loops.hasIrreducible {
+		return
+	}
+	unrolled := false
+	for _, iv := range ivs {
+		h := iv.ind.Block
+		l := loops.b2l[h.ID]
+		if l == nil || l.header != h || !l.isInner {
+			continue
+		}
+		if trips := unrollLoop(f, loops, l, iv); trips > 0 {
+			if f.pass.debug > 0 {
+				f.Warnl(h.Pos, "Unrolled loop %d times", trips)
+			}
+			unrolled = true
+		}
+	}
+	if unrolled {
+		// Remove the original loop body.
+		deadcode(f)
+	}
+}
+
+// unrollLoop unrolls the loop l with induction variable iv, if it is
+// small enough, and returns its trip count, or 0 if l is left alone.
+func unrollLoop(f *Func, loops *loopnest, l *loop, iv indVar) int {
+	h := l.header
+	inLoop := func(b *Block) bool {
+		// Blocks added by unrolling other loops are not in loops.
+		return int(b.ID) < len(loops.b2l) && loops.b2l[b.ID] == l
+	}
+	if len(h.Preds) != 2 || !inLoop(h.Succs[0].b) || inLoop(h.Succs[1].b) {
+		return 0
+	}
+	entry, back := 0, 1 // indexes of the entry and back edges into h
+	if inLoop(h.Preds[0].b) {
+		entry, back = 1, 0
+	}
+	if inLoop(h.Preds[entry].b) || !inLoop(h.Preds[back].b) {
+		return 0
+	}
+
+	// Compute the trip count.
+	init, inc, _ := parseIndVar(iv.ind)
+	if init != iv.ind.Args[entry] || init.Op != OpConst64 || inc.Op != OpConst64 {
+		return 0
+	}
+	ctl := h.Controls[0]
+	x, y := ctl.Args[0], ctl.Args[1]
+	if x != iv.ind && y != iv.ind || x.Op != OpConst64 && y.Op != OpConst64 {
+		return 0
+	}
+	taken := func(i int64) bool {
+		a, b := i, i
+		if x.Op == OpConst64 {
+			a = x.AuxInt
+		}
+		if y.Op == OpConst64 {
+			b = y.AuxInt
+		}
+		if ctl.Op == OpLeq64 {
+			return a <= b
+		}
+		return a < b
+	}
+	trips := 0
+	for i := init.AuxInt; taken(i); i += inc.AuxInt {
+		// findIndVar checked that i+inc does not overflow while taken(i).
+		trips++
+		if trips > unrollMaxTrips {
+			return 0
+		}
+	}
+	if trips == 0 {
+		return 0
+	}
+
+	// Collect the blocks of the loop, which must only exit from the
+	// header, and check that they are worth copying.
+	var blocks []*Block
+	size := 0
+	for _, b := range f.Blocks {
+		if !inLoop(b) {
+			continue
+		}
+		if b != h {
+			for i, e := range b.Succs {
+				if !inLoop(e.b) && !unrollDropsSucc(b, i, inLoop) {
+					return 0
+				}
+			}
+		}
+		for _, v := range b.Values {
+			if opcodeTable[v.Op].call {
+				return 0
+			}
+		}
+		blocks = append(blocks, b)
+		size += len(b.Values)
+	}
+	if trips*size > unrollMaxValues {
+		return 0
+	}
+
+	// The header phis, and their values at the start of the current
+	// iteration.
+	var phis, cur []*Value
+	for _, v := range h.Values {
+		if v.Op == OpPhi {
+			phis = append(phis, v)
+			cur = append(cur, v.Args[entry])
+		}
+	}
+
+	// Copy the loop once per iteration. Each copy starts with a copy of
+	// the header, which always enters the body, and its latch jumps to
+	// the copy of the next iteration. The edge from the block before
+	// the loop to the header, (pred, predIdx), becomes the edge into
+	// the first copy, and the latch of the last copy takes its place.
+	pred, predIdx := h.Preds[entry].b, h.Preds[entry].i
+	for k := 0; k < trips; k++ {
+		bmap := make(map[*Block]*Block, len(blocks))
+		for _, b := range blocks {
+			c := f.NewBlock(b.Kind)
+			c.Pos = b.Pos
+			c.Likely = b.Likely
+			c.AuxInt = b.AuxInt
+			c.Aux = b.Aux
+			if b == h {
+				c.Kind = BlockPlain
+				c.Likely = BranchUnknown
+				c.Preds = make([]Edge, 1)
+			} else {
+				c.Preds = make([]Edge, len(b.Preds))
+			}
+			bmap[b] = c
+		}
+		var latch *Block
+		var latchIdx int
+		for _, b := range blocks {
+			c := bmap[b]
+			for i, e := range b.Succs {
+				switch {
+				case b == h && i == 1:
+					// The copy of the header does not exit.
+				case unrollDropsSucc(b, i, inLoop):
+					// Left by prove, which removed a bounds check.
+					c.Kind = BlockPlain
+					c.Likely = BranchUnknown
+				case e.b == h:
+					latch, latchIdx = c, len(c.Succs)
+					c.Succs = append(c.Succs, Edge{})
+				default:
+					s := bmap[e.b]
+					s.Preds[e.i] = Edge{c, len(c.Succs)}
+					c.Succs = append(c.Succs, Edge{s, e.i})
+				}
+			}
+		}
+		hc := bmap[h]
+		pred.Succs[predIdx] = Edge{hc, 0}
+		hc.Preds[0] = Edge{pred, predIdx}
+		pred, predIdx = latch, latchIdx
+
+		vals := make(map[*Value]*Value)
+		for i, phi := range phis {
+			vals[phi] = cur[i]
+		}
+		var copyValue func(v *Value) *Value
+		copyValue = func(v *Value) *Value {
+			if c, ok := vals[v]; ok {
+				return c
+			}
+			b := bmap[v.Block]
+			if b == nil {
+				return v // defined outside the loop
+			}
+			c := b.NewValue0(v.Pos, v.Op, v.Type)
+			c.AuxInt = v.AuxInt
+			c.Aux = v.Aux
+			vals[v] = c
+			for _, a := range v.Args {
+				c.AddArg(copyValue(a))
+			}
+			return c
+		}
+		for _, b := range blocks {
+			for _, v := range b.Values {
+				copyValue(v)
+			}
+			if b != h {
+				for _, v := range b.ControlValues() {
+					bmap[b].AddControl(copyValue(v))
+				}
+			}
+		}
+		next := make([]*Value, len(phis))
+		for i, phi := range phis {
+			next[i] = copyValue(phi.Args[back])
+		}
+		cur = next
+	}
+
+	// The header now runs once, after the last copy, and exits.
+	pred.Succs[predIdx] = Edge{h, entry}
+	h.Preds[entry] = Edge{pred, predIdx}
+	for i, phi := range phis {
+		phi.SetArg(entry, cur[i])
+	}
+	h.Kind = BlockFirst
+	h.ResetControls()
+	h.swapSuccessors()
+	h.Likely = BranchUnknown
+	f.invalidateCFG()
+	return trips
+}
+
+// unrollDropsSucc reports whether the copy of b drops its ith successor:
+// the never taken successor of a BlockFirst, if it is outside the loop.
+func unrollDropsSucc(b *Block, i int, inLoop func(*Block) bool) bool {
+	return b.Kind == BlockFirst && i == 1 && !inLoop(b.Succs[1].b)
+}
@@ -0,0 +1,91 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+import (
+	"cmd/compile/internal/types"
+	"testing"
+)
+
+// unrollTestFunc returns a function with the loop
+//
+//	for i := 0; i < n; i++ {
+//		s += i
+//		*global = s
+//	}
+func unrollTestFunc(c *Conf, n int64) fun {
+	intType := c.config.Types.Int64
+	ptrType := c.config.Types.Int64.PtrTo()
+	return c.Fun("entry",
+		Bloc("entry",
+			Valu("mem", OpInitMem, types.TypeMem, 0, nil),
+			Valu("sb", OpSB, c.config.Types.Uintptr, 0, nil),
+			Valu("zero", OpConst64, intType, 0, nil),
+			Valu("one", OpConst64, intType, 1, nil),
+			Valu("n", OpConst64, intType, n, nil),
+			Valu("global", OpAddr, ptrType, 0, nil, "sb"),
+			Goto("header")),
+		Bloc("header",
+			Valu("i", OpPhi, intType, 0, nil, "zero", "inc"),
+			Valu("s", OpPhi, intType, 0, nil, "zero", "sum"),
+			Valu("m", OpPhi, types.TypeMem, 0, nil, "mem", "store"),
+			Valu("cmp", OpLess64, c.config.Types.Bool, 0, nil, "i", "n"),
+			If("cmp", "body", "exit")),
+		Bloc("body",
+			Valu("sum", OpAdd64, intType, 0, nil, "s", "i"),
+			Valu("store", OpStore, types.TypeMem, 0, intType, "global", "sum", "m"),
+			Valu("inc", OpAdd64, intType, 0, nil, "i", "one"),
+			Goto("header")),
+		Bloc("exit",
+			Valu("result", OpStore, types.TypeMem, 0, intType, "global", "s", "m"),
+			Exit("result")))
+}
+
+func TestUnroll(t *testing.T) {
+	c := testConfig(t)
+	fun := unrollTestFunc(c, 3)
+
+	CheckFunc(fun.f)
+	unroll(fun.f)
+	CheckFunc(fun.f)
+
+	if n := len(fun.f.loopnest().loops); n != 0 {
+		t.Errorf("got %d loops after unrolling, want 0", n)
+	}
+	if b := fun.blocks["body"]; b.Kind != BlockInvalid {
+		t.Errorf("the original loop body was not removed")
+	}
+	stores := 0
+	for _, b := range fun.f.Blocks {
+		for _, v := range b.Values {
+			if v.Op == OpStore {
+				stores++
+			}
+		}
+	}
+	if stores != 4 {
+		t.Errorf("got %d stores after unrolling, want 4", stores)
+	}
+
+	// The exit block stores the sum of the copies, which opt folds.
+	opt(fun.f)
+	deadcode(fun.f)
+	if s := fun.values["result"].Args[1]; s.Op != OpConst64 || s.AuxInt != 0+1+2 {
+		t.Errorf("got result %s after unrolling, want (Const64 [3])", s.LongString())
+	}
+}
+
+func TestUnrollTooLong(t *testing.T) {
+	c := testConfig(t)
+	fun := unrollTestFunc(c, unrollMaxTrips+1)
+
+	CheckFunc(fun.f)
+	unroll(fun.f)
+	CheckFunc(fun.f)
+
+	if n := len(fun.f.loopnest().loops); n != 1 {
+		t.Errorf("got %d loops, want the loop to be left alone", n)
+	}
+}
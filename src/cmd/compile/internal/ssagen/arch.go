@@ -23,6 +23,10 @@ type ArchInfo struct {
 	MAXWIDTH  int64
 	SoftFloat bool
 
+	// LoopAlign is the alignment, in bytes, of the start of hot loops
+	// when compiling with a profile, or 0 not to align them.
+	LoopAlign int64
+
 	PadFrame func(int64) int64
 
 	// ZeroRange zeroes a range of memory on stack. It is only inserted
@@ -0,0 +1,205 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssagen
+
+import (
+	"fmt"
+	"internal/buildcfg"
+
+	"cmd/compile/internal/ir"
+	"cmd/compile/internal/ssa"
+	"cmd/compile/internal/types"
+	"cmd/internal/obj"
+	"cmd/internal/sys"
+)
+
+// Intrinsics for the vector types of package simd (GOEXPERIMENT=simd).
+//
+// The vector types are arrays, which can't be SSA values, so variables of
+// these types live in memory like other arrays. An intrinsic loads its
+// vector arguments as values of the SSA vector types vec128 and vec256,
+// and the assignment its result appears in stores the result back.
+// The generic store-to-load forwarding rules then keep chains of
+// operations in registers.
+
+// A simdFeature is a CPU feature that the instructions implementing an
+// intrinsic need, beyond those of the minimum supported CPU.
+type simdFeature int
+
+const (
+	simdBase  simdFeature = iota
+	simdSSE41             // amd64, implied by GOAMD64=v2
+	simdAVX2              // amd64, implied by GOAMD64=v3
+)
+
+// initSIMDIntrinsics registers the intrinsics of package simd with addF.
+func initSIMDIntrinsics(addF func(pkg, fn string, b intrinsicBuilder, archFamilies ...sys.ArchFamily)) {
+	type lane struct {
+		names          []string // vector type names, without the lane count
+		bits           int
+		add, sub, mul  ssa.Op
+		div, eq, bcast ssa.Op
+		bitwise        bool // has And, Or, Xor and AndNot
+		mask           bool // has Mask
+	}
+	lanes := []lane{
+		{names: []string{"Int8", "Uint8"}, bits: 8, add: ssa.OpVecAddInt8, sub: ssa.OpVecSubInt8, eq: ssa.OpVecEqInt8, bcast: ssa.OpVecBroadcastInt8, mask: true, bitwise: true},
+		{names: []string{"Int16", "Uint16"}, bits: 16, add: ssa.OpVecAddInt16, sub: ssa.OpVecSubInt16, mul: ssa.OpVecMulInt16, eq: ssa.OpVecEqInt16, bcast: ssa.OpVecBroadcastInt16, bitwise: true},
+		{names: []string{"Int32", "Uint32"}, bits: 32, add: ssa.OpVecAddInt32, sub: ssa.OpVecSubInt32, mul: ssa.OpVecMulInt32, eq: ssa.OpVecEqInt32, bcast: ssa.OpVecBroadcastInt32, bitwise: true},
+		{names: []string{"Int64", "Uint64"}, bits: 64, add: ssa.OpVecAddInt64, sub: ssa.OpVecSubInt64, eq: ssa.OpVecEqInt64, bcast: ssa.OpVecBroadcastInt64, bitwise: true},
+		{names: []string{"Float32"}, bits: 32, add: ssa.OpVecAddFloat32, sub: ssa.OpVecSubFloat32, mul: ssa.OpVecMulFloat32, div: ssa.OpVecDivFloat32},
+		{names: []string{"Float64"}, bits: 64, add: ssa.OpVecAddFloat64, sub: ssa.OpVecSubFloat64, mul: ssa.OpVecMulFloat64, div: ssa.OpVecDivFloat64},
+	}
+
+	for _, width := range []int{128, 256} {
+		for _, l := range lanes {
+			for _, name := range l.names {
+				vec := fmt.Sprintf("%sx%d", name, width/l.bits)
+				add := func(fn string, op ssa.Op) {
+					if op == ssa.OpInvalid {
+						return
+					}
+					addF("simd", fn, makeSIMD(op, simdAMD64Feature(op, width)), sys.AMD64)
+					if simdARM64(op, width) {
+						addF("simd", fn, makeSIMD(op, simdBase), sys.ARM64)
+					}
+				}
+				add(vec+".Add", l.add)
+				add(vec+".Sub", l.sub)
+				add(vec+".Mul", l.mul)
+				add(vec+".Div", l.div)
+				add(vec+".Equal", l.eq)
+				add("Broadcast"+vec, l.bcast)
+				if l.bitwise {
+					add(vec+".And", ssa.OpVecAnd)
+					add(vec+".Or", ssa.OpVecOr)
+					add(vec+".Xor", ssa.OpVecXor)
+					add(vec+".AndNot", ssa.OpVecAndNot)
+				}
+				if l.mask {
+					add(vec+".Mask", ssa.OpVecSignMaskInt8)
+				}
+			}
+		}
+	}
+}
+
+// simdAMD64Feature returns the CPU feature that amd64 needs to implement
+// op on vectors of the given width in bits. All ops are implemented.
+func simdAMD64Feature(op ssa.Op, width int) simdFeature {
+	if width == 256 {
+		return simdAVX2
+	}
+	switch op {
+	case ssa.OpVecMulInt32, ssa.OpVecEqInt64: // PMULLD, PCMPEQQ
+		return simdSSE41
+	}
+	return simdBase
+}
+
+// simdARM64 reports whether arm64 implements op on vectors of the given
+// width in bits. Only 128-bit integer vectors are implemented, and none
+// of the multiplications, which the assembler has no instructions for.
+func simdARM64(op ssa.Op, width int) bool {
+	if width != 128 {
+		return false
+	}
+	switch op {
+	case ssa.OpVecAddInt8, ssa.OpVecAddInt16, ssa.OpVecAddInt32, ssa.OpVecAddInt64,
+		ssa.OpVecSubInt8, ssa.OpVecSubInt16, ssa.OpVecSubInt32, ssa.OpVecSubInt64,
+		ssa.OpVecAnd, ssa.OpVecOr, ssa.OpVecXor, ssa.OpVecAndNot,
+		ssa.OpVecEqInt8, ssa.OpVecEqInt16, ssa.OpVecEqInt32, ssa.OpVecEqInt64,
+		ssa.OpVecBroadcastInt8, ssa.OpVecBroadcastInt16, ssa.OpVecBroadcastInt32, ssa.OpVecBroadcastInt64:
+		return true
+	}
+	return false
+}
+
+// makeSIMD returns an intrinsic builder for a vector operation op that
+// needs CPU feature f. Unless f is implied by the GOAMD64 level, the
+// operation is guarded by a run-time check for f, and calls the Go
+// implementation on CPUs that lack it.
+func makeSIMD(op ssa.Op, f simdFeature) intrinsicBuilder {
+	return func(s *state, n *ir.CallExpr, args []*ssa.Value) *ssa.Value {
+		t := n.Type()
+		if !TypeOK(t) {
+			t = s.vecType(t)
+		}
+		var sym *obj.LSym
+		switch {
+		case f == simdSSE41 && buildcfg.GOAMD64 < 2:
+			sym = ir.Syms.X86HasSSE41
+		case f == simdAVX2 && buildcfg.GOAMD64 < 3:
+			sym = ir.Syms.X86HasAVX2
+		}
+		if sym == nil {
+			return s.simdValue(op, t, args)
+		}
+
+		v := s.entryNewValue0A(ssa.OpHasCPUFeature, types.Types[types.TBOOL], sym)
+		b := s.endBlock()
+		b.Kind = ssa.BlockIf
+		b.SetControl(v)
+		bTrue := s.f.NewBlock(ssa.BlockPlain)
+		bFalse := s.f.NewBlock(ssa.BlockPlain)
+		bEnd := s.f.NewBlock(ssa.BlockPlain)
+		b.AddEdgeTo(bTrue)
+		b.AddEdgeTo(bFalse)
+		b.Likely = ssa.BranchLikely
+
+		// We have the instructions - use them directly.
+		s.startBlock(bTrue)
+		s.vars[n] = s.simdValue(op, t, args)
+		s.endBlock().AddEdgeTo(bEnd)
+
+		// Call the pure Go version.
+		s.startBlock(bFalse)
+		if t.IsSIMD() {
+			s.vars[n] = s.load(t, s.callAddr(n, callNormal))
+		} else {
+			s.vars[n] = s.callResult(n, callNormal)
+		}
+		s.endBlock().AddEdgeTo(bEnd)
+
+		// Merge results.
+		s.startBlock(bEnd)
+		return s.variable(n, t)
+	}
+}
+
+// simdValue adds a vector operation op of type t on args, which are one
+// or two values, to the current block.
+func (s *state) simdValue(op ssa.Op, t *types.Type, args []*ssa.Value) *ssa.Value {
+	if len(args) == 1 {
+		return s.newValue1(op, t, args[0])
+	}
+	return s.newValue2(op, t, args[0], args[1])
+}
+
+// vecType returns the SSA vector type of the values of t, one of the
+// vector types of package simd.
+func (s *state) vecType(t *types.Type) *types.Type {
+	var vt *types.Type
+	switch t.Size() {
+	case 16:
+		vt = types.TypeVec128
+	case 32:
+		vt = types.TypeVec256
+	default:
+		s.Fatalf("unexpected SIMD vector type %v", t)
+	}
+	if vt.Size() > s.f.VecSize {
+		s.f.VecSize = vt.Size()
+	}
+	return vt
+}
+
+// vecExpr evaluates n, a vector of package simd, to an SSA vector value.
+func (s *state) vecExpr(n ir.Node) *ssa.Value {
+	if call, ok := n.(*ir.CallExpr); ok && n.Op() == ir.OCALLFUNC && IsIntrinsicCall(call) {
+		return s.intrinsicCall(call)
+	}
+	return s.load(s.vecType(n.Type()), s.addr(n))
+}
@@ -21,6 +21,7 @@ import (
 	"cmd/compile/internal/ir"
 	"cmd/compile/internal/liveness"
 	"cmd/compile/internal/objw"
+	"cmd/compile/internal/pgo"
 	"cmd/compile/internal/reflectdata"
 	"cmd/compile/internal/ssa"
 	"cmd/compile/internal/staticdata"
@@ -45,6 +46,10 @@ const ssaDumpFile = "ssa.html"
 // ssaDumpInlined holds all inlined functions when ssaDump contains a function name.
 var ssaDumpInlined []*ir.Func
 
+// PGOProfile is the profile used to lay out blocks, align hot loops and
+// place spills, if any.
+var PGOProfile *pgo.Profile
+
 func DumpInline(fn *ir.Func) {
 	if ssaDump != "" && ssaDump == ir.FuncName(fn) {
 		ssaDumpInlined = append(ssaDumpInlined, fn)
@@ -126,6 +131,7 @@ func InitConfig() {
 	ir.Syms.X86HasPOPCNT = typecheck.LookupRuntimeVar("x86HasPOPCNT")       // bool
 	ir.Syms.X86HasSSE41 = typecheck.LookupRuntimeVar("x86HasSSE41")         // bool
 	ir.Syms.X86HasFMA = typecheck.LookupRuntimeVar("x86HasFMA")             // bool
+	ir.Syms.X86HasAVX2 = typecheck.LookupRuntimeVar("x86HasAVX2")           // bool
 	ir.Syms.ARMHasVFPv4 = typecheck.LookupRuntimeVar("armHasVFPv4")         // bool
 	ir.Syms.ARM64HasATOMICS = typecheck.LookupRuntimeVar("arm64HasATOMICS") // bool
 	ir.Syms.Staticuint64s = typecheck.LookupRuntimeVar("staticuint64s")
@@ -364,6 +370,11 @@ func buildssa(fn *ir.Func, worker int) *ssa.Func {
 	s.f.ABI1 = ssaConfig.ABI1.Copy()
 	s.f.ABIDefault = abiForFunc(nil, s.f.ABI0, s.f.ABI1)
 	s.f.ABISelf = abiForFunc(fn, s.f.ABI0, s.f.ABI1)
+	if PGOProfile != nil {
+		if lines := PGOProfile.FuncLines(fn); lines != nil {
+			s.f.PGO = &ssa.PGOInfo{Lines: lines, HotWeight: PGOProfile.HotLineWeight}
+		}
+	}
 
 	s.panics = map[funcLine]*ssa.Block{}
 	s.softFloat = s.config.SoftFloat
@@ -1665,6 +1676,10 @@ func (s *state) stmt(n ir.Node) {
 		if deref {
 			if rhs == nil {
 				r = nil // Signal assign to use OpZero.
+			} else if call, ok := rhs.(*ir.CallExpr); ok && rhs.Op() == ir.OCALLFUNC && IsIntrinsicCall(call) {
+				// A vector of package simd, computed in a register.
+				r = s.intrinsicCall(call)
+				deref = false
 			} else {
 				r = s.addr(rhs)
 			}
@@ -3452,8 +3467,62 @@ func (s *state) append(n *ir.CallExpr, inplace bool) *ssa.Value {
 	b.AddEdgeTo(grow)
 	b.AddEdgeTo(assign)
 
-	// Call growslice
+	// setGrown records the grown slice, whose length is l.
+	setGrown := func(p, c *ssa.Value) {
+		s.vars[ptrVar] = p
+		s.vars[lenVar] = l
+		s.vars[capVar] = c
+		if inplace {
+			if sn.Op() == ir.ONAME {
+				sn := sn.(*ir.Name)
+				if sn.Class != ir.PEXTERN {
+					// Tell liveness we're about to build a new slice
+					s.vars[memVar] = s.newValue1A(ssa.OpVarDef, types.TypeMem, sn, s.mem())
+				}
+			}
+			capaddr := s.newValue1I(ssa.OpOffPtr, s.f.Config.Types.IntPtr, types.SliceCapOffset, addr)
+			s.store(types.Types[types.TINT], capaddr, c)
+			s.store(pt, addr, p)
+		}
+	}
+
 	s.startBlock(grow)
+	if n.StackBuf != nil {
+		// The first time an empty slice grows, it may use the
+		// stack array that walk set up for it (see appendGrow):
+		//
+		// if !used && cap == 0 && uint(len) <= K {
+		//     used = true
+		//     buf = [K]T{}
+		//     ptr, cap = &buf[0], K
+		// } else {
+		//     ptr, len, cap = growslice(ptr, len, cap, 3, typ)
+		// }
+		k := n.StackBuf.Type().NumElem()
+		useStack := s.newValue1(ssa.OpNot, types.Types[types.TBOOL], s.expr(n.StackBufUsed))
+		empty := s.newValue2(s.ssaOp(ir.OEQ, types.Types[types.TINT]), types.Types[types.TBOOL], c, s.zeroVal(types.Types[types.TINT]))
+		fits := s.newValue2(s.ssaOp(ir.OLE, types.Types[types.TUINT]), types.Types[types.TBOOL], l, s.constInt(types.Types[types.TINT], k))
+		useStack = s.newValue2(ssa.OpAndB, types.Types[types.TBOOL], useStack, s.newValue2(ssa.OpAndB, types.Types[types.TBOOL], empty, fits))
+
+		stack := s.f.NewBlock(ssa.BlockPlain)
+		heap := s.f.NewBlock(ssa.BlockPlain)
+		b := s.endBlock()
+		b.Kind = ssa.BlockIf
+		b.SetControl(useStack)
+		b.AddEdgeTo(stack)
+		b.AddEdgeTo(heap)
+
+		s.startBlock(stack)
+		s.assign(n.StackBufUsed, s.constBool(true), false, 0)
+		s.assign(n.StackBuf, nil, true, 0)
+		setGrown(s.newValue1(ssa.OpCopy, pt, s.addr(n.StackBuf)), s.constInt(types.Types[types.TINT], k))
+		b = s.endBlock()
+		b.AddEdgeTo(assign)
+
+		s.startBlock(heap)
+	}
+
+	// Call growslice
 	taddr := s.expr(n.X)
 	r := s.rtcall(ir.Syms.Growslice, true, []*types.Type{n.Type()}, p, l, c, nargs, taddr)
 
@@ -3461,22 +3530,7 @@ func (s *state) append(n *ir.CallExpr, inplace bool) *ssa.Value {
 	p = s.newValue1(ssa.OpSlicePtr, pt, r[0])
 	l = s.newValue1(ssa.OpSliceLen, types.Types[types.TINT], r[0])
 	c = s.newValue1(ssa.OpSliceCap, types.Types[types.TINT], r[0])
-
-	s.vars[ptrVar] = p
-	s.vars[lenVar] = l
-	s.vars[capVar] = c
-	if inplace {
-		if sn.Op() == ir.ONAME {
-			sn := sn.(*ir.Name)
-			if sn.Class != ir.PEXTERN {
-				// Tell liveness we're about to build a new slice
-				s.vars[memVar] = s.newValue1A(ssa.OpVarDef, types.TypeMem, sn, s.mem())
-			}
-		}
-		capaddr := s.newValue1I(ssa.OpOffPtr, s.f.Config.Types.IntPtr, types.SliceCapOffset, addr)
-		s.store(types.Types[types.TINT], capaddr, c)
-		s.store(pt, addr, p)
-	}
+	setGrown(p, c)
 
 	b = s.endBlock()
 	b.AddEdgeTo(assign)
@@ -4787,6 +4841,11 @@ func InitTables() {
 
 	/******** math/big ********/
 	alias("math/big", "mulWW", "math/bits", "Mul64", p8...)
+
+	/******** simd ********/
+	if buildcfg.Experiment.SIMD {
+		initSIMDIntrinsics(addF)
+	}
 }
 
 // findIntrinsic returns a function which builds the SSA equivalent of the
@@ -4825,8 +4884,20 @@ func IsIntrinsicCall(n *ir.CallExpr) bool {
 	if n == nil {
 		return false
 	}
-	name, ok := n.X.(*ir.Name)
-	if !ok {
+	var name *ir.Name
+	switch fn := n.X.(type) {
+	case *ir.Name:
+		name = fn
+	case *ir.SelectorExpr:
+		// Until walk, method calls are calls of method expressions.
+		if fn.Op() != ir.OMETHEXPR {
+			return false
+		}
+		name = ir.MethodExprName(fn)
+		if name == nil { // method of an interface type
+			return false
+		}
+	default:
 		return false
 	}
 	return findIntrinsic(name.Sym()) != nil
@@ -4852,6 +4923,11 @@ func (s *state) intrinsicCall(n *ir.CallExpr) *ssa.Value {
 func (s *state) intrinsicArgs(n *ir.CallExpr) []*ssa.Value {
 	args := make([]*ssa.Value, len(n.Args))
 	for i, n := range n.Args {
+		if !TypeOK(n.Type()) {
+			// A vector of package simd.
+			args[i] = s.vecExpr(n)
+			continue
+		}
 		args[i] = s.expr(n)
 	}
 	return args
@@ -6947,8 +7023,23 @@ func genssa(f *ssa.Func, pp *objw.Progs) {
 
 	var argLiveIdx int = -1 // argument liveness info index
 
+	// Align the start of hot loops.
+	var alignedBlocks map[ssa.ID]bool
+	if Arch.LoopAlign != 0 {
+		for _, b := range f.HotLoopStarts() {
+			if alignedBlocks == nil {
+				alignedBlocks = make(map[ssa.ID]bool)
+			}
+			alignedBlocks[b.ID] = true
+		}
+	}
+
 	// Emit basic blocks
 	for i, b := range f.Blocks {
+		if alignedBlocks[b.ID] {
+			p := s.pp.Prog(obj.APCALIGN)
+			p.From.SetConst(Arch.LoopAlign)
+		}
 		s.bstart[b.ID] = s.pp.Next
 		s.lineRunStart = nil
 		s.SetPos(s.pp.Pos.WithNotStmt()) // It needs a non-empty Pos, but cannot be a statement boundary (yet).
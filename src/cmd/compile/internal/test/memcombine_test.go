@@ -0,0 +1,174 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// Tests that the loads and stores combined by the memcombine pass
+// read and write the same bytes as encoding/binary.
+
+//go:noinline
+func readLE64Shuffled(b []byte) uint64 {
+	_ = b[7]
+	return uint64(b[4])<<32 | uint64(b[0]) | uint64(b[7])<<56 | uint64(b[2])<<16 |
+		uint64(b[1])<<8 | uint64(b[6])<<48 | uint64(b[3])<<24 | uint64(b[5])<<40
+}
+
+//go:noinline
+func readBE64Shuffled(b []byte) uint64 {
+	_ = b[7]
+	return uint64(b[3])<<32 | uint64(b[7]) | uint64(b[0])<<56 | uint64(b[5])<<16 |
+		uint64(b[6])<<8 | uint64(b[1])<<48 | uint64(b[4])<<24 | uint64(b[2])<<40
+}
+
+//go:noinline
+func readBE32Shuffled(b []byte) uint32 {
+	_ = b[3]
+	return uint32(b[2])<<8 | uint32(b[0])<<24 | uint32(b[3]) | uint32(b[1])<<16
+}
+
+//go:noinline
+func readLE32Shifted(b []byte) uint64 {
+	_ = b[3]
+	return uint64(b[0])<<16 | uint64(b[1])<<24 | uint64(b[2])<<32 | uint64(b[3])<<40
+}
+
+//go:noinline
+func readBE16(b []byte, i int) uint16 {
+	return uint16(b[i+1]) | uint16(b[i])<<8
+}
+
+//go:noinline
+func readLE16x4(b []uint16) uint64 {
+	_ = b[3]
+	return uint64(b[2])<<32 | uint64(b[0]) | uint64(b[3])<<48 | uint64(b[1])<<16
+}
+
+//go:noinline
+func writeLE64Shuffled(b []byte, x uint64) {
+	_ = b[7]
+	b[3] = byte(x >> 24)
+	b[0] = byte(x)
+	b[6] = byte(x >> 48)
+	b[1] = byte(x >> 8)
+	b[7] = byte(x >> 56)
+	b[5] = byte(x >> 40)
+	b[2] = byte(x >> 16)
+	b[4] = byte(x >> 32)
+}
+
+//go:noinline
+func writeBE64Shuffled(b []byte, x uint64) {
+	_ = b[7]
+	b[4] = byte(x >> 24)
+	b[7] = byte(x)
+	b[1] = byte(x >> 48)
+	b[6] = byte(x >> 8)
+	b[0] = byte(x >> 56)
+	b[2] = byte(x >> 40)
+	b[5] = byte(x >> 16)
+	b[3] = byte(x >> 32)
+}
+
+//go:noinline
+func writeBE32Shuffled(b []byte, x uint32) {
+	_ = b[3]
+	b[1] = byte(x >> 16)
+	b[3] = byte(x)
+	b[0] = byte(x >> 24)
+	b[2] = byte(x >> 8)
+}
+
+//go:noinline
+func writeLE32High(b []byte, x uint64) {
+	_ = b[3]
+	b[2] = byte(x >> 48)
+	b[0] = byte(x >> 32)
+	b[3] = byte(x >> 56)
+	b[1] = byte(x >> 40)
+}
+
+//go:noinline
+func writeConst(b []byte) {
+	_ = b[7]
+	b[7], b[2], b[5], b[0], b[3], b[6], b[1], b[4] = 7, 2, 5, 0, 3, 6, 1, 4
+}
+
+//go:noinline
+func copyShuffled(dst, src []byte) {
+	_, _ = dst[7], src[7]
+	dst[6], dst[1], dst[4], dst[3], dst[0], dst[7], dst[2], dst[5] = src[6], src[1], src[4], src[3], src[0], src[7], src[2], src[5]
+}
+
+//go:noinline
+func copyOverlapping(b []byte) {
+	// Each load reads memory written by the previous store,
+	// so the copy can't be done by a single wide load.
+	_ = b[4]
+	b[1] = b[0]
+	b[2] = b[1]
+	b[3] = b[2]
+	b[4] = b[3]
+}
+
+func TestMemCombine(t *testing.T) {
+	b := []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x42}
+	for off := 0; off < 2; off++ { // check unaligned accesses too
+		b := b[off:]
+		if got, want := readLE64Shuffled(b), binary.LittleEndian.Uint64(b); got != want {
+			t.Errorf("readLE64Shuffled = %#x, want %#x", got, want)
+		}
+		if got, want := readBE64Shuffled(b), binary.BigEndian.Uint64(b); got != want {
+			t.Errorf("readBE64Shuffled = %#x, want %#x", got, want)
+		}
+		if got, want := readBE32Shuffled(b), binary.BigEndian.Uint32(b); got != want {
+			t.Errorf("readBE32Shuffled = %#x, want %#x", got, want)
+		}
+		if got, want := readLE32Shifted(b), uint64(binary.LittleEndian.Uint32(b))<<16; got != want {
+			t.Errorf("readLE32Shifted = %#x, want %#x", got, want)
+		}
+		if got, want := readBE16(b, 1), binary.BigEndian.Uint16(b[1:]); got != want {
+			t.Errorf("readBE16 = %#x, want %#x", got, want)
+		}
+	}
+	if got, want := readLE16x4([]uint16{0x0123, 0x4567, 0x89ab, 0xcdef}), uint64(0xcdef89ab45670123); got != want {
+		t.Errorf("readLE16x4 = %#x, want %#x", got, want)
+	}
+
+	x := uint64(0x0123456789abcdef)
+	got, want := make([]byte, 9), make([]byte, 9)
+	check := func(name string) {
+		t.Helper()
+		if string(got) != string(want) {
+			t.Errorf("%s wrote %x, want %x", name, got, want)
+		}
+	}
+	writeLE64Shuffled(got[1:], x)
+	binary.LittleEndian.PutUint64(want[1:], x)
+	check("writeLE64Shuffled")
+	writeBE64Shuffled(got[1:], x)
+	binary.BigEndian.PutUint64(want[1:], x)
+	check("writeBE64Shuffled")
+	writeBE32Shuffled(got[1:], uint32(x))
+	binary.BigEndian.PutUint32(want[1:], uint32(x))
+	check("writeBE32Shuffled")
+	writeLE32High(got[1:], x)
+	binary.LittleEndian.PutUint32(want[1:], uint32(x>>32))
+	check("writeLE32High")
+	writeConst(got[1:])
+	copy(want[1:], []byte{0, 1, 2, 3, 4, 5, 6, 7})
+	check("writeConst")
+	copyShuffled(got[1:], b)
+	copy(want[1:], b[:8])
+	check("copyShuffled")
+
+	got = []byte{1, 2, 3, 4, 5}
+	copyOverlapping(got)
+	want = []byte{1, 1, 1, 1, 1}
+	check("copyOverlapping")
+}
@@ -0,0 +1,116 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"bufio"
+	"fmt"
+	"internal/testenv"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+// testPGODevirtualize tests that specific PGO devirtualize rewrites are
+// performed, and that the rewritten code behaves.
+func testPGODevirtualize(t *testing.T, dir string) {
+	testenv.MustHaveGoRun(t)
+	t.Parallel()
+
+	const pkg = "example.com/pgo/devirtualize"
+
+	// Add a go.mod so we have a consistent symbol names in this temp dir.
+	goMod := fmt.Sprintf(`module %s
+go 1.19
+`, pkg)
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0644); err != nil {
+		t.Fatalf("error writing go.mod: %v", err)
+	}
+
+	// Build the test with the profile.
+	pprof := filepath.Join(dir, "devirt.pprof")
+	gcflag := fmt.Sprintf("-gcflags=-m -pgoprofile=%s", pprof)
+	out := filepath.Join(dir, "test.exe")
+	cmd := testenv.CleanCmdEnv(testenv.Command(t, testenv.GoToolPath(t), "test", "-c", "-o", out, gcflag, "."))
+	cmd.Dir = dir
+
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("error creating pipe: %v", err)
+	}
+	defer pr.Close()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+
+	err = cmd.Start()
+	pw.Close()
+	if err != nil {
+		t.Fatalf("error starting go test: %v", err)
+	}
+
+	want := map[string]string{
+		// ExerciseIface
+		"a.Add": pkg + ".Add.Add",
+		// ExerciseFuncValue
+		"f": pkg + ".AddFn",
+	}
+
+	scanner := bufio.NewScanner(pr)
+	devirtualized := regexp.MustCompile(`: PGO devirtualizing (.*) to (.*)`)
+	for scanner.Scan() {
+		line := scanner.Text()
+		t.Logf("child: %s", line)
+
+		m := devirtualized.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		call, callee := m[1], m[2]
+		if want[call] != callee {
+			t.Errorf("unexpected devirtualization of %s to %s", call, callee)
+			continue
+		}
+		delete(want, call)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatalf("error running go test: %v", err)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("error reading go test output: %v", err)
+	}
+	for call, callee := range want {
+		t.Errorf("%s was not devirtualized to %s", call, callee)
+	}
+
+	// The devirtualized code must take both the direct and the fallback
+	// paths correctly.
+	cmd = testenv.Command(t, out, "-test.run=TestDevirt")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("error running devirtualized test: %v\n%s", err, out)
+	}
+}
+
+// TestPGODevirtualize tests that specific functions are devirtualized when PGO
+// is applied to the exact source that was profiled.
+func TestPGODevirtualize(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("error getting wd: %v", err)
+	}
+	srcDir := filepath.Join(wd, "testdata/pgo/devirtualize")
+
+	// Copy the module to a scratch location so we can add a go.mod.
+	dir := t.TempDir()
+
+	for _, file := range []string{"devirt.go", "devirt_test.go", "devirt.pprof"} {
+		if err := copyFile(filepath.Join(dir, file), filepath.Join(srcDir, file)); err != nil {
+			t.Fatalf("error copying %s: %v", file, err)
+		}
+	}
+
+	testPGODevirtualize(t, dir)
+}
@@ -0,0 +1,71 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"fmt"
+	"internal/testenv"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+// TestPGOLayout tests that the profile drives the branch predictions and
+// the alignment of hot loops, as explained by -d=pgodebug.
+func TestPGOLayout(t *testing.T) {
+	testenv.MustHaveGoRun(t)
+	t.Parallel()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("error getting wd: %v", err)
+	}
+	srcDir := filepath.Join(wd, "testdata/pgo/layout")
+
+	// Copy the module to a scratch location so we can add a go.mod.
+	dir := t.TempDir()
+	for _, file := range []string{"layout.go", "layout_test.go", "layout.pprof"} {
+		if err := copyFile(filepath.Join(dir, file), filepath.Join(srcDir, file)); err != nil {
+			t.Fatalf("error copying %s: %v", file, err)
+		}
+	}
+	const pkg = "example.com/pgo/layout"
+	goMod := fmt.Sprintf(`module %s
+go 1.19
+`, pkg)
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0644); err != nil {
+		t.Fatalf("error writing go.mod: %v", err)
+	}
+
+	pprof := filepath.Join(dir, "layout.pprof")
+	gcflag := fmt.Sprintf("-gcflags=-pgoprofile=%s -d=pgodebug=1", pprof)
+	exe := filepath.Join(dir, "test.exe")
+	cmd := testenv.CleanCmdEnv(testenv.Command(t, testenv.GoToolPath(t), "test", "-c", "-o", exe, gcflag, "."))
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("error building with profile: %v\n%s", err, out)
+	}
+	t.Logf("%s", out)
+
+	want := []*regexp.Regexp{
+		// The loop in Count is hot.
+		regexp.MustCompile(`layout\.go:16:\d+: PGO: aligning hot loop starting at b\d+`),
+		// Zero bytes are rare.
+		regexp.MustCompile(`layout\.go:17:\d+: PGO: b\d+: b\d+ likely \(weight [1-9]\d* vs 0\)`),
+	}
+	for _, re := range want {
+		if !re.Match(out) {
+			t.Errorf("missing %q in output", re)
+		}
+	}
+
+	cmd = testenv.Command(t, exe, "-test.run=TestCount")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("error running test built with profile: %v\n%s", err, out)
+	}
+}
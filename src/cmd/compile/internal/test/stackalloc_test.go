@@ -0,0 +1,116 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !race
+
+package test
+
+import (
+	"internal/testenv"
+	"testing"
+)
+
+//go:noinline
+func makeSum(n int) int {
+	s := make([]int, n)
+	for i := range s {
+		s[i] = i
+	}
+	t := 0
+	for _, v := range s {
+		t += v
+	}
+	return t
+}
+
+//go:noinline
+func makeLenCap(n, c int) int {
+	s := make([]byte, n, c)
+	s = s[:c]
+	for _, v := range s {
+		if v != 0 {
+			panic("not zeroed")
+		}
+		s[0]++
+	}
+	return len(s) + cap(s)
+}
+
+//go:noinline
+func appendLoop(n int) int {
+	var s []uint16
+	for i := 0; i < n; i++ {
+		s = append(s, uint16(i))
+	}
+	t := 0
+	for _, v := range s {
+		t += int(v)
+	}
+	return t
+}
+
+//go:noinline
+func appendSlice(b []byte) int {
+	s := append([]byte(nil), b...)
+	s[0]++
+	return int(s[0]) + len(s)
+}
+
+func TestStackAllocVariableMake(t *testing.T) {
+	testenv.SkipIfOptimizationOff(t)
+	for _, n := range []int{0, 1, 4, 5, 100} {
+		if got, want := makeSum(n), n*(n-1)/2; got != want {
+			t.Errorf("makeSum(%d) = %d, want %d", n, got, want)
+		}
+		if got, want := appendLoop(n), n*(n-1)/2; got != want {
+			t.Errorf("appendLoop(%d) = %d, want %d", n, got, want)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		// The stack buffer is zeroed each time it is used.
+		if got := makeLenCap(1, 3); got != 6 {
+			t.Errorf("makeLenCap(1, 3) = %d, want 6", got)
+		}
+	}
+	b := []byte{1, 2, 3}
+	if got := appendSlice(b); got != 5 || b[0] != 1 {
+		t.Errorf("appendSlice = %d with b[0] = %d, want 5 with 1", got, b[0])
+	}
+
+	for _, tt := range []struct {
+		name string
+		f    func()
+		want float64
+	}{
+		{"make small", func() { makeSum(4) }, 0},
+		{"make large", func() { makeSum(100) }, 1},
+		{"make len cap", func() { makeLenCap(2, 32) }, 0},
+		{"append small", func() { appendLoop(16) }, 0},
+		{"append slice", func() { appendSlice(b) }, 0},
+	} {
+		if n := testing.AllocsPerRun(10, tt.f); n != tt.want {
+			t.Errorf("%s: got %v allocs, want %v", tt.name, n, tt.want)
+		}
+	}
+}
+
+func TestStackAllocVariableMakePanics(t *testing.T) {
+	for _, tt := range []struct {
+		n, c int
+		want string
+	}{
+		{-1, 4, "runtime error: makeslice: len out of range"},
+		{3, 2, "runtime error: makeslice: cap out of range"},
+	} {
+		func() {
+			defer func() {
+				e, _ := recover().(error)
+				if e == nil || e.Error() != tt.want {
+					t.Errorf("make([]byte, %d, %d) panicked with %v, want %q", tt.n, tt.c, e, tt.want)
+				}
+			}()
+			makeLenCap(tt.n, tt.c)
+		}()
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// WARNING: Please avoid updating this file. If this file needs to be updated,
+// then a new devirt.pprof file should be generated:
+//
+//	$ cd $GOROOT/src/cmd/compile/internal/test/testdata/pgo/devirtualize/
+//	$ go mod init example.com/pgo/devirtualize
+//	$ go test -bench=. -cpuprofile ./devirt.pprof
+
+package devirt
+
+type Adder interface {
+	Add(a, b int) int
+}
+
+type Add struct{}
+
+func (Add) Add(a, b int) int {
+	for i := 0; i < 1000; i++ {
+		a += b
+	}
+	return a
+}
+
+type Sub struct{}
+
+func (Sub) Add(a, b int) int {
+	for i := 0; i < 1000; i++ {
+		a -= b
+	}
+	return a
+}
+
+//go:noinline
+func ExerciseIface(iter int, a1, a2 Adder) int {
+	val := 0
+	for i := 0; i < iter; i++ {
+		a := a1
+		if i%10 == 0 {
+			a = a2
+		}
+		val += a.Add(i, 1)
+	}
+	return val
+}
+
+func AddFn(a, b int) int {
+	for i := 0; i < 1000; i++ {
+		a += b
+	}
+	return a
+}
+
+func SubFn(a, b int) int {
+	for i := 0; i < 1000; i++ {
+		a -= b
+	}
+	return a
+}
+
+//go:noinline
+func ExerciseFuncValue(iter int, f1, f2 func(a, b int) int) int {
+	val := 0
+	for i := 0; i < iter; i++ {
+		f := f1
+		if i%10 == 0 {
+			f = f2
+		}
+		val += f(i, 1)
+	}
+	return val
+}
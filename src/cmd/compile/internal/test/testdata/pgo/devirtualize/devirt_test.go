@@ -0,0 +1,55 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// WARNING: Please avoid updating this file. If this file needs to be updated,
+// then a new devirt.pprof file should be generated:
+//
+//	$ cd $GOROOT/src/cmd/compile/internal/test/testdata/pgo/devirtualize/
+//	$ go mod init example.com/pgo/devirtualize
+//	$ go test -bench=. -cpuprofile ./devirt.pprof
+
+package devirt
+
+import "testing"
+
+func BenchmarkDevirtIface(b *testing.B) {
+	ExerciseIface(b.N, Add{}, Sub{})
+}
+
+func BenchmarkDevirtFuncValue(b *testing.B) {
+	ExerciseFuncValue(b.N, AddFn, SubFn)
+}
+
+func TestDevirt(t *testing.T) {
+	const iter = 100
+	want := 0
+	for i := 0; i < iter; i++ {
+		if i%10 == 0 {
+			want += i - 1000
+		} else {
+			want += i + 1000
+		}
+	}
+	if got := ExerciseIface(iter, Add{}, Sub{}); got != want {
+		t.Errorf("ExerciseIface got %d want %d", got, want)
+	}
+	if got := ExerciseFuncValue(iter, AddFn, SubFn); got != want {
+		t.Errorf("ExerciseFuncValue got %d want %d", got, want)
+	}
+	// Swap the operands, so that the fallback calls are the common ones.
+	want = 0
+	for i := 0; i < iter; i++ {
+		if i%10 == 0 {
+			want += i + 1000
+		} else {
+			want += i - 1000
+		}
+	}
+	if got := ExerciseIface(iter, Sub{}, Add{}); got != want {
+		t.Errorf("ExerciseIface got %d want %d", got, want)
+	}
+	if got := ExerciseFuncValue(iter, SubFn, AddFn); got != want {
+		t.Errorf("ExerciseFuncValue got %d want %d", got, want)
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// WARNING: Please avoid updating this file. If this file needs to be updated,
+// then a new layout.pprof file should be generated:
+//
+//	$ cd $GOROOT/src/cmd/compile/internal/test/testdata/pgo/layout/
+//	$ go mod init example.com/pgo/layout
+//	$ go test -bench=. -cpuprofile ./layout.pprof
+
+package layout
+
+//go:noinline
+func Count(b []byte) (n, zeros int) {
+	for _, c := range b {
+		if c == 0 {
+			n += int(c) * 3
+			zeros++
+		} else {
+			n++
+		}
+	}
+	return n, zeros
+}
@@ -0,0 +1,31 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// WARNING: Please avoid updating this file. If this file needs to be updated,
+// then a new layout.pprof file should be generated:
+//
+//	$ cd $GOROOT/src/cmd/compile/internal/test/testdata/pgo/layout/
+//	$ go mod init example.com/pgo/layout
+//	$ go test -bench=. -cpuprofile ./layout.pprof
+
+package layout
+
+import "testing"
+
+func BenchmarkCount(b *testing.B) {
+	buf := make([]byte, 4096)
+	for i := range buf {
+		buf[i] = byte(i%251 + 1)
+	}
+	for i := 0; i < b.N; i++ {
+		Count(buf)
+	}
+}
+
+func TestCount(t *testing.T) {
+	n, zeros := Count([]byte{0, 1, 2, 0})
+	if n != 2 || zeros != 2 {
+		t.Errorf("Count got %d, %d want 2, 2", n, zeros)
+	}
+}
@@ -278,5 +278,6 @@ func addCovMeta(p unsafe.Pointer, len uint32, hash [16]byte, pkpath string, pkgI
 var x86HasPOPCNT bool
 var x86HasSSE41 bool
 var x86HasFMA bool
+var x86HasAVX2 bool
 var armHasVFPv4 bool
 var arm64HasATOMICS bool
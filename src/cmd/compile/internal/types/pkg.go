@@ -55,6 +55,12 @@ func NewPkg(path, name string) *Pkg {
 	return p
 }
 
+// PkgMap returns the map from package path to package. It must not
+// be modified.
+func PkgMap() map[string]*Pkg {
+	return pkgMap
+}
+
 // ImportedPkgList returns the list of directly imported packages.
 // The list is sorted by package path.
 func ImportedPkgList() []*Pkg {
@@ -606,7 +606,7 @@ func PtrDataSize(t *Type) int64 {
 		return 0
 
 	case TSSA:
-		if t != TypeInt128 {
+		if t != TypeInt128 && !t.IsSIMD() {
 			base.Fatalf("PtrDataSize: unexpected ssa type %v", t)
 		}
 		return 0
@@ -1078,8 +1078,11 @@ func (t *Type) ArgWidth() int64 {
 
 func (t *Type) Size() int64 {
 	if t.kind == TSSA {
-		if t == TypeInt128 {
+		switch t {
+		case TypeInt128, TypeVec128:
 			return 16
+		case TypeVec256:
+			return 32
 		}
 		return 0
 	}
@@ -1432,6 +1435,12 @@ func (t *Type) IsFloat() bool {
 	return t.kind == TFLOAT32 || t.kind == TFLOAT64 || t == UntypedFloat
 }
 
+// IsSIMD reports whether t is one of the SSA vector types, TypeVec128
+// and TypeVec256, the values of which live in vector registers.
+func (t *Type) IsSIMD() bool {
+	return t == TypeVec128 || t == TypeVec256
+}
+
 func (t *Type) IsComplex() bool {
 	return t.kind == TCOMPLEX64 || t.kind == TCOMPLEX128 || t == UntypedComplex
 }
@@ -1690,12 +1699,18 @@ var (
 	TypeFlags     = newSSA("flags")
 	TypeVoid      = newSSA("void")
 	TypeInt128    = newSSA("int128")
+	TypeVec128    = newSSA("vec128")
+	TypeVec256    = newSSA("vec256")
 	TypeResultMem = newResults([]*Type{TypeMem})
 )
 
 func init() {
 	TypeInt128.width = 16
 	TypeInt128.align = 8
+	TypeVec128.width = 16
+	TypeVec128.align = 8
+	TypeVec256.width = 32
+	TypeVec256.align = 8
 }
 
 // NewNamed returns a new named type for the given type name. obj should be an
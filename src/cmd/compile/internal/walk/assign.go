@@ -517,9 +517,11 @@ func appendSlice(n *ir.CallExpr, init *ir.Nodes) ir.Node {
 	fn := typecheck.LookupRuntime("growslice")
 	fn = typecheck.SubstArgTypes(fn, elemtype, elemtype)
 
+	appendStackBuf(n)
+
 	// else { s = growslice(oldPtr, newLen, oldCap, num, T) }
 	call := mkcall1(fn, s.Type(), nif.PtrInit(), oldPtr, newLen, oldCap, num, reflectdata.TypePtr(elemtype))
-	nif.Else = []ir.Node{ir.NewAssignStmt(base.Pos, s, call)}
+	nif.Else = appendGrow(n, s, newLen, call)
 
 	nodes.Append(nif)
 
@@ -70,6 +70,8 @@ func walkAppend(n *ir.CallExpr, init *ir.Nodes, dst ir.Node) ir.Node {
 		return nsrc
 	}
 
+	appendStackBuf(n)
+
 	// General case, with no function calls left as arguments.
 	// Leave for ssagen, except that instrumentation requires the old form.
 	if !base.Flag.Cfg.Instrumenting || base.Flag.CompilingRuntime {
@@ -105,14 +107,12 @@ func walkAppend(n *ir.CallExpr, init *ir.Nodes, dst ir.Node) ir.Node {
 	fn = typecheck.SubstArgTypes(fn, s.Type().Elem(), s.Type().Elem())
 
 	// else { s = growslice(s.ptr, n, s.cap, a, T) }
-	nif.Else = []ir.Node{
-		ir.NewAssignStmt(base.Pos, s, mkcall1(fn, s.Type(), nif.PtrInit(),
-			ir.NewUnaryExpr(base.Pos, ir.OSPTR, s),
-			newLen,
-			ir.NewUnaryExpr(base.Pos, ir.OCAP, s),
-			num,
-			reflectdata.TypePtr(s.Type().Elem()))),
-	}
+	nif.Else = appendGrow(n, s, newLen, mkcall1(fn, s.Type(), nif.PtrInit(),
+		ir.NewUnaryExpr(base.Pos, ir.OSPTR, s),
+		newLen,
+		ir.NewUnaryExpr(base.Pos, ir.OCAP, s),
+		num,
+		reflectdata.TypePtr(s.Type().Elem())))
 
 	l = append(l, nif)
 
@@ -130,6 +130,59 @@ func walkAppend(n *ir.CallExpr, init *ir.Nodes, dst ir.Node) ir.Node {
 	return s
 }
 
+// appendStackBuf sets up the stack array that the append n grows
+// into the first time its slice is full, if n does not escape and the
+// array can hold the appended elements. The array is used at most once
+// per call, so escape analysis treats it as living until the function
+// returns, even if n is in a loop. Later growth uses the heap.
+func appendStackBuf(n *ir.CallExpr) {
+	elem := n.Type().Elem()
+	k := escape.StackBufLen(elem)
+	if n.Esc() != ir.EscNone || k == 0 || !n.IsDDD && int64(len(n.Args)-1) > k {
+		return
+	}
+	n.StackBuf = typecheck.Temp(types.NewArray(elem, k))
+	n.StackBuf.SetAddrtaken(true)
+	n.StackBufUsed = typecheck.Temp(types.Types[types.TBOOL])
+	appendWalkStmt(&ir.CurFunc.Enter, ir.NewAssignStmt(base.Pos, n.StackBufUsed, ir.NewBool(false)))
+}
+
+// appendGrow returns the statements that grow the slice s of the
+// append n to newLen elements when it is full, given the call that
+// grows it on the heap. With a stack array set up by appendStackBuf,
+// they are
+//
+//	if !used && cap(s) == 0 && uint(newLen) <= K {
+//		used = true
+//		buf = [K]T{}
+//		s = buf[:newLen:K]
+//	} else {
+//		s = growslice(...)
+//	}
+func appendGrow(n *ir.CallExpr, s, newLen, grow ir.Node) []ir.Node {
+	heap := ir.NewAssignStmt(base.Pos, s, grow)
+	if n.StackBuf == nil {
+		return []ir.Node{heap}
+	}
+	buf, used := n.StackBuf, n.StackBufUsed
+	k := buf.Type().NumElem()
+	cond := ir.NewLogicalExpr(base.Pos, ir.OANDAND,
+		ir.NewUnaryExpr(base.Pos, ir.ONOT, used),
+		ir.NewLogicalExpr(base.Pos, ir.OANDAND,
+			ir.NewBinaryExpr(base.Pos, ir.OEQ, ir.NewUnaryExpr(base.Pos, ir.OCAP, s), ir.NewInt(0)),
+			ir.NewBinaryExpr(base.Pos, ir.OLE, typecheck.Conv(newLen, types.Types[types.TUINT]), ir.NewInt(k))))
+	slice := ir.NewSliceExpr(base.Pos, ir.OSLICE3, buf, nil, newLen, ir.NewInt(k))
+	slice.SetBounded(true)
+	nif := ir.NewIfStmt(base.Pos, cond, nil, nil)
+	nif.Body = []ir.Node{
+		ir.NewAssignStmt(base.Pos, used, ir.NewBool(true)),
+		ir.NewAssignStmt(base.Pos, buf, nil),
+		ir.NewAssignStmt(base.Pos, s, slice),
+	}
+	nif.Else = []ir.Node{heap}
+	return []ir.Node{nif}
+}
+
 // walkClose walks an OCLOSE node.
 func walkClose(n *ir.UnaryExpr, init *ir.Nodes) ir.Node {
 	// cannot use chanfn - closechan takes any, not chan any
@@ -390,6 +443,10 @@ func walkMakeSlice(n *ir.MakeExpr, init *ir.Nodes) ir.Node {
 		if why := escape.HeapAllocReason(n); why != "" {
 			base.Fatalf("%v has EscNone, but %v", n, why)
 		}
+		if !ir.IsSmallIntConst(r) {
+			return walkMakeSliceStack(n, l, r, init)
+		}
+
 		// var arr [r]T
 		// n = arr[:l]
 		i := typecheck.IndexConst(r)
@@ -444,6 +501,50 @@ func walkMakeSlice(n *ir.MakeExpr, init *ir.Nodes) ir.Node {
 	return walkExpr(typecheck.Expr(sh), init)
 }
 
+// walkMakeSliceStack walks a non-escaping OMAKESLICE node whose
+// capacity is not constant. Small slices live in a stack array, and
+// larger ones on the heap:
+//
+//	var arr [K]T
+//	if uint64(cap) <= K && uint64(len) <= uint64(cap) {
+//		arr = [K]T{}
+//		s = arr[:len:cap]
+//	} else {
+//		s = makeslice(T, len, cap) // also panics for bad len or cap
+//	}
+func walkMakeSliceStack(n *ir.MakeExpr, l, r ir.Node, init *ir.Nodes) ir.Node {
+	t := n.Type()
+	k := escape.StackBufLen(t.Elem())
+	l = cheapExpr(l, init)
+	r = cheapExpr(r, init)
+
+	u64 := types.Types[types.TUINT64]
+	nif := ir.NewIfStmt(base.Pos, ir.NewLogicalExpr(base.Pos, ir.OANDAND,
+		ir.NewBinaryExpr(base.Pos, ir.OLE, typecheck.Conv(r, u64), ir.NewInt(k)),
+		ir.NewBinaryExpr(base.Pos, ir.OLE, typecheck.Conv(l, u64), typecheck.Conv(r, u64))), nil, nil)
+	nif.Likely = true
+
+	s := typecheck.Temp(t)
+	arr := typecheck.Temp(types.NewArray(t.Elem(), k))
+	slice := ir.NewSliceExpr(base.Pos, ir.OSLICE3, arr, nil, l, r)
+	slice.SetBounded(true)
+	nif.Body = []ir.Node{
+		ir.NewAssignStmt(base.Pos, arr, nil),
+		// The conv is necessary in case t is named.
+		ir.NewAssignStmt(base.Pos, s, typecheck.Conv(slice, t)),
+	}
+
+	mk := ir.NewMakeExpr(base.Pos, ir.OMAKESLICE, l, r)
+	mk.RType = n.RType
+	mk.SetType(t)
+	mk.SetTypecheck(1)
+	mk.SetEsc(ir.EscHeap)
+	nif.Else = []ir.Node{ir.NewAssignStmt(base.Pos, s, mk)}
+
+	appendWalkStmt(init, nif)
+	return s
+}
+
 // walkMakeSliceCopy walks an OMAKESLICECOPY node.
 func walkMakeSliceCopy(n *ir.MakeExpr, init *ir.Nodes) ir.Node {
 	if n.Esc() == ir.EscNone {
@@ -552,30 +552,10 @@ func walkCall(n *ir.CallExpr, init *ir.Nodes) ir.Node {
 		case "FuncPCABIInternal":
 			wantABI = obj.ABIInternal
 		}
-		if isIfaceOfFunc(arg) {
-			fn := arg.(*ir.ConvExpr).X.(*ir.Name)
-			abi := fn.Func.ABI
-			if abi != wantABI {
-				base.ErrorfAt(n.Pos(), "internal/abi.%s expects an %v function, %s is defined as %v", name, wantABI, fn.Sym().Name, abi)
-			}
-			var e ir.Node = ir.NewLinksymExpr(n.Pos(), fn.Sym().LinksymABI(abi), types.Types[types.TUINTPTR])
-			e = ir.NewAddrExpr(n.Pos(), e)
-			e.SetType(types.Types[types.TUINTPTR].PtrTo())
-			return typecheck.Expr(ir.NewConvExpr(n.Pos(), ir.OCONVNOP, n.Type(), e))
-		}
-		// fn is not a defined function. It must be ABIInternal.
-		// Read the address from func value, i.e. *(*uintptr)(idata(fn)).
-		if wantABI != obj.ABIInternal {
-			base.ErrorfAt(n.Pos(), "internal/abi.%s does not accept func expression, which is ABIInternal", name)
+		if ir.IsIfaceOfFunc(arg) == nil {
+			arg = walkExpr(arg, init)
 		}
-		arg = walkExpr(arg, init)
-		var e ir.Node = ir.NewUnaryExpr(n.Pos(), ir.OIDATA, arg)
-		e.SetType(n.Type().PtrTo())
-		e.SetTypecheck(1)
-		e = ir.NewStarExpr(n.Pos(), e)
-		e.SetType(n.Type())
-		e.SetTypecheck(1)
-		return e
+		return ir.FuncPC(n.Pos(), arg, wantABI)
 	}
 
 	walkCall1(n, init)
@@ -938,16 +918,33 @@ func bounded(n ir.Node, max int64) bool {
 	return false
 }
 
-// usemethod checks calls for uses of reflect.Type.{Method,MethodByName}.
+// usemethod checks calls for uses of reflect.Type.{Method,MethodByName}
+// and reflect.Value.{Method,MethodByName}.
 func usemethod(n *ir.CallExpr) {
 	// Don't mark reflect.(*rtype).Method, etc. themselves in the reflect package.
 	// Those functions may be alive via the itab, which should not cause all methods
 	// alive. We only want to mark their callers.
+	// The same goes for the method wrappers of types embedding *rtype and of
+	// *reflect.Value (the linker checks reflect.Value's methods itself), but
+	// not for the wrappers of method expressions like reflect.Type.Method,
+	// which make an interface call.
+	// reflect.Value.MethodByName only forwards its argument; its callers are
+	// marked instead, so that a constant name can be tracked. The methods
+	// StructOf creates for embedded interfaces are only called through the
+	// methods of the new struct type, whose callers are marked.
 	if base.Ctxt.Pkgpath == "reflect" {
-		switch ir.CurFunc.Nname.Sym().Name { // TODO: is there a better way than hardcoding the names?
-		case "(*rtype).Method", "(*rtype).MethodByName", "(*interfaceType).Method", "(*interfaceType).MethodByName":
+		if ir.CurFunc.Wrapper() && n.Op() != ir.OCALLINTER {
 			return
 		}
+		switch name := ir.CurFunc.Nname.Sym().Name; name { // TODO: is there a better way than hardcoding the names?
+		case "(*rtype).Method", "(*rtype).MethodByName", "(*interfaceType).Method", "(*interfaceType).MethodByName",
+			"Value.MethodByName":
+			return
+		default:
+			if strings.HasPrefix(name, "StructOf.func") {
+				return
+			}
+		}
 	}
 
 	dot, ok := n.X.(*ir.SelectorExpr)
@@ -956,8 +953,10 @@ func usemethod(n *ir.CallExpr) {
 	}
 
 	// Looking for either direct method calls and interface method calls of:
-	//	reflect.Type.Method       - func(int) reflect.Method
-	//	reflect.Type.MethodByName - func(string) (reflect.Method, bool)
+	//	reflect.Type.Method        - func(int) reflect.Method
+	//	reflect.Type.MethodByName  - func(string) (reflect.Method, bool)
+	//	reflect.Value.Method       - func(int) reflect.Value
+	//	reflect.Value.MethodByName - func(string) reflect.Value
 	var pKind types.Kind
 
 	switch dot.Sel.Name {
@@ -984,13 +983,34 @@ func usemethod(n *ir.CallExpr) {
 		return
 	}
 
-	// Check that first result type is "reflect.Method". Note that we have to check sym name and sym package
-	// separately, as we can't check for exact string "reflect.Method" reliably (e.g., see #19028 and #38515).
-	if s := t.Results().Field(0).Type.Sym(); s != nil && s.Name == "Method" && types.IsReflectPkg(s.Pkg) {
-		ir.CurFunc.SetReflectMethod(true)
-		// The LSym is initialized at this point. We need to set the attribute on the LSym.
-		ir.CurFunc.LSym.Set(obj.AttrReflectMethod, true)
+	// Check that first result type is "reflect.Method" or "reflect.Value". Note that we have to check
+	// sym name and sym package separately, as we can't check for exact string "reflect.Method" reliably
+	// (e.g., see #19028 and #38515).
+	s := t.Results().Field(0).Type.Sym()
+	if s == nil || !types.IsReflectPkg(s.Pkg) || (s.Name != "Method" && s.Name != "Value") {
+		return
+	}
+
+	if pKind == types.TSTRING {
+		// For a method call the receiver is the first argument.
+		arg := n.Args[0]
+		if n.Op() != ir.OCALLINTER {
+			arg = n.Args[1]
+		}
+		if ir.IsConst(arg, constant.String) {
+			// MethodByName with a constant name can only find exported
+			// methods of that name. Tell the linker to keep those instead
+			// of all exported methods.
+			if name := ir.StringVal(arg); types.IsExported(name) {
+				reflectdata.MarkUsedMethodName(name)
+			}
+			return
+		}
 	}
+
+	ir.CurFunc.SetReflectMethod(true)
+	// The LSym is initialized at this point. We need to set the attribute on the LSym.
+	ir.CurFunc.LSym.Set(obj.AttrReflectMethod, true)
 }
 
 func usefield(n *ir.SelectorExpr) {
@@ -54,6 +54,7 @@ var bootstrapDirs = []string{
 	"cmd/link/internal/...",
 	"compress/flate",
 	"compress/zlib",
+	"compress/zstd",
 	"container/heap",
 	"debug/dwarf",
 	"debug/elf",
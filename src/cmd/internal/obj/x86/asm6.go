@@ -2129,6 +2129,21 @@ func span6(ctxt *obj.Link, s *obj.LSym, newprog obj.ProgAlloc) {
 				}
 			}
 
+			if p.As == obj.APCALIGN {
+				// Pad with NOPs to the requested alignment.
+				a := p.From.Offset
+				if !(a&(a-1) == 0 && 8 <= a && a <= 64) {
+					ctxt.Diag("alignment value of an instruction must be a power of two and in the range [8, 64], got %d\n", a)
+				} else {
+					if v := int32(-int64(c) & (a - 1)); v > 0 {
+						c = noppad(ctxt, s, c, v)
+					}
+					if int32(a) > s.Func().Align {
+						s.Func().Align = int32(a)
+					}
+				}
+			}
+
 			p.Pc = int64(c)
 
 			// process forward jumps to p
@@ -2153,7 +2168,11 @@ func span6(ctxt *obj.Link, s *obj.LSym, newprog obj.ProgAlloc) {
 			p.Rel = nil
 
 			p.Pc = int64(c)
-			ab.asmins(ctxt, s, p)
+			if p.As == obj.APCALIGN {
+				ab.Reset()
+			} else {
+				ab.asmins(ctxt, s, p)
+			}
 			m := ab.Len()
 			if int(p.Isize) != m {
 				p.Isize = uint8(m)
@@ -93,11 +93,16 @@ const (
 	// This is a marker relocation (0-sized), for the linker's reachabililty
 	// analysis.
 	R_USEIFACEMETHOD
-	// Similar to R_USEIFACEMETHOD, except instead of indicating a type +
-	// method offset with Sym+Add, Sym points to a symbol containing the name
-	// of the method being called. See the description in
-	// cmd/compile/internal/reflectdata/reflect.go:MarkUsedIfaceMethod for details.
-	R_USEGENERICIFACEMETHOD
+	// R_USENAMEDMETHOD marks that methods with a specific name must not be
+	// eliminated. Instead of indicating a type + method offset with Sym+Add
+	// like R_USEIFACEMETHOD, Sym points to a symbol containing the name of
+	// the method. The method may be called through a generic interface or
+	// looked up by reflect.{Type,Value}.MethodByName with a constant name.
+	// See the description in cmd/compile/internal/reflectdata/reflect.go:
+	// MarkUsedIfaceMethod and MarkUsedMethodName for details.
+	// This is a marker relocation (0-sized), for the linker's reachabililty
+	// analysis.
+	R_USENAMEDMETHOD
 	// R_METHODOFF resolves to a 32-bit offset from the beginning of the section
 	// holding the data being relocated to the referenced symbol.
 	// It is a variant of R_ADDROFF used when linking from the uncommonType of a
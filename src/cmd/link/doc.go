@@ -53,8 +53,10 @@ Flags:
 		Set build mode (default exe).
 	-c
 		Dump call graphs.
-	-compressdwarf
-		Compress DWARF if possible (default true).
+	-compressdwarf=method
+		Compress DWARF if possible, using method zlib or zstd (default zlib).
+		-compressdwarf=true means zlib, and -compressdwarf=false disables compression.
+		Zstandard compression is only supported for ELF; other formats use zlib.
 	-cpuprofile file
 		Write CPU profile to file.
 	-d
@@ -68,6 +70,24 @@ Flags:
 		Debug trampolines.
 	-dumpdep
 		Dump symbol dependency graph.
+	-dumpmethods
+		Dump the reason each method of a reachable type is kept, after the
+		chain of symbols through which the symbol using it was reached.
+	-dwarf mode
+		Set where DWARF is written: inline, in the output file (default),
+		or split, in a separate debug file named by -dwarffile, leaving
+		only a .gnu_debuglink section naming it in the output file.
+		The debug file has the section headers and notes of the output file,
+		including any GNU build ID, like the output of objcopy --only-keep-debug.
+		If the -buildid flag is set and -B is not, split also adds a GNU build ID
+		derived from the Go build ID, so that debuggers can find the debug file
+		in a store such as /usr/lib/debug/.build-id.
+		Split is only supported for ELF, with internal linking. It does not
+		produce split DWARF (.dwo) files.
+	-dwarffile file
+		Write split DWARF to file (default the output file name plus ".debug").
+		When linking through the go command, which links in a temporary
+		directory, set this to an absolute path.
 	-extar ar
 		Set the external archive program (default "ar").
 		Used only for -buildmode=c-archive.
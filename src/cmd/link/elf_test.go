@@ -7,6 +7,7 @@
 package main
 
 import (
+	"debug/dwarf"
 	"debug/elf"
 	"fmt"
 	"internal/platform"
@@ -495,3 +496,121 @@ func TestIssue51939(t *testing.T) {
 		}
 	}
 }
+
+func TestSplitDWARF(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	if platform.MustLinkExternal(runtime.GOOS, runtime.GOARCH) {
+		t.Skipf("-dwarf=split requires internal linking, which %s/%s does not support", runtime.GOOS, runtime.GOARCH)
+	}
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "split.go")
+	if err := os.WriteFile(src, []byte(goSource), 0444); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, compress := range []string{"false", "zlib", "zstd"} {
+		compress := compress
+		t.Run(compress, func(t *testing.T) {
+			t.Parallel()
+
+			exe := filepath.Join(dir, compress+".exe")
+			debug := filepath.Join(dir, compress+".debug")
+			cmd := testenv.Command(t, testenv.GoToolPath(t), "build", "-o", exe,
+				"-ldflags=-dwarf=split -dwarffile="+debug+" -compressdwarf="+compress, src)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("%v: %v\n%s", cmd, err, out)
+			}
+
+			ef, err := elf.Open(exe)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ef.Close()
+			df, err := elf.Open(debug)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer df.Close()
+
+			if s := ef.Section(".debug_info"); s != nil {
+				t.Errorf("executable has a %s section", s.Name)
+			}
+			if ef.Section(".gnu_debuglink") == nil {
+				t.Errorf("executable has no .gnu_debuglink section")
+			}
+			info := df.Section(".debug_info")
+			if info == nil {
+				t.Fatalf("debug file has no .debug_info section")
+			}
+			if compressed := info.Flags&elf.SHF_COMPRESSED != 0; compressed != (compress != "false") {
+				t.Errorf(".debug_info has flags %v with -compressdwarf=%s", info.Flags, compress)
+			} else if compressed {
+				// Check the compression type in the section's header.
+				hdr := make([]byte, 4)
+				f, err := os.Open(debug)
+				if err != nil {
+					t.Fatal(err)
+				}
+				_, err = f.ReadAt(hdr, int64(info.Offset))
+				f.Close()
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := elf.COMPRESS_ZLIB
+				if compress == "zstd" {
+					want = elf.COMPRESS_ZSTD
+				}
+				if got := elf.CompressionType(ef.ByteOrder.Uint32(hdr)); got != want {
+					t.Errorf(".debug_info compressed with %v, want %v", got, want)
+				}
+			}
+
+			// The go command passes a Go build ID, from which the
+			// linker derives a GNU build ID for both files.
+			var ids [2][]byte
+			for i, f := range []*elf.File{ef, df} {
+				s := f.Section(".note.gnu.build-id")
+				if s == nil {
+					t.Fatalf("no GNU build ID")
+				}
+				if ids[i], err = s.Data(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if string(ids[0]) != string(ids[1]) {
+				t.Errorf("executable has build ID note %x, debug file has %x", ids[0], ids[1])
+			}
+
+			// debug/elf finds the debug file by the .gnu_debuglink section.
+			lf, err := ef.OpenDebugFile(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer lf.Close()
+			d, err := lf.DWARF()
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := d.Reader()
+			found := false
+			for {
+				e, err := r.Next()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if e == nil {
+					break
+				}
+				if e.Tag == dwarf.TagSubprogram && e.Val(dwarf.AttrName) == "main.main" {
+					found = true
+					break
+				}
+			}
+			if !found {
+				t.Errorf("main.main not found in DWARF of %s", exe)
+			}
+		})
+	}
+}
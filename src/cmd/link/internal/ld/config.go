@@ -175,6 +175,75 @@ func (mode *LinkMode) String() string {
 	return fmt.Sprintf("LinkMode(%d)", uint8(*mode))
 }
 
+// dwarfCompression is the compression method used for DWARF sections,
+// as set by the -compressdwarf flag.
+type dwarfCompression uint8
+
+const (
+	dwarfCompressNone dwarfCompression = iota
+	dwarfCompressZlib
+	dwarfCompressZstd // ELF only; other formats use zlib
+)
+
+func (c *dwarfCompression) Set(s string) error {
+	switch s {
+	default:
+		return fmt.Errorf("must be true, false, zlib or zstd")
+	case "false", "0", "none":
+		*c = dwarfCompressNone
+	case "true", "1", "zlib":
+		*c = dwarfCompressZlib
+	case "zstd":
+		*c = dwarfCompressZstd
+	}
+	return nil
+}
+
+func (c *dwarfCompression) String() string {
+	switch *c {
+	case dwarfCompressNone:
+		return "false"
+	case dwarfCompressZlib:
+		return "zlib"
+	case dwarfCompressZstd:
+		return "zstd"
+	}
+	return fmt.Sprintf("dwarfCompression(%d)", uint8(*c))
+}
+
+// IsBoolFlag lets -compressdwarf be used without a value, meaning zlib.
+func (c *dwarfCompression) IsBoolFlag() bool { return true }
+
+// dwarfMode is where DWARF is written, as set by the -dwarf flag.
+type dwarfMode uint8
+
+const (
+	dwarfInline dwarfMode = iota // in the output file
+	dwarfSplit                   // in a separate debug file
+)
+
+func (mode *dwarfMode) Set(s string) error {
+	switch s {
+	default:
+		return fmt.Errorf("invalid dwarf mode: %q", s)
+	case "inline":
+		*mode = dwarfInline
+	case "split":
+		*mode = dwarfSplit
+	}
+	return nil
+}
+
+func (mode *dwarfMode) String() string {
+	switch *mode {
+	case dwarfInline:
+		return "inline"
+	case dwarfSplit:
+		return "split"
+	}
+	return fmt.Sprintf("dwarfMode(%d)", uint8(*mode))
+}
+
 // mustLinkExternal reports whether the program being linked requires
 // the external linker be used to complete the link.
 func mustLinkExternal(ctxt *Link) (res bool, reason string) {
@@ -40,9 +40,11 @@ import (
 	"cmd/link/internal/loadpe"
 	"cmd/link/internal/sym"
 	"compress/zlib"
+	"compress/zstd"
 	"debug/elf"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"sort"
@@ -2863,18 +2865,27 @@ func compressSyms(ctxt *Link, syms []loader.Sym) []byte {
 		total += ldr.SymSize(sym)
 	}
 
+	// Zstandard is only used for ELF, which records the compression
+	// method in the section header. The .zdebug sections of other
+	// formats are always zlib compressed.
+	useZstd := ctxt.IsELF && ctxt.compressDWARF == dwarfCompressZstd
+
 	var buf bytes.Buffer
 	if ctxt.IsELF {
+		ctype := elf.COMPRESS_ZLIB
+		if useZstd {
+			ctype = elf.COMPRESS_ZSTD
+		}
 		switch ctxt.Arch.PtrSize {
 		case 8:
 			binary.Write(&buf, ctxt.Arch.ByteOrder, elf.Chdr64{
-				Type:      uint32(elf.COMPRESS_ZLIB),
+				Type:      uint32(ctype),
 				Size:      uint64(total),
 				Addralign: uint64(ctxt.Arch.Alignment),
 			})
 		case 4:
 			binary.Write(&buf, ctxt.Arch.ByteOrder, elf.Chdr32{
-				Type:      uint32(elf.COMPRESS_ZLIB),
+				Type:      uint32(ctype),
 				Size:      uint32(total),
 				Addralign: uint32(ctxt.Arch.Alignment),
 			})
@@ -2894,7 +2905,14 @@ func compressSyms(ctxt *Link, syms []loader.Sym) []byte {
 	// compression levels of zlib.DefaultCompression, but takes
 	// substantially less time. This is important because DWARF
 	// compression can be a significant fraction of link time.
-	z, err := zlib.NewWriterLevel(&buf, zlib.BestSpeed)
+	// The same holds for zstd.BestSpeed.
+	var z io.WriteCloser
+	var err error
+	if useZstd {
+		z, err = zstd.NewWriterLevel(&buf, zstd.BestSpeed)
+	} else {
+		z, err = zlib.NewWriterLevel(&buf, zlib.BestSpeed)
+	}
 	if err != nil {
 		log.Fatalf("NewWriterLevel failed: %s", err)
 	}
@@ -12,6 +12,7 @@ import (
 	"cmd/link/internal/sym"
 	"fmt"
 	"internal/buildcfg"
+	"strings"
 	"unicode"
 )
 
@@ -22,20 +23,25 @@ type deadcodePass struct {
 	ldr  *loader.Loader
 	wq   heap // work queue, using min-heap for better locality
 
-	ifaceMethod        map[methodsig]bool // methods called from reached interface call sites
-	genericIfaceMethod map[string]bool    // names of methods called from reached generic interface call sites
-	markableMethods    []methodref        // methods of reached types
-	reflectSeen        bool               // whether we have seen a reflect method call
-	dynlink            bool
+	ifaceMethod     map[methodsig]loader.Sym // methods called from reached interface call sites, and the first caller
+	namedMethod     map[string]loader.Sym    // names of methods called from reached generic interface call sites or looked up by constant name, and the first user
+	markableMethods []methodref              // methods of reached types
+	reflectSeen     bool                     // whether we have seen a reflect method call
+	reflectSym      loader.Sym               // the symbol that caused reflectSeen, if any
+	dynlink         bool
+
+	methSym, methByNameSym loader.Sym // reflect.Value.Method and reflect.Value.MethodByName
 
 	methodsigstmp []methodsig // scratch buffer for decoding method signatures
 }
 
 func (d *deadcodePass) init() {
 	d.ldr.InitReachable()
-	d.ifaceMethod = make(map[methodsig]bool)
-	d.genericIfaceMethod = make(map[string]bool)
-	if buildcfg.Experiment.FieldTrack {
+	d.ifaceMethod = make(map[methodsig]loader.Sym)
+	d.namedMethod = make(map[string]loader.Sym)
+	d.methSym = d.ldr.Lookup("reflect.Value.Method", abiInternalVer)
+	d.methByNameSym = d.ldr.Lookup("reflect.Value.MethodByName", abiInternalVer)
+	if buildcfg.Experiment.FieldTrack || *flagDumpMethods {
 		d.ldr.Reachparent = make([]loader.Sym, d.ldr.NSym())
 	}
 	d.dynlink = d.ctxt.DynlinkingGo()
@@ -112,7 +118,9 @@ func (d *deadcodePass) flood() {
 	for !d.wq.empty() {
 		symIdx := d.wq.pop()
 
-		d.reflectSeen = d.reflectSeen || d.ldr.IsReflectMethod(symIdx)
+		if d.ldr.IsReflectMethod(symIdx) {
+			d.seeReflect(symIdx)
+		}
 
 		isgotype := d.ldr.IsGoType(symIdx)
 		relocs := d.ldr.Relocs(symIdx)
@@ -191,17 +199,28 @@ func (d *deadcodePass) flood() {
 				if d.ctxt.Debugvlog > 1 {
 					d.ctxt.Logf("reached iface method: %v\n", m)
 				}
-				d.ifaceMethod[m] = true
+				if d.ifaceMethod[m] == 0 {
+					d.ifaceMethod[m] = symIdx
+				}
 				continue
-			case objabi.R_USEGENERICIFACEMETHOD:
-				name := d.decodeGenericIfaceMethod(d.ldr, r.Sym())
+			case objabi.R_USENAMEDMETHOD:
+				name := d.decodeNamedMethod(d.ldr, r.Sym())
 				if d.ctxt.Debugvlog > 1 {
-					d.ctxt.Logf("reached generic iface method: %s\n", name)
+					d.ctxt.Logf("reached named method: %s\n", name)
+				}
+				if d.namedMethod[name] == 0 {
+					d.namedMethod[name] = symIdx
 				}
-				d.genericIfaceMethod[name] = true
 				continue // don't mark referenced symbol - it is not needed in the final binary.
 			}
 			rs := r.Sym()
+			if rs != 0 && (rs == d.methSym || rs == d.methByNameSym) && !t.IsDirectCallOrJump() && !d.ldr.SymType(symIdx).IsDWARF() {
+				// The compiler only tracks direct calls of reflect.Value.Method
+				// and MethodByName. If their address is taken (e.g. by a method
+				// expression), they may be called with any argument. (Debug
+				// information referring to them does not count.)
+				d.seeReflect(symIdx)
+			}
 			if isgotype && usedInIface && d.ldr.IsGoType(rs) && !d.ldr.AttrUsedInIface(rs) {
 				// If a type is converted to an interface, it is possible to obtain an
 				// interface with a "child" type of it using reflection (e.g. obtain an
@@ -266,7 +285,7 @@ func (d *deadcodePass) mark(symIdx, parent loader.Sym) {
 	if symIdx != 0 && !d.ldr.AttrReachable(symIdx) {
 		d.wq.push(symIdx)
 		d.ldr.SetAttrReachable(symIdx, true)
-		if buildcfg.Experiment.FieldTrack && d.ldr.Reachparent[symIdx] == 0 {
+		if d.ldr.Reachparent != nil && d.ldr.Reachparent[symIdx] == 0 {
 			d.ldr.Reachparent[symIdx] = parent
 		}
 		if *flagDumpDep {
@@ -288,11 +307,52 @@ func (d *deadcodePass) mark(symIdx, parent loader.Sym) {
 	}
 }
 
-func (d *deadcodePass) markMethod(m methodref) {
+// seeReflect records that methods may be looked up by reflection with
+// an arbitrary name or index, because of the reachable symbol s.
+func (d *deadcodePass) seeReflect(s loader.Sym) {
+	if !d.reflectSeen {
+		d.reflectSeen = true
+		d.reflectSym = s
+	}
+}
+
+// markMethod marks the method m as reachable. why and from describe
+// the reason, for -dumpmethods.
+func (d *deadcodePass) markMethod(m methodref, why string, from loader.Sym) {
 	relocs := d.ldr.Relocs(m.src)
+	tfn := relocs.At(m.r + 2).Sym()
+	if *flagDumpMethods {
+		d.dumpMethod(m, tfn, why, from)
+	}
+	if m.m.name == "Method" || m.m.name == "MethodByName" {
+		if src := d.ldr.SymName(m.src); src == "type:reflect.Value" || src == "type:*reflect.Value" {
+			// reflect.Value.Method or MethodByName itself may be
+			// called dynamically, with any argument.
+			d.seeReflect(m.src)
+		}
+	}
 	d.mark(relocs.At(m.r).Sym(), m.src)
 	d.mark(relocs.At(m.r+1).Sym(), m.src)
-	d.mark(relocs.At(m.r+2).Sym(), m.src)
+	d.mark(tfn, m.src)
+}
+
+// dumpMethod prints why the method m, implemented by tfn, is kept: the
+// chain of symbols through which from, the symbol that uses it, was
+// reached, as recorded in Reachparent, followed by the method and why.
+func (d *deadcodePass) dumpMethod(m methodref, tfn loader.Sym, why string, from loader.Sym) {
+	to := d.ldr.SymName(tfn)
+	if tfn == 0 || to == "" {
+		to = d.ldr.SymName(m.src) + "." + m.m.name
+	}
+	chain := []string{to + " <" + why + ">"}
+	for s := from; s != 0; s = d.ldr.Reachparent[s] {
+		chain = append(chain, d.ldr.SymName(s))
+	}
+	chain = append(chain, "_")
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	fmt.Println(strings.Join(chain, " -> "))
 }
 
 // deadcode marks all reachable symbols.
@@ -320,13 +380,28 @@ func (d *deadcodePass) markMethod(m methodref) {
 // against the interface method signatures, if it matches it is marked
 // as reachable. This is extremely conservative, but easy and correct.
 //
-// The third case is handled by looking to see if any of:
-//   - reflect.Value.Method or MethodByName is reachable
-//   - reflect.Type.Method or MethodByName is called (through the
-//     REFLECTMETHOD attribute marked by the compiler).
+// The third case is handled by the compiler, which looks at the calls
+// of these functions. A call of MethodByName with a constant name is
+// recorded by an R_USENAMEDMETHOD relocation, and every method with
+// that name is marked as reachable, as for generic interface calls.
+// Any other call gets the REFLECTMETHOD attribute. If a function with
+// that attribute is reachable, or if reflect.Value.Method or
+// MethodByName is used other than by a direct call (e.g. through a
+// method expression or dynamically), all bets are off and all exported
+// methods of reachable types that are converted to interfaces are
+// marked reachable.
+//
+// Which of those types may flow into the reflect.Value or reflect.Type
+// used by such a call is not tracked. Their values reach it through
+// interfaces, which may be stored anywhere and passed around by code
+// that knows nothing of reflection (text/template, for one, gets them
+// as the argument to Execute), so only a whole-program pointer
+// analysis could tell, and anything less would drop methods that are
+// looked up at run time.
 //
-// If any of these happen, all bets are off and all exported methods
-// of reachable types are marked reachable.
+// With -dumpmethods, the reason each method is kept is printed, with
+// the chain of symbols through which the symbol causing it was reached,
+// like -dumpdep.
 //
 // Any unreached text symbols are removed from ctxt.Textp.
 func deadcode(ctxt *Link) {
@@ -335,9 +410,6 @@ func deadcode(ctxt *Link) {
 	d.init()
 	d.flood()
 
-	methSym := ldr.Lookup("reflect.Value.Method", abiInternalVer)
-	methByNameSym := ldr.Lookup("reflect.Value.MethodByName", abiInternalVer)
-
 	if ctxt.DynlinkingGo() {
 		// Exported methods may satisfy interfaces we don't know
 		// about yet when dynamically linking.
@@ -345,19 +417,21 @@ func deadcode(ctxt *Link) {
 	}
 
 	for {
-		// Methods might be called via reflection. Give up on
-		// static analysis, mark all exported methods of
-		// all reachable types as reachable.
-		d.reflectSeen = d.reflectSeen || (methSym != 0 && ldr.AttrReachable(methSym)) || (methByNameSym != 0 && ldr.AttrReachable(methByNameSym))
-
 		// Mark all methods that could satisfy a discovered
 		// interface as reachable. We recheck old marked interfaces
 		// as new types (with new methods) may have been discovered
 		// in the last pass.
+		// If methods might be called via reflection, give up on
+		// static analysis, mark all exported methods of all
+		// reachable types as reachable.
 		rem := d.markableMethods[:0]
 		for _, m := range d.markableMethods {
-			if (d.reflectSeen && (m.isExported() || d.dynlink)) || d.ifaceMethod[m.m] || d.genericIfaceMethod[m.m.name] {
-				d.markMethod(m)
+			if from := d.ifaceMethod[m.m]; from != 0 {
+				d.markMethod(m, "interface", from)
+			} else if from := d.namedMethod[m.m.name]; from != 0 {
+				d.markMethod(m, "named", from)
+			} else if d.reflectSeen && (m.isExported() || d.dynlink) {
+				d.markMethod(m, "reflect", d.reflectSym)
 			} else {
 				rem = append(rem, m)
 			}
@@ -430,7 +504,7 @@ func (d *deadcodePass) decodeIfaceMethod(ldr *loader.Loader, arch *sys.Arch, sym
 }
 
 // Decode the method name stored in symbol symIdx. The symbol should contain just the bytes of a method name.
-func (d *deadcodePass) decodeGenericIfaceMethod(ldr *loader.Loader, symIdx loader.Sym) string {
+func (d *deadcodePass) decodeNamedMethod(ldr *loader.Loader, symIdx loader.Sym) string {
 	return string(ldr.Data(symIdx))
 }
 
@@ -27,6 +27,11 @@ func TestDeadcode(t *testing.T) {
 		{"ifacemethod2", "main.T.M", ""},
 		{"ifacemethod3", "main.S.M", ""},
 		{"ifacemethod4", "", "main.T.M"},
+		{"reflectmethodbyname", "main.T.M", "main.T.N"},
+		{"reflectmethodbyname2", "main.T.M", "main.T.N"},
+		{"reflectmethodbyname3", "main.T.N", ""},
+		{"reflectmethodexpr", "main.T.N", ""},
+		{"reflectmethodiface", "main.T.N", ""},
 	}
 	for _, test := range tests {
 		test := test
@@ -48,3 +53,36 @@ func TestDeadcode(t *testing.T) {
 		})
 	}
 }
+
+func TestDeadcodeDumpMethods(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	tmpdir := t.TempDir()
+
+	tests := []struct {
+		src  string
+		want string
+	}{
+		// The whole chain through which the user was reached is printed.
+		{"ifacemethod2", " -> runtime.main -> runtime.main_main·f -> main.main -> main.T.M <interface>\n"},
+		{"reflectmethodbyname", " -> runtime.main -> runtime.main_main·f -> main.main -> main.T.M <named>\n"},
+		{"reflectmethodbyname3", " -> runtime.main -> runtime.main_main·f -> main.main -> main.T.N <reflect>\n"},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.src, func(t *testing.T) {
+			t.Parallel()
+			src := filepath.Join("testdata", "deadcode", test.src+".go")
+			exe := filepath.Join(tmpdir, test.src+".exe")
+			cmd := testenv.Command(t, testenv.GoToolPath(t), "build", "-ldflags=-dumpmethods", "-o", exe, src)
+			out, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("%v: %v:\n%s", cmd.Args, err, out)
+			}
+			if !bytes.Contains(out, []byte(test.want)) {
+				t.Errorf("missing %q. Output:\n%s", test.want, out)
+			}
+		})
+	}
+}
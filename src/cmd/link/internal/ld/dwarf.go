@@ -2171,6 +2171,9 @@ func dwarfaddshstrings(ctxt *Link, shstrtab *loader.SymbolBuilder) {
 			shstrtab.Addstring(".zdebug_" + sec)
 		}
 	}
+	if ctxt.dwarfMode == dwarfSplit {
+		shstrtab.Addstring(".gnu_debuglink")
+	}
 }
 
 func dwarfaddelfsectionsyms(ctxt *Link) {
@@ -2201,7 +2204,7 @@ func dwarfcompress(ctxt *Link) {
 	}
 
 	supported := ctxt.IsELF || ctxt.IsWindows() || ctxt.IsDarwin()
-	if !ctxt.compressDWARF || !supported || ctxt.IsExternal() {
+	if ctxt.compressDWARF == dwarfCompressNone || !supported || ctxt.IsExternal() {
 		return
 	}
 
@@ -0,0 +1,171 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ld
+
+import (
+	"cmd/link/internal/loader"
+	"debug/elf"
+	"hash/crc32"
+	"path/filepath"
+)
+
+// A splitDwarfSect is a DWARF section moved to the separate debug file
+// by -dwarf=split.
+type splitDwarfSect struct {
+	name       string
+	data       []byte // contents, with relocations applied
+	align      int32
+	compressed bool
+}
+
+var splitDwarfSects []splitDwarfSect
+
+// dwarfsplit implements -dwarf=split. It takes the DWARF sections out
+// of the output file and puts a .gnu_debuglink section naming the
+// debug file in their place. The debug file itself is written by
+// elfWriteDebugFile, once the layout of the output file is final.
+//
+// This runs after dwarfcompress, so the sections in the debug file are
+// compressed as requested by -compressdwarf.
+func dwarfsplit(ctxt *Link) {
+	if ctxt.dwarfMode != dwarfSplit || len(dwarfp) == 0 {
+		return
+	}
+
+	ldr := ctxt.loader
+	st := ctxt.makeRelocSymState()
+	for i, si := range dwarfp {
+		sect := Segdwarf.Sections[i]
+		data := make([]byte, sect.Length)
+		for _, s := range si.syms {
+			P := data[ldr.SymValue(s)-int64(sect.Vaddr):]
+			P = P[:copy(P, ldr.Data(s))]
+			if relocs := ldr.Relocs(s); relocs.Count() != 0 {
+				st.relocsym(s, P)
+			}
+		}
+		splitDwarfSects = append(splitDwarfSects, splitDwarfSect{
+			name:       sect.Name,
+			data:       data,
+			align:      sect.Align,
+			compressed: sect.Compressed,
+		})
+	}
+
+	for _, si := range dwarfp {
+		for _, s := range si.syms {
+			ldr.SetAttrReachable(s, false)
+		}
+	}
+
+	// The .gnu_debuglink section holds the base name of the debug
+	// file, padded to a multiple of 4 bytes, followed by the CRC-32
+	// checksum of the debug file's contents.
+	name := filepath.Base(*flagDWARFFile)
+	data := make([]byte, (len(name)+4)&^3+4)
+	copy(data, name)
+
+	Segdwarf.Sections = Segdwarf.Sections[:0]
+	sect := addsection(ldr, ctxt.Arch, &Segdwarf, ".gnu_debuglink", 04)
+	sect.Align = 4
+	sect.Vaddr = Segdwarf.Vaddr
+	sect.Length = uint64(len(data))
+	link := ldr.MakeSymbolBuilder(".gnu_debuglink")
+	link.SetReachable(true)
+	link.SetData(data)
+	link.SetSize(int64(len(data)))
+	link.SetValue(int64(sect.Vaddr))
+	ldr.SetSymSect(link.Sym(), sect)
+	dwarfp = []dwarfSecInfo{{syms: []loader.Sym{link.Sym()}}}
+	Segdwarf.Length = sect.Length
+}
+
+// elfWriteDebugFile writes the DWARF sections set aside by dwarfsplit to
+// the debug file and records its checksum in the .gnu_debuglink section
+// of the output file.
+//
+// Like the output of "objcopy --only-keep-debug", the debug file has the
+// ELF header and section headers of the output file, so that debuggers
+// can match the two up. It keeps the contents of notes, such as the GNU
+// build ID, and of symbol and string tables, but not of the other
+// sections. The DWARF sections follow.
+func elfWriteDebugFile(ctxt *Link) {
+	out := NewOutBuf(ctxt.Arch)
+	if err := out.Open(*flagDWARFFile); err != nil {
+		Exitf("cannot create debug file: %v", err)
+	}
+
+	eh := *getElfEhdr()
+	if elf64 {
+		out.SeekSet(ELF64HDRSIZE)
+	} else {
+		out.SeekSet(ELF32HDRSIZE)
+	}
+
+	data := ctxt.Out.Data()
+	link := Segdwarf.Sections[0].Elfsect.(*ElfShdr)
+	var shdrs []*ElfShdr
+	for _, s := range shdr[:eh.Shnum] {
+		sh := *s
+		switch t := elf.SectionType(sh.Type); {
+		case t == elf.SHT_NULL:
+		case s != link && (t == elf.SHT_NOTE || t == elf.SHT_SYMTAB || t == elf.SHT_STRTAB):
+			off := out.Offset()
+			if sh.Addralign > 1 {
+				off = Rnd(off, int64(sh.Addralign))
+			}
+			out.SeekSet(off)
+			out.Write(data[sh.Off : sh.Off+sh.Size])
+			sh.Off = uint64(off)
+		default:
+			sh.Type = uint32(elf.SHT_NOBITS)
+			sh.Off = uint64(out.Offset())
+		}
+		shdrs = append(shdrs, &sh)
+	}
+
+	for _, ds := range splitDwarfSects {
+		sh := new(ElfShdr)
+		for _, s := range elfstr[:nelfstr] {
+			if s.s == ds.name {
+				sh.Name = uint32(s.off)
+				break
+			}
+		}
+		sh.Type = uint32(elf.SHT_PROGBITS)
+		if ds.compressed {
+			sh.Flags = uint64(elf.SHF_COMPRESSED)
+		}
+		sh.Addralign = uint64(ds.align)
+		off := Rnd(out.Offset(), int64(ds.align))
+		out.SeekSet(off)
+		out.Write(ds.data)
+		sh.Off = uint64(off)
+		sh.Size = uint64(len(ds.data))
+		shdrs = append(shdrs, sh)
+	}
+
+	eh.Phoff = 0
+	eh.Phnum = 0
+	eh.Shoff = uint64(Rnd(out.Offset(), int64(ctxt.Arch.PtrSize)))
+	eh.Shnum = uint16(len(shdrs))
+	out.SeekSet(int64(eh.Shoff))
+	for _, sh := range shdrs {
+		if elf64 {
+			elf64shdr(out, sh)
+		} else {
+			elf32shdr(out, sh)
+		}
+	}
+	out.SeekSet(0)
+	elfwritehdr(out, &eh)
+
+	crc := crc32.ChecksumIEEE(out.Data())
+	if err := out.Close(); err != nil {
+		Exitf("cannot write debug file: %v", err)
+	}
+	ctxt.Out.SeekSet(int64(link.Off + link.Size - 4))
+	ctxt.Out.Write32(crc)
+}
@@ -415,47 +415,47 @@ func getElfEhdr() *ElfEhdr {
 	return &ehdr
 }
 
-func elf64writehdr(out *OutBuf) uint32 {
-	out.Write(ehdr.Ident[:])
-	out.Write16(uint16(ehdr.Type))
-	out.Write16(uint16(ehdr.Machine))
-	out.Write32(uint32(ehdr.Version))
-	out.Write64(ehdr.Entry)
-	out.Write64(ehdr.Phoff)
-	out.Write64(ehdr.Shoff)
-	out.Write32(ehdr.Flags)
-	out.Write16(ehdr.Ehsize)
-	out.Write16(ehdr.Phentsize)
-	out.Write16(ehdr.Phnum)
-	out.Write16(ehdr.Shentsize)
-	out.Write16(ehdr.Shnum)
-	out.Write16(ehdr.Shstrndx)
+func elf64writehdr(out *OutBuf, eh *ElfEhdr) uint32 {
+	out.Write(eh.Ident[:])
+	out.Write16(uint16(eh.Type))
+	out.Write16(uint16(eh.Machine))
+	out.Write32(uint32(eh.Version))
+	out.Write64(eh.Entry)
+	out.Write64(eh.Phoff)
+	out.Write64(eh.Shoff)
+	out.Write32(eh.Flags)
+	out.Write16(eh.Ehsize)
+	out.Write16(eh.Phentsize)
+	out.Write16(eh.Phnum)
+	out.Write16(eh.Shentsize)
+	out.Write16(eh.Shnum)
+	out.Write16(eh.Shstrndx)
 	return ELF64HDRSIZE
 }
 
-func elf32writehdr(out *OutBuf) uint32 {
-	out.Write(ehdr.Ident[:])
-	out.Write16(uint16(ehdr.Type))
-	out.Write16(uint16(ehdr.Machine))
-	out.Write32(uint32(ehdr.Version))
-	out.Write32(uint32(ehdr.Entry))
-	out.Write32(uint32(ehdr.Phoff))
-	out.Write32(uint32(ehdr.Shoff))
-	out.Write32(ehdr.Flags)
-	out.Write16(ehdr.Ehsize)
-	out.Write16(ehdr.Phentsize)
-	out.Write16(ehdr.Phnum)
-	out.Write16(ehdr.Shentsize)
-	out.Write16(ehdr.Shnum)
-	out.Write16(ehdr.Shstrndx)
+func elf32writehdr(out *OutBuf, eh *ElfEhdr) uint32 {
+	out.Write(eh.Ident[:])
+	out.Write16(uint16(eh.Type))
+	out.Write16(uint16(eh.Machine))
+	out.Write32(uint32(eh.Version))
+	out.Write32(uint32(eh.Entry))
+	out.Write32(uint32(eh.Phoff))
+	out.Write32(uint32(eh.Shoff))
+	out.Write32(eh.Flags)
+	out.Write16(eh.Ehsize)
+	out.Write16(eh.Phentsize)
+	out.Write16(eh.Phnum)
+	out.Write16(eh.Shentsize)
+	out.Write16(eh.Shnum)
+	out.Write16(eh.Shstrndx)
 	return ELF32HDRSIZE
 }
 
-func elfwritehdr(out *OutBuf) uint32 {
+func elfwritehdr(out *OutBuf, eh *ElfEhdr) uint32 {
 	if elf64 {
-		return elf64writehdr(out)
+		return elf64writehdr(out, eh)
 	}
-	return elf32writehdr(out)
+	return elf32writehdr(out, eh)
 }
 
 /* Taken directly from the definition document for ELF64. */
@@ -1176,7 +1176,7 @@ func elfshbits(linkmode LinkMode, sect *sym.Section) *ElfShdr {
 		sh.Addr = sect.Vaddr
 	}
 
-	if strings.HasPrefix(sect.Name, ".debug") || strings.HasPrefix(sect.Name, ".zdebug") {
+	if strings.HasPrefix(sect.Name, ".debug") || strings.HasPrefix(sect.Name, ".zdebug") || sect.Name == ".gnu_debuglink" {
 		sh.Flags = 0
 		sh.Addr = 0
 		if sect.Compressed {
@@ -2265,7 +2265,7 @@ elfobj:
 
 	ctxt.Out.SeekSet(0)
 	a := int64(0)
-	a += int64(elfwritehdr(ctxt.Out))
+	a += int64(elfwritehdr(ctxt.Out, eh))
 	a += int64(elfwritephdrs(ctxt.Out))
 	a += int64(elfwriteshdrs(ctxt.Out))
 	if !*FlagD {
@@ -2305,6 +2305,10 @@ elfobj:
 	if a > int64(HEADR) {
 		Errorf(nil, "HEADR too small: %d > %d with %d text sections", a, HEADR, numtext)
 	}
+
+	if len(splitDwarfSects) > 0 {
+		elfWriteDebugFile(ctxt)
+	}
 }
 
 func elfadddynsym(ldr *loader.Loader, target *Target, syms *ArchSyms, s loader.Sym) {
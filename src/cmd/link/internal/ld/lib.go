@@ -1619,9 +1619,14 @@ func (ctxt *Link) hostlink() {
 		argv = append(argv, unusedArguments)
 	}
 
-	const compressDWARF = "-Wl,--compress-debug-sections=zlib"
-	if ctxt.compressDWARF && linkerFlagSupported(ctxt.Arch, argv[0], altLinker, compressDWARF) {
-		argv = append(argv, compressDWARF)
+	if ctxt.compressDWARF != dwarfCompressNone {
+		compressDWARF := "-Wl,--compress-debug-sections=zlib"
+		if ctxt.compressDWARF == dwarfCompressZstd && ctxt.IsELF {
+			compressDWARF = "-Wl,--compress-debug-sections=zstd"
+		}
+		if linkerFlagSupported(ctxt.Arch, argv[0], altLinker, compressDWARF) {
+			argv = append(argv, compressDWARF)
+		}
 	}
 
 	argv = append(argv, filepath.Join(*flagTmpdir, "go.o"))
@@ -63,7 +63,8 @@ type Link struct {
 
 	Loaded bool // set after all inputs have been loaded as symbols
 
-	compressDWARF bool
+	compressDWARF dwarfCompression
+	dwarfMode     dwarfMode
 
 	Libdir       []string
 	Library      []*sym.Library
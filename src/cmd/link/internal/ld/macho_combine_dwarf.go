@@ -251,7 +251,7 @@ func machoCombineDwarf(ctxt *Link, exef *os.File, exem *macho.File, dsym, outexe
 // returning the updated sections and segment contents, nils if the sections
 // weren't compressed, or an error if there was a problem reading dwarfm.
 func machoCompressSections(ctxt *Link, dwarfm *macho.File) ([]*macho.Section, []byte, error) {
-	if !ctxt.compressDWARF {
+	if ctxt.compressDWARF == dwarfCompressNone {
 		return nil, nil, nil
 	}
 
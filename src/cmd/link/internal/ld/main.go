@@ -33,6 +33,7 @@ package ld
 import (
 	"bufio"
 	"cmd/internal/goobj"
+	"cmd/internal/notsha256"
 	"cmd/internal/objabi"
 	"cmd/internal/quoted"
 	"cmd/internal/sys"
@@ -63,10 +64,12 @@ var (
 	flagBuildid = flag.String("buildid", "", "record `id` as Go toolchain build id")
 
 	flagOutfile    = flag.String("o", "", "write output to `file`")
+	flagDWARFFile  = flag.String("dwarffile", "", "write split DWARF to `file` (default output file name plus .debug)")
 	flagPluginPath = flag.String("pluginpath", "", "full path name for plugin")
 
 	flagInstallSuffix = flag.String("installsuffix", "", "set package directory `suffix`")
 	flagDumpDep       = flag.Bool("dumpdep", false, "dump symbol dependency graph")
+	flagDumpMethods   = flag.Bool("dumpmethods", false, "dump why methods are kept")
 	flagRace          = flag.Bool("race", false, "enable race detector")
 	flagMsan          = flag.Bool("msan", false, "enable MSan interface")
 	flagAsan          = flag.Bool("asan", false, "enable ASan interface")
@@ -144,7 +147,9 @@ func Main(arch *sys.Arch, theArch Arch) {
 	flag.BoolVar(&ctxt.linkShared, "linkshared", false, "link against installed Go shared libraries")
 	flag.Var(&ctxt.LinkMode, "linkmode", "set link `mode`")
 	flag.Var(&ctxt.BuildMode, "buildmode", "set build `mode`")
-	flag.BoolVar(&ctxt.compressDWARF, "compressdwarf", true, "compress DWARF if possible")
+	ctxt.compressDWARF = dwarfCompressZlib
+	flag.Var(&ctxt.compressDWARF, "compressdwarf", "compress DWARF if possible, using `method` zlib or zstd")
+	flag.Var(&ctxt.dwarfMode, "dwarf", "write DWARF in `mode` inline or split")
 	objabi.Flagfn1("B", "add an ELF NT_GNU_BUILD_ID `note` when using ELF", addbuildinfo)
 	objabi.Flagfn1("L", "add specified `directory` to library path", func(a string) { Lflag(ctxt, a) })
 	objabi.AddVersionFlag() // -V
@@ -215,6 +220,18 @@ func Main(arch *sys.Arch, theArch Arch) {
 		*flagBuildid = "go-openbsd"
 	}
 
+	if ctxt.dwarfMode == dwarfSplit {
+		if *flagDWARFFile == "" {
+			*flagDWARFFile = *flagOutfile + ".debug"
+		}
+		if len(buildinfo) == 0 && *flagBuildid != "" {
+			// Derive a GNU build ID from the Go build ID, so that
+			// debuggers can find the debug file in a symbol store.
+			sum := notsha256.Sum256([]byte(*flagBuildid))
+			buildinfo = sum[:20]
+		}
+	}
+
 	// enable benchmarking
 	var bench *benchmark.Metrics
 	if len(*benchmarkFlag) != 0 {
@@ -267,6 +284,15 @@ func Main(arch *sys.Arch, theArch Arch) {
 	bench.Start("loadlib")
 	ctxt.loadlib()
 
+	if ctxt.dwarfMode == dwarfSplit {
+		if !ctxt.IsELF {
+			Exitf("-dwarf=split is only supported for ELF")
+		}
+		if ctxt.IsExternal() {
+			Exitf("-dwarf=split requires -linkmode=internal")
+		}
+	}
+
 	bench.Start("deadcode")
 	deadcode(ctxt)
 
@@ -340,6 +366,8 @@ func Main(arch *sys.Arch, theArch Arch) {
 	order := ctxt.address()
 	bench.Start("dwarfcompress")
 	dwarfcompress(ctxt)
+	bench.Start("dwarfsplit")
+	dwarfsplit(ctxt)
 	bench.Start("layout")
 	filesize := ctxt.layout(order)
 
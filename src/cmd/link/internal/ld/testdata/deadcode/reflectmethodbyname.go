@@ -0,0 +1,23 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that reflect.Value.MethodByName with a constant name
+// only keeps methods with that name live.
+
+package main
+
+import "reflect"
+
+type T int
+
+//go:noinline
+func (T) M() {}
+
+//go:noinline
+func (T) N() {}
+
+func main() {
+	v := reflect.ValueOf(T(1))
+	v.MethodByName("M").Call(nil)
+}
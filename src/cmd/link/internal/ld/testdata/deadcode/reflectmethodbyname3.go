@@ -0,0 +1,26 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that reflect.Value.MethodByName with a name that is
+// not constant keeps all exported methods live.
+
+package main
+
+import (
+	"os"
+	"reflect"
+)
+
+type T int
+
+//go:noinline
+func (T) M() {}
+
+//go:noinline
+func (T) N() {}
+
+func main() {
+	v := reflect.ValueOf(T(1))
+	v.MethodByName(os.Args[0]).Call(nil)
+}
@@ -0,0 +1,24 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that a method expression of reflect.Value.MethodByName,
+// which may be called with any name, keeps all exported methods live.
+
+package main
+
+import "reflect"
+
+type T int
+
+//go:noinline
+func (T) M() {}
+
+//go:noinline
+func (T) N() {}
+
+var f = reflect.Value.MethodByName
+
+func main() {
+	f(reflect.ValueOf(T(1)), "M").Call(nil)
+}
@@ -0,0 +1,28 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test that calling reflect.Value.MethodByName through an
+// interface keeps all exported methods live.
+
+package main
+
+import (
+	"os"
+	"reflect"
+)
+
+type T int
+
+//go:noinline
+func (T) M() {}
+
+//go:noinline
+func (T) N() {}
+
+type byName interface{ MethodByName(string) reflect.Value }
+
+func main() {
+	var b byName = reflect.ValueOf(T(1))
+	b.MethodByName(os.Args[0][:0] + "N").Call(nil)
+}
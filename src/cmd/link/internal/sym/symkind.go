@@ -179,3 +179,8 @@ var RelROMap = map[SymKind]SymKind{
 func (t SymKind) IsData() bool {
 	return t == SDATA || t == SNOPTRDATA || t == SBSS || t == SNOPTRBSS
 }
+
+// IsDWARF returns true if the type is a DWARF symbol type.
+func (t SymKind) IsDWARF() bool {
+	return t >= SDWARFSECT && t <= SDWARFLINES
+}
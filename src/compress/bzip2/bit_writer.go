@@ -0,0 +1,67 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bzip2
+
+import "io"
+
+// bitWriter wraps an io.Writer and provides the ability to write values,
+// bit-by-bit, most significant bit first, to it. Like bitReader, its
+// Write* methods don't return the usual error; any error is kept and
+// can be checked afterwards.
+type bitWriter struct {
+	w     io.Writer
+	n     uint64 // pending bits, in the low end
+	nbits uint   // number of pending bits
+	buf   []byte
+	err   error
+}
+
+// WriteBits writes the low bits bits of v. bits must be at most 32.
+func (bw *bitWriter) WriteBits(bits uint, v uint32) {
+	bw.n = bw.n<<bits | uint64(v)&(1<<bits-1)
+	bw.nbits += bits
+	for bw.nbits >= 8 {
+		bw.nbits -= 8
+		bw.buf = append(bw.buf, byte(bw.n>>bw.nbits))
+	}
+	if len(bw.buf) >= 4096 {
+		bw.flush()
+	}
+}
+
+// WriteBits64 writes the low bits bits of v. bits must be at most 64.
+func (bw *bitWriter) WriteBits64(bits uint, v uint64) {
+	if bits > 32 {
+		bw.WriteBits(bits-32, uint32(v>>32))
+		bits = 32
+	}
+	bw.WriteBits(bits, uint32(v))
+}
+
+// WriteBit writes a single bit.
+func (bw *bitWriter) WriteBit(bit bool) {
+	if bit {
+		bw.WriteBits(1, 1)
+	} else {
+		bw.WriteBits(1, 0)
+	}
+}
+
+// Close pads any pending bits with zeros to a byte boundary and
+// writes all buffered data to the underlying writer.
+func (bw *bitWriter) Close() error {
+	if bw.nbits > 0 {
+		bw.WriteBits(8-bw.nbits, 0)
+	}
+	bw.flush()
+	return bw.err
+}
+
+func (bw *bitWriter) flush() {
+	if bw.err == nil && len(bw.buf) > 0 {
+		_, bw.err = bw.w.Write(bw.buf)
+	}
+	bw.buf = bw.buf[:0]
+}
@@ -0,0 +1,117 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bzip2
+
+// bwtSorter computes the Burrows-Wheeler transform of a block. Its
+// slices are kept between blocks to avoid reallocating them.
+type bwtSorter struct {
+	sa   []int32 // rotations in sorted order
+	sa2  []int32
+	rank []int32 // rank[i] is the index of the first rotation in i's group
+	tmp  []int32
+}
+
+// transform sorts the rotations of block and stores the last column of
+// the sorted rotations in out, which must be as long as block. It
+// returns the position of the unrotated block among the sorted
+// rotations, which the decoder calls origPtr.
+//
+// The rotations are sorted by prefix doubling: given the rotations
+// sorted by their first k bytes, sorting them by the pairs of ranks
+// (rank[i], rank[i+k]) sorts them by their first 2k bytes. Each round
+// is a linear counting sort, so the whole transform takes O(n log n)
+// time, even for highly repetitive input.
+func (s *bwtSorter) transform(block, out []byte) int {
+	n := len(block)
+	s.sa = resize(s.sa, n)
+	s.sa2 = resize(s.sa2, n)
+	s.rank = resize(s.rank, n)
+	s.tmp = resize(s.tmp, n)
+	sa, sa2, rank, tmp := s.sa, s.sa2, s.rank, s.tmp
+
+	// Sort by the first byte.
+	var count [257]int32
+	for _, b := range block {
+		count[int(b)+1]++
+	}
+	for i := 1; i < 257; i++ {
+		count[i] += count[i-1]
+	}
+	for i, b := range block {
+		rank[i] = count[b]
+	}
+	for i, b := range block {
+		sa[count[b]] = int32(i)
+		count[b]++
+	}
+	groups := 0
+	for i := range sa {
+		if i == 0 || block[sa[i]] != block[sa[i-1]] {
+			groups++
+		}
+	}
+
+	for k := 1; groups < n && k < n; k *= 2 {
+		// Order the rotations by their second key, rank[i+k], which
+		// is the order of sa shifted back by k. Then sort them stably
+		// by their first key, rank[i], placing each rotation at the
+		// next free slot in its group; tmp[g] holds the next free slot
+		// of the group starting at g.
+		j := 0
+		for _, p := range sa {
+			q := int(p) - k
+			if q < 0 {
+				q += n
+			}
+			sa2[j] = int32(q)
+			j++
+		}
+		for i := range tmp {
+			tmp[i] = int32(i)
+		}
+		for _, p := range sa2 {
+			g := rank[p]
+			sa[tmp[g]] = p
+			tmp[g]++
+		}
+
+		// Split the groups whose members now differ in their second key.
+		second := func(p int32) int32 {
+			q := int(p) + k
+			if q >= n {
+				q -= n
+			}
+			return rank[q]
+		}
+		groups = 0
+		for i, p := range sa {
+			if i == 0 || rank[p] != rank[sa[i-1]] || second(p) != second(sa[i-1]) {
+				tmp[p] = int32(i)
+				groups++
+			} else {
+				tmp[p] = tmp[sa[i-1]]
+			}
+		}
+		copy(rank, tmp)
+	}
+
+	origPtr := 0
+	for i, p := range sa {
+		if p == 0 {
+			origPtr = i
+			out[i] = block[n-1]
+		} else {
+			out[i] = block[p-1]
+		}
+	}
+	return origPtr
+}
+
+func resize(s []int32, n int) []int32 {
+	if cap(s) < n {
+		return make([]int32, n)
+	}
+	return s[:n]
+}
@@ -0,0 +1,511 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bzip2
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// These constants are the compression levels accepted by NewWriterLevel.
+// The level sets the block size, in units of 100 kB: larger blocks
+// usually compress better but need more memory to compress and to
+// decompress.
+const (
+	BestSpeed          = 1
+	BestCompression    = 9
+	DefaultCompression = -1
+)
+
+const (
+	maxCodeLen     = 17 // longest Huffman code the writer produces
+	groupSize      = 50 // symbols coded with the same Huffman table
+	huffIterations = 4  // rounds of refining the Huffman tables
+)
+
+// A Writer is an io.WriteCloser.
+// Writes to a Writer are compressed and written to w.
+type Writer struct {
+	bw          bitWriter
+	level       int
+	maxBlock    int    // maximum size of block
+	block       []byte // current block, after the initial run-length encoding
+	blockCRC    uint32 // CRC of the input in the current block
+	fileCRC     uint32 // combined CRC of the blocks written so far
+	runByte     int    // byte value of the pending run, or -1
+	runLen      int    // length of the pending run
+	wroteHeader bool
+	closed      bool
+	err         error
+
+	// Scratch space for compressing blocks.
+	sorter bwtSorter
+	bwt    []byte
+	syms   []uint16
+}
+
+// NewWriter returns a new Writer compressing data at the default level,
+// which uses the largest block size, as the bzip2 command does.
+// Writes to the returned writer are compressed and written to w.
+//
+// It is the caller's responsibility to call Close on the Writer when done.
+// Writes may be buffered and not flushed until Close.
+func NewWriter(w io.Writer) *Writer {
+	z, _ := NewWriterLevel(w, DefaultCompression)
+	return z
+}
+
+// NewWriterLevel is like NewWriter but specifies the compression level
+// instead of assuming DefaultCompression.
+//
+// The compression level can be DefaultCompression or any integer value
+// between BestSpeed and BestCompression inclusive. The error returned
+// will be nil if the level is valid.
+func NewWriterLevel(w io.Writer, level int) (*Writer, error) {
+	if level == DefaultCompression {
+		level = BestCompression
+	}
+	if level < BestSpeed || level > BestCompression {
+		return nil, fmt.Errorf("bzip2: invalid compression level: %d", level)
+	}
+	z := &Writer{level: level}
+	z.Reset(w)
+	return z, nil
+}
+
+// Reset discards the Writer z's state and makes it equivalent to the
+// result of its original state from NewWriter or NewWriterLevel, but
+// writing to w instead. This permits reusing a Writer rather than
+// allocating a new one.
+func (z *Writer) Reset(w io.Writer) {
+	z.bw = bitWriter{w: w, buf: z.bw.buf[:0]}
+	// Leave room for a run of five bytes and a little slack,
+	// as the reference implementation does.
+	z.maxBlock = z.level*100*1000 - 19
+	z.block = z.block[:0]
+	z.blockCRC = 0
+	z.fileCRC = 0
+	z.runByte = -1
+	z.runLen = 0
+	z.wroteHeader = false
+	z.closed = false
+	z.err = nil
+}
+
+// Write writes a compressed form of p to the underlying io.Writer. The
+// compressed bytes are not necessarily flushed until the Writer is closed.
+func (z *Writer) Write(p []byte) (int, error) {
+	if z.err != nil {
+		return 0, z.err
+	}
+	if z.closed {
+		return 0, errors.New("bzip2: write to closed Writer")
+	}
+	if !z.wroteHeader {
+		z.writeHeader()
+	}
+	for _, b := range p {
+		if int(b) == z.runByte && z.runLen < 255 {
+			z.runLen++
+			continue
+		}
+		z.flushRun()
+		z.runByte = int(b)
+		z.runLen = 1
+	}
+	if z.err = z.bw.err; z.err != nil {
+		return 0, z.err
+	}
+	return len(p), nil
+}
+
+// Close closes the Writer by flushing any unwritten data to the underlying
+// io.Writer and writing the end of stream marker and checksum.
+// It does not close the underlying io.Writer.
+func (z *Writer) Close() error {
+	if z.err != nil {
+		return z.err
+	}
+	if z.closed {
+		return nil
+	}
+	z.closed = true
+	if !z.wroteHeader {
+		z.writeHeader()
+	}
+	z.flushRun()
+	z.writeBlock()
+	z.bw.WriteBits64(48, bzip2FinalMagic)
+	z.bw.WriteBits(32, z.fileCRC)
+	z.err = z.bw.Close()
+	return z.err
+}
+
+func (z *Writer) writeHeader() {
+	z.wroteHeader = true
+	z.bw.WriteBits(16, bzip2FileMagic)
+	z.bw.WriteBits(8, 'h')
+	z.bw.WriteBits(8, uint32('0'+z.level))
+}
+
+// flushRun adds the pending run to the block, first writing the block
+// if the run does not fit. The initial run-length encoding replaces
+// runs of four to 255 equal bytes by four bytes and a count of the
+// remaining ones.
+func (z *Writer) flushRun() {
+	if z.runLen == 0 {
+		return
+	}
+	need := z.runLen
+	if need >= 4 {
+		need = 5
+	}
+	if len(z.block)+need > z.maxBlock {
+		z.writeBlock()
+	}
+	b := byte(z.runByte)
+	if z.runLen < 4 {
+		for i := 0; i < z.runLen; i++ {
+			z.block = append(z.block, b)
+		}
+	} else {
+		z.block = append(z.block, b, b, b, b, byte(z.runLen-4))
+	}
+	crc := ^z.blockCRC
+	for i := 0; i < z.runLen; i++ {
+		crc = crctab[byte(crc>>24)^b] ^ (crc << 8)
+	}
+	z.blockCRC = ^crc
+	z.runByte = -1
+	z.runLen = 0
+}
+
+// writeBlock compresses the current block and writes it.
+func (z *Writer) writeBlock() {
+	block := z.block
+	if len(block) == 0 {
+		return
+	}
+	bw := &z.bw
+
+	// Burrows-Wheeler transform.
+	if cap(z.bwt) < len(block) {
+		z.bwt = make([]byte, len(block))
+	}
+	bwt := z.bwt[:len(block)]
+	origPtr := z.sorter.transform(block, bwt)
+
+	// The symbols in use, in increasing order.
+	var inUse [256]bool
+	for _, b := range block {
+		inUse[b] = true
+	}
+	var seq [256]byte // seq[b] is the index of b among the symbols in use
+	var mtfList [256]byte
+	numInUse := 0
+	for b, used := range inUse {
+		if used {
+			seq[b] = byte(numInUse)
+			mtfList[numInUse] = byte(numInUse)
+			numInUse++
+		}
+	}
+
+	// Move-to-front transform, with runs of zeros coded in bijective
+	// base 2 using the RUNA and RUNB symbols, 0 and 1. The other
+	// symbols are offset by one, as the front of the list is only
+	// referenced by runs, and the last one ends the block.
+	alphaSize := numInUse + 2
+	eob := uint16(numInUse + 1)
+	syms := z.syms[:0]
+	var freq [258]int32
+	zeros := 0
+	flushZeros := func() {
+		for zeros > 0 {
+			zeros--
+			s := uint16(zeros & 1)
+			syms = append(syms, s)
+			freq[s]++
+			zeros >>= 1
+		}
+	}
+	for _, b := range bwt {
+		s := seq[b]
+		if mtfList[0] == s {
+			zeros++
+			continue
+		}
+		flushZeros()
+		j := 1
+		for mtfList[j] != s {
+			j++
+		}
+		copy(mtfList[1:j+1], mtfList[:j])
+		mtfList[0] = s
+		syms = append(syms, uint16(j+1))
+		freq[j+1]++
+	}
+	flushZeros()
+	syms = append(syms, eob)
+	freq[eob]++
+	z.syms = syms
+
+	tables, selectors := chooseTables(syms, freq[:alphaSize])
+
+	bw.WriteBits64(48, bzip2BlockMagic)
+	bw.WriteBits(32, z.blockCRC)
+	bw.WriteBits(1, 0) // not randomized
+	bw.WriteBits(24, uint32(origPtr))
+
+	// The bitmap of symbols in use, in two levels.
+	var ranges uint32
+	for i := 0; i < 16; i++ {
+		for _, used := range inUse[16*i : 16*i+16] {
+			if used {
+				ranges |= 1 << (15 - i)
+				break
+			}
+		}
+	}
+	bw.WriteBits(16, ranges)
+	for i := 0; i < 16; i++ {
+		if ranges&(1<<(15-i)) == 0 {
+			continue
+		}
+		var bits uint32
+		for j, used := range inUse[16*i : 16*i+16] {
+			if used {
+				bits |= 1 << (15 - j)
+			}
+		}
+		bw.WriteBits(16, bits)
+	}
+
+	// The table selectors, move-to-front transformed and in unary.
+	bw.WriteBits(3, uint32(len(tables)))
+	bw.WriteBits(15, uint32(len(selectors)))
+	var tableList [6]byte
+	for i := range tableList {
+		tableList[i] = byte(i)
+	}
+	for _, sel := range selectors {
+		j := 0
+		for tableList[j] != sel {
+			j++
+		}
+		copy(tableList[1:j+1], tableList[:j])
+		tableList[0] = sel
+		for ; j > 0; j-- {
+			bw.WriteBits(1, 1)
+		}
+		bw.WriteBits(1, 0)
+	}
+
+	// The code lengths of each table, delta encoded.
+	for _, t := range tables {
+		cur := t.lens[0]
+		bw.WriteBits(5, uint32(cur))
+		for _, l := range t.lens[:alphaSize] {
+			for cur < l {
+				bw.WriteBits(2, 2)
+				cur++
+			}
+			for cur > l {
+				bw.WriteBits(2, 3)
+				cur--
+			}
+			bw.WriteBits(1, 0)
+		}
+	}
+
+	// The symbols.
+	for i, sel := range selectors {
+		t := &tables[sel]
+		group := syms[i*groupSize:]
+		if len(group) > groupSize {
+			group = group[:groupSize]
+		}
+		for _, s := range group {
+			bw.WriteBits(uint(t.lens[s]), t.codes[s])
+		}
+	}
+
+	z.fileCRC = (z.fileCRC<<1 | z.fileCRC>>31) ^ z.blockCRC
+	z.blockCRC = 0
+	z.block = z.block[:0]
+}
+
+// A huffmanTable holds the code lengths and codes of a Huffman table
+// used by the writer.
+type huffmanTable struct {
+	lens  [258]uint8
+	codes [258]uint32
+}
+
+// chooseTables chooses the Huffman tables for coding syms and the
+// table to use for each group of symbols, refining an initial guess
+// that gives each table a range of symbols with a similar share of
+// the frequency.
+func chooseTables(syms []uint16, freq []int32) ([]huffmanTable, []uint8) {
+	alphaSize := len(freq)
+	var numTables int
+	switch n := len(syms); {
+	case n < 200:
+		numTables = 2
+	case n < 600:
+		numTables = 3
+	case n < 1200:
+		numTables = 4
+	case n < 2400:
+		numTables = 5
+	default:
+		numTables = 6
+	}
+	tables := make([]huffmanTable, numTables)
+
+	// Initial tables: cheap codes for a range of symbols.
+	remaining := int32(len(syms))
+	start := 0
+	for part := numTables; part > 0; part-- {
+		target := remaining / int32(part)
+		end := start - 1
+		var sum int32
+		for sum < target && end < alphaSize-1 {
+			end++
+			sum += freq[end]
+		}
+		if end > start && part != numTables && part != 1 && (numTables-part)%2 == 1 {
+			sum -= freq[end]
+			end--
+		}
+		t := &tables[part-1]
+		for s := 0; s < alphaSize; s++ {
+			if start <= s && s <= end {
+				t.lens[s] = 0
+			} else {
+				t.lens[s] = 15
+			}
+		}
+		start = end + 1
+		remaining -= sum
+	}
+
+	numGroups := (len(syms) + groupSize - 1) / groupSize
+	selectors := make([]uint8, numGroups)
+	tableFreq := make([][258]int32, numTables)
+	var cost [6]int
+	for iter := 0; iter < huffIterations; iter++ {
+		for i := range tableFreq {
+			tableFreq[i] = [258]int32{}
+		}
+		for g := range selectors {
+			group := syms[g*groupSize:]
+			if len(group) > groupSize {
+				group = group[:groupSize]
+			}
+			for t := range tables {
+				cost[t] = 0
+			}
+			for _, s := range group {
+				for t := range tables {
+					cost[t] += int(tables[t].lens[s])
+				}
+			}
+			best := 0
+			for t := 1; t < numTables; t++ {
+				if cost[t] < cost[best] {
+					best = t
+				}
+			}
+			selectors[g] = uint8(best)
+			for _, s := range group {
+				tableFreq[best][s]++
+			}
+		}
+		for t := range tables {
+			makeCodeLengths(tables[t].lens[:alphaSize], tableFreq[t][:alphaSize], maxCodeLen)
+		}
+	}
+	for t := range tables {
+		assignCodes(&tables[t], alphaSize)
+	}
+	return tables, selectors
+}
+
+// makeCodeLengths sets lens to the lengths of a Huffman code for
+// symbols with the given frequencies, no longer than maxLen. Every
+// symbol gets a code, as the decoder expects a length for each.
+func makeCodeLengths(lens []uint8, freq []int32, maxLen int) {
+	n := len(freq)
+	weight := make([]int64, 2*n-1)
+	parent := make([]int32, 2*n-1)
+	order := make([]int32, n)
+	for i, f := range freq {
+		weight[i] = int64(f)
+		if f == 0 {
+			weight[i] = 1
+		}
+		order[i] = int32(i)
+	}
+	for {
+		sort.Slice(order, func(i, j int) bool { return weight[order[i]] < weight[order[j]] })
+
+		// Build the tree by repeatedly joining the two lightest nodes,
+		// taken from the sorted leaves or the internal nodes, which
+		// are created in order of increasing weight.
+		leaf, inner, next := 0, n, n
+		lightest := func() int32 {
+			if leaf < n && (inner == next || weight[order[leaf]] <= weight[inner]) {
+				leaf++
+				return order[leaf-1]
+			}
+			inner++
+			return int32(inner - 1)
+		}
+		for ; next < 2*n-1; next++ {
+			a, b := lightest(), lightest()
+			weight[next] = weight[a] + weight[b]
+			parent[a], parent[b] = int32(next), int32(next)
+		}
+
+		// Parents come after their children, so depths can be
+		// computed from the root down.
+		depth := make([]uint8, 2*n-1)
+		tooLong := false
+		for i := 2*n - 3; i >= 0; i-- {
+			depth[i] = depth[parent[i]] + 1
+			if i < n {
+				lens[i] = depth[i]
+				if int(depth[i]) > maxLen {
+					tooLong = true
+				}
+			}
+		}
+		if !tooLong {
+			return
+		}
+		// Flatten the frequencies and try again.
+		for i := 0; i < n; i++ {
+			weight[i] = 1 + weight[i]/2
+		}
+	}
+}
+
+// assignCodes assigns canonical codes to the symbols of t given their
+// lengths: shorter codes first and, within a length, in symbol order.
+func assignCodes(t *huffmanTable, alphaSize int) {
+	code := uint32(0)
+	for l := uint8(1); l <= maxCodeLen; l++ {
+		for s := 0; s < alphaSize; s++ {
+			if t.lens[s] == l {
+				t.codes[s] = code
+				code++
+			}
+		}
+		code <<= 1
+	}
+}
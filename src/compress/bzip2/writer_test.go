@@ -0,0 +1,163 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bzip2
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func compress(t testing.TB, data []byte, level int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriterLevel(&buf, level)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Write in pieces, so that runs span calls to Write.
+	for len(data) > 0 {
+		n := 1000 + len(data)%7777
+		if n > len(data) {
+			n = len(data)
+		}
+		if _, err := w.Write(data[:n]); err != nil {
+			t.Fatal(err)
+		}
+		data = data[n:]
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func decompress(t testing.TB, data []byte) []byte {
+	t.Helper()
+	out, err := io.ReadAll(NewReader(bytes.NewReader(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// TestWriterTestdata recompresses the decompressed testdata files and
+// checks that the result decompresses to the same data and is about
+// the size the bzip2 command produced.
+func TestWriterTestdata(t *testing.T) {
+	files := []string{
+		"e.txt.bz2",
+		"Isaac.Newton-Opticks.txt.bz2",
+		"random.data.bz2",
+		"pass-random1.bz2",
+		"pass-random2.bz2",
+		"pass-sawtooth.bz2",
+	}
+	for _, name := range files {
+		t.Run(name, func(t *testing.T) {
+			orig := mustLoadFile("testdata/" + name)
+			want := decompress(t, orig)
+			levels := []int{BestSpeed, 5, BestCompression}
+			if testing.Short() {
+				levels = []int{BestCompression}
+			}
+			for _, level := range levels {
+				got := compress(t, want, level)
+				if !bytes.Equal(decompress(t, got), want) {
+					t.Fatalf("level %d: round trip mismatch", level)
+				}
+				if level == BestCompression && len(got) > len(orig)+len(orig)/50+64 {
+					t.Errorf("level %d: compressed to %d bytes, bzip2 command produced %d", level, len(got), len(orig))
+				}
+			}
+		})
+	}
+}
+
+func TestWriterRuns(t *testing.T) {
+	tests := []string{
+		"",
+		"a",
+		"aaa",
+		"aaaa",
+		"aaaab",
+		"aaaaa",
+		strings.Repeat("a", 255),
+		strings.Repeat("a", 256),
+		strings.Repeat("a", 260) + "b" + strings.Repeat("a", 4),
+		strings.Repeat("ab", 1000),
+		strings.Repeat("abc", 100000),
+		strings.Repeat("\x00", 250000),
+	}
+	for _, tt := range tests {
+		for _, level := range []int{BestSpeed, BestCompression} {
+			got := decompress(t, compress(t, []byte(tt), level))
+			if string(got) != tt {
+				t.Errorf("level %d: round trip of %s produced %s", level, trim([]byte(tt)), trim(got))
+			}
+		}
+	}
+}
+
+func TestWriterBlocks(t *testing.T) {
+	// Runs and distinct bytes straddling the block boundaries of the
+	// smallest block size.
+	var data []byte
+	for i := 0; len(data) < 450000; i++ {
+		data = append(data, byte(i), byte(i>>8))
+		for j := 0; j < i%300; j++ {
+			data = append(data, byte(i>>3))
+		}
+	}
+	for _, n := range []int{99980, 99981, 99985, 100000, 200000, len(data)} {
+		got := decompress(t, compress(t, data[:n], BestSpeed))
+		if !bytes.Equal(got, data[:n]) {
+			t.Errorf("%d bytes: round trip mismatch", n)
+		}
+	}
+}
+
+func TestWriterLevel(t *testing.T) {
+	for _, level := range []int{-2, 0, 10} {
+		if _, err := NewWriterLevel(io.Discard, level); err == nil {
+			t.Errorf("NewWriterLevel(%d) succeeded", level)
+		}
+	}
+}
+
+func TestWriterReset(t *testing.T) {
+	data := decompress(t, digits)
+	var buf1, buf2 bytes.Buffer
+	w := NewWriter(&buf1)
+	w.Write(data)
+	w.Close()
+	w.Reset(&buf2)
+	w.Write(data)
+	w.Close()
+	if !bytes.Equal(buf1.Bytes(), buf2.Bytes()) {
+		t.Error("output after Reset differs from first output")
+	}
+	if _, err := w.Write(data); err == nil {
+		t.Error("Write after Close succeeded")
+	}
+}
+
+func benchmarkEncode(b *testing.B, compressed []byte) {
+	data := decompress(b, compressed)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	w := NewWriter(io.Discard)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w.Reset(io.Discard)
+		w.Write(data)
+		w.Close()
+	}
+}
+
+func BenchmarkEncodeDigits(b *testing.B) { benchmarkEncode(b, digits) }
+func BenchmarkEncodeNewton(b *testing.B) { benchmarkEncode(b, newton) }
+func BenchmarkEncodeRand(b *testing.B)   { benchmarkEncode(b, random) }
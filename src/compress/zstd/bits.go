@@ -0,0 +1,135 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"errors"
+	"math/bits"
+)
+
+var errBitstream = errors.New("invalid bitstream")
+
+// A forwardBitReader reads bits from a byte slice starting at the
+// least significant bit of the first byte. It is used for the
+// FSE table descriptions, which are stored in that order.
+type forwardBitReader struct {
+	data []byte
+	pos  int // bit offset of the next unread bit
+}
+
+// peek returns the next n bits without consuming them.
+// Bits beyond the end of the data read as zero.
+func (br *forwardBitReader) peek(n uint) uint32 {
+	var v uint64
+	i := br.pos >> 3
+	for k := 0; k < 5 && i+k < len(br.data); k++ {
+		v |= uint64(br.data[i+k]) << (8 * k)
+	}
+	return uint32(v>>(br.pos&7)) & (1<<n - 1)
+}
+
+func (br *forwardBitReader) skip(n uint) { br.pos += int(n) }
+
+// bytesRead reports the number of bytes touched by the bits read so far.
+func (br *forwardBitReader) bytesRead() int { return (br.pos + 7) >> 3 }
+
+// A reverseBitReader reads a bitstream backward, starting at the most
+// significant set bit of the last byte, which marks the end of the stream.
+// This is the order used by FSE and Huffman coded streams.
+type reverseBitReader struct {
+	data []byte
+	pos  int // number of unread bits; negative after reading past the start
+}
+
+func newReverseBitReader(data []byte) (reverseBitReader, error) {
+	if len(data) == 0 || data[len(data)-1] == 0 {
+		return reverseBitReader{}, errBitstream
+	}
+	pos := 8*(len(data)-1) + bits.Len8(data[len(data)-1]) - 1
+	return reverseBitReader{data: data, pos: pos}, nil
+}
+
+// load returns the 64 bits starting at bit offset pos.
+// Bits outside the data read as zero.
+func (br *reverseBitReader) load(pos int) uint64 {
+	if pos < 0 {
+		return br.load(0) << uint(-pos)
+	}
+	i := pos >> 3
+	if i+8 <= len(br.data) {
+		return uint64(le.Uint64(br.data[i:])) >> (pos & 7)
+	}
+	var v uint64
+	for k := 0; i+k < len(br.data); k++ {
+		v |= uint64(br.data[i+k]) << (8 * k)
+	}
+	return v >> (pos & 7)
+}
+
+// val reads and returns the next n bits, n <= 56.
+func (br *reverseBitReader) val(n uint8) uint32 {
+	if n == 0 {
+		return 0
+	}
+	br.pos -= int(n)
+	return uint32(br.load(br.pos) & (1<<n - 1))
+}
+
+// peek returns the next n bits without consuming them.
+func (br *reverseBitReader) peek(n uint8) uint32 {
+	return uint32(br.load(br.pos-int(n)) & (1<<n - 1))
+}
+
+func (br *reverseBitReader) skip(n uint8) { br.pos -= int(n) }
+
+// overflow reports whether more bits have been read than the stream holds.
+func (br *reverseBitReader) overflow() bool { return br.pos < 0 }
+
+// finished reports whether every bit of the stream has been read.
+func (br *reverseBitReader) finished() bool { return br.pos == 0 }
+
+// A bitWriter accumulates bits starting at the least significant bit,
+// the inverse of reverseBitReader.
+type bitWriter struct {
+	out   []byte
+	acc   uint64
+	nbits uint
+}
+
+// addBits appends the low n bits of v, n <= 32.
+func (bw *bitWriter) addBits(v uint32, n uint) {
+	bw.acc |= uint64(v&(1<<n-1)) << bw.nbits
+	bw.nbits += n
+	if bw.nbits >= 32 {
+		bw.out = append(bw.out, byte(bw.acc), byte(bw.acc>>8), byte(bw.acc>>16), byte(bw.acc>>24))
+		bw.acc >>= 32
+		bw.nbits -= 32
+	}
+}
+
+// close writes the end-of-stream marker bit and flushes the
+// remaining bits, padding the last byte with zeros.
+func (bw *bitWriter) close() []byte {
+	bw.addBits(1, 1)
+	return bw.flushBytes()
+}
+
+// flushBytes writes the accumulated bits, padded with zeros
+// to a byte boundary, without an end-of-stream marker.
+func (bw *bitWriter) flushBytes() []byte {
+	for bw.nbits > 0 {
+		bw.out = append(bw.out, byte(bw.acc))
+		bw.acc >>= 8
+		if bw.nbits < 8 {
+			bw.nbits = 0
+		} else {
+			bw.nbits -= 8
+		}
+	}
+	return bw.out
+}
+
+// highBit returns the index of the most significant set bit of v, v > 0.
+func highBit(v uint32) uint32 { return uint32(bits.Len32(v)) - 1 }
@@ -0,0 +1,377 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import "errors"
+
+// Decoding of compressed blocks, RFC 8878 section 3.1.1.3.
+
+var (
+	errLiterals  = errors.New("invalid literals section")
+	errSequences = errors.New("invalid sequences section")
+	errOffset    = errors.New("match offset out of range")
+)
+
+// A seqTable is the FSE decoding table in use for one of
+// the three sequence fields.
+type seqTable struct {
+	entries []fseEntry
+	log     int
+	buf     [1 << maxLLLog]fseEntry // storage for tables read from blocks
+}
+
+func (t *seqTable) set(entries []fseEntry, log int) {
+	t.entries = entries
+	t.log = log
+}
+
+// Baselines and extra bits of the sequence codes,
+// RFC 8878 section 3.1.1.3.2.1.1.
+var (
+	llBase = [maxLLCode + 1]uint32{
+		0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15,
+		16, 18, 20, 22, 24, 28, 32, 40, 48, 64, 128, 256, 512, 1024, 2048, 4096,
+		8192, 16384, 32768, 65536,
+	}
+	llBits = [maxLLCode + 1]uint8{
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		1, 1, 1, 1, 2, 2, 3, 3, 4, 6, 7, 8, 9, 10, 11, 12,
+		13, 14, 15, 16,
+	}
+	mlBase = [maxMLCode + 1]uint32{
+		3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18,
+		19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34,
+		35, 37, 39, 41, 43, 47, 51, 59, 67, 83, 99, 131, 259, 515, 1027, 2051,
+		4099, 8195, 16387, 32771, 65539,
+	}
+	mlBits = [maxMLCode + 1]uint8{
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		1, 1, 1, 1, 2, 2, 3, 3, 4, 4, 5, 7, 8, 9, 10, 11,
+		12, 13, 14, 15, 16,
+	}
+)
+
+// compressedBlock decodes the compressed block data, appending its
+// output to z.hist.
+func (z *Reader) compressedBlock(data []byte) error {
+	n, err := z.readLiterals(data)
+	if err != nil {
+		return err
+	}
+	return z.execSequences(data[n:])
+}
+
+// readLiterals decodes the literals section at the start of data
+// into z.literals, returning the size of the section.
+func (z *Reader) readLiterals(data []byte) (int, error) {
+	if len(data) == 0 {
+		return 0, errLiterals
+	}
+	typ := data[0] & 3
+	sizeFormat := (data[0] >> 2) & 3
+
+	if typ < 2 {
+		// Raw_Literals_Block or RLE_Literals_Block.
+		var size, hdr int
+		switch sizeFormat {
+		case 0, 2:
+			size, hdr = int(data[0]>>3), 1
+		case 1:
+			if len(data) < 2 {
+				return 0, errLiterals
+			}
+			size, hdr = int(data[0]>>4)|int(data[1])<<4, 2
+		case 3:
+			if len(data) < 3 {
+				return 0, errLiterals
+			}
+			size, hdr = int(data[0]>>4)|int(data[1])<<4|int(data[2])<<12, 3
+		}
+		if size > maxBlockSize {
+			return 0, errLiterals
+		}
+		z.literals = z.literals[:0]
+		if typ == 0 {
+			if hdr+size > len(data) {
+				return 0, errLiterals
+			}
+			z.literals = append(z.literals, data[hdr:hdr+size]...)
+			return hdr + size, nil
+		}
+		if hdr+1 > len(data) {
+			return 0, errLiterals
+		}
+		z.literals = grow(z.literals, size)
+		for i := range z.literals {
+			z.literals[i] = data[hdr]
+		}
+		return hdr + 1, nil
+	}
+
+	// Compressed_Literals_Block or Treeless_Literals_Block.
+	var regen, comp, hdr int
+	streams := 4
+	switch sizeFormat {
+	case 0, 1:
+		if len(data) < 3 {
+			return 0, errLiterals
+		}
+		v := uint32(data[0]) | uint32(data[1])<<8 | uint32(data[2])<<16
+		regen, comp, hdr = int(v>>4&0x3FF), int(v>>14&0x3FF), 3
+		if sizeFormat == 0 {
+			streams = 1
+		}
+	case 2:
+		if len(data) < 4 {
+			return 0, errLiterals
+		}
+		v := le.Uint32(data)
+		regen, comp, hdr = int(v>>4&0x3FFF), int(v>>18), 4
+	case 3:
+		if len(data) < 5 {
+			return 0, errLiterals
+		}
+		v := uint64(le.Uint32(data)) | uint64(data[4])<<32
+		regen, comp, hdr = int(v>>4&0x3FFFF), int(v>>22), 5
+	}
+	if regen > maxBlockSize || hdr+comp > len(data) {
+		return 0, errLiterals
+	}
+	src := data[hdr : hdr+comp]
+	if typ == 2 {
+		n, err := z.huffBuf.read(src)
+		if err != nil {
+			return 0, err
+		}
+		z.huff = &z.huffBuf
+		src = src[n:]
+	} else if z.huff == nil {
+		return 0, errLiterals
+	}
+
+	if cap(z.literals) < regen {
+		z.literals = make([]byte, regen)
+	}
+	z.literals = z.literals[:regen]
+	if streams == 1 {
+		if err := z.huff.decode(src, z.literals); err != nil {
+			return 0, err
+		}
+		return hdr + comp, nil
+	}
+
+	// Four streams, preceded by a jump table giving
+	// the sizes of the first three.
+	if len(src) < 6 {
+		return 0, errLiterals
+	}
+	s1 := int(le.Uint16(src))
+	s2 := int(le.Uint16(src[2:]))
+	s3 := int(le.Uint16(src[4:]))
+	src = src[6:]
+	if s1+s2+s3 > len(src) {
+		return 0, errLiterals
+	}
+	per := (regen + 3) / 4
+	if 3*per > regen {
+		return 0, errLiterals
+	}
+	bounds := [5]int{0, s1, s1 + s2, s1 + s2 + s3, len(src)}
+	for i := 0; i < 4; i++ {
+		out := z.literals[i*per:]
+		if i < 3 {
+			out = out[:per]
+		}
+		if err := z.huff.decode(src[bounds[i]:bounds[i+1]], out); err != nil {
+			return 0, err
+		}
+	}
+	return hdr + comp, nil
+}
+
+// readSeqTable reads the table for one sequence field in the given mode,
+// returning the number of bytes consumed.
+func readSeqTable(t *seqTable, mode byte, data []byte, predef []fseEntry, predefLog, maxLog, maxSym int) (int, error) {
+	switch mode {
+	case 0: // Predefined_Mode
+		t.set(predef, predefLog)
+		return 0, nil
+	case 1: // RLE_Mode
+		if len(data) < 1 || int(data[0]) > maxSym {
+			return 0, errSequences
+		}
+		rleFSETable(t.buf[:1], data[0])
+		t.set(t.buf[:1], 0)
+		return 1, nil
+	case 2: // FSE_Compressed_Mode
+		var norm [maxMLCode + 1]int16
+		log, n, err := readNormalizedCounts(data, norm[:maxSym+1], maxLog)
+		if err != nil {
+			return 0, err
+		}
+		if err := buildFSETable(norm[:maxSym+1], log, t.buf[:1<<log]); err != nil {
+			return 0, err
+		}
+		t.set(t.buf[:1<<log], log)
+		return n, nil
+	default: // Repeat_Mode
+		if t.entries == nil {
+			return 0, errSequences
+		}
+		return 0, nil
+	}
+}
+
+// execSequences decodes the sequences section in data and executes
+// the sequences against z.literals, appending the output to z.hist.
+func (z *Reader) execSequences(data []byte) error {
+	if len(data) == 0 {
+		return errSequences
+	}
+	nseq := int(data[0])
+	n := 1
+	switch {
+	case nseq == 0:
+		if len(data) != 1 {
+			return errSequences
+		}
+		z.hist = append(z.hist, z.literals...)
+		return nil
+	case nseq == 255:
+		if len(data) < 3 {
+			return errSequences
+		}
+		nseq = int(le.Uint16(data[1:])) + 0x7F00
+		n = 3
+	case nseq >= 128:
+		if len(data) < 2 {
+			return errSequences
+		}
+		nseq = (nseq-128)<<8 | int(data[1])
+		n = 2
+	}
+	if n >= len(data) {
+		return errSequences
+	}
+	modes := data[n]
+	n++
+	if modes&3 != 0 {
+		return errSequences
+	}
+
+	initPredefined()
+	m, err := readSeqTable(&z.ll, modes>>6, data[n:], predefLLTable[:], predefLLLog, maxLLLog, maxLLCode)
+	if err != nil {
+		return err
+	}
+	n += m
+	m, err = readSeqTable(&z.of, modes>>4&3, data[n:], predefOFTable[:], predefOFLog, maxOFLog, maxOFCode)
+	if err != nil {
+		return err
+	}
+	n += m
+	m, err = readSeqTable(&z.ml, modes>>2&3, data[n:], predefMLTable[:], predefMLLog, maxMLLog, maxMLCode)
+	if err != nil {
+		return err
+	}
+	n += m
+
+	br, err := newReverseBitReader(data[n:])
+	if err != nil {
+		return errSequences
+	}
+	llState := br.val(uint8(z.ll.log))
+	ofState := br.val(uint8(z.of.log))
+	mlState := br.val(uint8(z.ml.log))
+
+	lits := z.literals
+	rep := &z.repeats
+	for i := 0; i < nseq; i++ {
+		lle := z.ll.entries[llState]
+		mle := z.ml.entries[mlState]
+		ofe := z.of.entries[ofState]
+		if lle.sym > maxLLCode || mle.sym > maxMLCode || ofe.sym > maxOFCode {
+			return errSequences
+		}
+
+		ofCode := ofe.sym
+		offset := uint32(1)<<ofCode + br.val(ofCode)
+		ml := mlBase[mle.sym] + br.val(mlBits[mle.sym])
+		ll := llBase[lle.sym] + br.val(llBits[lle.sym])
+
+		// Resolve repeat offsets, RFC 8878 section 3.1.1.5.
+		if offset > 3 {
+			offset -= 3
+			rep[2], rep[1], rep[0] = rep[1], rep[0], offset
+		} else {
+			idx := offset - 1
+			if ll == 0 {
+				idx++
+			}
+			switch idx {
+			case 0:
+				offset = rep[0]
+			case 1:
+				offset = rep[1]
+				rep[1], rep[0] = rep[0], offset
+			case 2:
+				offset = rep[2]
+				rep[2], rep[1], rep[0] = rep[1], rep[0], offset
+			case 3:
+				offset = rep[0] - 1
+				if offset == 0 {
+					return errOffset
+				}
+				rep[2], rep[1], rep[0] = rep[1], rep[0], offset
+			}
+		}
+
+		if int(ll) > len(lits) {
+			return errSequences
+		}
+		z.hist = append(z.hist, lits[:ll]...)
+		lits = lits[ll:]
+		if err := z.copyMatch(int(offset), int(ml)); err != nil {
+			return err
+		}
+
+		if i < nseq-1 {
+			llState = uint32(lle.base) + br.val(lle.bits)
+			mlState = uint32(mle.base) + br.val(mle.bits)
+			ofState = uint32(ofe.base) + br.val(ofe.bits)
+		}
+		if br.overflow() {
+			return errSequences
+		}
+	}
+	if !br.finished() {
+		return errSequences
+	}
+	z.hist = append(z.hist, lits...)
+	return nil
+}
+
+// copyMatch appends n bytes copied from offset bytes back in z.hist.
+func (z *Reader) copyMatch(offset, n int) error {
+	start := len(z.hist) - offset
+	if offset <= 0 || start < 0 || n > maxBlockSize {
+		return errOffset
+	}
+	z.hist = grow(z.hist, n)
+	dst := z.hist[len(z.hist)-n:]
+	src := z.hist[start:]
+	if offset >= n {
+		copy(dst, src[:n])
+		return nil
+	}
+	// Overlapping copy: replicate the pattern offset bytes at a time.
+	for len(dst) > 0 {
+		c := copy(dst, src[:offset])
+		dst = dst[c:]
+		src = src[c:]
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import "errors"
+
+var errDictFormat = errors.New("zstd: invalid dictionary")
+
+// A dictionary holds the parsed form of a Zstandard dictionary,
+// RFC 8878 section 5.
+type dictionary struct {
+	id      uint32
+	content []byte
+	repeats [3]uint32
+
+	// Entropy tables; hasTables reports whether they are present.
+	hasTables bool
+	huff      huffTable
+	llTable   [1 << maxLLLog]fseEntry
+	mlTable   [1 << maxMLLog]fseEntry
+	ofTable   [1 << maxOFLog]fseEntry
+	llLog     int
+	mlLog     int
+	ofLog     int
+}
+
+// parseDict parses data as a Zstandard dictionary. Data that does not
+// begin with the dictionary magic number is used as raw content with
+// an identifier of zero.
+func parseDict(data []byte) (*dictionary, error) {
+	d := &dictionary{repeats: [3]uint32{1, 4, 8}}
+	if len(data) < 8 || le.Uint32(data) != dictMagic {
+		d.content = data
+		return d, nil
+	}
+	d.id = le.Uint32(data[4:])
+	data = data[8:]
+
+	n, err := d.huff.read(data)
+	if err != nil {
+		return nil, errDictFormat
+	}
+	data = data[n:]
+	for _, t := range []struct {
+		table  []fseEntry
+		log    *int
+		nsym   int
+		maxLog int
+	}{
+		{d.ofTable[:], &d.ofLog, maxOFCode + 1, maxOFLog},
+		{d.mlTable[:], &d.mlLog, maxMLCode + 1, maxMLLog},
+		{d.llTable[:], &d.llLog, maxLLCode + 1, maxLLLog},
+	} {
+		var norm [maxMLCode + 1]int16
+		log, n, err := readNormalizedCounts(data, norm[:t.nsym], t.maxLog)
+		if err != nil {
+			return nil, errDictFormat
+		}
+		if err := buildFSETable(norm[:t.nsym], log, t.table[:1<<log]); err != nil {
+			return nil, errDictFormat
+		}
+		*t.log = log
+		data = data[n:]
+	}
+	if len(data) < 12 {
+		return nil, errDictFormat
+	}
+	for i := range d.repeats {
+		d.repeats[i] = le.Uint32(data[4*i:])
+		if d.repeats[i] == 0 {
+			return nil, errDictFormat
+		}
+	}
+	d.content = data[12:]
+	d.hasTables = true
+	return d, nil
+}
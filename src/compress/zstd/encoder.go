@@ -0,0 +1,636 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"math"
+	"math/bits"
+)
+
+// encParams are the tuning parameters of a compression level.
+type encParams struct {
+	windowLog uint // log2 of the window size
+	hashLog   uint // log2 of the hash table size
+	chainLog  uint // log2 of the chain table size; 0 disables chaining
+	depth     int  // maximum number of match candidates examined
+	lazy      bool // look for a longer match at the next position
+}
+
+var levelParams = [BestCompression + 1]encParams{
+	1: {windowLog: 20, hashLog: 15},
+	2: {windowLog: 20, hashLog: 16, chainLog: 16, depth: 4},
+	3: {windowLog: 21, hashLog: 17, chainLog: 17, depth: 8},
+	4: {windowLog: 21, hashLog: 17, chainLog: 17, depth: 8, lazy: true},
+	5: {windowLog: 22, hashLog: 17, chainLog: 18, depth: 16, lazy: true},
+	6: {windowLog: 22, hashLog: 18, chainLog: 18, depth: 32, lazy: true},
+	7: {windowLog: 23, hashLog: 18, chainLog: 19, depth: 64, lazy: true},
+	8: {windowLog: 23, hashLog: 18, chainLog: 20, depth: 128, lazy: true},
+	9: {windowLog: 23, hashLog: 18, chainLog: 20, depth: 256, lazy: true},
+}
+
+const (
+	minMatch      = 4
+	minLiteralsHC = 32 // fewer literals than this are stored raw
+)
+
+// A sequence is a run of literals followed by a match.
+type sequence struct {
+	litLen   uint32
+	matchLen uint32
+	offVal   uint32 // offset value as coded: repeat code or offset+3
+}
+
+// An encoder compresses blocks for a single frame, or for one
+// independent segment of a frame. It keeps the history that
+// matches may refer to.
+type encoder struct {
+	p encParams
+
+	// hist holds the window of previous input followed by the block
+	// being compressed. Positions in table and chain are indexes into
+	// hist plus one, so that zero marks an empty slot.
+	hist  []byte
+	table []int32
+	chain []int32
+	tmp   []int32
+
+	// Repeat offsets, of which the first repValid are known.
+	// The decoder's repeat offsets at the start of a segment
+	// depend on earlier segments, so they start out unknown there.
+	rep      [3]uint32
+	repValid int
+
+	seqs    []sequence
+	lits    []byte
+	huff    huffEncoder
+	llCodes []uint8
+	mlCodes []uint8
+	ofCodes []uint8
+}
+
+// reset prepares e to compress a new frame or segment that follows
+// history, which matches may refer to.
+func (e *encoder) reset(p encParams, history []byte) {
+	e.p = p
+	if len(e.table) != 1<<p.hashLog {
+		e.table = make([]int32, 1<<p.hashLog)
+	} else {
+		for i := range e.table {
+			e.table[i] = 0
+		}
+	}
+	if p.chainLog == 0 {
+		e.chain = nil
+	} else if len(e.chain) != 1<<p.chainLog {
+		e.chain = make([]int32, 1<<p.chainLog)
+	}
+	if w := e.windowSize(); len(history) > w {
+		history = history[len(history)-w:]
+	}
+	e.hist = append(e.hist[:0], history...)
+	for i := 0; i+minMatch <= len(e.hist); i++ {
+		e.insert(i)
+	}
+	e.rep = [3]uint32{1, 4, 8}
+	e.repValid = 0
+}
+
+func (e *encoder) windowSize() int { return 1 << e.p.windowLog }
+
+func (e *encoder) hash(i int) uint32 {
+	return (le.Uint32(e.hist[i:]) * 2654435761) >> (32 - e.p.hashLog)
+}
+
+// insert records position i in the hash and chain tables.
+func (e *encoder) insert(i int) {
+	h := e.hash(i)
+	if e.chain != nil {
+		e.chain[i&(len(e.chain)-1)] = e.table[h]
+	}
+	e.table[h] = int32(i + 1)
+}
+
+// slide discards history older than the window so that hist has room
+// for another block, adjusting the positions in the tables.
+func (e *encoder) slide() {
+	w := e.windowSize()
+	if len(e.hist)+maxBlockSize <= 2*w {
+		return
+	}
+	delta := len(e.hist) - w
+	copy(e.hist, e.hist[delta:])
+	e.hist = e.hist[:w]
+	adjust := func(t []int32) {
+		for i, v := range t {
+			if int(v) <= delta {
+				t[i] = 0
+			} else {
+				t[i] = v - int32(delta)
+			}
+		}
+	}
+	adjust(e.table)
+	if e.chain != nil {
+		// The chain table is indexed by position; rotate it
+		// so that it stays aligned with the moved history.
+		mask := len(e.chain) - 1
+		e.tmp = append(e.tmp[:0], e.chain...)
+		for i := range e.chain {
+			e.chain[i] = e.tmp[(i+delta)&mask]
+		}
+		adjust(e.chain)
+	}
+}
+
+// matchLen returns the length of the common prefix of hist[a:]
+// and hist[b:end], a < b.
+func (e *encoder) matchLen(a, b, end int) int {
+	n := 0
+	for b+n+8 <= end {
+		x := le.Uint64(e.hist[a+n:]) ^ le.Uint64(e.hist[b+n:])
+		if x != 0 {
+			return n + bits.TrailingZeros64(x)>>3
+		}
+		n += 8
+	}
+	for b+n < end && e.hist[a+n] == e.hist[b+n] {
+		n++
+	}
+	return n
+}
+
+// findMatch returns the longest match for position i found among the
+// candidates in the hash and chain tables, limited to end.
+func (e *encoder) findMatch(i, end int) (length, offset int) {
+	minPos := i - e.windowSize()
+	if minPos < 0 {
+		minPos = 0
+	}
+	cand := int(e.table[e.hash(i)]) - 1
+	depth := e.p.depth
+	if depth < 1 {
+		depth = 1
+	}
+	for ; depth > 0 && cand >= minPos && cand < i; depth-- {
+		if e.hist[cand+length] == e.hist[i+length] {
+			if n := e.matchLen(cand, i, end); n > length {
+				length, offset = n, i-cand
+				if i+n == end {
+					break
+				}
+			}
+		}
+		// The chain entry for cand has been overwritten
+		// if cand is further back than the chain table is long.
+		if e.chain == nil || i-cand >= len(e.chain) {
+			break
+		}
+		next := int(e.chain[cand&(len(e.chain)-1)]) - 1
+		if next >= cand {
+			break
+		}
+		cand = next
+	}
+	if length < minMatch {
+		return 0, 0
+	}
+	return length, offset
+}
+
+// repMatch returns the length of a match at position i using the
+// most recent offset, if that offset is known.
+func (e *encoder) repMatch(i, end int) int {
+	if e.repValid == 0 {
+		return 0
+	}
+	off := int(e.rep[0])
+	if off > i || off > e.windowSize() {
+		return 0
+	}
+	if n := e.matchLen(i-off, i, end); n >= minMatch {
+		return n
+	}
+	return 0
+}
+
+// offsetValue returns the offset value coding a match at offset that
+// follows litLen literals, and updates the repeat offsets as the
+// decoder will.
+func (e *encoder) offsetValue(offset, litLen uint32) uint32 {
+	r := &e.rep
+	if litLen > 0 {
+		switch {
+		case e.repValid >= 1 && offset == r[0]:
+			return 1
+		case e.repValid >= 2 && offset == r[1]:
+			r[0], r[1] = r[1], r[0]
+			return 2
+		case e.repValid >= 3 && offset == r[2]:
+			r[0], r[1], r[2] = r[2], r[0], r[1]
+			return 3
+		}
+	} else {
+		switch {
+		case e.repValid >= 2 && offset == r[1]:
+			r[0], r[1] = r[1], r[0]
+			return 1
+		case e.repValid >= 3 && offset == r[2]:
+			r[0], r[1], r[2] = r[2], r[0], r[1]
+			return 2
+		case e.repValid >= 1 && offset == r[0]-1:
+			r[0], r[1], r[2] = offset, r[0], r[1]
+			if e.repValid < 3 {
+				e.repValid++
+			}
+			return 3
+		}
+	}
+	r[0], r[1], r[2] = offset, r[0], r[1]
+	if e.repValid < 3 {
+		e.repValid++
+	}
+	return offset + 3
+}
+
+// parse finds the sequences for hist[start:end], storing them in e.seqs
+// and the literals in e.lits.
+func (e *encoder) parse(start, end int) {
+	e.seqs = e.seqs[:0]
+	e.lits = e.lits[:0]
+	anchor := start
+	i := start
+	limit := end - minMatch
+	for i <= limit {
+		length, offset := 0, 0
+		if n := e.repMatch(i, end); n > 0 {
+			length, offset = n, int(e.rep[0])
+		}
+		if n, off := e.findMatch(i, end); n > length {
+			length, offset = n, off
+		}
+		e.insert(i)
+		if length == 0 {
+			i += 1 + (i-anchor)>>8 // skip faster through incompressible data
+			continue
+		}
+		if e.p.lazy {
+			for i+1 <= limit {
+				n, off := e.findMatch(i+1, end)
+				if n <= length {
+					break
+				}
+				e.insert(i + 1)
+				i++
+				length, offset = n, off
+			}
+		}
+
+		litLen := uint32(i - anchor)
+		e.lits = append(e.lits, e.hist[anchor:i]...)
+		e.seqs = append(e.seqs, sequence{
+			litLen:   litLen,
+			matchLen: uint32(length),
+			offVal:   e.offsetValue(uint32(offset), litLen),
+		})
+
+		// Index the positions covered by the match.
+		next := i + length
+		step := 1
+		if e.chain == nil {
+			step = 4
+		}
+		for j := i + 1; j < next && j <= limit; j += step {
+			e.insert(j)
+		}
+		i = next
+		anchor = i
+	}
+	e.lits = append(e.lits, e.hist[anchor:end]...)
+}
+
+// appendBlock compresses src as the next block, appending it with its
+// block header to dst. If last is set, the block ends the frame.
+func (e *encoder) appendBlock(dst, src []byte, last bool) []byte {
+	e.slide()
+	start := len(e.hist)
+	e.hist = append(e.hist, src...)
+	end := len(e.hist)
+
+	if len(src) == 0 {
+		return appendBlockHeader(dst, 0, len(src), last)
+	}
+	if isRun(src) && len(src) > 1 {
+		dst = appendBlockHeader(dst, 1, len(src), last)
+		for i := start; i+minMatch <= end; i++ {
+			e.insert(i)
+		}
+		return append(dst, src[0])
+	}
+
+	e.parse(start, end)
+	hdr := len(dst)
+	dst = appendBlockHeader(dst, 2, 0, last)
+	body := len(dst)
+	dst = e.appendLiterals(dst, e.lits)
+	dst = e.appendSequences(dst)
+	if size := len(dst) - body; size < len(src) {
+		h := appendBlockHeader(nil, 2, size, last)
+		copy(dst[hdr:], h)
+		return dst
+	}
+
+	// Not compressible: store the block instead. The repeat offsets
+	// chosen while parsing never reach the decoder, so forget them.
+	e.repValid = 0
+	dst = appendBlockHeader(dst[:hdr], 0, len(src), last)
+	return append(dst, src...)
+}
+
+// appendBlockHeader appends a block header for a block of the given
+// type and size to dst.
+func appendBlockHeader(dst []byte, typ, size int, last bool) []byte {
+	v := uint32(typ)<<1 | uint32(size)<<3
+	if last {
+		v |= 1
+	}
+	return append(dst, byte(v), byte(v>>8), byte(v>>16))
+}
+
+func isRun(b []byte) bool {
+	for _, c := range b {
+		if c != b[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// appendLiterals appends a literals section holding lits to dst.
+func (e *encoder) appendLiterals(dst, lits []byte) []byte {
+	n := len(lits)
+	if n > 1 && isRun(lits) {
+		return append(appendLiteralsHeader(dst, 1, n), lits[0])
+	}
+	if n < minLiteralsHC {
+		return append(appendLiteralsHeader(dst, 0, n), lits...)
+	}
+
+	var counts [256]uint32
+	for _, c := range lits {
+		counts[c]++
+	}
+	e.huff.build(&counts)
+	start := len(dst)
+	streams := 4
+	if n < 256 {
+		streams = 1
+	}
+	hdrSize := 3
+	if n > 1023 {
+		hdrSize = 4
+	}
+	if n > 16383 {
+		hdrSize = 5
+	}
+	dst = append(dst, make([]byte, hdrSize)...)
+	body := len(dst)
+	if t := e.huff.appendTable(dst); t != nil {
+		dst = t
+	} else {
+		return append(appendLiteralsHeader(dst[:start], 0, n), lits...)
+	}
+	if streams == 1 {
+		dst = e.huff.encode(dst, lits)
+	} else {
+		per := (n + 3) / 4
+		jump := len(dst)
+		dst = append(dst, 0, 0, 0, 0, 0, 0)
+		for i := 0; i < 4; i++ {
+			s := len(dst)
+			chunk := lits[i*per:]
+			if i < 3 {
+				chunk = chunk[:per]
+			}
+			dst = e.huff.encode(dst, chunk)
+			if i < 3 {
+				le.PutUint16(dst[jump+2*i:], uint16(len(dst)-s))
+			}
+		}
+	}
+	comp := len(dst) - body
+	if comp >= n-n/32 || comp >= 1<<18 || streams == 1 && comp > 1023 {
+		return append(appendLiteralsHeader(dst[:start], 0, n), lits...)
+	}
+
+	// Fill in the header now that the compressed size is known.
+	var v uint64
+	switch hdrSize {
+	case 3:
+		v = uint64(n)<<4 | uint64(comp)<<14
+		if streams == 4 {
+			v |= 1 << 2
+		}
+	case 4:
+		v = 2<<2 | uint64(n)<<4 | uint64(comp)<<18
+	case 5:
+		v = 3<<2 | uint64(n)<<4 | uint64(comp)<<22
+	}
+	v |= 2 // Compressed_Literals_Block
+	for i := 0; i < hdrSize; i++ {
+		dst[start+i] = byte(v >> (8 * i))
+	}
+	return dst
+}
+
+// appendLiteralsHeader appends the header of a raw or RLE
+// literals section of size n to dst.
+func appendLiteralsHeader(dst []byte, typ byte, n int) []byte {
+	switch {
+	case n < 32:
+		return append(dst, typ|byte(n)<<3)
+	case n < 4096:
+		return append(dst, typ|1<<2|byte(n)<<4, byte(n>>4))
+	default:
+		return append(dst, typ|3<<2|byte(n)<<4, byte(n>>4), byte(n>>12))
+	}
+}
+
+var llCodeTable, mlCodeTable [128]uint8
+
+func init() {
+	for c := maxLLCode; c >= 0; c-- {
+		for v := llBase[c]; v < 64 && llCodeTable[v] == 0; v++ {
+			llCodeTable[v] = uint8(c)
+		}
+	}
+	for c := maxMLCode; c >= 0; c-- {
+		for v := mlBase[c] - 3; v < 128 && mlCodeTable[v] == 0; v++ {
+			mlCodeTable[v] = uint8(c)
+		}
+	}
+}
+
+func llCode(ll uint32) uint8 {
+	if ll < 64 {
+		return llCodeTable[ll]
+	}
+	return uint8(highBit(ll) + 19)
+}
+
+func mlCode(ml uint32) uint8 {
+	if v := ml - 3; v < 128 {
+		return mlCodeTable[v]
+	}
+	return uint8(highBit(ml-3) + 36)
+}
+
+// A seqField describes how one sequence field is coded in a block.
+type seqField struct {
+	mode  byte         // compression mode
+	table *fseEncTable // nil in RLE mode
+	log   int
+	norm  []int16 // for FSE_Compressed_Mode
+	sym   uint8   // for RLE_Mode
+	tab   fseEncTable
+}
+
+// choose selects the cheapest way to code codes, whose largest
+// possible value is maxSym.
+func (f *seqField) choose(codes []uint8, maxSym int, maxLog int, predef []int16, predefLog int, predefEnc *fseEncTable) {
+	var counts [maxMLCode + 1]uint32
+	top := 0
+	for _, c := range codes {
+		counts[c]++
+		if int(c) > top {
+			top = int(c)
+		}
+	}
+	if int(counts[codes[0]]) == len(codes) {
+		f.mode, f.sym, f.table = 1, codes[0], nil
+		return
+	}
+
+	// Estimate the cost in bits of the predefined distribution,
+	// if it covers every code, and of a table fitted to the counts.
+	predefCost := math.Inf(1)
+	if top < len(predef) {
+		predefCost = 0
+		for s, c := range counts[:top+1] {
+			if c == 0 {
+				continue
+			}
+			p := predef[s]
+			if p == -1 {
+				p = 1
+			}
+			predefCost += float64(c) * (float64(predefLog) - math.Log2(float64(p)))
+		}
+	}
+	log := optimalTableLog(maxLog, len(codes), top)
+	if cap(f.norm) < maxSym+1 {
+		f.norm = make([]int16, maxSym+1)
+	}
+	f.norm = f.norm[:top+1]
+	normalizeCounts(counts[:top+1], len(codes), log, f.norm)
+	fitCost := float64(8 * len(writeNormalizedCounts(nil, f.norm, log)))
+	for s, c := range counts[:top+1] {
+		if c > 0 {
+			fitCost += float64(c) * (float64(log) - math.Log2(float64(f.norm[s])))
+		}
+	}
+	if predefCost <= fitCost {
+		f.mode, f.table, f.log = 0, predefEnc, predefLog
+		return
+	}
+	f.tab.build(f.norm, log)
+	f.mode, f.table, f.log = 2, &f.tab, log
+}
+
+// appendSequences appends the sequences section for e.seqs to dst.
+func (e *encoder) appendSequences(dst []byte) []byte {
+	nseq := len(e.seqs)
+	switch {
+	case nseq < 128:
+		dst = append(dst, byte(nseq))
+	case nseq < 0x7F00:
+		dst = append(dst, byte(nseq>>8+128), byte(nseq))
+	default:
+		dst = append(dst, 255, byte(nseq-0x7F00), byte((nseq-0x7F00)>>8))
+	}
+	if nseq == 0 {
+		return dst
+	}
+
+	e.llCodes = e.llCodes[:0]
+	e.mlCodes = e.mlCodes[:0]
+	e.ofCodes = e.ofCodes[:0]
+	for _, s := range e.seqs {
+		e.llCodes = append(e.llCodes, llCode(s.litLen))
+		e.mlCodes = append(e.mlCodes, mlCode(s.matchLen))
+		e.ofCodes = append(e.ofCodes, uint8(highBit(s.offVal)))
+	}
+
+	initPredefined()
+	var ll, of, ml seqField
+	ll.choose(e.llCodes, maxLLCode, maxLLLog, predefLL, predefLLLog, &predefLLEnc)
+	of.choose(e.ofCodes, maxOFCode, maxOFLog, predefOF, predefOFLog, &predefOFEnc)
+	ml.choose(e.mlCodes, maxMLCode, maxMLLog, predefML, predefMLLog, &predefMLEnc)
+	dst = append(dst, ll.mode<<6|of.mode<<4|ml.mode<<2)
+	for _, f := range []*seqField{&ll, &of, &ml} {
+		switch f.mode {
+		case 1:
+			dst = append(dst, f.sym)
+		case 2:
+			dst = writeNormalizedCounts(dst, f.norm, f.log)
+		}
+	}
+
+	// Sequences are coded last to first, so that the decoder,
+	// reading the bitstream backward, sees them in order.
+	bw := bitWriter{out: dst}
+	var llEnc, ofEnc, mlEnc fseEncoder
+	n := nseq - 1
+	if ml.table != nil {
+		mlEnc.init(ml.table, e.mlCodes[n])
+	}
+	if of.table != nil {
+		ofEnc.init(of.table, e.ofCodes[n])
+	}
+	if ll.table != nil {
+		llEnc.init(ll.table, e.llCodes[n])
+	}
+	e.addExtraBits(&bw, n)
+	for n--; n >= 0; n-- {
+		if of.table != nil {
+			ofEnc.encode(&bw, e.ofCodes[n])
+		}
+		if ml.table != nil {
+			mlEnc.encode(&bw, e.mlCodes[n])
+		}
+		if ll.table != nil {
+			llEnc.encode(&bw, e.llCodes[n])
+		}
+		e.addExtraBits(&bw, n)
+	}
+	if ml.table != nil {
+		mlEnc.flush(&bw)
+	}
+	if of.table != nil {
+		ofEnc.flush(&bw)
+	}
+	if ll.table != nil {
+		llEnc.flush(&bw)
+	}
+	return bw.close()
+}
+
+// addExtraBits writes the extra bits of sequence n.
+func (e *encoder) addExtraBits(bw *bitWriter, n int) {
+	s := e.seqs[n]
+	llc, mlc, ofc := e.llCodes[n], e.mlCodes[n], e.ofCodes[n]
+	bw.addBits(s.litLen-llBase[llc], uint(llBits[llc]))
+	bw.addBits(s.matchLen-mlBase[mlc], uint(mlBits[mlc]))
+	bw.addBits(s.offVal-1<<ofc, uint(ofc))
+}
@@ -0,0 +1,74 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zstd"
+	"fmt"
+	"io"
+	"log"
+	"os"
+)
+
+func Example_writerReader() {
+	var buf bytes.Buffer
+	zw := zstd.NewWriter(&buf)
+	if _, err := zw.Write([]byte("hello, hello, hello, world\n")); err != nil {
+		log.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		log.Fatal(err)
+	}
+
+	zr := zstd.NewReader(&buf)
+	if _, err := io.Copy(os.Stdout, zr); err != nil {
+		log.Fatal(err)
+	}
+	if err := zr.Close(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Output:
+	// hello, hello, hello, world
+}
+
+func Example_zip() {
+	const methodZstd = 93
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	zw.RegisterCompressor(methodZstd, func(w io.Writer) (io.WriteCloser, error) {
+		return zstd.NewWriter(w), nil
+	})
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: "greeting.txt", Method: methodZstd})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Fprintln(w, "hello from a Zstandard-compressed zip entry")
+	if err := zw.Close(); err != nil {
+		log.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		log.Fatal(err)
+	}
+	zr.RegisterDecompressor(methodZstd, func(r io.Reader) io.ReadCloser {
+		return zstd.NewReader(r)
+	})
+	rc, err := zr.File[0].Open()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rc.Close()
+	if _, err := io.Copy(os.Stdout, rc); err != nil {
+		log.Fatal(err)
+	}
+
+	// Output:
+	// hello from a Zstandard-compressed zip entry
+}
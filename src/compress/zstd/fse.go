@@ -0,0 +1,411 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"errors"
+	"sync"
+)
+
+// Finite State Entropy (FSE) coding, RFC 8878 section 4.1.
+
+const (
+	minFSELog = 5
+	maxLLLog  = 9 // literals lengths
+	maxMLLog  = 9 // match lengths
+	maxOFLog  = 8 // offsets
+	maxHWLog  = 6 // Huffman weights
+
+	maxLLCode = 35
+	maxMLCode = 52
+	maxOFCode = 31
+)
+
+var errFSETable = errors.New("invalid FSE table description")
+
+// An fseEntry is one state of an FSE decoding table.
+type fseEntry struct {
+	sym  uint8  // symbol decoded in this state
+	bits uint8  // number of bits to read for the next state
+	base uint16 // added to the bits read to form the next state
+}
+
+// readNormalizedCounts reads an FSE table description from data into norm,
+// whose length bounds the number of symbols. It returns the accuracy log
+// of the table and the number of bytes consumed.
+func readNormalizedCounts(data []byte, norm []int16, maxLog int) (accLog, n int, err error) {
+	for i := range norm {
+		norm[i] = 0
+	}
+	br := forwardBitReader{data: data}
+	if len(data) == 0 {
+		return 0, 0, errFSETable
+	}
+	accLog = int(br.peek(4)) + minFSELog
+	br.skip(4)
+	if accLog > maxLog {
+		return 0, 0, errFSETable
+	}
+	remaining := int32(1<<accLog) + 1
+	threshold := int32(1 << accLog)
+	nbits := uint(accLog + 1)
+	sym := 0
+	prevZero := false
+	for remaining > 1 {
+		if prevZero {
+			// A zero probability is followed by 2-bit repeat flags
+			// counting further zero probabilities.
+			for {
+				if br.bytesRead() > len(data) {
+					return 0, 0, errFSETable
+				}
+				rep := int(br.peek(2))
+				br.skip(2)
+				sym += rep
+				if rep != 3 {
+					break
+				}
+			}
+		}
+		if sym >= len(norm) || br.bytesRead() > len(data) {
+			return 0, 0, errFSETable
+		}
+		max := 2*threshold - 1 - remaining
+		var count int32
+		if v := int32(br.peek(nbits - 1)); v < max {
+			count = v
+			br.skip(nbits - 1)
+		} else {
+			count = int32(br.peek(nbits))
+			if count >= threshold {
+				count -= max
+			}
+			br.skip(nbits)
+		}
+		count-- // -1 marks a "less than one" probability
+		if count < 0 {
+			remaining += count
+		} else {
+			remaining -= count
+		}
+		norm[sym] = int16(count)
+		sym++
+		prevZero = count == 0
+		for remaining < threshold {
+			nbits--
+			threshold >>= 1
+		}
+	}
+	if remaining != 1 || br.bytesRead() > len(data) {
+		return 0, 0, errFSETable
+	}
+	return accLog, br.bytesRead(), nil
+}
+
+// fseStep returns the step used to spread symbols over a table.
+func fseStep(tableSize int) int { return tableSize>>1 + tableSize>>3 + 3 }
+
+// spreadSymbols distributes the symbols of norm over a table of
+// 1<<accLog states, placing "less than one" probabilities at the end.
+// It returns the symbol of each state.
+func spreadSymbols(norm []int16, accLog int, symbols []uint8) error {
+	size := 1 << accLog
+	high := size - 1
+	for s, c := range norm {
+		if c == -1 {
+			symbols[high] = uint8(s)
+			high--
+		}
+	}
+	mask := size - 1
+	step := fseStep(size)
+	pos := 0
+	for s, c := range norm {
+		for i := 0; i < int(c); i++ {
+			symbols[pos] = uint8(s)
+			pos = (pos + step) & mask
+			for pos > high {
+				pos = (pos + step) & mask
+			}
+		}
+	}
+	if pos != 0 {
+		return errFSETable
+	}
+	return nil
+}
+
+// buildFSETable builds the decoding table for norm into table,
+// which must have 1<<accLog entries.
+func buildFSETable(norm []int16, accLog int, table []fseEntry) error {
+	var symbols [1 << maxLLLog]uint8
+	if err := spreadSymbols(norm, accLog, symbols[:len(table)]); err != nil {
+		return err
+	}
+	var next [256]uint16
+	for s, c := range norm {
+		if c == -1 {
+			next[s] = 1
+		} else {
+			next[s] = uint16(c)
+		}
+	}
+	size := uint32(len(table))
+	for i := range table {
+		s := symbols[i]
+		n := uint32(next[s])
+		next[s]++
+		nb := uint32(accLog) - highBit(n)
+		table[i] = fseEntry{
+			sym:  s,
+			bits: uint8(nb),
+			base: uint16(n<<nb - size),
+		}
+	}
+	return nil
+}
+
+// rleFSETable fills table, which has a single entry,
+// so that it always decodes sym.
+func rleFSETable(table []fseEntry, sym uint8) {
+	table[0] = fseEntry{sym: sym}
+}
+
+// Predefined distributions, RFC 8878 section 3.1.1.3.2.2.
+var (
+	predefLL = []int16{
+		4, 3, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 1, 1, 1,
+		2, 2, 2, 2, 2, 2, 2, 2, 2, 3, 2, 1, 1, 1, 1, 1,
+		-1, -1, -1, -1,
+	}
+	predefML = []int16{
+		1, 4, 3, 2, 2, 2, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1,
+		1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+		1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, -1, -1,
+		-1, -1, -1, -1, -1,
+	}
+	predefOF = []int16{
+		1, 1, 1, 1, 1, 1, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1,
+		1, 1, 1, 1, 1, 1, 1, 1, -1, -1, -1, -1, -1,
+	}
+)
+
+const (
+	predefLLLog = 6
+	predefMLLog = 6
+	predefOFLog = 5
+)
+
+var (
+	predefOnce                            sync.Once
+	predefLLTable, predefMLTable          [1 << 6]fseEntry
+	predefOFTable                         [1 << 5]fseEntry
+	predefLLEnc, predefMLEnc, predefOFEnc fseEncTable
+)
+
+func initPredefined() {
+	predefOnce.Do(func() {
+		buildFSETable(predefLL, predefLLLog, predefLLTable[:])
+		buildFSETable(predefML, predefMLLog, predefMLTable[:])
+		buildFSETable(predefOF, predefOFLog, predefOFTable[:])
+		predefLLEnc.build(predefLL, predefLLLog)
+		predefMLEnc.build(predefML, predefMLLog)
+		predefOFEnc.build(predefOF, predefOFLog)
+	})
+}
+
+// An fseEncTable is an FSE encoding table.
+type fseEncTable struct {
+	accLog     uint32
+	stateTable []uint16
+	symbolTT   []fseSymbolTransform
+}
+
+type fseSymbolTransform struct {
+	deltaFindState int32
+	deltaNbBits    uint32
+}
+
+// build initializes t to encode the distribution norm.
+func (t *fseEncTable) build(norm []int16, accLog int) {
+	size := 1 << accLog
+	t.accLog = uint32(accLog)
+	t.stateTable = make([]uint16, size)
+	t.symbolTT = make([]fseSymbolTransform, len(norm))
+
+	symbols := make([]uint8, size)
+	spreadSymbols(norm, accLog, symbols)
+
+	cumul := make([]int, len(norm)+1)
+	for s, c := range norm {
+		if c == -1 {
+			c = 1
+		}
+		cumul[s+1] = cumul[s] + int(c)
+	}
+	for u, s := range symbols {
+		t.stateTable[cumul[s]] = uint16(size + u)
+		cumul[s]++
+	}
+
+	total := int32(0)
+	for s, c := range norm {
+		switch c {
+		case 0:
+			t.symbolTT[s].deltaNbBits = uint32(accLog+1)<<16 - uint32(size)
+		case -1, 1:
+			t.symbolTT[s].deltaNbBits = uint32(accLog)<<16 - uint32(size)
+			t.symbolTT[s].deltaFindState = total - 1
+			total++
+		default:
+			maxBitsOut := uint32(accLog) - highBit(uint32(c)-1)
+			minStatePlus := uint32(c) << maxBitsOut
+			t.symbolTT[s].deltaNbBits = maxBitsOut<<16 - minStatePlus
+			t.symbolTT[s].deltaFindState = total - int32(c)
+			total += int32(c)
+		}
+	}
+}
+
+// An fseEncoder is the state of an FSE encoder writing to a bitWriter.
+type fseEncoder struct {
+	t     *fseEncTable
+	value uint32
+}
+
+// init starts encoding with sym, the last symbol of the stream.
+func (e *fseEncoder) init(t *fseEncTable, sym uint8) {
+	e.t = t
+	tt := t.symbolTT[sym]
+	nbBitsOut := (tt.deltaNbBits + 1<<15) >> 16
+	v := nbBitsOut<<16 - tt.deltaNbBits
+	e.value = uint32(t.stateTable[int32(v>>nbBitsOut)+tt.deltaFindState])
+}
+
+func (e *fseEncoder) encode(bw *bitWriter, sym uint8) {
+	tt := e.t.symbolTT[sym]
+	nbBitsOut := (e.value + tt.deltaNbBits) >> 16
+	bw.addBits(e.value, uint(nbBitsOut))
+	e.value = uint32(e.t.stateTable[int32(e.value>>nbBitsOut)+tt.deltaFindState])
+}
+
+// flush writes the final state, which the decoder reads first.
+func (e *fseEncoder) flush(bw *bitWriter) {
+	bw.addBits(e.value, uint(e.t.accLog))
+}
+
+// optimalTableLog returns the accuracy log to use for a distribution of
+// total samples over symbols 0 through maxSym.
+func optimalTableLog(maxLog int, total int, maxSym int) int {
+	log := maxLog
+	if b := int(highBit(uint32(total-1))) - 2; b < log {
+		log = b
+	}
+	minBits := int(highBit(uint32(total))) + 1
+	if b := int(highBit(uint32(maxSym))) + 2; b < minBits {
+		minBits = b
+	}
+	if minBits > log {
+		log = minBits
+	}
+	if log < minFSELog {
+		log = minFSELog
+	}
+	if log > maxLog {
+		log = maxLog
+	}
+	return log
+}
+
+// normalizeCounts scales counts, whose sum is total, to a distribution
+// summing to 1<<accLog in which every present symbol has a probability
+// of at least one. The result is stored in norm.
+func normalizeCounts(counts []uint32, total int, accLog int, norm []int16) {
+	scale := int64(1) << accLog
+	sum := int64(0)
+	largest := 0
+	for s, c := range counts {
+		if c == 0 {
+			norm[s] = 0
+			continue
+		}
+		p := int64(c) * scale / int64(total)
+		if p < 1 {
+			p = 1
+		}
+		norm[s] = int16(p)
+		sum += p
+		if c > counts[largest] {
+			largest = s
+		}
+	}
+	// Give the rounding error to the most frequent symbol, or spread it
+	// over the others if that would leave it without any probability.
+	diff := scale - sum
+	if int64(norm[largest])+diff >= 1 {
+		norm[largest] += int16(diff)
+		return
+	}
+	for diff < 0 {
+		for s := range norm {
+			if norm[s] > 1 && diff < 0 {
+				norm[s]--
+				diff++
+			}
+		}
+	}
+}
+
+// writeNormalizedCounts appends the FSE table description of norm to dst.
+func writeNormalizedCounts(dst []byte, norm []int16, accLog int) []byte {
+	var bw bitWriter
+	bw.out = dst
+	bw.addBits(uint32(accLog-minFSELog), 4)
+
+	nsym := len(norm)
+	for nsym > 0 && norm[nsym-1] == 0 {
+		nsym--
+	}
+	remaining := int32(1<<accLog) + 1
+	threshold := int32(1 << accLog)
+	nbits := uint(accLog + 1)
+	prevZero := false
+	for sym := 0; sym < nsym && remaining > 1; {
+		if prevZero {
+			start := sym
+			for norm[sym] == 0 {
+				sym++
+			}
+			for sym >= start+3 {
+				start += 3
+				bw.addBits(3, 2)
+			}
+			bw.addBits(uint32(sym-start), 2)
+		}
+		count := int32(norm[sym])
+		sym++
+		max := 2*threshold - 1 - remaining
+		if count < 0 {
+			remaining += count
+		} else {
+			remaining -= count
+		}
+		count++
+		if count >= threshold {
+			count += max
+		}
+		if count < max {
+			bw.addBits(uint32(count), nbits-1)
+		} else {
+			bw.addBits(uint32(count), nbits)
+		}
+		prevZero = count == 1
+		for remaining < threshold {
+			nbits--
+			threshold >>= 1
+		}
+	}
+	return bw.flushBytes()
+}
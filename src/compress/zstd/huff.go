@@ -0,0 +1,381 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"errors"
+	"sort"
+)
+
+// Huffman coding of literals, RFC 8878 section 4.2.
+
+const maxHuffBits = 11
+
+var errHuffTable = errors.New("invalid Huffman tree description")
+
+// A huffEntry is one entry of a Huffman decoding table,
+// indexed by the next maxBits bits of the stream.
+type huffEntry struct {
+	sym  uint8
+	bits uint8
+}
+
+// A huffTable is a Huffman decoding table.
+type huffTable struct {
+	maxBits uint8
+	entries []huffEntry // 1<<maxBits entries
+}
+
+// readHuffTable reads a Huffman tree description from data into t,
+// returning the number of bytes consumed.
+func (t *huffTable) read(data []byte) (int, error) {
+	if len(data) == 0 {
+		return 0, errHuffTable
+	}
+	var weights [256]uint8
+	var nw int
+	hdr := int(data[0])
+	n := 1
+	if hdr < 128 {
+		// FSE compressed weights.
+		if n+hdr > len(data) {
+			return 0, errHuffTable
+		}
+		var err error
+		nw, err = readFSEWeights(data[n:n+hdr], weights[:])
+		if err != nil {
+			return 0, err
+		}
+		n += hdr
+	} else {
+		// Weights stored as 4-bit values.
+		nw = hdr - 127
+		nb := (nw + 1) / 2
+		if n+nb > len(data) {
+			return 0, errHuffTable
+		}
+		for i := 0; i < nw; i++ {
+			b := data[n+i/2]
+			if i%2 == 0 {
+				weights[i] = b >> 4
+			} else {
+				weights[i] = b & 15
+			}
+		}
+		n += nb
+	}
+	if err := t.build(weights[:nw]); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// readFSEWeights decodes FSE compressed Huffman weights into weights,
+// returning the number of weights decoded.
+func readFSEWeights(data []byte, weights []uint8) (int, error) {
+	var norm [maxHuffBits + 1]int16
+	accLog, n, err := readNormalizedCounts(data, norm[:], maxHWLog)
+	if err != nil {
+		return 0, errHuffTable
+	}
+	var table [1 << maxHWLog]fseEntry
+	if err := buildFSETable(norm[:], accLog, table[:1<<accLog]); err != nil {
+		return 0, errHuffTable
+	}
+	br, err := newReverseBitReader(data[n:])
+	if err != nil {
+		return 0, errHuffTable
+	}
+	state1 := br.val(uint8(accLog))
+	state2 := br.val(uint8(accLog))
+	if br.overflow() {
+		return 0, errHuffTable
+	}
+
+	// Decode from the two interleaved states alternately until
+	// reading a state update runs past the start of the stream.
+	count := 0
+	for {
+		if count+2 > len(weights) {
+			return 0, errHuffTable
+		}
+		e := table[state1]
+		weights[count] = e.sym
+		count++
+		state1 = uint32(e.base) + br.val(e.bits)
+		if br.overflow() {
+			weights[count] = table[state2].sym
+			count++
+			break
+		}
+		e = table[state2]
+		weights[count] = e.sym
+		count++
+		state2 = uint32(e.base) + br.val(e.bits)
+		if br.overflow() {
+			weights[count] = table[state1].sym
+			count++
+			break
+		}
+	}
+	return count, nil
+}
+
+// build fills t from the weights of all symbols but the last,
+// whose weight is implied.
+func (t *huffTable) build(weights []uint8) error {
+	if len(weights) == 0 || len(weights) > 255 {
+		return errHuffTable
+	}
+	total := uint32(0)
+	for _, w := range weights {
+		if w > maxHuffBits {
+			return errHuffTable
+		}
+		if w > 0 {
+			total += 1 << (w - 1)
+		}
+	}
+	if total == 0 {
+		return errHuffTable
+	}
+	maxBits := highBit(total) + 1
+	if maxBits > maxHuffBits {
+		return errHuffTable
+	}
+	rest := uint32(1)<<maxBits - total
+	if rest&(rest-1) != 0 {
+		return errHuffTable
+	}
+	lastWeight := uint8(highBit(rest) + 1)
+
+	var all [256]uint8
+	copy(all[:], weights)
+	all[len(weights)] = lastWeight
+	nsym := len(weights) + 1
+
+	size := 1 << maxBits
+	if cap(t.entries) < size {
+		t.entries = make([]huffEntry, size)
+	}
+	t.entries = t.entries[:size]
+	t.maxBits = uint8(maxBits)
+
+	// Codes are assigned in order of increasing weight
+	// and, within a weight, increasing symbol value.
+	pos := 0
+	for w := uint8(1); w <= uint8(maxBits); w++ {
+		span := 1 << (w - 1)
+		e := huffEntry{bits: uint8(maxBits) + 1 - w}
+		for s := 0; s < nsym; s++ {
+			if all[s] != w {
+				continue
+			}
+			e.sym = uint8(s)
+			for i := 0; i < span; i++ {
+				t.entries[pos+i] = e
+			}
+			pos += span
+		}
+	}
+	if pos != size {
+		return errHuffTable
+	}
+	return nil
+}
+
+// decode decodes the single Huffman coded stream data into out.
+func (t *huffTable) decode(data []byte, out []byte) error {
+	br, err := newReverseBitReader(data)
+	if err != nil {
+		return err
+	}
+	for i := range out {
+		e := t.entries[br.peek(t.maxBits)]
+		out[i] = e.sym
+		br.skip(e.bits)
+	}
+	if !br.finished() {
+		return errBitstream
+	}
+	return nil
+}
+
+// A huffEncoder holds a Huffman code for literals.
+type huffEncoder struct {
+	maxBits uint8
+	nsym    int // symbols 0 through nsym-1 may be coded
+	bits    [256]uint8
+	codes   [256]uint16
+}
+
+// build constructs a code for the symbol frequencies in counts,
+// limiting code lengths to maxHuffBits. At least two symbols
+// must have nonzero counts.
+func (h *huffEncoder) build(counts *[256]uint32) {
+	type node struct {
+		syms  []uint8
+		count uint64
+	}
+	var leaves []node
+	h.nsym = 0
+	for s, c := range counts {
+		h.bits[s] = 0
+		if c > 0 {
+			leaves = append(leaves, node{syms: []uint8{uint8(s)}, count: uint64(c)})
+			h.nsym = s + 1
+		}
+	}
+	sort.SliceStable(leaves, func(i, j int) bool { return leaves[i].count < leaves[j].count })
+
+	// Package-merge: each of the maxHuffBits rounds merges pairs of
+	// packages, and every time a symbol appears in one of the 2n-2
+	// cheapest packages of the final list its code grows by one bit.
+	list := leaves
+	for i := 1; i < maxHuffBits; i++ {
+		var pkgs []node
+		for j := 0; j+1 < len(list); j += 2 {
+			syms := append(append([]uint8(nil), list[j].syms...), list[j+1].syms...)
+			pkgs = append(pkgs, node{syms: syms, count: list[j].count + list[j+1].count})
+		}
+		merged := make([]node, 0, len(leaves)+len(pkgs))
+		a, b := 0, 0
+		for a < len(leaves) || b < len(pkgs) {
+			if b == len(pkgs) || a < len(leaves) && leaves[a].count <= pkgs[b].count {
+				merged = append(merged, leaves[a])
+				a++
+			} else {
+				merged = append(merged, pkgs[b])
+				b++
+			}
+		}
+		list = merged
+	}
+	for _, n := range list[:2*len(leaves)-2] {
+		for _, s := range n.syms {
+			h.bits[s]++
+		}
+	}
+
+	h.maxBits = 0
+	for _, b := range h.bits[:h.nsym] {
+		if b > h.maxBits {
+			h.maxBits = b
+		}
+	}
+
+	// Assign codes in the order used by huffTable.build.
+	pos := 0
+	for w := uint8(1); w <= h.maxBits; w++ {
+		for s := 0; s < h.nsym; s++ {
+			if h.bits[s] == 0 || h.maxBits+1-h.bits[s] != w {
+				continue
+			}
+			h.codes[s] = uint16(pos >> (w - 1))
+			pos += 1 << (w - 1)
+		}
+	}
+}
+
+// weight returns the weight of symbol s.
+func (h *huffEncoder) weight(s int) uint8 {
+	if h.bits[s] == 0 {
+		return 0
+	}
+	return h.maxBits + 1 - h.bits[s]
+}
+
+// appendTable appends the Huffman tree description of h to dst.
+// It returns nil if h cannot be described.
+func (h *huffEncoder) appendTable(dst []byte) []byte {
+	nw := h.nsym - 1 // the last weight is implied
+	if nw <= 128 {
+		if fse := h.appendFSEWeights(nil, nw); fse != nil && len(fse) < 1+(nw+1)/2 {
+			return append(dst, fse...)
+		}
+		dst = append(dst, byte(127+nw))
+		for i := 0; i < nw; i += 2 {
+			b := h.weight(i) << 4
+			if i+1 < nw {
+				b |= h.weight(i + 1)
+			}
+			dst = append(dst, b)
+		}
+		return dst
+	}
+	return h.appendFSEWeights(dst, nw)
+}
+
+// appendFSEWeights appends the first nw weights of h, FSE compressed and
+// preceded by their size, to dst. It returns nil if they cannot be
+// represented that way.
+func (h *huffEncoder) appendFSEWeights(dst []byte, nw int) []byte {
+	var weights [256]uint8
+	var counts [maxHuffBits + 1]uint32
+	maxSym := 0
+	for i := 0; i < nw; i++ {
+		w := h.weight(i)
+		weights[i] = w
+		counts[w]++
+		if int(w) > maxSym {
+			maxSym = int(w)
+		}
+	}
+	for _, c := range counts {
+		if int(c) == nw {
+			return nil // a single weight cannot be FSE coded
+		}
+	}
+	accLog := optimalTableLog(maxHWLog, nw, maxSym)
+	var norm [maxHuffBits + 1]int16
+	normalizeCounts(counts[:maxSym+1], nw, accLog, norm[:maxSym+1])
+	var t fseEncTable
+	t.build(norm[:maxSym+1], accLog)
+
+	start := len(dst)
+	dst = append(dst, 0)
+	dst = writeNormalizedCounts(dst, norm[:maxSym+1], accLog)
+
+	// Encode backward with two interleaved states; the decoder
+	// starts with the first state on the first weight.
+	bw := bitWriter{out: dst}
+	var s1, s2 fseEncoder
+	i := nw
+	if nw%2 == 1 {
+		s1.init(&t, weights[i-1])
+		s2.init(&t, weights[i-2])
+		i -= 2
+		i--
+		s1.encode(&bw, weights[i])
+	} else {
+		s2.init(&t, weights[i-1])
+		s1.init(&t, weights[i-2])
+		i -= 2
+	}
+	for i > 0 {
+		s2.encode(&bw, weights[i-1])
+		s1.encode(&bw, weights[i-2])
+		i -= 2
+	}
+	s2.flush(&bw)
+	s1.flush(&bw)
+	dst = bw.close()
+	size := len(dst) - start - 1
+	if size >= 128 {
+		return nil
+	}
+	dst[start] = byte(size)
+	return dst
+}
+
+// encode appends the Huffman coding of src to dst as a single stream.
+func (h *huffEncoder) encode(dst, src []byte) []byte {
+	bw := bitWriter{out: dst}
+	for i := len(src) - 1; i >= 0; i-- {
+		s := src[i]
+		bw.addBits(uint32(h.codes[s]), uint(h.bits[s]))
+	}
+	return bw.close()
+}
@@ -0,0 +1,377 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"io"
+)
+
+// A Reader is an io.ReadCloser that decompresses a Zstandard stream.
+//
+// The stream may be a concatenation of frames; reads return the
+// concatenation of their decompressed contents. Skippable frames
+// are ignored. If a frame carries a content checksum, the Reader
+// returns ErrChecksum when it reaches the end of a frame whose
+// checksum does not match, so clients should treat data returned
+// by Read as tentative until they receive io.EOF.
+//
+// The Reader never reads past the end of the last frame from the
+// underlying reader.
+type Reader struct {
+	r    io.Reader
+	dict *dictionary
+	err  error
+	off  int64 // offset of the next byte to read from r
+
+	// Current frame.
+	inFrame     bool
+	windowSize  int
+	blockMax    int
+	hasChecksum bool
+	contentSize int64 // -1 if unknown
+	decoded     int64
+	digest      xxhash64
+
+	// hist holds the window of previously decoded data
+	// followed by the output of the current block.
+	// hist[pos:] has not been returned by Read yet.
+	hist []byte
+	pos  int
+
+	// Entropy state carried from block to block.
+	repeats [3]uint32
+	huff    *huffTable
+	huffBuf huffTable
+	ll      seqTable
+	ml      seqTable
+	of      seqTable
+
+	block    []byte
+	literals []byte
+	buf      [18]byte
+}
+
+// NewReader returns a new Reader decompressing r.
+//
+// It is the caller's responsibility to call Close on the Reader when done.
+func NewReader(r io.Reader) *Reader {
+	z := new(Reader)
+	z.Reset(r)
+	return z
+}
+
+// NewReaderDict is like NewReader but decompresses frames that were
+// compressed with the dictionary dict. The dictionary may be in the
+// Zstandard dictionary format or consist of raw content.
+// Frames that name a different dictionary fail with ErrDictionary.
+func NewReaderDict(r io.Reader, dict []byte) (*Reader, error) {
+	d, err := parseDict(dict)
+	if err != nil {
+		return nil, err
+	}
+	z := new(Reader)
+	z.dict = d
+	z.Reset(r)
+	return z, nil
+}
+
+// Reset discards the Reader z's state and makes it equivalent to the
+// result of its original state from NewReader or NewReaderDict, but
+// reading from r instead. This permits reusing a Reader rather than
+// allocating a new one.
+func (z *Reader) Reset(r io.Reader) {
+	*z = Reader{
+		r:        r,
+		dict:     z.dict,
+		hist:     z.hist[:0],
+		block:    z.block,
+		literals: z.literals,
+		huffBuf:  z.huffBuf,
+	}
+}
+
+// Close closes the Reader. It does not close the underlying io.Reader.
+// In order for the content checksum to be verified, the reader must be
+// fully consumed until the io.EOF.
+func (z *Reader) Close() error {
+	if z.err == io.EOF {
+		return nil
+	}
+	return z.err
+}
+
+// Read reads decompressed data from the stream.
+func (z *Reader) Read(p []byte) (int, error) {
+	for z.pos == len(z.hist) {
+		if z.err != nil {
+			return 0, z.err
+		}
+		z.err = z.step()
+	}
+	n := copy(p, z.hist[z.pos:])
+	z.pos += n
+	return n, nil
+}
+
+// step decodes the next block, or the next frame header,
+// adding any output to z.hist.
+func (z *Reader) step() error {
+	if !z.inFrame {
+		return z.readFrameHeader()
+	}
+
+	start := z.off
+	if err := z.readFull(z.buf[:3]); err != nil {
+		return noEOF(err)
+	}
+	hdr := uint32(z.buf[0]) | uint32(z.buf[1])<<8 | uint32(z.buf[2])<<16
+	last := hdr&1 != 0
+	typ := (hdr >> 1) & 3
+	size := int(hdr >> 3)
+
+	// Keep no more than the window before the block output.
+	if len(z.hist) > 2*z.windowSize {
+		n := copy(z.hist, z.hist[len(z.hist)-z.windowSize:])
+		z.hist = z.hist[:n]
+		z.pos = n
+	}
+	out := len(z.hist)
+
+	switch typ {
+	case 0: // Raw_Block
+		if size > z.blockMax {
+			return z.corrupt(start, "block too large")
+		}
+		z.hist = grow(z.hist, size)
+		if err := z.readFull(z.hist[out:]); err != nil {
+			return noEOF(err)
+		}
+	case 1: // RLE_Block
+		if size > z.blockMax {
+			return z.corrupt(start, "block too large")
+		}
+		if err := z.readFull(z.buf[:1]); err != nil {
+			return noEOF(err)
+		}
+		z.hist = grow(z.hist, size)
+		b := z.hist[out:]
+		for i := range b {
+			b[i] = z.buf[0]
+		}
+	case 2: // Compressed_Block
+		if size > z.blockMax {
+			return z.corrupt(start, "block too large")
+		}
+		if cap(z.block) < size {
+			z.block = make([]byte, size)
+		}
+		z.block = z.block[:size]
+		if err := z.readFull(z.block); err != nil {
+			return noEOF(err)
+		}
+		if err := z.compressedBlock(z.block); err != nil {
+			return z.corrupt(start, err.Error())
+		}
+		if len(z.hist)-out > z.blockMax {
+			return z.corrupt(start, "block too large")
+		}
+	default:
+		return z.corrupt(start, "reserved block type")
+	}
+
+	z.decoded += int64(len(z.hist) - out)
+	if z.hasChecksum {
+		z.digest.write(z.hist[out:])
+	}
+	if last {
+		return z.endFrame()
+	}
+	return nil
+}
+
+// readFrameHeader reads the header of the next frame,
+// skipping any skippable frames that precede it.
+func (z *Reader) readFrameHeader() error {
+	for {
+		start := z.off
+		if err := z.readFull(z.buf[:4]); err != nil {
+			if err == io.EOF && z.off == start {
+				return io.EOF
+			}
+			return noEOF(err)
+		}
+		magic := le.Uint32(z.buf[:4])
+		if magic&skippableMask == skippableMagic {
+			if err := z.readFull(z.buf[:4]); err != nil {
+				return noEOF(err)
+			}
+			n := int64(le.Uint32(z.buf[:4]))
+			m, err := io.CopyN(io.Discard, z.r, n)
+			z.off += m
+			if err != nil {
+				return noEOF(err)
+			}
+			continue
+		}
+		if magic != frameMagic {
+			return z.corrupt(start, "invalid magic number")
+		}
+		return z.readFrameDescriptor(start)
+	}
+}
+
+func (z *Reader) readFrameDescriptor(start int64) error {
+	if err := z.readFull(z.buf[:1]); err != nil {
+		return noEOF(err)
+	}
+	desc := z.buf[0]
+	fcsFlag := desc >> 6
+	singleSegment := desc&(1<<5) != 0
+	if desc&(1<<3) != 0 {
+		return z.corrupt(start, "reserved bit set in frame header")
+	}
+	z.hasChecksum = desc&(1<<2) != 0
+	didSize := [4]int{0, 1, 2, 4}[desc&3]
+	fcsSize := [4]int{0, 2, 4, 8}[fcsFlag]
+	if fcsFlag == 0 && singleSegment {
+		fcsSize = 1
+	}
+	n := didSize + fcsSize
+	if !singleSegment {
+		n++
+	}
+	b := z.buf[:n]
+	if err := z.readFull(b); err != nil {
+		return noEOF(err)
+	}
+
+	windowSize := 0
+	if !singleSegment {
+		wd := b[0]
+		b = b[1:]
+		exp := uint(wd >> 3)
+		if exp+minWindowLog > maxWindowLog {
+			return z.corrupt(start, "window size too large")
+		}
+		base := uint64(1) << (exp + minWindowLog)
+		size := base + base/8*uint64(wd&7)
+		if size > maxDecodedWindow {
+			return z.corrupt(start, "window size too large")
+		}
+		windowSize = int(size)
+	}
+
+	var dictID uint32
+	switch didSize {
+	case 1:
+		dictID = uint32(b[0])
+	case 2:
+		dictID = uint32(le.Uint16(b))
+	case 4:
+		dictID = le.Uint32(b)
+	}
+	b = b[didSize:]
+
+	z.contentSize = -1
+	switch fcsSize {
+	case 1:
+		z.contentSize = int64(b[0])
+	case 2:
+		z.contentSize = int64(le.Uint16(b)) + 256
+	case 4:
+		z.contentSize = int64(le.Uint32(b))
+	case 8:
+		z.contentSize = int64(le.Uint64(b))
+		if z.contentSize < 0 {
+			return z.corrupt(start, "frame content size too large")
+		}
+	}
+	if singleSegment {
+		if z.contentSize > maxDecodedWindow {
+			return z.corrupt(start, "window size too large")
+		}
+		windowSize = int(z.contentSize)
+	}
+
+	z.windowSize = windowSize
+	z.blockMax = maxBlockSize
+	if windowSize < z.blockMax {
+		z.blockMax = windowSize
+	}
+	z.decoded = 0
+	z.digest.reset()
+
+	// Start the window with the dictionary content, if any.
+	z.hist = z.hist[:0]
+	z.repeats = [3]uint32{1, 4, 8}
+	z.huff = nil
+	z.ll, z.ml, z.of = seqTable{}, seqTable{}, seqTable{}
+	if dictID != 0 && (z.dict == nil || z.dict.id != dictID) {
+		return ErrDictionary
+	}
+	if d := z.dict; d != nil {
+		z.hist = append(z.hist, d.content...)
+		z.repeats = d.repeats
+		if d.hasTables {
+			z.huff = &d.huff
+			z.ll.set(d.llTable[:1<<d.llLog], d.llLog)
+			z.ml.set(d.mlTable[:1<<d.mlLog], d.mlLog)
+			z.of.set(d.ofTable[:1<<d.ofLog], d.ofLog)
+		}
+	}
+	z.pos = len(z.hist)
+	if z.windowSize < len(z.hist) {
+		// Matches may refer to the whole dictionary;
+		// keep it while trimming the window.
+		z.windowSize = len(z.hist)
+	}
+	z.inFrame = true
+	return nil
+}
+
+// endFrame verifies the content size and checksum of the current frame.
+func (z *Reader) endFrame() error {
+	z.inFrame = false
+	if z.contentSize >= 0 && z.decoded != z.contentSize {
+		return z.corrupt(z.off, "frame content size mismatch")
+	}
+	if z.hasChecksum {
+		if err := z.readFull(z.buf[:4]); err != nil {
+			return noEOF(err)
+		}
+		if le.Uint32(z.buf[:4]) != uint32(z.digest.sum64()) {
+			return ErrChecksum
+		}
+	}
+	return nil
+}
+
+func (z *Reader) readFull(b []byte) error {
+	n, err := io.ReadFull(z.r, b)
+	z.off += int64(n)
+	return err
+}
+
+func (z *Reader) corrupt(off int64, reason string) error {
+	return &CorruptInputError{Offset: off, Reason: reason}
+}
+
+// noEOF converts io.EOF to io.ErrUnexpectedEOF.
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// grow extends b by n bytes, reallocating if necessary.
+func grow(b []byte, n int) []byte {
+	if cap(b)-len(b) < n {
+		nb := make([]byte, len(b), 2*cap(b)+n)
+		copy(nb, b)
+		b = nb
+	}
+	return b[:len(b)+n]
+}
@@ -0,0 +1,167 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+)
+
+func mustReadFile(t testing.TB, name string) []byte {
+	t.Helper()
+	b, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// The files in testdata were produced by the reference zstd tool,
+// at various levels, with and without checksums and dictionaries.
+var readerTests = []struct {
+	file  string
+	dict  string
+	input []string // files whose concatenation is the expected output
+}{
+	{"gettysburg.txt.zst", "", []string{"../testdata/gettysburg.txt"}},
+	{"e.txt.zst", "", []string{"../testdata/e.txt"}},
+	{"pi.txt.zst", "", []string{"../testdata/pi.txt"}},
+	{"concat.zst", "", []string{
+		"../testdata/e.txt",
+		"../testdata/pi.txt",
+		"../testdata/gettysburg.txt",
+		"../testdata/e.txt",
+	}},
+	{"gettysburg.txt.dict.zst", "dict", []string{"../testdata/gettysburg.txt"}},
+}
+
+func TestReader(t *testing.T) {
+	for _, tt := range readerTests {
+		t.Run(tt.file, func(t *testing.T) {
+			var want []byte
+			for _, name := range tt.input {
+				want = append(want, mustReadFile(t, name)...)
+			}
+			data := mustReadFile(t, "testdata/"+tt.file)
+			var z *Reader
+			if tt.dict != "" {
+				var err error
+				z, err = NewReaderDict(bytes.NewReader(data), mustReadFile(t, "testdata/"+tt.dict))
+				if err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				z = NewReader(bytes.NewReader(data))
+			}
+			got, err := io.ReadAll(z)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf("decompressed %d bytes, want %d bytes matching input", len(got), len(want))
+			}
+			if err := z.Close(); err != nil {
+				t.Errorf("Close: %v", err)
+			}
+		})
+	}
+}
+
+func TestReaderMissingDict(t *testing.T) {
+	data := mustReadFile(t, "testdata/gettysburg.txt.dict.zst")
+	_, err := io.ReadAll(NewReader(bytes.NewReader(data)))
+	if err != ErrDictionary {
+		t.Fatalf("got error %v, want %v", err, ErrDictionary)
+	}
+}
+
+func TestReaderFrames(t *testing.T) {
+	a := mustReadFile(t, "testdata/gettysburg.txt.zst")
+	b := mustReadFile(t, "testdata/pi.txt.zst")
+	want := append(mustReadFile(t, "../testdata/gettysburg.txt"), mustReadFile(t, "../testdata/pi.txt")...)
+
+	// A skippable frame before, between, and after the frames.
+	skip := []byte{0x50, 0x2A, 0x4D, 0x18, 3, 0, 0, 0, 'a', 'b', 'c'}
+	var data []byte
+	data = append(data, skip...)
+	data = append(data, a...)
+	data = append(data, skip...)
+	data = append(data, b...)
+	data = append(data, skip...)
+
+	z := NewReader(bytes.NewReader(data))
+	got, err := io.ReadAll(z)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("decompressed %d bytes, want %d bytes", len(got), len(want))
+	}
+
+	// Reset reuses the Reader.
+	z.Reset(bytes.NewReader(a))
+	got, err = io.ReadAll(z)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want[:len(got)]) || len(got) != 1548 {
+		t.Fatalf("after Reset, decompressed %d bytes, want 1548", len(got))
+	}
+}
+
+func TestReaderCorrupt(t *testing.T) {
+	data := mustReadFile(t, "testdata/e.txt.zst")
+
+	// Truncation anywhere is an error.
+	for _, n := range []int{0, 3, 5, 100, len(data) / 2, len(data) - 4, len(data) - 1} {
+		_, err := io.ReadAll(NewReader(bytes.NewReader(data[:n])))
+		if n == 0 {
+			if err != nil {
+				t.Errorf("empty input: got error %v, want nil", err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("input truncated to %d bytes: got nil error", n)
+		}
+	}
+
+	// A damaged checksum is detected.
+	bad := bytes.Clone(data)
+	bad[len(bad)-1] ^= 1
+	if _, err := io.ReadAll(NewReader(bytes.NewReader(bad))); err != ErrChecksum {
+		t.Errorf("damaged checksum: got error %v, want %v", err, ErrChecksum)
+	}
+
+	// So is a bad magic number.
+	bad = bytes.Clone(data)
+	bad[0] ^= 1
+	_, err := io.ReadAll(NewReader(bytes.NewReader(bad)))
+	var cerr *CorruptInputError
+	if !errors.As(err, &cerr) || cerr.Offset != 0 {
+		t.Errorf("bad magic: got error %v, want CorruptInputError at offset 0", err)
+	}
+
+	// Damage to the compressed data must not cause a panic.
+	for i := 6; i < len(data); i += 97 {
+		bad := bytes.Clone(data)
+		bad[i] ^= 0x55
+		io.ReadAll(NewReader(bytes.NewReader(bad)))
+	}
+}
+
+func FuzzReader(f *testing.F) {
+	for _, tt := range readerTests {
+		if tt.dict == "" {
+			f.Add(mustReadFile(f, "testdata/"+tt.file))
+		}
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		io.ReadAll(NewReader(bytes.NewReader(data)))
+	})
+}
@@ -0,0 +1,326 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// segmentSize is the amount of input compressed by each goroutine
+// when a Writer compresses concurrently.
+const segmentSize = 8 * maxBlockSize
+
+// A Writer is an io.WriteCloser.
+// Writes to a Writer are compressed and written to w
+// as a single Zstandard frame.
+type Writer struct {
+	w           io.Writer
+	p           encParams
+	dict        *dictionary
+	concurrency int
+	wroteHeader bool
+	closed      bool
+	err         error
+	digest      xxhash64
+	buf         []byte // input not yet compressed
+	out         []byte
+
+	// Sequential compression.
+	enc *encoder
+
+	// Concurrent compression. prev holds the input preceding buf,
+	// which the next segment may refer to, and jobs the results of
+	// segments in flight, in order.
+	prev []byte
+	jobs []chan segmentResult
+	pool sync.Pool // of *encoder
+}
+
+type segmentResult struct {
+	out []byte
+}
+
+// NewWriter returns a new Writer compressing data at the default level.
+// Writes to the returned writer are compressed and written to w.
+//
+// It is the caller's responsibility to call Close on the Writer when done.
+// Writes may be buffered and not flushed until Close.
+func NewWriter(w io.Writer) *Writer {
+	z, _ := NewWriterLevel(w, DefaultCompression)
+	return z
+}
+
+// NewWriterLevel is like NewWriter but specifies the compression level
+// instead of assuming DefaultCompression.
+//
+// The compression level can be DefaultCompression or any integer value
+// between BestSpeed and BestCompression inclusive. The error returned
+// will be nil if the level is valid.
+func NewWriterLevel(w io.Writer, level int) (*Writer, error) {
+	return NewWriterDict(w, level, nil)
+}
+
+// NewWriterDict is like NewWriterLevel but compresses using a dictionary,
+// which must also be supplied to decompress the data, using NewReaderDict.
+// The dictionary may be in the Zstandard dictionary format, in which case
+// its identifier is recorded in the frame header, or consist of raw content.
+func NewWriterDict(w io.Writer, level int, dict []byte) (*Writer, error) {
+	if level == DefaultCompression {
+		level = 3
+	}
+	if level < BestSpeed || level > BestCompression {
+		return nil, fmt.Errorf("zstd: invalid compression level: %d", level)
+	}
+	z := &Writer{p: levelParams[level], concurrency: 1}
+	if dict != nil {
+		d, err := parseDict(dict)
+		if err != nil {
+			return nil, err
+		}
+		z.dict = d
+	}
+	z.Reset(w)
+	return z, nil
+}
+
+// SetConcurrency sets the number of goroutines the Writer uses to
+// compress its input. With n > 1, the input is divided into segments
+// that are compressed independently of each other, at some cost in
+// compression ratio. The default is 1.
+//
+// SetConcurrency must be called before the first call to
+// Write, Flush, or Close.
+func (z *Writer) SetConcurrency(n int) error {
+	if z.wroteHeader {
+		return errors.New("zstd: SetConcurrency called after Write")
+	}
+	if n < 1 {
+		n = 1
+	}
+	z.concurrency = n
+	return nil
+}
+
+// Reset discards the Writer z's state and makes it equivalent to the
+// result of its original state from NewWriter, NewWriterLevel or
+// NewWriterDict, but writing to w instead. This permits reusing
+// a Writer rather than allocating a new one.
+func (z *Writer) Reset(w io.Writer) {
+	z.wait()
+	z.w = w
+	z.wroteHeader = false
+	z.closed = false
+	z.err = nil
+	z.digest.reset()
+	z.buf = z.buf[:0]
+	z.prev = z.prev[:0]
+	if z.dict != nil {
+		z.prev = append(z.prev, z.dict.content...)
+	}
+}
+
+// wait discards the results of any segments still being compressed.
+func (z *Writer) wait() {
+	for _, job := range z.jobs {
+		<-job
+	}
+	z.jobs = z.jobs[:0]
+}
+
+func (z *Writer) writeHeader() error {
+	z.wroteHeader = true
+	b := z.out[:0]
+	b = appendUint32(b, frameMagic)
+	desc := byte(1 << 2) // Content_Checksum_flag
+	if z.dict != nil && z.dict.id != 0 {
+		desc |= 3 // 4-byte Dictionary_ID
+	}
+	b = append(b, desc, byte(z.p.windowLog-minWindowLog)<<3)
+	if desc&3 != 0 {
+		b = appendUint32(b, z.dict.id)
+	}
+	z.out = b
+
+	if z.concurrency <= 1 {
+		// A single encoder compresses the whole frame, starting
+		// with the repeat offsets the decoder will use.
+		if z.enc == nil {
+			z.enc = new(encoder)
+		}
+		var history []byte
+		if z.dict != nil {
+			history = z.dict.content
+		}
+		z.enc.reset(z.p, history)
+		if z.dict != nil {
+			z.enc.rep = z.dict.repeats
+		}
+		z.enc.repValid = 3
+	}
+	_, err := z.w.Write(b)
+	return err
+}
+
+// Write writes a compressed form of p to the underlying io.Writer. The
+// compressed bytes are not necessarily flushed until the Writer is closed.
+func (z *Writer) Write(p []byte) (int, error) {
+	if z.err != nil {
+		return 0, z.err
+	}
+	if z.closed {
+		return 0, errors.New("zstd: write to closed Writer")
+	}
+	if !z.wroteHeader {
+		if z.err = z.writeHeader(); z.err != nil {
+			return 0, z.err
+		}
+	}
+	z.digest.write(p)
+	z.buf = append(z.buf, p...)
+
+	// Compress whole blocks (or segments), holding back the
+	// last one so that Close has data for the frame's last block.
+	chunk := maxBlockSize
+	if z.concurrency > 1 {
+		chunk = segmentSize
+	}
+	off := 0
+	for len(z.buf)-off > chunk {
+		if z.err = z.compress(z.buf[off:off+chunk], false); z.err != nil {
+			return 0, z.err
+		}
+		off += chunk
+	}
+	if off > 0 {
+		n := copy(z.buf, z.buf[off:])
+		z.buf = z.buf[:n]
+	}
+	return len(p), nil
+}
+
+// compress compresses src as the next block or segment.
+func (z *Writer) compress(src []byte, last bool) error {
+	if z.concurrency <= 1 {
+		z.out = z.enc.appendBlock(z.out[:0], src, last)
+		_, err := z.w.Write(z.out)
+		return err
+	}
+
+	// Start a goroutine to compress the segment, first waiting for
+	// the oldest segment in flight if there are already enough.
+	if len(z.jobs) >= z.concurrency {
+		if err := z.writeResult(); err != nil {
+			return err
+		}
+	}
+	seg := append([]byte(nil), src...)
+	prev := z.prev
+	ch := make(chan segmentResult, 1)
+	z.jobs = append(z.jobs, ch)
+	go func() {
+		enc, _ := z.pool.Get().(*encoder)
+		if enc == nil {
+			enc = new(encoder)
+		}
+		enc.reset(z.p, prev)
+		var out []byte
+		for len(seg) > maxBlockSize {
+			out = enc.appendBlock(out, seg[:maxBlockSize], false)
+			seg = seg[maxBlockSize:]
+		}
+		out = enc.appendBlock(out, seg, last)
+		z.pool.Put(enc)
+		ch <- segmentResult{out}
+	}()
+
+	// Remember the end of this segment for the next one.
+	n := z.p.windowLog
+	keep := 1 << n
+	if keep > segmentSize {
+		keep = segmentSize
+	}
+	if len(src) >= keep {
+		z.prev = append([]byte(nil), src[len(src)-keep:]...)
+	} else {
+		z.prev = append(append([]byte(nil), z.prev...), src...)
+		if len(z.prev) > keep {
+			z.prev = z.prev[len(z.prev)-keep:]
+		}
+	}
+	return nil
+}
+
+// writeResult writes the output of the oldest segment in flight.
+func (z *Writer) writeResult() error {
+	r := <-z.jobs[0]
+	z.jobs = z.jobs[1:]
+	_, err := z.w.Write(r.out)
+	return err
+}
+
+// Flush writes any pending data to the underlying writer.
+//
+// It is useful mainly in compressed network protocols, to ensure that
+// a remote reader has enough data to reconstruct a packet. Flush does
+// not return until the data has been written. If the underlying
+// writer returns an error, Flush returns that error.
+func (z *Writer) Flush() error {
+	if z.err != nil {
+		return z.err
+	}
+	if z.closed {
+		return nil
+	}
+	if !z.wroteHeader {
+		if z.err = z.writeHeader(); z.err != nil {
+			return z.err
+		}
+	}
+	if len(z.buf) > 0 {
+		if z.err = z.compress(z.buf, false); z.err != nil {
+			return z.err
+		}
+		z.buf = z.buf[:0]
+	}
+	for len(z.jobs) > 0 {
+		if z.err = z.writeResult(); z.err != nil {
+			return z.err
+		}
+	}
+	return nil
+}
+
+// Close closes the Writer by flushing any unwritten data to the underlying
+// io.Writer and writing the frame's last block and content checksum.
+// It does not close the underlying io.Writer.
+func (z *Writer) Close() error {
+	if z.err != nil {
+		return z.err
+	}
+	if z.closed {
+		return nil
+	}
+	if !z.wroteHeader {
+		if z.err = z.writeHeader(); z.err != nil {
+			return z.err
+		}
+	}
+	z.closed = true
+	if z.err = z.compress(z.buf, true); z.err != nil {
+		return z.err
+	}
+	z.buf = z.buf[:0]
+	for len(z.jobs) > 0 {
+		if z.err = z.writeResult(); z.err != nil {
+			return z.err
+		}
+	}
+	z.out = appendUint32(z.out[:0], uint32(z.digest.sum64()))
+	_, z.err = z.w.Write(z.out)
+	return z.err
+}
@@ -0,0 +1,203 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+)
+
+// testInputs returns inputs exercising raw, RLE and compressed blocks,
+// several blocks per frame, and matches across block boundaries.
+func testInputs(t testing.TB) map[string][]byte {
+	e := mustReadFile(t, "../testdata/e.txt")
+	g := mustReadFile(t, "../testdata/gettysburg.txt")
+	random := make([]byte, 300<<10)
+	rand.New(rand.NewSource(1)).Read(random)
+
+	var text []byte
+	for len(text) < 1<<20 {
+		text = append(text, g...)
+		text = append(text, e[:len(text)%len(e)]...)
+	}
+	return map[string][]byte{
+		"empty":      {},
+		"byte":       {'x'},
+		"gettysburg": g,
+		"e":          e,
+		"zeros":      make([]byte, 500<<10),
+		"random":     random,
+		"text":       text,
+		"mixed":      append(append(bytes.Clone(random[:100<<10]), g...), random[:100<<10]...),
+	}
+}
+
+func roundTrip(t *testing.T, input []byte, level, concurrency int, dict []byte) {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriterDict(&buf, level, dict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.SetConcurrency(concurrency); err != nil {
+		t.Fatal(err)
+	}
+	// Write in uneven pieces, flushing occasionally.
+	for i, p := 0, input; len(p) > 0; i++ {
+		n := 1 + (i*7919)%(200<<10)
+		if n > len(p) {
+			n = len(p)
+		}
+		if _, err := w.Write(p[:n]); err != nil {
+			t.Fatal(err)
+		}
+		p = p[n:]
+		if i%5 == 4 {
+			if err := w.Flush(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var r *Reader
+	if dict != nil {
+		r, err = NewReaderDict(&buf, dict)
+		if err != nil {
+			t.Fatal(err)
+		}
+	} else {
+		r = NewReader(&buf)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("level %d, concurrency %d: %v", level, concurrency, err)
+	}
+	if !bytes.Equal(got, input) {
+		t.Fatalf("level %d, concurrency %d: round trip produced %d bytes, want %d", level, concurrency, len(got), len(input))
+	}
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	levels := []int{BestSpeed, DefaultCompression, 6, BestCompression}
+	if testing.Short() {
+		levels = []int{BestSpeed, DefaultCompression}
+	}
+	for name, input := range testInputs(t) {
+		t.Run(name, func(t *testing.T) {
+			for _, level := range levels {
+				for _, c := range []int{1, 3} {
+					roundTrip(t, input, level, c, nil)
+				}
+			}
+		})
+	}
+}
+
+func TestWriterDict(t *testing.T) {
+	dict := mustReadFile(t, "testdata/dict")
+	input := mustReadFile(t, "../testdata/gettysburg.txt")
+	for _, d := range [][]byte{dict, input[:500]} {
+		for _, c := range []int{1, 2} {
+			roundTrip(t, input, DefaultCompression, c, d)
+		}
+	}
+
+	// A dictionary identified in the frame must be supplied.
+	var buf bytes.Buffer
+	w, err := NewWriterDict(&buf, DefaultCompression, dict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write(input)
+	w.Close()
+	if _, err := io.ReadAll(NewReader(&buf)); err != ErrDictionary {
+		t.Errorf("reading without dictionary: got error %v, want %v", err, ErrDictionary)
+	}
+}
+
+func TestWriterCompresses(t *testing.T) {
+	input := testInputs(t)["text"]
+	prev := len(input)
+	for _, level := range []int{BestSpeed, 5, BestCompression} {
+		var buf bytes.Buffer
+		w, _ := NewWriterLevel(&buf, level)
+		w.Write(input)
+		w.Close()
+		if buf.Len() > prev {
+			t.Errorf("level %d: compressed to %d bytes, more than %d at the previous level", level, buf.Len(), prev)
+		}
+		prev = buf.Len()
+	}
+	if prev > len(input)/10 {
+		t.Errorf("compressed %d bytes of repetitive text to %d bytes", len(input), prev)
+	}
+}
+
+func TestWriterReset(t *testing.T) {
+	input := mustReadFile(t, "../testdata/e.txt")
+	var buf1, buf2 bytes.Buffer
+	w := NewWriter(&buf1)
+	w.Write(input)
+	w.Close()
+	w.Reset(&buf2)
+	w.Write(input)
+	w.Close()
+	if !bytes.Equal(buf1.Bytes(), buf2.Bytes()) {
+		t.Error("output after Reset differs from first output")
+	}
+	if err := w.SetConcurrency(2); err == nil {
+		t.Error("SetConcurrency after Write succeeded")
+	}
+}
+
+func TestWriterLevel(t *testing.T) {
+	for _, level := range []int{-2, 0, BestCompression + 1} {
+		if _, err := NewWriterLevel(io.Discard, level); err == nil {
+			t.Errorf("NewWriterLevel(%d) succeeded", level)
+		}
+	}
+}
+
+func BenchmarkEncode(b *testing.B) {
+	input := testInputs(b)["text"]
+	for _, level := range []int{BestSpeed, DefaultCompression, BestCompression} {
+		b.Run(levelName(level), func(b *testing.B) {
+			b.SetBytes(int64(len(input)))
+			w, _ := NewWriterLevel(io.Discard, level)
+			for i := 0; i < b.N; i++ {
+				w.Reset(io.Discard)
+				w.Write(input)
+				w.Close()
+			}
+		})
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	data := mustReadFile(b, "testdata/e.txt.zst")
+	b.SetBytes(100003)
+	r := NewReader(bytes.NewReader(data))
+	for i := 0; i < b.N; i++ {
+		r.Reset(bytes.NewReader(data))
+		io.Copy(io.Discard, r)
+	}
+}
+
+func levelName(level int) string {
+	switch level {
+	case BestSpeed:
+		return "BestSpeed"
+	case DefaultCompression:
+		return "Default"
+	case BestCompression:
+		return "BestCompression"
+	}
+	return "Level"
+}
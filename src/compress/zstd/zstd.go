@@ -0,0 +1,75 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package zstd implements reading and writing of the Zstandard
+// compressed data format, as specified in RFC 8878.
+//
+// The Reader decodes any conforming stream, including concatenated and
+// skippable frames and frames that refer to a dictionary.
+// The Writer produces frames with a content checksum and can compress
+// independent segments of its input concurrently.
+//
+// Zstandard is registered as compression method 93 in the ZIP file format
+// and as the "zstd" HTTP content coding. For example, to use this package
+// with archive/zip:
+//
+//	zip.RegisterCompressor(93, func(w io.Writer) (io.WriteCloser, error) {
+//		return zstd.NewWriter(w), nil
+//	})
+//	zip.RegisterDecompressor(93, func(r io.Reader) io.ReadCloser {
+//		return zstd.NewReader(r)
+//	})
+package zstd
+
+import (
+	"encoding/binary"
+	"errors"
+	"strconv"
+)
+
+// Compression levels accepted by NewWriterLevel and NewWriterDict.
+const (
+	BestSpeed          = 1
+	BestCompression    = 9
+	DefaultCompression = -1
+)
+
+const (
+	frameMagic       = 0xFD2FB528
+	skippableMagic   = 0x184D2A50 // low 4 bits are user defined
+	skippableMask    = 0xFFFFFFF0
+	dictMagic        = 0xEC30A437
+	maxBlockSize     = 128 << 10
+	minWindowLog     = 10
+	maxWindowLog     = 31
+	maxDecodedWindow = 1 << 27 // largest window the Reader accepts
+)
+
+// ErrChecksum is returned when reading Zstandard data whose content
+// checksum does not match the decoded data.
+var ErrChecksum = errors.New("zstd: invalid checksum")
+
+// ErrDictionary is returned when a frame requires a dictionary that
+// was not supplied to the Reader.
+var ErrDictionary = errors.New("zstd: missing or mismatched dictionary")
+
+// A CorruptInputError reports the presence of corrupt input
+// at a given offset in the compressed stream.
+type CorruptInputError struct {
+	Offset int64  // byte offset of the frame or block at fault
+	Reason string // description of the problem
+}
+
+func (e *CorruptInputError) Error() string {
+	return "zstd: corrupt input at offset " + strconv.FormatInt(e.Offset, 10) + ": " + e.Reason
+}
+
+var le = binary.LittleEndian
+
+// appendUint32 appends the little-endian encoding of v to b.
+// It is le.AppendUint32, which is not available when bootstrapping
+// the toolchain.
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
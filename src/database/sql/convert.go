@@ -12,7 +12,10 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 	"unicode"
 	"unicode/utf8"
@@ -551,6 +554,166 @@ func callValuerValue(vr driver.Valuer) (v driver.Value, err error) {
 	return vr.Value()
 }
 
+// A structField is a field that ScanStruct can assign a column to.
+type structField struct {
+	name  string // column name: the "db" tag, or the field name
+	index []int  // for fieldByIndex
+}
+
+var structFieldCache sync.Map // map[reflect.Type][]structField
+
+// cachedStructFields returns the fields of the struct type t
+// that ScanStruct can assign columns to.
+func cachedStructFields(t reflect.Type) []structField {
+	if f, ok := structFieldCache.Load(t); ok {
+		return f.([]structField)
+	}
+	f, _ := structFieldCache.LoadOrStore(t, structFields(t))
+	return f.([]structField)
+}
+
+// structFields returns the fields of the struct type t that ScanStruct
+// can assign columns to, in the order of their indexes.
+//
+// The fields of untagged embedded structs, and of pointers to them, are
+// promoted as by Go's rules for selectors, which encoding/json follows
+// too: of the fields with a given column name, the ones at the shallowest
+// depth win. If there are several there, the one with a "db" tag wins if
+// it is the only tagged one; otherwise the name is ambiguous and none of
+// them is used.
+func structFields(t reflect.Type) []structField {
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+	var fields []structField
+	hidden := make(map[string]bool) // names used at a shallower depth
+	visited := make(map[reflect.Type]bool)
+	next := []embedded{{typ: t}}
+	for len(next) > 0 {
+		current := next
+		next = nil
+
+		// The candidate fields at this depth.
+		var level []structField
+		var tagged []bool
+		for _, e := range current {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+			for i := 0; i < e.typ.NumField(); i++ {
+				f := e.typ.Field(i)
+				tag, hasTag := f.Tag.Lookup("db")
+				if tag == "-" {
+					continue
+				}
+				index := append(e.index[:len(e.index):len(e.index)], i)
+				if f.Anonymous && !hasTag {
+					ft := f.Type
+					if ft.Kind() == reflect.Pointer {
+						// The pointer is allocated when a column
+						// is assigned to one of its fields, which
+						// is impossible if the field is unexported.
+						if !f.IsExported() {
+							continue
+						}
+						ft = ft.Elem()
+					}
+					if ft.Kind() == reflect.Struct {
+						next = append(next, embedded{ft, index})
+						continue
+					}
+				}
+				if !f.IsExported() {
+					continue
+				}
+				name := f.Name
+				if tag != "" {
+					name = tag
+				}
+				level = append(level, structField{name: name, index: index})
+				tagged = append(tagged, tag != "")
+			}
+		}
+
+		count := make(map[string]int)
+		taggedCount := make(map[string]int)
+		for i, f := range level {
+			count[f.name]++
+			if tagged[i] {
+				taggedCount[f.name]++
+			}
+		}
+		for i, f := range level {
+			if hidden[f.name] {
+				continue
+			}
+			if count[f.name] > 1 && (taggedCount[f.name] != 1 || !tagged[i]) {
+				continue // ambiguous, or hidden by the tagged field
+			}
+			fields = append(fields, f)
+		}
+		for name := range count {
+			hidden[name] = true
+		}
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		x, y := fields[i].index, fields[j].index
+		for k := 0; k < len(x) && k < len(y); k++ {
+			if x[k] != y[k] {
+				return x[k] < y[k]
+			}
+		}
+		return len(x) < len(y)
+	})
+	return fields
+}
+
+// fieldByIndex returns the field of the struct v with the given index,
+// allocating the embedded struct pointers on the way that are nil.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// columnFieldIndex returns, for each column, the index of the field of
+// the struct type t that the column is assigned to by ScanStruct.
+func columnFieldIndex(t reflect.Type, columns []*ColumnType) ([][]int, error) {
+	fields := cachedStructFields(t)
+	index := make([][]int, len(columns))
+	for i, c := range columns {
+		name := c.Name()
+		for _, f := range fields {
+			if f.name == name {
+				index[i] = f.index
+				break
+			}
+		}
+		if index[i] == nil {
+			for _, f := range fields {
+				if strings.EqualFold(f.name, name) {
+					index[i] = f.index
+					break
+				}
+			}
+		}
+		if index[i] == nil {
+			return nil, fmt.Errorf("sql: no field in %v for column %q", t, name)
+		}
+	}
+	return index, nil
+}
+
 // decimal composes or decomposes a decimal value to and from individual parts.
 // There are four parts: a boolean negative flag, a form byte with three possible states
 // (finite=0, infinite=1, NaN=2), a base-2 big-endian integer
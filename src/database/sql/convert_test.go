@@ -595,3 +595,57 @@ func TestDecimal(t *testing.T) {
 		})
 	}
 }
+
+type fieldsC struct{ ID int }
+type fieldsA struct{ fieldsC }
+type fieldsB struct {
+	ID   int
+	Name string
+}
+type fieldsD struct {
+	Name  string
+	Email string
+}
+type fieldsE struct {
+	Mail string `db:"Email"`
+}
+
+// FieldsP is exported, as the embedded pointer can't be allocated
+// otherwise.
+type FieldsP struct {
+	ID   int
+	Name string
+}
+
+func TestStructFields(t *testing.T) {
+	tests := []struct {
+		v    any
+		want []structField
+	}{
+		// The shallower field wins, whatever the order of embedding.
+		{struct {
+			fieldsA
+			fieldsB
+		}{}, []structField{{"ID", []int{1, 0}}, {"Name", []int{1, 1}}}},
+		// Fields of the same name at the same depth are ambiguous,
+		// unless only one of them is tagged.
+		{struct {
+			fieldsB
+			fieldsD
+			fieldsE
+		}{}, []structField{{"ID", []int{0, 0}}, {"Email", []int{2, 0}}}},
+		// The fields of embedded pointers are promoted too, unless
+		// the pointer is unexported.
+		{struct {
+			*FieldsP
+			*fieldsD
+			Age int
+		}{}, []structField{{"ID", []int{0, 0}}, {"Name", []int{0, 1}}, {"Age", []int{2}}}},
+	}
+	for _, tt := range tests {
+		got := structFields(reflect.TypeOf(tt.v))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("structFields(%T) = %v; want %v", tt.v, got, tt.want)
+		}
+	}
+}
@@ -19,6 +19,9 @@
 // If named parameters or context are supported, the driver's Conn should implement:
 // ExecerContext, QueryerContext, ConnPrepareContext, and ConnBeginTx.
 //
+// If the database can execute several statements in one round trip,
+// the driver's Conn should implement Batcher.
+//
 // To support custom data types, implement NamedValueChecker. NamedValueChecker
 // also allows queries to accept per-query options as a parameter by returning
 // ErrRemoveArgument from CheckNamedValue.
@@ -199,6 +202,39 @@ type ExecerContext interface {
 	ExecContext(ctx context.Context, query string, args []NamedValue) (Result, error)
 }
 
+// BatchStatement is a statement in a batch passed to Batcher.
+type BatchStatement struct {
+	Query string
+	Args  []NamedValue
+}
+
+// BatchResult is the outcome of executing one statement of a batch.
+// Exactly one of Result and Err is non-nil.
+type BatchResult struct {
+	Result Result
+	Err    error
+}
+
+// Batcher is an optional interface that may be implemented by a Conn.
+//
+// If a Conn implements Batcher, the sql package's DB.Batch and Tx.Batch
+// pass all the statements of a batch to ExecBatch, which may send them
+// to the database in a single round trip. If a Conn does not implement
+// Batcher, or ExecBatch returns ErrSkip, the statements are executed
+// one at a time, as by DB.Exec.
+//
+// ExecBatch returns one BatchResult for each statement, in order.
+// An error executing a statement is reported in its BatchResult; whether
+// the statements after it are executed depends on the database.
+// ExecBatch returns a non-nil error only if the batch as a whole could
+// not be executed. It may return ErrBadConn only if no statement was
+// executed.
+//
+// ExecBatch must honor the context timeout and return when the context is canceled.
+type Batcher interface {
+	ExecBatch(ctx context.Context, stmts []BatchStatement) ([]BatchResult, error)
+}
+
 // Queryer is an optional interface that may be implemented by a Conn.
 //
 // If a Conn implements neither QueryerContext nor Queryer,
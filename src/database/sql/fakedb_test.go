@@ -55,13 +55,15 @@ type fakeDriver struct {
 type fakeConnector struct {
 	name string
 
-	waiter func(context.Context)
-	closed bool
+	waiter  func(context.Context)
+	noBatch bool
+	closed  bool
 }
 
 func (c *fakeConnector) Connect(context.Context) (driver.Conn, error) {
 	conn, err := fdriver.Open(c.name)
 	conn.(*fakeConn).waiter = c.waiter
+	conn.(*fakeConn).noBatch = c.noBatch
 	return conn, err
 }
 
@@ -148,6 +150,7 @@ type fakeConn struct {
 	stmtsMade   int
 	stmtsClosed int
 	numPrepare  int
+	numBatch    int
 
 	// bad connection tests; see isBad()
 	bad       bool
@@ -155,6 +158,8 @@ type fakeConn struct {
 
 	skipDirtySession bool // tests that use Conn should set this to true.
 
+	noBatch bool // ExecBatch returns ErrSkip
+
 	// dirtySession tests ResetSession, true if a query has executed
 	// until ResetSession is called.
 	dirtySession bool
@@ -488,6 +493,48 @@ func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.
 	return nil, driver.ErrSkip
 }
 
+func (c *fakeConn) ExecBatch(ctx context.Context, stmts []driver.BatchStatement) ([]driver.BatchResult, error) {
+	if c.noBatch {
+		return nil, driver.ErrSkip
+	}
+	if c.isBad() {
+		return nil, fakeError{Wrapped: driver.ErrBadConn}
+	}
+	c.incrStat(&c.numBatch)
+	res := make([]driver.BatchResult, len(stmts))
+	for i, bs := range stmts {
+		if err := checkSubsetTypes(c.db.allowAny, bs.Args); err != nil {
+			res[i].Err = err
+			continue
+		}
+		si, err := c.PrepareContext(ctx, bs.Query)
+		if err != nil {
+			res[i].Err = err
+			continue
+		}
+		res[i].Result, res[i].Err = c.execBatchStmt(ctx, si.(*fakeStmt), bs.Args)
+		si.Close()
+	}
+	return res, nil
+}
+
+// execBatchStmt converts args for the columns of s,
+// as the sql package does for prepared statements, and executes s.
+func (c *fakeConn) execBatchStmt(ctx context.Context, s *fakeStmt, args []driver.NamedValue) (driver.Result, error) {
+	if len(args) != s.NumInput() {
+		return nil, fmt.Errorf("fakedb: expected %d arguments, got %d", s.NumInput(), len(args))
+	}
+	args = append([]driver.NamedValue(nil), args...)
+	for i := range args {
+		v, err := s.ColumnConverter(i).ConvertValue(args[i].Value)
+		if err != nil {
+			return nil, err
+		}
+		args[i].Value = v
+	}
+	return s.ExecContext(ctx, args)
+}
+
 func (c *fakeConn) Query(query string, args []driver.Value) (driver.Rows, error) {
 	// Ensure that ExecContext is called if available.
 	panic("QueryContext was not called.")
@@ -1265,6 +1312,8 @@ func colTypeToReflectType(typ string) reflect.Type {
 		return reflect.TypeOf(NullFloat64{})
 	case "datetime":
 		return reflect.TypeOf(time.Time{})
+	case "blob":
+		return reflect.TypeOf([]byte(nil))
 	case "any":
 		return reflect.TypeOf(new(any)).Elem()
 	}
@@ -391,6 +391,54 @@ func (n NullTime) Value() (driver.Value, error) {
 	return n.Time, nil
 }
 
+// Null represents a value that may be null.
+// Null implements the Scanner interface so
+// it can be used as a scan destination:
+//
+//	var s Null[string]
+//	err := db.QueryRow("SELECT name FROM foo WHERE id=?", id).Scan(&s)
+//	...
+//	if s.Valid {
+//	   // use s.V
+//	} else {
+//	   // NULL value
+//	}
+//
+// T should be a type that Scan can convert database values to,
+// such as string, int64 or time.Time.
+type Null[T any] struct {
+	V     T
+	Valid bool // Valid is true if V is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (n *Null[T]) Scan(value any) error {
+	if value == nil {
+		n.V, n.Valid = *new(T), false
+		return nil
+	}
+	n.Valid = true
+	return convertAssign(&n.V, value)
+}
+
+// Value implements the driver Valuer interface.
+func (n Null[T]) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	v := any(n.V)
+	if vr, ok := v.(driver.Valuer); ok {
+		var err error
+		v, err = callValuerValue(vr)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Convert values such as int or named string types,
+	// which are not valid driver.Values themselves.
+	return driver.DefaultParameterConverter.ConvertValue(v)
+}
+
 // Scanner is an interface used by Scan.
 type Scanner interface {
 	// Scan assigns a value from a database driver.
@@ -486,6 +534,10 @@ type DB struct {
 	maxIdleTimeClosed int64 // Total number of connections closed due to idle time.
 	maxLifetimeClosed int64 // Total number of connections closed due to max connection lifetime limit.
 
+	hooks      atomic.Pointer[Hooks]
+	nextConnID atomic.Uint64
+	waitHist   [len(waitHistogramBounds) + 1]atomic.Int64
+
 	stop func() // stop cancels the connection opener.
 }
 
@@ -507,8 +559,19 @@ const (
 // Result, Rows)
 type driverConn struct {
 	db        *DB
+	id        uint64 // for Hooks
 	createdAt time.Time
 
+	// Set when the connection is handed out, and used by the same
+	// holder when it is released. acquireCtx and acquiredAt are only
+	// set when Hooks are installed; op and query are set by the
+	// operation that acquired the connection, for Hooks.OnRelease.
+	used       bool
+	acquireCtx context.Context
+	acquiredAt time.Time
+	op         string
+	query      string
+
 	sync.Mutex  // guards following
 	ci          driver.Conn
 	needReset   bool // The connection session should be reset before use if true.
@@ -517,16 +580,35 @@ type driverConn struct {
 	openStmt    map[*driverStmt]bool
 
 	// guarded by db.mu
-	inUse      bool
-	returnedAt time.Time // Time the connection was created or returned.
-	onPut      []func()  // code (with db.mu held) run when conn is next returned
-	dbmuClosed bool      // same as closed, but guarded by db.mu, for removeClosedStmtLocked
+	inUse       bool
+	returnedAt  time.Time   // Time the connection was created or returned.
+	onPut       []func()    // code (with db.mu held) run when conn is next returned
+	dbmuClosed  bool        // same as closed, but guarded by db.mu, for removeClosedStmtLocked
+	closeReason CloseReason // why the connection cleaner is closing the conn
 }
 
 func (dc *driverConn) releaseConn(err error) {
+	if h := dc.db.hooks.Load(); h != nil && h.OnRelease != nil {
+		ctx := dc.acquireCtx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		var held time.Duration
+		if !dc.acquiredAt.IsZero() {
+			held = nowFunc().Sub(dc.acquiredAt)
+		}
+		h.OnRelease(ctx, ReleaseInfo{ConnID: dc.id, Op: dc.op, Query: dc.query, Held: held, Err: err})
+	}
 	dc.db.putConn(dc, err, true)
 }
 
+// setOp records the operation that acquired dc, and its query text if
+// it has one, to be reported when dc is released.
+func (dc *driverConn) setOp(op, query string) {
+	dc.op = op
+	dc.query = query
+}
+
 func (dc *driverConn) removeOpenStmt(ds *driverStmt) {
 	dc.Lock()
 	defer dc.Unlock()
@@ -542,17 +624,25 @@ func (dc *driverConn) expired(timeout time.Duration) bool {
 
 // resetSession checks if the driver connection needs the
 // session to be reset and if required, resets it.
-func (dc *driverConn) resetSession(ctx context.Context) error {
+// It reports whether the session was reset.
+func (dc *driverConn) resetSession(ctx context.Context) (bool, error) {
 	dc.Lock()
 	defer dc.Unlock()
 
 	if !dc.needReset {
-		return nil
+		return false, nil
 	}
 	if cr, ok := dc.ci.(driver.SessionResetter); ok {
-		return cr.ResetSession(ctx)
+		return true, cr.ResetSession(ctx)
 	}
-	return nil
+	return false, nil
+}
+
+// discard closes dc, which the pool is discarding for the given reason.
+// err is the error that made dc unusable, for CloseBadConn.
+func (dc *driverConn) discard(ctx context.Context, reason CloseReason, err error) {
+	dc.db.traceClose(ctx, dc.id, reason, err)
+	dc.Close()
 }
 
 // validateConnection checks if the connection is valid and can
@@ -855,6 +945,7 @@ func (db *DB) PingContext(ctx context.Context) error {
 		return err
 	}
 
+	dc.setOp("Ping", "")
 	return db.pingDC(ctx, dc, dc.releaseConn)
 }
 
@@ -887,12 +978,16 @@ func (db *DB) Close() error {
 	for _, dc := range db.freeConn {
 		fns = append(fns, dc.closeDBLocked())
 	}
+	closing := db.freeConn
 	db.freeConn = nil
 	db.closed = true
 	for _, req := range db.connRequests {
 		close(req)
 	}
 	db.mu.Unlock()
+	for _, dc := range closing {
+		db.traceClose(context.Background(), dc.id, CloseDBClosed, nil)
+	}
 	for _, fn := range fns {
 		err1 := fn()
 		if err1 != nil {
@@ -971,7 +1066,7 @@ func (db *DB) SetMaxIdleConns(n int) {
 	db.maxIdleClosed += int64(len(closing))
 	db.mu.Unlock()
 	for _, c := range closing {
-		c.Close()
+		c.discard(context.Background(), CloseMaxIdle, nil)
 	}
 }
 
@@ -1075,7 +1170,7 @@ func (db *DB) connectionCleaner(d time.Duration) {
 		d, closing := db.connectionCleanerRunLocked(d)
 		db.mu.Unlock()
 		for _, c := range closing {
-			c.Close()
+			c.discard(context.Background(), c.closeReason, nil)
 		}
 
 		if d < minInterval {
@@ -1110,6 +1205,9 @@ func (db *DB) connectionCleanerRunLocked(d time.Duration) (time.Duration, []*dri
 				closing = db.freeConn[:i:i]
 				db.freeConn = db.freeConn[i:]
 				idleClosing = int64(len(closing))
+				for _, c := range closing {
+					c.closeReason = CloseMaxIdleTime
+				}
 				db.maxIdleTimeClosed += idleClosing
 				break
 			}
@@ -1130,6 +1228,7 @@ func (db *DB) connectionCleanerRunLocked(d time.Duration) (time.Duration, []*dri
 		for i := 0; i < len(db.freeConn); i++ {
 			c := db.freeConn[i]
 			if c.createdAt.Before(expiredSince) {
+				c.closeReason = CloseMaxLifetime
 				closing = append(closing, c)
 
 				last := len(db.freeConn) - 1
@@ -1166,6 +1265,8 @@ type DBStats struct {
 	MaxIdleClosed     int64         // The total number of connections closed due to SetMaxIdleConns.
 	MaxIdleTimeClosed int64         // The total number of connections closed due to SetConnMaxIdleTime.
 	MaxLifetimeClosed int64         // The total number of connections closed due to SetConnMaxLifetime.
+
+	WaitHistogram WaitHistogram // The distribution of the waits counted in WaitCount.
 }
 
 // Stats returns database statistics.
@@ -1187,10 +1288,237 @@ func (db *DB) Stats() DBStats {
 		MaxIdleClosed:     db.maxIdleClosed,
 		MaxIdleTimeClosed: db.maxIdleTimeClosed,
 		MaxLifetimeClosed: db.maxLifetimeClosed,
+
+		WaitHistogram: WaitHistogram{
+			Bounds: waitHistogramBounds[:],
+			Counts: make([]int64, len(db.waitHist)),
+		},
+	}
+	for i := range db.waitHist {
+		stats.WaitHistogram.Counts[i] = db.waitHist[i].Load()
 	}
 	return stats
 }
 
+// waitHistogramBounds are the bucket bounds of DBStats.WaitHistogram.
+var waitHistogramBounds = [...]time.Duration{
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+	10 * time.Second,
+}
+
+// A WaitHistogram counts the waits for a connection by their duration.
+type WaitHistogram struct {
+	// Bounds are the bounds between the buckets, in increasing order.
+	Bounds []time.Duration
+
+	// Counts has one element more than Bounds. Counts[i] is the
+	// number of waits that took less than Bounds[i], and at least
+	// Bounds[i-1] if i > 0. The last element counts the waits that
+	// took at least the last of the Bounds.
+	Counts []int64
+}
+
+// recordWait adds a wait for a connection of duration d to the stats.
+func (db *DB) recordWait(d time.Duration) {
+	db.waitDuration.Add(int64(d))
+	i := 0
+	for i < len(waitHistogramBounds) && d >= waitHistogramBounds[i] {
+		i++
+	}
+	db.waitHist[i].Add(1)
+}
+
+// Hooks are functions that a DB calls on events in its connection
+// pool, to let programs trace the use of connections. Any of the
+// functions may be nil. They may be called concurrently, and must not
+// block or call methods of the DB.
+//
+// Each function is passed the context of the operation that caused
+// the event. Events not caused by an operation, such as closing an
+// idle connection, get a background context.
+type Hooks struct {
+	// OnConnect is called when the DB has opened a new connection
+	// to the database, or failed to.
+	OnConnect func(ctx context.Context, info ConnectInfo)
+
+	// OnAcquire is called when an operation takes a connection
+	// from the pool, after the connection's session has been reset.
+	OnAcquire func(ctx context.Context, info AcquireInfo)
+
+	// OnRelease is called when an operation is done with a
+	// connection and returns it to the pool. It is passed the
+	// context of the operation that acquired the connection.
+	OnRelease func(ctx context.Context, info ReleaseInfo)
+
+	// OnClose is called when the DB discards a connection.
+	OnClose func(ctx context.Context, info CloseInfo)
+}
+
+// ConnectInfo is the argument to Hooks.OnConnect.
+type ConnectInfo struct {
+	ConnID   uint64        // The new connection's ID, or zero if Err is not nil.
+	Duration time.Duration // How long opening the connection took.
+	Err      error         // The error opening the connection, if any.
+}
+
+// AcquireInfo is the argument to Hooks.OnAcquire.
+type AcquireInfo struct {
+	// ConnID identifies the connection. Connection IDs are
+	// unique within a DB.
+	ConnID uint64
+
+	// Wait is how long the operation waited for a connection to be
+	// returned to the pool, or zero if it did not have to wait.
+	Wait time.Duration
+
+	// Reused reports whether the connection was used before.
+	Reused bool
+
+	// Reset reports whether the connection's session was reset
+	// with driver.SessionResetter before it was handed out.
+	Reset bool
+}
+
+// ReleaseInfo is the argument to Hooks.OnRelease.
+type ReleaseInfo struct {
+	ConnID uint64 // The connection's ID.
+
+	// Op names the operation that acquired the connection: "Exec",
+	// "Query", "Prepare", "Batch", "Begin", "Ping" or "Conn", for
+	// the methods of DB and Stmt of the same names and for BeginTx
+	// and Conn. A connection held by a Tx or Conn is reported as
+	// "Begin" or "Conn", whatever was run on it.
+	Op string
+
+	// Query is the query text of an Exec, Query or Prepare
+	// operation, and empty for the others.
+	Query string
+
+	Held time.Duration // How long the connection was held.
+	Err  error         // The last error the operation got on the connection, if any.
+}
+
+// CloseInfo is the argument to Hooks.OnClose.
+type CloseInfo struct {
+	ConnID uint64      // The connection's ID.
+	Reason CloseReason // Why the connection is closed.
+	Err    error       // For CloseBadConn, the error that made the connection unusable.
+}
+
+// A CloseReason is the reason a DB closed a connection.
+type CloseReason int
+
+const (
+	// CloseBadConn means the driver reported the connection
+	// unusable, such as by returning driver.ErrBadConn from a query
+	// or from driver.SessionResetter, or with driver.Validator.
+	CloseBadConn CloseReason = iota + 1
+
+	// CloseMaxIdle means the pool already had as many idle
+	// connections as SetMaxIdleConns allows.
+	CloseMaxIdle
+
+	// CloseMaxIdleTime means the connection was idle for longer
+	// than SetConnMaxIdleTime allows.
+	CloseMaxIdleTime
+
+	// CloseMaxLifetime means the connection was older than
+	// SetConnMaxLifetime allows.
+	CloseMaxLifetime
+
+	// CloseMaxOpen means the pool had more open connections than
+	// SetMaxOpenConns allows.
+	CloseMaxOpen
+
+	// CloseDBClosed means the DB was closed.
+	CloseDBClosed
+)
+
+var closeReasonNames = [...]string{
+	CloseBadConn:     "bad connection",
+	CloseMaxIdle:     "max idle connections",
+	CloseMaxIdleTime: "max idle time",
+	CloseMaxLifetime: "max lifetime",
+	CloseMaxOpen:     "max open connections",
+	CloseDBClosed:    "database closed",
+}
+
+func (r CloseReason) String() string {
+	if r > 0 && int(r) < len(closeReasonNames) {
+		return closeReasonNames[r]
+	}
+	return "CloseReason(" + strconv.Itoa(int(r)) + ")"
+}
+
+// SetHooks sets the functions called on events in the connection pool.
+// If h is nil, no functions are called. SetHooks may be called at any
+// time, but it does not affect calls already in progress.
+func (db *DB) SetHooks(h *Hooks) {
+	db.hooks.Store(h)
+}
+
+// newDriverConn returns a new driverConn for ci,
+// and calls the OnConnect hook.
+func (db *DB) newDriverConn(ctx context.Context, ci driver.Conn, start time.Time, inUse bool) *driverConn {
+	dc := &driverConn{
+		db:         db,
+		id:         db.nextConnID.Add(1),
+		createdAt:  nowFunc(),
+		returnedAt: nowFunc(),
+		ci:         ci,
+		inUse:      inUse,
+	}
+	if h := db.hooks.Load(); h != nil && h.OnConnect != nil {
+		h.OnConnect(ctx, ConnectInfo{ConnID: dc.id, Duration: nowFunc().Sub(start)})
+	}
+	return dc
+}
+
+// connectFailed calls the OnConnect hook for a failed connection attempt.
+func (db *DB) connectFailed(ctx context.Context, start time.Time, err error) {
+	if h := db.hooks.Load(); h != nil && h.OnConnect != nil {
+		h.OnConnect(ctx, ConnectInfo{Duration: nowFunc().Sub(start), Err: err})
+	}
+}
+
+// acquired is called when conn hands out dc, and calls the OnAcquire hook.
+func (db *DB) acquired(ctx context.Context, dc *driverConn, wait time.Duration, reset bool) {
+	reused := dc.used
+	dc.used = true
+	h := db.hooks.Load()
+	if h == nil {
+		return
+	}
+	dc.acquireCtx = ctx
+	dc.acquiredAt = nowFunc()
+	if h.OnAcquire != nil {
+		h.OnAcquire(ctx, AcquireInfo{ConnID: dc.id, Wait: wait, Reused: reused, Reset: reset})
+	}
+}
+
+// traceClose calls the OnClose hook for the connection with the given ID.
+func (db *DB) traceClose(ctx context.Context, id uint64, reason CloseReason, err error) {
+	if h := db.hooks.Load(); h != nil && h.OnClose != nil {
+		h.OnClose(ctx, CloseInfo{ConnID: id, Reason: reason, Err: err})
+	}
+}
+
+// putConnFailReasonLocked returns why putConnDBLocked did not take a
+// connection.
+func (db *DB) putConnFailReasonLocked() CloseReason {
+	switch {
+	case db.closed:
+		return CloseDBClosed
+	case db.maxOpen > 0 && db.numOpen > db.maxOpen:
+		return CloseMaxOpen
+	}
+	return CloseMaxIdle
+}
+
 // Assumes db.mu is locked.
 // If there are connRequests and the connection limit hasn't been reached,
 // then tell the connectionOpener to open new connections.
@@ -1229,12 +1557,27 @@ func (db *DB) openNewConnection(ctx context.Context) {
 	// maybeOpenNewConnections has already executed db.numOpen++ before it sent
 	// on db.openerCh. This function must execute db.numOpen-- if the
 	// connection fails or is closed before returning.
+	start := nowFunc()
 	ci, err := db.connector.Connect(ctx)
+	var dc *driverConn
+	if err != nil {
+		db.connectFailed(ctx, start, err)
+	} else {
+		dc = db.newDriverConn(ctx, ci, start, false)
+	}
+	var reason CloseReason
+	defer func() {
+		// Call the hook without holding db.mu.
+		if reason != 0 {
+			db.traceClose(ctx, dc.id, reason, nil)
+		}
+	}()
 	db.mu.Lock()
 	defer db.mu.Unlock()
 	if db.closed {
 		if err == nil {
 			ci.Close()
+			reason = CloseDBClosed
 		}
 		db.numOpen--
 		return
@@ -1245,15 +1588,10 @@ func (db *DB) openNewConnection(ctx context.Context) {
 		db.maybeOpenNewConnections()
 		return
 	}
-	dc := &driverConn{
-		db:         db,
-		createdAt:  nowFunc(),
-		returnedAt: nowFunc(),
-		ci:         ci,
-	}
 	if db.putConnDBLocked(dc, err) {
 		db.addDepLocked(dc, dc)
 	} else {
+		reason = db.putConnFailReasonLocked()
 		db.numOpen--
 		ci.Close()
 	}
@@ -1304,17 +1642,19 @@ func (db *DB) conn(ctx context.Context, strategy connReuseStrategy) (*driverConn
 		if conn.expired(lifetime) {
 			db.maxLifetimeClosed++
 			db.mu.Unlock()
-			conn.Close()
+			conn.discard(ctx, CloseMaxLifetime, nil)
 			return nil, driver.ErrBadConn
 		}
 		db.mu.Unlock()
 
 		// Reset the session if required.
-		if err := conn.resetSession(ctx); errors.Is(err, driver.ErrBadConn) {
-			conn.Close()
+		reset, err := conn.resetSession(ctx)
+		if errors.Is(err, driver.ErrBadConn) {
+			conn.discard(ctx, CloseBadConn, err)
 			return nil, err
 		}
 
+		db.acquired(ctx, conn, 0, reset)
 		return conn, nil
 	}
 
@@ -1340,7 +1680,7 @@ func (db *DB) conn(ctx context.Context, strategy connReuseStrategy) (*driverConn
 			delete(db.connRequests, reqKey)
 			db.mu.Unlock()
 
-			db.waitDuration.Add(int64(time.Since(waitStart)))
+			db.recordWait(time.Since(waitStart))
 
 			select {
 			default:
@@ -1351,7 +1691,8 @@ func (db *DB) conn(ctx context.Context, strategy connReuseStrategy) (*driverConn
 			}
 			return nil, ctx.Err()
 		case ret, ok := <-req:
-			db.waitDuration.Add(int64(time.Since(waitStart)))
+			wait := time.Since(waitStart)
+			db.recordWait(wait)
 
 			if !ok {
 				return nil, errDBClosed
@@ -1366,7 +1707,7 @@ func (db *DB) conn(ctx context.Context, strategy connReuseStrategy) (*driverConn
 				db.mu.Lock()
 				db.maxLifetimeClosed++
 				db.mu.Unlock()
-				ret.conn.Close()
+				ret.conn.discard(ctx, CloseMaxLifetime, nil)
 				return nil, driver.ErrBadConn
 			}
 			if ret.conn == nil {
@@ -1374,34 +1715,33 @@ func (db *DB) conn(ctx context.Context, strategy connReuseStrategy) (*driverConn
 			}
 
 			// Reset the session if required.
-			if err := ret.conn.resetSession(ctx); errors.Is(err, driver.ErrBadConn) {
-				ret.conn.Close()
+			reset, err := ret.conn.resetSession(ctx)
+			if errors.Is(err, driver.ErrBadConn) {
+				ret.conn.discard(ctx, CloseBadConn, err)
 				return nil, err
 			}
+			db.acquired(ctx, ret.conn, wait, reset)
 			return ret.conn, ret.err
 		}
 	}
 
 	db.numOpen++ // optimistically
 	db.mu.Unlock()
+	start := nowFunc()
 	ci, err := db.connector.Connect(ctx)
 	if err != nil {
+		db.connectFailed(ctx, start, err)
 		db.mu.Lock()
 		db.numOpen-- // correct for earlier optimism
 		db.maybeOpenNewConnections()
 		db.mu.Unlock()
 		return nil, err
 	}
+	dc := db.newDriverConn(ctx, ci, start, true)
 	db.mu.Lock()
-	dc := &driverConn{
-		db:         db,
-		createdAt:  nowFunc(),
-		returnedAt: nowFunc(),
-		ci:         ci,
-		inUse:      true,
-	}
 	db.addDepLocked(dc, dc)
 	db.mu.Unlock()
+	db.acquired(ctx, dc, 0, false)
 	return dc, nil
 }
 
@@ -1435,10 +1775,19 @@ const debugGetPut = false
 // putConn adds a connection to the db's free pool.
 // err is optionally the last error that occurred on this connection.
 func (db *DB) putConn(dc *driverConn, err error, resetSession bool) {
-	if !errors.Is(err, driver.ErrBadConn) {
-		if !dc.validateConnection(resetSession) {
-			err = driver.ErrBadConn
-		}
+	ctx := dc.acquireCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	dc.acquireCtx = nil
+	dc.op, dc.query = "", ""
+
+	var reason CloseReason
+	if errors.Is(err, driver.ErrBadConn) {
+		reason = CloseBadConn
+	} else if !dc.validateConnection(resetSession) {
+		err = driver.ErrBadConn
+		reason = CloseBadConn
 	}
 	db.mu.Lock()
 	if !dc.inUse {
@@ -1449,9 +1798,10 @@ func (db *DB) putConn(dc *driverConn, err error, resetSession bool) {
 		panic("sql: connection returned that was never out")
 	}
 
-	if !errors.Is(err, driver.ErrBadConn) && dc.expired(db.maxLifetime) {
+	if reason == 0 && dc.expired(db.maxLifetime) {
 		db.maxLifetimeClosed++
 		err = driver.ErrBadConn
+		reason = CloseMaxLifetime
 	}
 	if debugGetPut {
 		db.lastPut[dc] = stack()
@@ -1471,17 +1821,24 @@ func (db *DB) putConn(dc *driverConn, err error, resetSession bool) {
 		// take care of that.
 		db.maybeOpenNewConnections()
 		db.mu.Unlock()
-		dc.Close()
+		if reason == CloseBadConn {
+			dc.discard(ctx, reason, err)
+		} else {
+			dc.discard(ctx, reason, nil)
+		}
 		return
 	}
 	if putConnHook != nil {
 		putConnHook(db, dc)
 	}
 	added := db.putConnDBLocked(dc, nil)
+	if !added {
+		reason = db.putConnFailReasonLocked()
+	}
 	db.mu.Unlock()
 
 	if !added {
-		dc.Close()
+		dc.discard(ctx, reason, nil)
 		return
 	}
 }
@@ -1588,6 +1945,7 @@ func (db *DB) prepare(ctx context.Context, query string, strategy connReuseStrat
 	if err != nil {
 		return nil, err
 	}
+	dc.setOp("Prepare", query)
 	return db.prepareDC(ctx, dc, dc.releaseConn, nil, query)
 }
 
@@ -1647,11 +2005,138 @@ func (db *DB) Exec(query string, args ...any) (Result, error) {
 	return db.ExecContext(context.Background(), query, args...)
 }
 
+// A Batch is a sequence of statements to be executed together by
+// DB.Batch or Tx.Batch.
+//
+// The zero value is an empty batch ready to use.
+type Batch struct {
+	stmts []batchStmt
+}
+
+type batchStmt struct {
+	query string
+	args  []any
+}
+
+// Queue adds a statement that doesn't return rows to the batch.
+// The args are for any placeholder parameters in the query.
+func (b *Batch) Queue(query string, args ...any) {
+	b.stmts = append(b.stmts, batchStmt{query, args})
+}
+
+// Len returns the number of statements in the batch.
+func (b *Batch) Len() int {
+	return len(b.stmts)
+}
+
+// BatchResult is the outcome of executing one statement of a Batch.
+// Exactly one of Result and Err is non-nil.
+type BatchResult struct {
+	Result Result
+	Err    error
+}
+
+// Batch executes the statements of b on a single connection and
+// returns one BatchResult for each statement, in order.
+//
+// If the driver's connection implements driver.Batcher, the statements
+// are passed to the driver together, which may send them to the
+// database in a single round trip; whether the statements after a
+// failed one are executed then depends on the database. Otherwise the
+// statements are executed one at a time, and a failed statement does
+// not stop the ones after it.
+//
+// The statements are not executed in a transaction unless the driver
+// or database does so implicitly; use Tx.Batch to execute a batch in
+// a transaction. The returned error is non-nil only if the batch as a
+// whole could not be executed, in which case no results are returned.
+func (db *DB) Batch(ctx context.Context, b *Batch) ([]BatchResult, error) {
+	var res []BatchResult
+	var err error
+
+	err = db.retry(func(strategy connReuseStrategy) error {
+		res, err = db.batch(ctx, b, strategy)
+		return err
+	})
+
+	return res, err
+}
+
+func (db *DB) batch(ctx context.Context, b *Batch, strategy connReuseStrategy) ([]BatchResult, error) {
+	dc, err := db.conn(ctx, strategy)
+	if err != nil {
+		return nil, err
+	}
+	dc.setOp("Batch", "")
+	return db.batchDC(ctx, dc, dc.releaseConn, b)
+}
+
+func (db *DB) batchDC(ctx context.Context, dc *driverConn, release func(error), b *Batch) (res []BatchResult, err error) {
+	defer func() {
+		release(err)
+	}()
+	if batcher, ok := dc.ci.(driver.Batcher); ok {
+		var resi []driver.BatchResult
+		withLock(dc, func() {
+			stmts := make([]driver.BatchStatement, len(b.stmts))
+			for i, s := range b.stmts {
+				stmts[i].Query = s.query
+				stmts[i].Args, err = driverArgsConnLocked(dc.ci, nil, s.args)
+				if err != nil {
+					return
+				}
+			}
+			resi, err = batcher.ExecBatch(ctx, stmts)
+		})
+		if err != driver.ErrSkip {
+			if err != nil {
+				return nil, err
+			}
+			if len(resi) != len(b.stmts) {
+				return nil, fmt.Errorf("sql: driver returned %d batch results for %d statements", len(resi), len(b.stmts))
+			}
+			res = make([]BatchResult, len(resi))
+			for i, r := range resi {
+				switch {
+				case r.Err != nil:
+					res[i].Err = r.Err
+				case r.Result != nil:
+					res[i].Result = driverResult{dc, r.Result}
+				default:
+					return nil, fmt.Errorf("sql: driver returned neither a result nor an error for batch statement %d", i)
+				}
+			}
+			return res, nil
+		}
+	}
+
+	res = make([]BatchResult, len(b.stmts))
+	for i, s := range b.stmts {
+		r, err := db.execDC(ctx, dc, func(error) {}, s.query, s.args)
+		if errors.Is(err, driver.ErrBadConn) {
+			if i == 0 {
+				// Nothing was executed, so the batch can be retried.
+				return nil, err
+			}
+			for j := i; j < len(res); j++ {
+				res[j].Err = err
+			}
+			// Discard the connection, but report the results.
+			release(err)
+			release = func(error) {}
+			return res, nil
+		}
+		res[i] = BatchResult{r, err}
+	}
+	return res, nil
+}
+
 func (db *DB) exec(ctx context.Context, query string, args []any, strategy connReuseStrategy) (Result, error) {
 	dc, err := db.conn(ctx, strategy)
 	if err != nil {
 		return nil, err
 	}
+	dc.setOp("Exec", query)
 	return db.execDC(ctx, dc, dc.releaseConn, query, args)
 }
 
@@ -1723,6 +2208,7 @@ func (db *DB) query(ctx context.Context, query string, args []any, strategy conn
 		return nil, err
 	}
 
+	dc.setOp("Query", query)
 	return db.queryDC(ctx, nil, dc, dc.releaseConn, query, args)
 }
 
@@ -1854,6 +2340,7 @@ func (db *DB) begin(ctx context.Context, opts *TxOptions, strategy connReuseStra
 	if err != nil {
 		return nil, err
 	}
+	dc.setOp("Begin", "")
 	return db.beginDC(ctx, dc, dc.releaseConn, opts)
 }
 
@@ -1917,6 +2404,7 @@ func (db *DB) Conn(ctx context.Context) (*Conn, error) {
 		return nil, err
 	}
 
+	dc.setOp("Conn", "")
 	conn := &Conn{
 		db: db,
 		dc: dc,
@@ -2471,6 +2959,17 @@ func (tx *Tx) ExecContext(ctx context.Context, query string, args ...any) (Resul
 	return tx.db.execDC(ctx, dc, release, query, args)
 }
 
+// Batch executes the statements of b within the transaction and
+// returns one BatchResult for each statement, in order.
+// See DB.Batch for details.
+func (tx *Tx) Batch(ctx context.Context, b *Batch) ([]BatchResult, error) {
+	dc, release, err := tx.grabConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tx.db.batchDC(ctx, dc, release, b)
+}
+
 // Exec executes a query that doesn't return rows.
 // For example: an INSERT and UPDATE.
 //
@@ -2601,7 +3100,7 @@ func (s *Stmt) ExecContext(ctx context.Context, args ...any) (Result, error) {
 
 	var res Result
 	err := s.db.retry(func(strategy connReuseStrategy) error {
-		dc, releaseConn, ds, err := s.connStmt(ctx, strategy)
+		dc, releaseConn, ds, err := s.connStmt(ctx, "Exec", strategy)
 		if err != nil {
 			return err
 		}
@@ -2667,8 +3166,9 @@ func (s *Stmt) removeClosedStmtLocked() {
 
 // connStmt returns a free driver connection on which to execute the
 // statement, a function to call to release the connection, and a
-// statement bound to that connection.
-func (s *Stmt) connStmt(ctx context.Context, strategy connReuseStrategy) (dc *driverConn, releaseConn func(error), ds *driverStmt, err error) {
+// statement bound to that connection. op names the operation
+// for Hooks.OnRelease, if a connection is taken from the pool.
+func (s *Stmt) connStmt(ctx context.Context, op string, strategy connReuseStrategy) (dc *driverConn, releaseConn func(error), ds *driverStmt, err error) {
 	if err = s.stickyErr; err != nil {
 		return
 	}
@@ -2697,6 +3197,7 @@ func (s *Stmt) connStmt(ctx context.Context, strategy connReuseStrategy) (dc *dr
 	if err != nil {
 		return nil, nil, nil, err
 	}
+	dc.setOp(op, s.query)
 
 	s.mu.Lock()
 	for _, v := range s.css {
@@ -2743,7 +3244,7 @@ func (s *Stmt) QueryContext(ctx context.Context, args ...any) (*Rows, error) {
 	var rows *Rows
 
 	err := s.db.retry(func(strategy connReuseStrategy) error {
-		dc, releaseConn, ds, err := s.connStmt(ctx, strategy)
+		dc, releaseConn, ds, err := s.connStmt(ctx, "Query", strategy)
 		if err != nil {
 			return err
 		}
@@ -2898,6 +3399,13 @@ type Rows struct {
 	// lastcols is only used in Scan, Next, and NextResultSet which are expected
 	// not to be called concurrently.
 	lastcols []driver.Value
+
+	// structType and structIndex cache the mapping from the columns of
+	// the current result set to the fields of the struct type last
+	// passed to ScanStruct. Like lastcols, they are only used in
+	// ScanStruct and NextResultSet.
+	structType  reflect.Type
+	structIndex [][]int
 }
 
 // lasterrOrErrLocked returns either lasterr or the provided err.
@@ -3015,6 +3523,7 @@ func (rs *Rows) NextResultSet() bool {
 	}
 
 	rs.lastcols = nil
+	rs.structType, rs.structIndex = nil, nil
 	nextResultSet, ok := rs.rowsi.(driver.RowsNextResultSet)
 	if !ok {
 		doClose = true
@@ -3258,6 +3767,56 @@ func (rs *Rows) Scan(dest ...any) error {
 	return nil
 }
 
+// ScanStruct copies the columns in the current row into the fields of
+// the struct pointed at by dest, matching each column to a field by
+// name. A field's column name is given by its "db" struct tag, or is
+// the field name if it has none; fields tagged `db:"-"` are ignored.
+// A column matches a field whose column name is equal to the column's
+// name or, failing that, equal under Unicode case-folding.
+//
+// Only exported fields are considered. The fields of an embedded
+// struct, or pointer to struct, without a "db" tag are treated as if
+// they were fields of the outer struct, following the Go rules for
+// selectors as encoding/json does: a field hides the fields of the same
+// name that are embedded more deeply, and fields of the same name at
+// the same depth are ambiguous and ignored, unless exactly one of them
+// has a "db" tag. A nil embedded pointer is allocated when a column is
+// assigned to one of its fields.
+//
+// Each column is converted and assigned to its field as by Scan. It is
+// an error for a column to have no matching field; fields without a
+// matching column are left unchanged.
+func (rs *Rows) ScanStruct(dest any) error {
+	return rs.scanStruct(dest, true)
+}
+
+func (rs *Rows) scanStruct(dest any, allowRawBytes bool) error {
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() || dv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("sql: ScanStruct requires a non-nil pointer to a struct, not %T", dest)
+	}
+	sv := dv.Elem()
+	if rs.structType != sv.Type() {
+		cts, err := rs.ColumnTypes()
+		if err != nil {
+			return err
+		}
+		index, err := columnFieldIndex(sv.Type(), cts)
+		if err != nil {
+			return err
+		}
+		rs.structType, rs.structIndex = sv.Type(), index
+	}
+	fields := make([]any, len(rs.structIndex))
+	for i, index := range rs.structIndex {
+		fields[i] = fieldByIndex(sv, index).Addr().Interface()
+		if _, ok := fields[i].(*RawBytes); ok && !allowRawBytes {
+			return errors.New("sql: RawBytes isn't allowed on Row.ScanStruct")
+		}
+	}
+	return rs.Scan(fields...)
+}
+
 // rowsCloseHook returns a function so tests may install the
 // hook through a test only mutex.
 var rowsCloseHook = func() func(*Rows, *error) { return nil }
@@ -3353,6 +3912,32 @@ func (r *Row) Scan(dest ...any) error {
 	return r.rows.Close()
 }
 
+// ScanStruct copies the columns from the matched row into the fields
+// of the struct pointed at by dest, as described by Rows.ScanStruct.
+// If more than one row matches the query, ScanStruct uses the first
+// row and discards the rest. If no row matches the query, ScanStruct
+// returns ErrNoRows.
+func (r *Row) ScanStruct(dest any) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	// See the comment in Scan about RawBytes.
+	defer r.rows.Close()
+
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return err
+		}
+		return ErrNoRows
+	}
+	if err := r.rows.scanStruct(dest, false); err != nil {
+		return err
+	}
+	// Make sure the query can be processed to completion with no errors.
+	return r.rows.Close()
+}
+
 // Err provides a way for wrapping packages to check for
 // query errors without calling Scan.
 // Err returns the error, if any, that was encountered while running the query.
@@ -1043,6 +1043,122 @@ func TestExec(t *testing.T) {
 	}
 }
 
+func TestBatch(t *testing.T) {
+	for _, noBatch := range []bool{false, true} {
+		t.Run(fmt.Sprintf("noBatch=%v", noBatch), func(t *testing.T) {
+			db := newTestDBConnector(t, &fakeConnector{noBatch: noBatch}, "foo")
+			defer closeDB(t, db)
+			exec(t, db, "CREATE|t1|name=string,age=int32")
+
+			// The statements of a batch all run on one connection.
+			db.SetMaxOpenConns(1)
+			conn, err := db.Conn(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			conn.dc.ci.(*fakeConn).skipDirtySession = true
+			conn.Close()
+
+			var b Batch
+			b.Queue("INSERT|t1|name=?,age=?", "Alice", 1)
+			b.Queue("INSERT|t1|name=?,age=?", "Bob", "not a number")
+			b.Queue("INSERT|t1|name=?,age=?", "Chris", 3)
+			b.Queue("BOGUS")
+			if b.Len() != 4 {
+				t.Fatalf("Len = %d; want 4", b.Len())
+			}
+			res, err := db.Batch(context.Background(), &b)
+			if err == nil && len(res) != 4 {
+				err = fmt.Errorf("got %d results; want 4", len(res))
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, wantErr := range []bool{false, true, false, true} {
+				if gotErr := res[i].Err != nil; gotErr != wantErr || (res[i].Result == nil) != wantErr {
+					t.Errorf("result %d = %+v; want error %v", i, res[i], wantErr)
+				}
+			}
+			if n, err := res[0].Result.RowsAffected(); n != 1 || err != nil {
+				t.Errorf("RowsAffected = %d, %v; want 1, nil", n, err)
+			}
+
+			var count int
+			if err := db.QueryRow("SELECT|t1|COUNT(*)|").Scan(&count); err == nil && count != 2 {
+				t.Errorf("inserted %d rows; want 2", count)
+			}
+
+			db.mu.Lock()
+			fc := db.freeConn[0].ci.(*fakeConn)
+			db.mu.Unlock()
+			if want := map[bool]int{false: 1, true: 0}[noBatch]; fc.numBatch != want {
+				t.Errorf("driver ExecBatch called %d times; want %d", fc.numBatch, want)
+			}
+		})
+	}
+}
+
+func TestTxBatch(t *testing.T) {
+	db := newTestDB(t, "foo")
+	defer closeDB(t, db)
+	exec(t, db, "CREATE|t1|name=string,age=int32")
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b Batch
+	b.Queue("INSERT|t1|name=?,age=?", "Alice", 1)
+	b.Queue("INSERT|t1|name=?,age=?", "Bob", 2)
+	res, err := tx.Batch(context.Background(), &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range res {
+		if r.Err != nil {
+			t.Errorf("result %d: %v", i, r.Err)
+		}
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Batch(context.Background(), &b); err != ErrTxDone {
+		t.Errorf("Batch after Rollback = %v; want ErrTxDone", err)
+	}
+}
+
+// emptyBatchConn is a driver.Conn whose ExecBatch returns
+// results with neither a Result nor an Err.
+type emptyBatchConn struct {
+	badConn
+}
+
+func (emptyBatchConn) ExecBatch(ctx context.Context, stmts []driver.BatchStatement) ([]driver.BatchResult, error) {
+	return make([]driver.BatchResult, len(stmts)), nil
+}
+
+type emptyBatchDriver struct{}
+
+func (emptyBatchDriver) Open(name string) (driver.Conn, error) {
+	return emptyBatchConn{}, nil
+}
+
+func TestBatchEmptyResult(t *testing.T) {
+	Register("emptybatch", emptyBatchDriver{})
+	db, err := Open("emptybatch", "ignored")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var b Batch
+	b.Queue("ignored")
+	res, err := db.Batch(context.Background(), &b)
+	if err == nil {
+		t.Errorf("Batch = %+v, nil; want error", res)
+	}
+}
+
 func TestTxPrepare(t *testing.T) {
 	db := newTestDB(t, "")
 	defer closeDB(t, db)
@@ -1888,6 +2004,45 @@ func TestNullTimeParam(t *testing.T) {
 	nullTestRun(t, spec)
 }
 
+func TestGenericNullStringParam(t *testing.T) {
+	spec := nullTestSpec{"nullstring", "string", [6]nullTestRow{
+		{Null[string]{"aqua", true}, "", Null[string]{"aqua", true}},
+		{Null[string]{"brown", false}, "", Null[string]{"", false}},
+		{"chartreuse", "", Null[string]{"chartreuse", true}},
+		{Null[string]{"darkred", true}, "", Null[string]{"darkred", true}},
+		{Null[string]{"eel", false}, "", Null[string]{"", false}},
+		{"foo", Null[string]{"black", false}, nil},
+	}}
+	nullTestRun(t, spec)
+}
+
+func TestGenericNullIntParam(t *testing.T) {
+	spec := nullTestSpec{"nullint64", "int64", [6]nullTestRow{
+		{Null[int]{31, true}, 1, Null[int]{31, true}},
+		{Null[int]{-22, false}, 1, Null[int]{0, false}},
+		{22, 1, Null[int]{22, true}},
+		{Null[int]{33, true}, 1, Null[int]{33, true}},
+		{Null[int]{222, false}, 1, Null[int]{0, false}},
+		{0, Null[int]{31, false}, nil},
+	}}
+	nullTestRun(t, spec)
+}
+
+func TestGenericNullTimeParam(t *testing.T) {
+	t0 := time.Time{}
+	t1 := time.Date(2000, 1, 1, 8, 9, 10, 11, time.UTC)
+	t2 := time.Date(2010, 1, 1, 8, 9, 10, 11, time.UTC)
+	spec := nullTestSpec{"nulldatetime", "datetime", [6]nullTestRow{
+		{Null[time.Time]{t1, true}, t2, Null[time.Time]{t1, true}},
+		{Null[time.Time]{t1, false}, t2, Null[time.Time]{t0, false}},
+		{t1, t2, Null[time.Time]{t1, true}},
+		{Null[time.Time]{t1, true}, t2, Null[time.Time]{t1, true}},
+		{Null[time.Time]{t1, false}, t2, Null[time.Time]{t0, false}},
+		{t2, Null[time.Time]{t1, false}, nil},
+	}}
+	nullTestRun(t, spec)
+}
+
 func nullTestRun(t *testing.T, spec nullTestSpec) {
 	db := newTestDB(t, "")
 	defer closeDB(t, db)
@@ -1943,6 +2098,84 @@ func nullTestRun(t *testing.T, spec nullTestSpec) {
 	}
 }
 
+type scanBase struct {
+	Name string
+	Age  int
+}
+
+type scanPerson struct {
+	scanBase
+	Years      int64           `db:"age"`
+	Photo      []byte          `db:"photo"`
+	Birthday   Null[time.Time] `db:"bdate"`
+	Ignored    string          `db:"-"`
+	unexported string
+}
+
+func TestScanStruct(t *testing.T) {
+	db := newTestDB(t, "people")
+	defer closeDB(t, db)
+
+	rows, err := db.Query("SELECT|people|name,age,photo,bdate|")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var got []scanPerson
+	for rows.Next() {
+		p := scanPerson{Ignored: "keep"}
+		if err := rows.ScanStruct(&p); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, p)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	want := []scanPerson{
+		{scanBase: scanBase{Name: "Alice"}, Years: 1, Photo: []byte("APHOTO"), Ignored: "keep"},
+		{scanBase: scanBase{Name: "Bob"}, Years: 2, Photo: []byte("BPHOTO"), Ignored: "keep"},
+		{scanBase: scanBase{Name: "Chris"}, Years: 3, Photo: []byte("CPHOTO"), Birthday: Null[time.Time]{chrisBirthday, true}, Ignored: "keep"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+
+	// Columns match fields by name, folding case.
+	var b scanBase
+	if err := db.QueryRow("SELECT|people|age,name|name=?", "Bob").ScanStruct(&b); err != nil {
+		t.Fatal(err)
+	}
+	if b != (scanBase{Name: "Bob", Age: 2}) {
+		t.Errorf("got %+v; want Bob, 2", b)
+	}
+
+	// A nil embedded pointer is allocated.
+	var pb struct {
+		*FieldsP
+		Photo []byte
+	}
+	if err := db.QueryRow("SELECT|people|name,photo|name=?", "Bob").ScanStruct(&pb); err != nil {
+		t.Fatal(err)
+	}
+	if pb.FieldsP == nil || pb.Name != "Bob" || string(pb.Photo) != "BPHOTO" {
+		t.Errorf("got %+v, %q; want Bob, BPHOTO", pb.FieldsP, pb.Photo)
+	}
+
+	err = db.QueryRow("SELECT|people|name,dead|").ScanStruct(&b)
+	if err == nil || !strings.Contains(err.Error(), `no field in sql.scanBase for column "dead"`) {
+		t.Errorf("ScanStruct with unmatched column: got %v", err)
+	}
+	err = db.QueryRow("SELECT|people|name|").ScanStruct(b)
+	if err == nil || !strings.Contains(err.Error(), "non-nil pointer to a struct") {
+		t.Errorf("ScanStruct of non-pointer: got %v", err)
+	}
+	err = db.QueryRow("SELECT|people|name|name=?", "Nobody").ScanStruct(&b)
+	if err != ErrNoRows {
+		t.Errorf("ScanStruct of no rows: got %v; want ErrNoRows", err)
+	}
+}
+
 // golang.org/issue/4859
 func TestQueryRowNilScanDest(t *testing.T) {
 	db := newTestDB(t, "people")
@@ -2331,6 +2564,138 @@ func TestStats(t *testing.T) {
 	}
 }
 
+func TestStatsWaitHistogram(t *testing.T) {
+	db := newTestDB(t, "people")
+	defer closeDB(t, db)
+	db.SetMaxOpenConns(1)
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error)
+	go func() {
+		c, err := db.Conn(ctx)
+		if err == nil {
+			c.Close()
+		}
+		done <- err
+	}()
+	waitCondition(t, func() bool { return db.Stats().WaitCount == 1 })
+	conn.Close()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	h := db.Stats().WaitHistogram
+	if len(h.Counts) != len(h.Bounds)+1 {
+		t.Fatalf("got %d counts for %d bounds", len(h.Counts), len(h.Bounds))
+	}
+	var total int64
+	for _, n := range h.Counts {
+		total += n
+	}
+	if total != 1 {
+		t.Errorf("histogram counts %v; want 1 wait", h.Counts)
+	}
+}
+
+func TestHooks(t *testing.T) {
+	db := newTestDB(t, "people")
+	defer closeDB(t, db)
+	db.SetMaxIdleConns(1)
+
+	type ctxKey struct{}
+	var (
+		mu     sync.Mutex
+		events []string
+	)
+	record := func(ctx context.Context, format string, args ...any) {
+		mu.Lock()
+		defer mu.Unlock()
+		op, _ := ctx.Value(ctxKey{}).(string)
+		events = append(events, op+": "+fmt.Sprintf(format, args...))
+	}
+	db.SetHooks(&Hooks{
+		OnConnect: func(ctx context.Context, info ConnectInfo) {
+			record(ctx, "connect %d %v", info.ConnID, info.Err)
+		},
+		OnAcquire: func(ctx context.Context, info AcquireInfo) {
+			record(ctx, "acquire %d reused=%v reset=%v", info.ConnID, info.Reused, info.Reset)
+		},
+		OnRelease: func(ctx context.Context, info ReleaseInfo) {
+			if info.Held < 0 {
+				t.Errorf("negative Held %v", info.Held)
+			}
+			record(ctx, "release %d %s %q %v", info.ConnID, info.Op, info.Query, info.Err)
+		},
+		OnClose: func(ctx context.Context, info CloseInfo) {
+			record(ctx, "close %d %v", info.ConnID, info.Reason)
+		},
+	})
+
+	// Use the idle connection, and a second, new one at the same time.
+	ctx1 := context.WithValue(context.Background(), ctxKey{}, "q1")
+	ctx2 := context.WithValue(context.Background(), ctxKey{}, "q2")
+	rows1, err := db.QueryContext(ctx1, "SELECT|people|name|")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows2, err := db.QueryContext(ctx2, "SELECT|people|name|")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows1.Close()
+	rows2.Close()
+
+	// Statements report their query, whichever way they are run.
+	ctx3 := context.WithValue(context.Background(), ctxKey{}, "q3")
+	if _, err := db.ExecContext(ctx3, "INSERT|people|name=Eve,age=?", 5); err != nil {
+		t.Fatal(err)
+	}
+	stmt, err := db.PrepareContext(ctx3, "SELECT|people|name|age=?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stmt.QueryRowContext(ctx3, 5).Scan(new(string)); err != nil {
+		t.Fatal(err)
+	}
+	stmt.Close()
+	tx, err := db.BeginTx(ctx3, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.ExecContext(ctx3, "INSERT|people|name=Fay,age=?", 6); err != nil {
+		t.Fatal(err)
+	}
+	tx.Commit()
+	db.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{
+		"q1: acquire 1 reused=true reset=true",
+		"q2: connect 2 <nil>",
+		"q2: acquire 2 reused=false reset=false",
+		"q1: release 1 Query \"SELECT|people|name|\" <nil>",
+		"q2: release 2 Query \"SELECT|people|name|\" <nil>",
+		"q2: close 2 max idle connections",
+		"q3: acquire 1 reused=true reset=true",
+		"q3: release 1 Exec \"INSERT|people|name=Eve,age=?\" <nil>",
+		"q3: acquire 1 reused=true reset=true",
+		"q3: release 1 Prepare \"SELECT|people|name|age=?\" <nil>",
+		"q3: acquire 1 reused=true reset=true",
+		"q3: release 1 Query \"SELECT|people|name|age=?\" <nil>",
+		"q3: acquire 1 reused=true reset=true",
+		"q3: release 1 Begin \"\" <nil>",
+		": close 1 database closed",
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events:\n%s\nwant:\n%s", strings.Join(events, "\n"), strings.Join(want, "\n"))
+	}
+}
+
 func TestConnMaxLifetime(t *testing.T) {
 	t0 := time.Unix(1000000, 0)
 	offset := time.Duration(0)
@@ -18,13 +18,17 @@ package elf
 import (
 	"bytes"
 	"compress/zlib"
+	"compress/zstd"
 	"debug/dwarf"
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"hash/crc32"
 	"internal/saferio"
 	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
@@ -140,7 +144,8 @@ func (s *Section) Open() io.ReadSeeker {
 	if s.Flags&SHF_COMPRESSED == 0 {
 		return io.NewSectionReader(s.sr, 0, 1<<63-1)
 	}
-	if s.compressionType == COMPRESS_ZLIB {
+	switch s.compressionType {
+	case COMPRESS_ZLIB:
 		return &readSeekerFromReader{
 			reset: func() (io.Reader, error) {
 				fr := io.NewSectionReader(s.sr, s.compressionOffset, int64(s.FileSize)-s.compressionOffset)
@@ -148,6 +153,14 @@ func (s *Section) Open() io.ReadSeeker {
 			},
 			size: int64(s.Size),
 		}
+	case COMPRESS_ZSTD:
+		return &readSeekerFromReader{
+			reset: func() (io.Reader, error) {
+				fr := io.NewSectionReader(s.sr, s.compressionOffset, int64(s.FileSize)-s.compressionOffset)
+				return zstd.NewReader(fr), nil
+			},
+			size: int64(s.Size),
+		}
 	}
 	err := &FormatError{int64(s.Offset), "unknown compression type", s.compressionType}
 	return errorReader{err}
@@ -1427,6 +1440,131 @@ func (f *File) DWARF() (*dwarf.Data, error) {
 	return d, nil
 }
 
+// OpenDebugFile opens the separate debug file that holds the debug
+// information removed from f, as by "objcopy --only-keep-debug" or the
+// Go linker's -dwarf=split flag, so that it can be read with the DWARF
+// method of the returned File. Split DWARF (.dwo) files are not
+// supported.
+//
+// OpenDebugFile looks in each of the directories dirs, in order, for
+// the file named by f's .gnu_debuglink section, and for the file
+// .build-id/xx/yyyy.debug named by f's GNU build ID, where xx is the
+// first byte of the ID in hexadecimal and yyyy the rest. A file is only
+// used if its build ID matches f's or, if f has none, its checksum
+// matches the one in the .gnu_debuglink section. The directories
+// debuggers usually search are the directory of f, its .debug
+// subdirectory, /usr/lib/debug, and /usr/lib/debug followed by the
+// absolute path of the directory of f.
+//
+// If no debug file is found, the error wraps fs.ErrNotExist.
+// The caller must close the returned File.
+func (f *File) OpenDebugFile(dirs ...string) (*File, error) {
+	id, err := f.buildID()
+	if err != nil {
+		return nil, err
+	}
+	link, crc, err := f.debugLink()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, dir := range dirs {
+		if len(id) >= 2 {
+			h := fmt.Sprintf("%x", id)
+			names = append(names, filepath.Join(dir, ".build-id", h[:2], h[2:]+".debug"))
+		}
+		if link != "" {
+			names = append(names, filepath.Join(dir, link))
+		}
+	}
+	for _, name := range names {
+		df, err := Open(name)
+		if err != nil {
+			continue
+		}
+		if df.isDebugFileFor(name, id, crc) {
+			return df, nil
+		}
+		df.Close()
+	}
+	return nil, errNoDebugFile
+}
+
+var errNoDebugFile = fmt.Errorf("elf: separate debug file not found: %w", fs.ErrNotExist)
+
+// isDebugFileFor reports whether f, read from the named file, is the
+// debug file of a file with the given build ID or, if there is none,
+// .gnu_debuglink checksum.
+func (f *File) isDebugFileFor(name string, id []byte, crc uint32) bool {
+	if f.Section(".debug_info") == nil && f.Section(".zdebug_info") == nil {
+		return false
+	}
+	if len(id) > 0 {
+		fid, err := f.buildID()
+		return err == nil && bytes.Equal(fid, id)
+	}
+	r, err := os.Open(name)
+	if err != nil {
+		return false
+	}
+	defer r.Close()
+	h := crc32.NewIEEE()
+	if _, err := io.Copy(h, r); err != nil {
+		return false
+	}
+	return h.Sum32() == crc
+}
+
+// buildID returns the GNU build ID of f, or nil if it has none.
+func (f *File) buildID() ([]byte, error) {
+	for _, s := range f.Sections {
+		if s.Type != SHT_NOTE {
+			continue
+		}
+		b, err := s.Data()
+		if err != nil {
+			return nil, err
+		}
+		for len(b) >= 12 {
+			namesz := uint64(f.ByteOrder.Uint32(b))
+			descsz := uint64(f.ByteOrder.Uint32(b[4:]))
+			typ := f.ByteOrder.Uint32(b[8:])
+			b = b[12:]
+			descoff := (namesz + 3) &^ 3
+			end := descoff + (descsz+3)&^3
+			if end > uint64(len(b)) {
+				return nil, &FormatError{int64(s.Offset), "invalid note", nil}
+			}
+			if typ == 3 && string(b[:namesz]) == "GNU\x00" { // NT_GNU_BUILD_ID
+				return b[descoff : descoff+descsz], nil
+			}
+			b = b[end:]
+		}
+	}
+	return nil, nil
+}
+
+// debugLink returns the name of the separate debug file and the CRC-32
+// checksum of its contents recorded in f's .gnu_debuglink section.
+// It returns an empty name if f has no such section.
+func (f *File) debugLink() (name string, crc uint32, err error) {
+	s := f.Section(".gnu_debuglink")
+	if s == nil || s.Type == SHT_NOBITS {
+		return "", 0, nil
+	}
+	b, err := s.Data()
+	if err != nil {
+		return "", 0, err
+	}
+	i := bytes.IndexByte(b, 0)
+	off := (i + 4) &^ 3
+	if i <= 0 || off+4 > len(b) {
+		return "", 0, &FormatError{int64(s.Offset), "invalid .gnu_debuglink section", nil}
+	}
+	return filepath.Base(string(b[:i])), f.ByteOrder.Uint32(b[off:]), nil
+}
+
 // Symbols returns the symbol table for f. The symbols will be listed in the order
 // they appear in f.
 //
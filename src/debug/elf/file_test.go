@@ -9,8 +9,10 @@ import (
 	"compress/gzip"
 	"debug/dwarf"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"math/rand"
 	"net"
 	"os"
@@ -812,6 +814,131 @@ func TestCompressedDWARF(t *testing.T) {
 	}
 }
 
+func TestZstdCompressedSection(t *testing.T) {
+	// Test file made from compressed-64.obj with
+	// objcopy --compress-debug-sections=zstd.
+	zf, err := Open("testdata/compressed-zstd-64.obj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zf.Close()
+	f, err := Open("testdata/compressed-64.obj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if s := zf.Section(".debug_info"); s.Flags&SHF_COMPRESSED == 0 || s.compressionType != COMPRESS_ZSTD {
+		t.Fatalf("want zstd compressed .debug_info, got flags %v, compression %v", s.Flags, s.compressionType)
+	}
+	for _, name := range []string{".debug_info", ".debug_aranges", ".debug_str"} {
+		zsec, sec := zf.Section(name), f.Section(name)
+		want, err := sec.Data()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := zsec.Data()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s: got data %x, want %x", name, got, want)
+		}
+	}
+	if _, err := zf.DWARF(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSeparateDebugFile(t *testing.T) {
+	// Test files made from gcc-amd64-linux-exec with
+	// objcopy --only-keep-debug gcc-amd64-linux-exec gcc-amd64-linux-exec-split.debug
+	// objcopy --strip-debug --add-gnu-debuglink=gcc-amd64-linux-exec-split.debug \
+	//	gcc-amd64-linux-exec gcc-amd64-linux-exec-split
+	entries := func(file string) []string {
+		f, err := Open(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if f.Section(".debug_info") == nil {
+			df, err := f.OpenDebugFile(path.Dir(file))
+			if err != nil {
+				t.Fatalf("%s: %v", file, err)
+			}
+			defer df.Close()
+			f = df
+		}
+		d, err := f.DWARF()
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		var list []string
+		r := d.Reader()
+		for {
+			e, err := r.Next()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e == nil {
+				break
+			}
+			list = append(list, fmt.Sprint(e.Offset, e.Tag, e.Val(dwarf.AttrName)))
+		}
+		return list
+	}
+
+	want := entries("testdata/gcc-amd64-linux-exec")
+	got := entries("testdata/gcc-amd64-linux-exec-split")
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DWARF of stripped file = %v, want %v", got, want)
+	}
+
+	// The debug file is only used if its checksum matches.
+	dir := t.TempDir()
+	for _, name := range []string{"gcc-amd64-linux-exec-split", "gcc-amd64-linux-exec-split.debug"} {
+		b, err := os.ReadFile("testdata/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path.Ext(name) == ".debug" {
+			b = append(b, 0)
+		}
+		if err := os.WriteFile(dir+"/"+name, b, 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f, err := Open(dir + "/gcc-amd64-linux-exec-split")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if df, err := f.OpenDebugFile(dir); !errors.Is(err, fs.ErrNotExist) {
+		if err == nil {
+			df.Close()
+		}
+		t.Errorf("OpenDebugFile with a mismatched checksum: %v; want not found", err)
+	}
+
+	// Only the given directories are searched.
+	f, err = Open("testdata/gcc-amd64-linux-exec-split")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if df, err := f.OpenDebugFile(); !errors.Is(err, fs.ErrNotExist) {
+		if err == nil {
+			df.Close()
+		}
+		t.Errorf("OpenDebugFile() = %v; want not found", err)
+	}
+	if d, err := f.DWARF(); err == nil {
+		if e, _ := d.Reader().Next(); e != nil {
+			t.Errorf("DWARF of stripped file read a debug file")
+		}
+	}
+}
+
 func TestCompressedSection(t *testing.T) {
 	// Test files built with gcc -g -S hello.c and assembled with
 	// --compress-debug-sections=zlib-gabi.
@@ -83,6 +83,9 @@ var depsRules = `
 	RUNTIME
 	< arena;
 
+	RUNTIME
+	< simd;
+
 	syscall !< io;
 	reflect !< sort;
 
@@ -224,7 +227,7 @@ var depsRules = `
 
 	# compression
 	FMT, encoding/binary, hash/adler32, hash/crc32
-	< compress/bzip2, compress/flate, compress/lzw
+	< compress/bzip2, compress/flate, compress/lzw, compress/zstd
 	< archive/zip, compress/gzip, compress/zlib;
 
 	# templates
@@ -249,7 +252,7 @@ var depsRules = `
 	< index/suffixarray;
 
 	# executable parsing
-	FMT, encoding/binary, compress/zlib, internal/saferio
+	FMT, encoding/binary, compress/zlib, compress/zstd, internal/saferio
 	< runtime/debug
 	< debug/dwarf
 	< debug/elf, debug/gosym, debug/macho, debug/pe, debug/plan9obj, internal/xcoff
@@ -488,6 +491,8 @@ var depsRules = `
 	< net/http/httptrace;
 
 	compress/gzip,
+	compress/zlib,
+	compress/zstd,
 	golang.org/x/net/http/httpguts,
 	golang.org/x/net/http/httpproxy,
 	golang.org/x/net/http2/hpack,
@@ -507,9 +512,15 @@ var depsRules = `
 	net/http, net/http/internal/ascii
 	< net/http/cookiejar, net/http/httputil;
 
-	net/http, flag
+	FMT
+	< internal/txtar;
+
+	net/http, flag, internal/txtar
 	< net/http/httptest;
 
+	net/http
+	< net/dnstransport;
+
 	net/http, regexp
 	< net/http/cgi
 	< net/http/fcgi;
@@ -570,7 +581,7 @@ var depsRules = `
 	< internal/trace;
 
 	FMT
-	< internal/diff, internal/txtar;
+	< internal/diff;
 
 	FMT, crypto/md5, encoding/binary, regexp, sort, text/tabwriter, unsafe,
 	internal/coverage, internal/coverage/uleb128
@@ -100,4 +100,9 @@ type Flags struct {
 	// this compels the Go runtime to write to some arbitrary file, which
 	// may be exploited.
 	PageTrace bool
+
+	// SIMD enables the experimental "simd" standard library package
+	// of fixed-width vector types, and the compiler intrinsics that
+	// implement its operations with vector instructions.
+	SIMD bool
 }
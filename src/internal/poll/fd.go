@@ -81,3 +81,9 @@ func consume(v *[][]byte, n int64) {
 
 // TestHookDidWritev is a hook for testing writev.
 var TestHookDidWritev = func(wrote int) {}
+
+// String is a string type for the signatures of methods that other
+// packages in std export only for use within the standard library,
+// such as the extension methods of net's syscall.RawConn. Since it is
+// internal, code outside std cannot use those methods.
+type String string
@@ -34,3 +34,12 @@ func (fd *FD) SetsockoptLinger(level, name int, l *syscall.Linger) error {
 	defer fd.decref()
 	return syscall.SetsockoptLinger(fd.Sysfd, level, name, l)
 }
+
+// GetsockoptInt wraps the getsockopt network call with an int argument.
+func (fd *FD) GetsockoptInt(level, name int) (int, error) {
+	if err := fd.incref(); err != nil {
+		return -1, err
+	}
+	defer fd.decref()
+	return syscall.GetsockoptInt(fd.Sysfd, level, name)
+}
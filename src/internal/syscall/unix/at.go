@@ -38,3 +38,35 @@ func Openat(dirfd int, path string, flags int, perm uint32) (int, error) {
 
 	return int(fd), nil
 }
+
+func Mkdirat(dirfd int, path string, mode uint32) error {
+	p, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return err
+	}
+
+	_, _, errno := syscall.Syscall(mkdiratTrap, uintptr(dirfd), uintptr(unsafe.Pointer(p)), uintptr(mode))
+	if errno != 0 {
+		return errno
+	}
+
+	return nil
+}
+
+func Readlinkat(dirfd int, path string, buf []byte) (int, error) {
+	p, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	var p1 unsafe.Pointer
+	if len(buf) > 0 {
+		p1 = unsafe.Pointer(&buf[0])
+	}
+
+	n, _, errno := syscall.Syscall6(readlinkatTrap, uintptr(dirfd), uintptr(unsafe.Pointer(p)), uintptr(p1), uintptr(len(buf)), 0, 0)
+	if errno != 0 {
+		return 0, errno
+	}
+
+	return int(n), nil
+}
@@ -10,6 +10,8 @@ const (
 	AT_REMOVEDIR        = 0x800
 	AT_SYMLINK_NOFOLLOW = 0x200
 
-	unlinkatTrap uintptr = syscall.SYS_UNLINKAT
-	openatTrap   uintptr = syscall.SYS_OPENAT
+	unlinkatTrap   uintptr = syscall.SYS_UNLINKAT
+	openatTrap     uintptr = syscall.SYS_OPENAT
+	mkdiratTrap    uintptr = syscall.SYS_MKDIRAT
+	readlinkatTrap uintptr = syscall.SYS_READLINKAT
 )
@@ -8,6 +8,8 @@ import "syscall"
 
 const unlinkatTrap uintptr = syscall.SYS_UNLINKAT
 const openatTrap uintptr = syscall.SYS_OPENAT
+const mkdiratTrap uintptr = syscall.SYS_MKDIRAT
+const readlinkatTrap uintptr = syscall.SYS_READLINKAT
 const fstatatTrap uintptr = syscall.SYS_FSTATAT
 
 const AT_REMOVEDIR = 0x08
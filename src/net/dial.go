@@ -6,7 +6,9 @@ package net
 
 import (
 	"context"
+	"internal/itoa"
 	"internal/nettrace"
+	"net/netip"
 	"syscall"
 	"time"
 )
@@ -17,6 +19,38 @@ const (
 	defaultTCPKeepAlive = 15 * time.Second
 )
 
+// mptcpStatus is a tristate for Multipath TCP.
+type mptcpStatus uint8
+
+const (
+	// The value 0 is the system default, linked to defaultMPTCPEnabled
+	mptcpUseDefault mptcpStatus = iota
+	mptcpEnabled
+	mptcpDisabled
+)
+
+// defaultMPTCPEnabled reports whether MPTCP is used when neither
+// Dialer.SetMultipathTCP nor ListenConfig.SetMultipathTCP was called.
+const defaultMPTCPEnabled = false
+
+func (m *mptcpStatus) get() bool {
+	switch *m {
+	case mptcpEnabled:
+		return true
+	case mptcpDisabled:
+		return false
+	}
+	return defaultMPTCPEnabled
+}
+
+func (m *mptcpStatus) set(use bool) {
+	if use {
+		*m = mptcpEnabled
+	} else {
+		*m = mptcpDisabled
+	}
+}
+
 // A Dialer contains options for connecting to an address.
 //
 // The zero value for each field is equivalent to dialing
@@ -108,10 +142,33 @@ type Dialer struct {
 	//
 	// If ControlContext is not nil, Control is ignored.
 	ControlContext func(ctx context.Context, network, address string, c syscall.RawConn) error
+
+	// If mptcpStatus is set to a value allowing Multipath TCP (MPTCP) to be
+	// used, any call to Dial with "tcp(4|6)" as network will use MPTCP if
+	// supported by the operating system.
+	mptcpStatus mptcpStatus
 }
 
 func (d *Dialer) dualStack() bool { return d.FallbackDelay >= 0 }
 
+// MultipathTCP reports whether MPTCP will be used.
+//
+// This method doesn't check if MPTCP is supported by the operating
+// system or not.
+func (d *Dialer) MultipathTCP() bool {
+	return d.mptcpStatus.get()
+}
+
+// SetMultipathTCP directs the Dial methods to use, or not use, MPTCP,
+// if supported by the operating system. This method overrides the
+// system default.
+//
+// If MPTCP is not available on the host or not supported by the server,
+// the Dial methods will fall back to TCP.
+func (d *Dialer) SetMultipathTCP(use bool) {
+	d.mptcpStatus.set(use)
+}
+
 func minNonzeroTime(a, b time.Time) time.Time {
 	if a.IsZero() {
 		return b
@@ -386,6 +443,37 @@ func (d *Dialer) Dial(network, address string) (Conn, error) {
 // See func Dial for a description of the network and address
 // parameters.
 func (d *Dialer) DialContext(ctx context.Context, network, address string) (Conn, error) {
+	c, _, err := d.dialContext(ctx, network, address, false)
+	return c, err
+}
+
+// DialService connects to the HTTPS service at the address on the
+// named network using the provided context, like DialContext, and
+// returns the service binding of the endpoint it connected to, if it
+// used one. It is meant for connections that will carry HTTPS: the
+// service bindings tell how to reach the HTTPS service of a host, and
+// may name other hosts and ports than those of the address.
+//
+// If the network is a TCP network and the address's host is a domain
+// name, DialService connects to the endpoints the bindings returned by
+// LookupHTTPS describe, in order of priority, at the ports they give
+// and with their address hints if the endpoint's addresses cannot be
+// looked up, and finally to the address itself. Bindings that support
+// only HTTP/3, which runs over QUIC, are skipped. Otherwise it dials
+// the address as DialContext does.
+//
+// The addresses of each endpoint are raced in the manner of RFC 8305
+// ("Happy Eyeballs Version 2") rather than RFC 6555: the IPv6 and IPv4
+// addresses are interleaved, and a new connection attempt starts
+// whenever the previous one fails or after FallbackDelay, while the
+// earlier attempts continue.
+func (d *Dialer) DialService(ctx context.Context, network, address string) (Conn, *SVCB, error) {
+	return d.dialContext(ctx, network, address, true)
+}
+
+// dialContext implements DialContext, and DialService if service is
+// set.
+func (d *Dialer) dialContext(ctx context.Context, network, address string, service bool) (Conn, *SVCB, error) {
 	if ctx == nil {
 		panic("nil context")
 	}
@@ -419,16 +507,19 @@ func (d *Dialer) DialContext(ctx context.Context, network, address string) (Conn
 		resolveCtx = context.WithValue(resolveCtx, nettrace.TraceKey{}, &shadow)
 	}
 
-	addrs, err := d.resolver().resolveAddrList(resolveCtx, "dial", network, address, d.LocalAddr)
-	if err != nil {
-		return nil, &OpError{Op: "dial", Net: network, Source: nil, Addr: nil, Err: err}
-	}
-
 	sd := &sysDialer{
 		Dialer:  *d,
 		network: network,
 		address: address,
 	}
+	if service {
+		return sd.dialService(ctx, resolveCtx)
+	}
+
+	addrs, err := d.resolver().resolveAddrList(resolveCtx, "dial", network, address, d.LocalAddr)
+	if err != nil {
+		return nil, nil, &OpError{Op: "dial", Net: network, Source: nil, Addr: nil, Err: err}
+	}
 
 	var primaries, fallbacks addrList
 	if d.dualStack() && network == "tcp" {
@@ -437,7 +528,191 @@ func (d *Dialer) DialContext(ctx context.Context, network, address string) (Conn
 		primaries = addrs
 	}
 
-	return sd.dialParallel(ctx, primaries, fallbacks)
+	c, err := sd.dialParallel(ctx, primaries, fallbacks)
+	return c, nil, err
+}
+
+// dialService connects to sd.address using its HTTPS records, as
+// described for Dialer.DialService. Lookups use resolveCtx.
+func (sd *sysDialer) dialService(ctx, resolveCtx context.Context) (Conn, *SVCB, error) {
+	r := sd.resolver()
+	var firstErr error
+	if host, port, ok := sd.serviceHostPort(resolveCtx); ok {
+		svcbs, _ := r.LookupHTTPS(resolveCtx, host, port)
+		for _, s := range svcbs {
+			if !s.tcpCompatible() {
+				continue
+			}
+			p := port
+			if s.Port != 0 {
+				p = int(s.Port)
+			}
+			addrs, err := sd.serviceAddrs(resolveCtx, s, p)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = &OpError{Op: "dial", Net: sd.network, Source: nil, Addr: nil, Err: err}
+				}
+				continue
+			}
+			c, err := sd.dialRace(ctx, addrs)
+			if err == nil {
+				return c, s, nil
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			if ctx.Err() != nil {
+				return nil, nil, firstErr
+			}
+		}
+	}
+
+	// Fall back to the addresses of the host itself.
+	addrs, err := r.resolveAddrList(resolveCtx, "dial", sd.network, sd.address, sd.LocalAddr)
+	if err != nil {
+		if firstErr == nil {
+			firstErr = &OpError{Op: "dial", Net: sd.network, Source: nil, Addr: nil, Err: err}
+		}
+		return nil, nil, firstErr
+	}
+	c, err := sd.dialRace(ctx, addrs)
+	if err != nil {
+		if firstErr == nil {
+			firstErr = err
+		}
+		return nil, nil, firstErr
+	}
+	return c, nil, nil
+}
+
+// serviceHostPort returns the host and port of sd.address, and reports
+// whether they name a service that may have HTTPS records: a domain
+// name and a port reached over TCP.
+func (sd *sysDialer) serviceHostPort(ctx context.Context) (host string, port int, ok bool) {
+	switch sd.network {
+	case "tcp", "tcp4", "tcp6":
+	default:
+		return "", 0, false
+	}
+	host, service, err := SplitHostPort(sd.address)
+	if err != nil || host == "" {
+		return "", 0, false
+	}
+	if _, err := netip.ParseAddr(host); err == nil {
+		return "", 0, false
+	}
+	port, err = sd.resolver().LookupPort(ctx, sd.network, service)
+	if err != nil {
+		return "", 0, false
+	}
+	return host, port, true
+}
+
+// serviceAddrs returns the addresses of the endpoint of s, at the given
+// port. If the addresses of s.Target cannot be looked up, it returns
+// the address hints of s instead.
+func (sd *sysDialer) serviceAddrs(ctx context.Context, s *SVCB, port int) (addrList, error) {
+	addrs, err := sd.resolver().resolveAddrList(ctx, "dial", sd.network, JoinHostPort(s.Target, itoa.Itoa(port)), sd.LocalAddr)
+	if err == nil {
+		return addrs, nil
+	}
+	var hints addrList
+	for _, hint := range [][]netip.Addr{s.IPv6Hint, s.IPv4Hint} {
+		for _, ip := range hint {
+			if (sd.network == "tcp4" && !ip.Is4()) || (sd.network == "tcp6" && !ip.Is6()) {
+				continue
+			}
+			hints = append(hints, TCPAddrFromAddrPort(netip.AddrPortFrom(ip, uint16(port))))
+		}
+	}
+	if len(hints) == 0 {
+		return nil, err
+	}
+	return hints, nil
+}
+
+// dialRace connects to the addresses of ras in the manner of RFC 8305:
+// the addresses of the two families are interleaved, starting with the
+// family of the first address, and a new connection attempt starts
+// whenever the previous one fails or after the fallback delay, while
+// the earlier attempts continue. It returns the first established
+// connection and closes the others. Otherwise it returns the error
+// from the first address.
+func (sd *sysDialer) dialRace(ctx context.Context, ras addrList) (Conn, error) {
+	primaries, fallbacks := ras.partition(isIPv4)
+	ras = make(addrList, 0, len(ras))
+	for i := 0; i < len(primaries) || i < len(fallbacks); i++ {
+		if i < len(primaries) {
+			ras = append(ras, primaries[i])
+		}
+		if i < len(fallbacks) {
+			ras = append(ras, fallbacks[i])
+		}
+	}
+	if len(ras) == 0 {
+		return nil, &OpError{Op: "dial", Net: sd.network, Source: nil, Addr: nil, Err: errMissingAddress}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type dialResult struct {
+		Conn
+		error
+		index int
+	}
+	results := make(chan dialResult, len(ras))
+	next, pending := 0, 0
+	startAttempt := func() {
+		i := next
+		next++
+		pending++
+		go func() {
+			c, err := sd.dialSingle(ctx, ras[i])
+			results <- dialResult{Conn: c, error: err, index: i}
+		}()
+	}
+
+	startAttempt()
+	timer := time.NewTimer(sd.fallbackDelay())
+	defer timer.Stop()
+	var firstErr error
+	firstErrIndex := len(ras)
+	for pending > 0 {
+		select {
+		case <-timer.C:
+			if next < len(ras) {
+				startAttempt()
+				timer.Reset(sd.fallbackDelay())
+			}
+
+		case res := <-results:
+			pending--
+			if res.error == nil {
+				// Close the connections of the attempts
+				// still in progress, should they succeed.
+				go func(pending int) {
+					for ; pending > 0; pending-- {
+						if res := <-results; res.Conn != nil {
+							res.Conn.Close()
+						}
+					}
+				}(pending)
+				return res.Conn, nil
+			}
+			if res.index < firstErrIndex {
+				firstErr, firstErrIndex = res.error, res.index
+			}
+			if next < len(ras) {
+				if !timer.Stop() {
+					<-timer.C
+				}
+				startAttempt()
+				timer.Reset(sd.fallbackDelay())
+			}
+		}
+	}
+	return nil, firstErr
 }
 
 // dialParallel races two copies of dialSerial, giving the first a
@@ -577,7 +852,11 @@ func (sd *sysDialer) dialSingle(ctx context.Context, ra Addr) (c Conn, err error
 	switch ra := ra.(type) {
 	case *TCPAddr:
 		la, _ := la.(*TCPAddr)
-		c, err = sd.dialTCP(ctx, la, ra)
+		if sd.MultipathTCP() {
+			c, err = sd.dialMPTCP(ctx, la, ra)
+		} else {
+			c, err = sd.dialTCP(ctx, la, ra)
+		}
 	case *UDPAddr:
 		la, _ := la.(*UDPAddr)
 		c, err = sd.dialUDP(ctx, la, ra)
@@ -613,6 +892,29 @@ type ListenConfig struct {
 	// that do not support keep-alives ignore this field.
 	// If negative, keep-alives are disabled.
 	KeepAlive time.Duration
+
+	// If mptcpStatus is set to a value allowing Multipath TCP (MPTCP) to be
+	// used, any call to Listen with "tcp(4|6)" as network will use MPTCP if
+	// supported by the operating system.
+	mptcpStatus mptcpStatus
+}
+
+// MultipathTCP reports whether MPTCP will be used.
+//
+// This method doesn't check if MPTCP is supported by the operating
+// system or not.
+func (lc *ListenConfig) MultipathTCP() bool {
+	return lc.mptcpStatus.get()
+}
+
+// SetMultipathTCP directs the Listen method to use, or not use, MPTCP,
+// if supported by the operating system. This method overrides the
+// system default.
+//
+// If MPTCP is not available on the host or not supported by the client,
+// the Listen method will fall back to TCP.
+func (lc *ListenConfig) SetMultipathTCP(use bool) {
+	lc.mptcpStatus.set(use)
 }
 
 // Listen announces on the local network address.
@@ -633,7 +935,11 @@ func (lc *ListenConfig) Listen(ctx context.Context, network, address string) (Li
 	la := addrs.first(isIPv4)
 	switch la := la.(type) {
 	case *TCPAddr:
-		l, err = sl.listenTCP(ctx, la)
+		if sl.MultipathTCP() {
+			l, err = sl.listenMPTCP(ctx, la)
+		} else {
+			l, err = sl.listenTCP(ctx, la)
+		}
 	case *UnixAddr:
 		l, err = sl.listenUnix(ctx, la)
 	default:
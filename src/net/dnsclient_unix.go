@@ -159,6 +159,9 @@ func dnsStreamRoundTrip(c Conn, id uint16, query dnsmessage.Question, b []byte)
 
 // exchange sends a query on the connection and hopes for a response.
 func (r *Resolver) exchange(ctx context.Context, server string, q dnsmessage.Question, timeout time.Duration, useTCP, ad bool) (dnsmessage.Parser, dnsmessage.Header, error) {
+	if r != nil && r.Transport != nil {
+		return r.exchangeTransport(ctx, q, timeout, ad)
+	}
 	q.Class = dnsmessage.ClassINET
 	id, udpReq, tcpReq, err := newRequest(q, ad)
 	if err != nil {
@@ -203,6 +206,44 @@ func (r *Resolver) exchange(ctx context.Context, server string, q dnsmessage.Que
 	return dnsmessage.Parser{}, dnsmessage.Header{}, errNoAnswerFromDNSServer
 }
 
+// exchangeTransport sends a query with r.Transport.
+func (r *Resolver) exchangeTransport(ctx context.Context, q dnsmessage.Question, timeout time.Duration, ad bool) (dnsmessage.Parser, dnsmessage.Header, error) {
+	q.Class = dnsmessage.ClassINET
+	id, req, _, err := newRequest(q, ad)
+	if err != nil {
+		return dnsmessage.Parser{}, dnsmessage.Header{}, errCannotMarshalDNSMessage
+	}
+	ctx, cancel := context.WithDeadline(ctx, time.Now().Add(timeout))
+	defer cancel()
+	resp, err := r.Transport.Exchange(ctx, req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		return dnsmessage.Parser{}, dnsmessage.Header{}, mapErr(err)
+	}
+	var p dnsmessage.Parser
+	h, err := p.Start(resp)
+	if err != nil {
+		return dnsmessage.Parser{}, dnsmessage.Header{}, errCannotUnmarshalDNSMessage
+	}
+	rq, err := p.Question()
+	if err != nil {
+		return dnsmessage.Parser{}, dnsmessage.Header{}, errCannotUnmarshalDNSMessage
+	}
+	if !checkResponse(id, q, h, rq) {
+		return dnsmessage.Parser{}, dnsmessage.Header{}, errInvalidDNSResponse
+	}
+	if err := p.SkipQuestion(); err != dnsmessage.ErrSectionDone {
+		return dnsmessage.Parser{}, dnsmessage.Header{}, errInvalidDNSResponse
+	}
+	if h.Truncated {
+		// The transport is expected to deliver whole messages.
+		return dnsmessage.Parser{}, dnsmessage.Header{}, errNoAnswerFromDNSServer
+	}
+	return p, h, nil
+}
+
 // checkHeader performs basic sanity checks on the header.
 func checkHeader(p *dnsmessage.Parser, h dnsmessage.Header) error {
 	if h.RCode == dnsmessage.RCodeNameError {
@@ -257,8 +298,13 @@ func skipToAnswer(p *dnsmessage.Parser, qtype dnsmessage.Type) error {
 // (otherwise answer will not find the answers).
 func (r *Resolver) tryOneName(ctx context.Context, cfg *dnsConfig, name string, qtype dnsmessage.Type) (dnsmessage.Parser, string, error) {
 	var lastErr error
+	servers := cfg.servers
+	if r != nil && r.Transport != nil {
+		// The Transport chooses the server.
+		servers = []string{""}
+	}
 	serverOffset := cfg.serverOffset()
-	sLen := uint32(len(cfg.servers))
+	sLen := uint32(len(servers))
 
 	n, err := dnsmessage.NewName(name)
 	if err != nil {
@@ -272,7 +318,7 @@ func (r *Resolver) tryOneName(ctx context.Context, cfg *dnsConfig, name string,
 
 	for i := 0; i < cfg.attempts; i++ {
 		for j := uint32(0); j < sLen; j++ {
-			server := cfg.servers[(serverOffset+j)%sLen]
+			server := servers[(serverOffset+j)%sLen]
 
 			p, h, err := r.exchange(ctx, server, q, cfg.timeout, cfg.useTCP, cfg.trustAD)
 			if err != nil {
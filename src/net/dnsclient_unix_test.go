@@ -2594,3 +2594,162 @@ func TestLookupOrderFilesNoSuchHost(t *testing.T) {
 		}
 	}
 }
+
+// fakeDNSTransport is a DNSTransport that answers queries with rh.
+type fakeDNSTransport struct {
+	rh func(q dnsmessage.Message) (dnsmessage.Message, error)
+}
+
+func (t *fakeDNSTransport) Exchange(_ context.Context, query []byte) ([]byte, error) {
+	var q dnsmessage.Message
+	if err := q.Unpack(query); err != nil {
+		return nil, err
+	}
+	resp, err := t.rh(q)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Pack()
+}
+
+func TestResolverTransport(t *testing.T) {
+	conf, err := newResolvConfTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conf.teardown()
+	if err := conf.writeAndUpdate([]string{"nameserver 192.0.2.1", "search example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var queries atomic.Int32
+	fake := &fakeDNSTransport{rh: func(q dnsmessage.Message) (dnsmessage.Message, error) {
+		queries.Add(1)
+		r := dnsmessage.Message{
+			Header: dnsmessage.Header{
+				ID:                 q.ID,
+				Response:           true,
+				RecursionAvailable: true,
+			},
+			Questions: q.Questions,
+		}
+		qq := q.Questions[0]
+		switch {
+		case qq.Name.String() != "www.example.com.":
+			r.RCode = dnsmessage.RCodeNameError
+		case qq.Type == dnsmessage.TypeA:
+			r.Answers = []dnsmessage.Resource{{
+				Header: dnsmessage.ResourceHeader{Name: qq.Name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET},
+				Body:   &dnsmessage.AResource{A: TestAddr},
+			}}
+		}
+		return r, nil
+	}}
+	r := &Resolver{
+		Transport: fake,
+		Dial: func(ctx context.Context, network, address string) (Conn, error) {
+			t.Errorf("unexpected dial of %s %s", network, address)
+			return nil, errors.New("no dialing")
+		},
+	}
+
+	addrs, err := r.LookupHost(context.Background(), "www")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"192.0.2.1"}; !reflect.DeepEqual(addrs, want) {
+		t.Errorf("LookupHost = %v; want %v", addrs, want)
+	}
+	if queries.Load() == 0 {
+		t.Error("Transport was not used")
+	}
+
+	fake.rh = func(q dnsmessage.Message) (dnsmessage.Message, error) {
+		return dnsmessage.Message{}, errors.New("transport failure")
+	}
+	_, err = r.LookupTXT(context.Background(), "www.example.com")
+	var dnsErr *DNSError
+	if !errors.As(err, &dnsErr) || dnsErr.Err != "transport failure" {
+		t.Errorf("LookupTXT with failing transport: err = %v; want transport failure", err)
+	}
+
+	// A response to a different question is rejected.
+	fake.rh = func(q dnsmessage.Message) (dnsmessage.Message, error) {
+		return dnsmessage.Message{
+			Header:    dnsmessage.Header{ID: q.ID + 1, Response: true},
+			Questions: q.Questions,
+		}, nil
+	}
+	_, err = r.LookupTXT(context.Background(), "www.example.com")
+	if !errors.As(err, &dnsErr) || dnsErr.Err != errInvalidDNSResponse.Error() {
+		t.Errorf("LookupTXT with mismatched response: err = %v; want %v", err, errInvalidDNSResponse)
+	}
+}
+
+func TestLookupRecords(t *testing.T) {
+	conf, err := newResolvConfTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conf.teardown()
+	if err := conf.writeAndUpdate([]string{"nameserver 192.0.2.1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	const typeHTTPS = 65
+	httpsData := []byte{0, 1, 0, 0, 1, 0, 3, 2, 'h', '2'} // 1 . alpn=h2
+	fake := &fakeDNSTransport{rh: func(q dnsmessage.Message) (dnsmessage.Message, error) {
+		r := dnsmessage.Message{
+			Header: dnsmessage.Header{
+				ID:                 q.ID,
+				Response:           true,
+				RecursionAvailable: true,
+			},
+			Questions: q.Questions,
+		}
+		qq := q.Questions[0]
+		switch qq.Type {
+		case typeHTTPS:
+			r.Answers = []dnsmessage.Resource{{
+				Header: dnsmessage.ResourceHeader{Name: qq.Name, Type: dnsmessage.TypeCNAME, Class: dnsmessage.ClassINET, TTL: 60},
+				Body:   &dnsmessage.CNAMEResource{CNAME: mustNewName("svc.example.com.")},
+			}, {
+				Header: dnsmessage.ResourceHeader{Name: mustNewName("svc.example.com."), Class: dnsmessage.ClassINET, TTL: 300},
+				Body:   &dnsmessage.UnknownResource{Type: typeHTTPS, Data: httpsData},
+			}}
+		case dnsmessage.TypeMX:
+			r.Answers = []dnsmessage.Resource{{
+				Header: dnsmessage.ResourceHeader{Name: qq.Name, Type: dnsmessage.TypeMX, Class: dnsmessage.ClassINET, TTL: 60},
+				Body:   &dnsmessage.MXResource{Pref: 10, MX: mustNewName("mail.example.com.")},
+			}}
+		}
+		return r, nil
+	}}
+	r := &Resolver{Transport: fake}
+
+	rrs, err := r.LookupRecords(context.Background(), "www.example.com", typeHTTPS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []DNSRecord{{Name: "svc.example.com.", Type: typeHTTPS, Class: 1, TTL: 300, Data: httpsData}}
+	if !reflect.DeepEqual(rrs, want) {
+		t.Errorf("LookupRecords(HTTPS) = %+v; want %+v", rrs, want)
+	}
+
+	// The name in the MX record, which the response compresses,
+	// is returned uncompressed.
+	rrs, err = r.LookupRecords(context.Background(), "example.com", uint16(dnsmessage.TypeMX))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mxData := []byte("\x00\x0a\x04mail\x07example\x03com\x00")
+	want = []DNSRecord{{Name: "example.com.", Type: uint16(dnsmessage.TypeMX), Class: 1, TTL: 60, Data: mxData}}
+	if !reflect.DeepEqual(rrs, want) {
+		t.Errorf("LookupRecords(MX) = %+v; want %+v", rrs, want)
+	}
+
+	rrs, err = r.LookupRecords(context.Background(), "example.com", 257)
+	if err == nil || len(rrs) != 0 {
+		t.Errorf("LookupRecords(CAA) = %v, %v; want no such host", rrs, err)
+	}
+}
@@ -0,0 +1,241 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package dnstransport implements encrypted transports for the DNS
+// queries of a net.Resolver: DNS over TLS (RFC 7858) and DNS over
+// HTTPS (RFC 8484).
+//
+// For example, to resolve names with a DNS-over-HTTPS server:
+//
+//	r := &net.Resolver{
+//		Transport: &dnstransport.HTTPS{URL: "https://192.0.2.53/dns-query"},
+//	}
+//
+// The address of the server is best given as an IP address, so that
+// finding it does not require a DNS query of its own.
+package dnstransport
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxMessageSize is the largest DNS message size,
+// the limit of the two-byte length prefix of RFC 1035 section 4.2.2.
+const maxMessageSize = 65535
+
+var errMessageTooLarge = errors.New("dnstransport: DNS message too large")
+
+// TLS is a net.DNSTransport that sends queries to a DNS-over-TLS
+// server. Connections to the server are kept open and reused, as
+// RFC 7858 recommends. The zero value is not usable; Addr must be set.
+//
+// A TLS must not be copied after first use.
+type TLS struct {
+	// Addr is the address of the server, such as "192.0.2.53:853".
+	// If it has no port, port 853 is used.
+	Addr string
+
+	// Config is the TLS configuration to use. If nil, the zero
+	// configuration is used. If Config.ServerName is empty, the
+	// host in Addr is used to verify the server's certificate.
+	Config *tls.Config
+
+	// Dialer optionally specifies the dialer for the underlying TCP
+	// connections. If nil, the net.Dialer zero value is used.
+	Dialer *net.Dialer
+
+	// MaxIdleConns is the number of idle connections kept for
+	// reuse. If zero, 2 is used, which allows for the concurrent
+	// A and AAAA queries of an address lookup. If negative,
+	// connections are not reused.
+	MaxIdleConns int
+
+	mu   sync.Mutex
+	idle []net.Conn
+}
+
+// Exchange implements the net.DNSTransport interface.
+func (t *TLS) Exchange(ctx context.Context, query []byte) ([]byte, error) {
+	if len(query) > maxMessageSize {
+		return nil, errMessageTooLarge
+	}
+	for {
+		c, reused, err := t.conn(ctx)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := exchangeStream(ctx, c, query)
+		if err != nil {
+			c.Close()
+			if reused && ctx.Err() == nil {
+				// The server may have closed the idle
+				// connection. Try a new one.
+				continue
+			}
+			return nil, err
+		}
+		t.putIdle(c)
+		return resp, nil
+	}
+}
+
+// conn returns an idle connection, or dials a new one. reused reports
+// whether the connection was idle.
+func (t *TLS) conn(ctx context.Context) (c net.Conn, reused bool, err error) {
+	t.mu.Lock()
+	if n := len(t.idle); n > 0 {
+		c = t.idle[n-1]
+		t.idle = t.idle[:n-1]
+		t.mu.Unlock()
+		return c, true, nil
+	}
+	t.mu.Unlock()
+
+	addr := t.Addr
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "853")
+	}
+	d := &tls.Dialer{NetDialer: t.Dialer, Config: t.Config}
+	c, err = d.DialContext(ctx, "tcp", addr)
+	return c, false, err
+}
+
+func (t *TLS) putIdle(c net.Conn) {
+	max := t.MaxIdleConns
+	if max == 0 {
+		max = 2
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.idle) >= max {
+		c.Close()
+		return
+	}
+	t.idle = append(t.idle, c)
+}
+
+// CloseIdleConnections closes the connections kept for reuse.
+func (t *TLS) CloseIdleConnections() {
+	t.mu.Lock()
+	idle := t.idle
+	t.idle = nil
+	t.mu.Unlock()
+	for _, c := range idle {
+		c.Close()
+	}
+}
+
+// aLongTimeAgo is a non-zero time, far in the past, used for
+// immediate cancellation of I/O.
+var aLongTimeAgo = time.Unix(1, 0)
+
+// exchangeStream sends query on c, with the length prefix of RFC 1035
+// section 4.2.2, and reads the response.
+func exchangeStream(ctx context.Context, c net.Conn, query []byte) ([]byte, error) {
+	deadline, _ := ctx.Deadline()
+	c.SetDeadline(deadline)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.SetDeadline(aLongTimeAgo)
+		case <-done:
+		}
+	}()
+
+	b := make([]byte, 2+len(query))
+	b[0], b[1] = byte(len(query)>>8), byte(len(query))
+	copy(b[2:], query)
+	if _, err := c.Write(b); err != nil {
+		return nil, ctxErr(ctx, err)
+	}
+	if _, err := io.ReadFull(c, b[:2]); err != nil {
+		return nil, ctxErr(ctx, err)
+	}
+	resp := make([]byte, int(b[0])<<8|int(b[1]))
+	if _, err := io.ReadFull(c, resp); err != nil {
+		return nil, ctxErr(ctx, err)
+	}
+	if len(resp) < 2 || len(query) < 2 || resp[0] != query[0] || resp[1] != query[1] {
+		return nil, errors.New("dnstransport: response ID does not match query")
+	}
+	return resp, nil
+}
+
+// ctxErr returns the error of ctx, if it is done, and err otherwise.
+// A timeout at the deadline of ctx, which the connection shares, is
+// reported as context.DeadlineExceeded.
+func ctxErr(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if ne, ok := err.(net.Error); ok && ne.Timeout() {
+		if d, ok := ctx.Deadline(); ok && !time.Now().Before(d) {
+			return context.DeadlineExceeded
+		}
+	}
+	return err
+}
+
+// HTTPS is a net.DNSTransport that sends queries to a DNS-over-HTTPS
+// server, as POST requests. The zero value is not usable; URL must be
+// set.
+type HTTPS struct {
+	// URL is the URL of the server's DNS endpoint, such as
+	// "https://192.0.2.53/dns-query".
+	URL string
+
+	// Client is the HTTP client to use. If nil,
+	// http.DefaultClient is used.
+	Client *http.Client
+}
+
+const dnsMessageType = "application/dns-message"
+
+// Exchange implements the net.DNSTransport interface.
+func (t *HTTPS) Exchange(ctx context.Context, query []byte) ([]byte, error) {
+	if len(query) > maxMessageSize {
+		return nil, errMessageTooLarge
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", t.URL, bytes.NewReader(query))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", dnsMessageType)
+	req.Header.Set("Accept", dnsMessageType)
+	c := t.Client
+	if c == nil {
+		c = http.DefaultClient
+	}
+	res, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("dnstransport: %s: %s", t.URL, res.Status)
+	}
+	if mt, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type")); mt != dnsMessageType {
+		return nil, fmt.Errorf("dnstransport: %s: unexpected Content-Type %q", t.URL, res.Header.Get("Content-Type"))
+	}
+	resp, err := io.ReadAll(io.LimitReader(res.Body, maxMessageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(resp) > maxMessageSize {
+		return nil, errMessageTooLarge
+	}
+	return resp, nil
+}
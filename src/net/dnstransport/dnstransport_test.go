@@ -0,0 +1,185 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dnstransport
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// answer returns the response of a fake DNS server to query: an A
+// record of 192.0.2.1 for any name.
+func answer(t *testing.T, query []byte) []byte {
+	var q dnsmessage.Message
+	if err := q.Unpack(query); err != nil {
+		t.Errorf("bad query: %v", err)
+		return nil
+	}
+	r := dnsmessage.Message{
+		Header: dnsmessage.Header{
+			ID:                 q.ID,
+			Response:           true,
+			RecursionAvailable: true,
+		},
+		Questions: q.Questions,
+	}
+	if qq := q.Questions[0]; qq.Type == dnsmessage.TypeA {
+		r.Answers = []dnsmessage.Resource{{
+			Header: dnsmessage.ResourceHeader{Name: qq.Name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET},
+			Body:   &dnsmessage.AResource{A: [4]byte{192, 0, 2, 1}},
+		}}
+	}
+	b, err := r.Pack()
+	if err != nil {
+		t.Error(err)
+	}
+	return b
+}
+
+// newTLSConfigs returns the configurations of a TLS server and of a
+// client trusting it.
+func newTLSConfigs(t *testing.T) (server, client *tls.Config) {
+	ts := httptest.NewTLSServer(nil)
+	ts.Close()
+	server = ts.TLS.Clone()
+	server.NextProtos = nil
+	client = ts.Client().Transport.(*http.Transport).TLSClientConfig.Clone()
+	client.ServerName = "example.com"
+	return server, client
+}
+
+func lookup(t *testing.T, tr net.DNSTransport) {
+	t.Helper()
+	r := &net.Resolver{Transport: tr}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for i := 0; i < 2; i++ {
+		addrs, err := r.LookupHost(ctx, "www.example.test.")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"192.0.2.1"}; !reflect.DeepEqual(addrs, want) {
+			t.Errorf("LookupHost = %v; want %v", addrs, want)
+		}
+	}
+}
+
+func TestTLS(t *testing.T) {
+	serverConfig, clientConfig := newTLSConfigs(t)
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	var conns atomic.Int32
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conns.Add(1)
+			go func() {
+				defer c.Close()
+				for {
+					var n uint16
+					if err := binary.Read(c, binary.BigEndian, &n); err != nil {
+						return
+					}
+					query := make([]byte, n)
+					if _, err := io.ReadFull(c, query); err != nil {
+						return
+					}
+					resp := answer(t, query)
+					binary.Write(c, binary.BigEndian, uint16(len(resp)))
+					c.Write(resp)
+				}
+			}()
+		}
+	}()
+
+	tr := &TLS{Addr: ln.Addr().String(), Config: clientConfig}
+	defer tr.CloseIdleConnections()
+	lookup(t, tr)
+	// The A and AAAA queries of each lookup may run concurrently,
+	// but connections are reused after that.
+	if n := conns.Load(); n > 2 {
+		t.Errorf("%d connections for 4 queries; want at most 2", n)
+	}
+}
+
+func TestTLSCanceled(t *testing.T) {
+	serverConfig, clientConfig := newTLSConfigs(t)
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			// Never answer.
+			defer c.Close()
+			go io.Copy(io.Discard, c)
+		}
+	}()
+
+	tr := &TLS{Addr: ln.Addr().String(), Config: clientConfig}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := tr.Exchange(ctx, make([]byte, 12)); err != context.DeadlineExceeded {
+		t.Errorf("Exchange with unresponsive server: err = %v; want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestHTTPS(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.Header.Get("Content-Type") != dnsMessageType || r.Header.Get("Accept") != dnsMessageType {
+			t.Errorf("unexpected request: %s %v", r.Method, r.Header)
+		}
+		query, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		w.Header().Set("Content-Type", dnsMessageType)
+		w.Write(answer(t, query))
+	}))
+	defer ts.Close()
+
+	lookup(t, &HTTPS{URL: ts.URL + "/dns-query", Client: ts.Client()})
+}
+
+func TestHTTPSErrors(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/text":
+			w.Write([]byte("not a DNS message"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	for _, path := range []string{"/missing", "/text"} {
+		tr := &HTTPS{URL: ts.URL + path, Client: ts.Client()}
+		if _, err := tr.Exchange(context.Background(), make([]byte, 12)); err == nil {
+			t.Errorf("%s: Exchange succeeded", path)
+		}
+	}
+}
@@ -7,6 +7,7 @@
 package http_test
 
 import (
+	"bufio"
 	"bytes"
 	"compress/gzip"
 	"context"
@@ -1754,3 +1755,96 @@ func testEarlyHintsRequest(t *testing.T, mode testMode) {
 		t.Errorf("Read body %q; want Hello", body)
 	}
 }
+
+func TestExtendedConnect(t *testing.T) { run(t, testExtendedConnect, testNotParallel) }
+func testExtendedConnect(t *testing.T, mode testMode) {
+	defer ExportSetH2ExtendedConnect(true)()
+	cst := newClientServerTest(t, mode, HandlerFunc(func(w ResponseWriter, r *Request) {
+		if r.Method != "CONNECT" || r.URL.Path != "/chat" {
+			t.Errorf("got %v %v; want CONNECT /chat", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get(":protocol"); got != "websocket" {
+			t.Errorf(":protocol = %q; want websocket", got)
+		}
+		w.WriteHeader(200)
+		w.(Flusher).Flush()
+		// Echo the stream back, a line at a time.
+		br := bufio.NewReader(r.Body)
+		for {
+			line, err := br.ReadString('\n')
+			if err != nil {
+				return
+			}
+			io.WriteString(w, strings.ToUpper(line))
+			w.(Flusher).Flush()
+		}
+	}))
+
+	pr, pw := io.Pipe()
+	req, _ := NewRequest("CONNECT", cst.ts.URL+"/chat", pr)
+	req.Header.Set(":protocol", "websocket")
+	res, err := cst.tr.RoundTrip(req)
+	if mode == http1Mode {
+		if err == nil {
+			res.Body.Close()
+			t.Fatal("extended CONNECT over HTTP/1 succeeded; want error")
+		}
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != 200 {
+		t.Fatalf("status = %v; want 200", res.StatusCode)
+	}
+	br := bufio.NewReader(res.Body)
+	for _, msg := range []string{"hello\n", "world\n"} {
+		if _, err := io.WriteString(pw, msg); err != nil {
+			t.Fatal(err)
+		}
+		got, err := br.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := strings.ToUpper(msg); got != want {
+			t.Errorf("read %q; want %q", got, want)
+		}
+	}
+	pw.Close()
+}
+
+// TestExtendedConnectDisabled checks that servers do not accept extended
+// CONNECT requests unless enabled with GODEBUG=http2xconnect=1.
+func TestExtendedConnectDisabled(t *testing.T) {
+	run(t, testExtendedConnectDisabled, []testMode{http2Mode}, testNotParallel)
+}
+func testExtendedConnectDisabled(t *testing.T, mode testMode) {
+	defer ExportSetH2ExtendedConnect(false)()
+	cst := newClientServerTest(t, mode, HandlerFunc(func(w ResponseWriter, r *Request) {
+		t.Errorf("handler called for %v request", r.Method)
+	}))
+
+	req, _ := NewRequest("CONNECT", cst.ts.URL+"/chat", nil)
+	req.Header.Set(":protocol", "websocket")
+	if res, err := cst.tr.RoundTrip(req); err == nil {
+		res.Body.Close()
+		t.Errorf("extended CONNECT to server without support succeeded; want error")
+	}
+}
+
+func TestExtendedConnectInvalid(t *testing.T) {
+	run(t, testExtendedConnectInvalid, []testMode{http2Mode}, testNotParallel)
+}
+func testExtendedConnectInvalid(t *testing.T, mode testMode) {
+	defer ExportSetH2ExtendedConnect(true)()
+	cst := newClientServerTest(t, mode, HandlerFunc(func(w ResponseWriter, r *Request) {}))
+
+	// A :protocol header is only sent with the CONNECT method.
+	req, _ := NewRequest("GET", cst.ts.URL, nil)
+	req.Header.Set(":protocol", "websocket")
+	if res, err := cst.tr.RoundTrip(req); err == nil {
+		res.Body.Close()
+		t.Errorf("GET with :protocol header succeeded; want error")
+	}
+}
@@ -0,0 +1,362 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"compress/zstd"
+	"io"
+	"net/http/internal/ascii"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// compressMinLength is the length below which response bodies
+// are not worth compressing.
+const compressMinLength = 256
+
+// CompressHandler returns a Handler that runs h and compresses its
+// responses with a content coding the client accepts, as listed in the
+// request's Accept-Encoding header. The supported codings are "gzip",
+// "deflate" and "zstd", preferred in that order when the client has
+// no preference.
+//
+// A response is sent uncompressed if the client accepts none of the
+// codings, if h sets a Content-Encoding of its own, if its status code
+// does not allow a body or is 206 Partial Content, if its body is
+// shorter than a few hundred bytes, or if its Content-Type is one that
+// is normally already compressed, such as most image, audio and video
+// formats and archives. When h sets no Content-Type, it is determined
+// from the uncompressed body with DetectContentType, as the Server
+// would. Responses to range requests served by ServeContent are
+// therefore never compressed.
+//
+// Compressed responses have their Content-Length and Accept-Ranges
+// headers removed, and a strong ETag is made weak, since it identifies
+// the uncompressed representation. All responses get a
+// "Vary: Accept-Encoding" header. Responses to HEAD requests get the
+// same header as responses to GET requests would: as they have no body,
+// the Content-Length set by h, if any, stands in for its length.
+//
+// The ResponseWriter passed to h implements Flusher; flushing it
+// flushes the compressor too. If h flushes the response before writing
+// to it, whether to compress is decided from the header alone. Other
+// optional interfaces of the underlying ResponseWriter, such as
+// Hijacker, are available through a ResponseController.
+//
+// If the end of a compressed body cannot be written, the response is
+// aborted as if h had panicked with ErrAbortHandler, so that the client
+// does not mistake the truncated body for a complete one.
+func CompressHandler(h Handler) Handler {
+	return &compressHandler{h}
+}
+
+type compressHandler struct {
+	handler Handler
+}
+
+func (ch *compressHandler) ServeHTTP(w ResponseWriter, r *Request) {
+	cw := &compressWriter{
+		rw:   w,
+		enc:  negotiateEncoding(r.Header["Accept-Encoding"]),
+		head: r.Method == "HEAD",
+	}
+	ch.handler.ServeHTTP(cw, r)
+	if err := cw.finish(); err != nil {
+		panic(ErrAbortHandler)
+	}
+}
+
+// A compressor is a compressing writer that can be reused.
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(io.Writer)
+}
+
+var compressorPools = map[string]*sync.Pool{
+	"gzip": {New: func() any { return gzip.NewWriter(nil) }},
+	"deflate": {New: func() any {
+		// The "deflate" content coding is the zlib format.
+		return zlib.NewWriter(nil)
+	}},
+	"zstd": {New: func() any { return zstd.NewWriter(nil) }},
+}
+
+// negotiateEncoding returns the content coding to use for a response
+// to a request with the given Accept-Encoding header values, or "" if
+// the response should not be compressed.
+func negotiateEncoding(accept []string) string {
+	var q [3]float64 // for gzip, deflate, zstd
+	var set [3]bool
+	wildcard := -1.0
+	for _, v := range accept {
+		for _, elem := range strings.Split(v, ",") {
+			coding, params, _ := strings.Cut(elem, ";")
+			coding = textproto.TrimString(coding)
+			weight := 1.0
+			for params != "" {
+				var p string
+				p, params, _ = strings.Cut(params, ";")
+				name, value, _ := strings.Cut(p, "=")
+				if ascii.EqualFold(textproto.TrimString(name), "q") {
+					f, err := strconv.ParseFloat(textproto.TrimString(value), 64)
+					if err != nil || f < 0 || f > 1 {
+						f = 0
+					}
+					weight = f
+				}
+			}
+			i := -1
+			switch {
+			case ascii.EqualFold(coding, "gzip"), ascii.EqualFold(coding, "x-gzip"):
+				i = 0
+			case ascii.EqualFold(coding, "deflate"):
+				i = 1
+			case ascii.EqualFold(coding, "zstd"):
+				i = 2
+			case coding == "*":
+				wildcard = weight
+			}
+			if i >= 0 && (!set[i] || weight > q[i]) {
+				q[i], set[i] = weight, true
+			}
+		}
+	}
+	best, bestQ := "", 0.0
+	for i, name := range [...]string{"gzip", "deflate", "zstd"} {
+		w := q[i]
+		if !set[i] && wildcard >= 0 {
+			w = wildcard
+		}
+		if w > bestQ {
+			best, bestQ = name, w
+		}
+	}
+	return best
+}
+
+// isPrecompressedType reports whether responses with the given
+// Content-Type are normally already compressed.
+func isPrecompressedType(ct string) bool {
+	mt, _, _ := strings.Cut(ct, ";")
+	mt, _ = ascii.ToLower(textproto.TrimString(mt))
+	switch mt {
+	case "image/svg+xml", "image/bmp", "image/x-icon", "image/vnd.microsoft.icon":
+		return false
+	case "application/zip", "application/gzip", "application/x-gzip",
+		"application/zstd", "application/x-bzip2", "application/x-xz",
+		"application/x-7z-compressed", "application/x-rar-compressed",
+		"application/pdf", "application/ogg", "font/woff", "font/woff2":
+		return true
+	}
+	return strings.HasPrefix(mt, "image/") ||
+		strings.HasPrefix(mt, "audio/") ||
+		strings.HasPrefix(mt, "video/")
+}
+
+// compressWriter is the ResponseWriter passed to the handler by
+// CompressHandler. It holds back the start of the body until it can
+// decide whether to compress the response.
+type compressWriter struct {
+	rw   ResponseWriter
+	enc  string // content coding to use, or "" for none
+	head bool   // response to a HEAD request, which has no body
+
+	status  int  // status code set by the handler, or 0
+	decided bool // status line and headers have been written
+	buf     []byte
+	zw      compressor // non-nil when compressing
+	discard bool       // compressed response to a HEAD request
+}
+
+func (cw *compressWriter) Header() Header { return cw.rw.Header() }
+
+// Unwrap returns the underlying ResponseWriter,
+// for use by ResponseController.
+func (cw *compressWriter) Unwrap() ResponseWriter { return cw.rw }
+
+func (cw *compressWriter) WriteHeader(code int) {
+	if code >= 100 && code <= 199 && code != StatusSwitchingProtocols {
+		// Informational responses are passed through.
+		cw.rw.WriteHeader(code)
+		return
+	}
+	if cw.status != 0 {
+		// Let the underlying ResponseWriter report the superfluous call.
+		if cw.decided {
+			cw.rw.WriteHeader(code)
+		}
+		return
+	}
+	checkWriteHeaderCode(code)
+	cw.status = code
+	if !bodyAllowedForStatus(code) || code == StatusPartialContent {
+		cw.enc = ""
+	}
+	if cw.enc == "" {
+		cw.decide(false)
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if cw.status == 0 {
+		cw.WriteHeader(StatusOK)
+	}
+	if cw.decided {
+		if cw.discard {
+			return len(p), nil
+		}
+		if cw.zw != nil {
+			return cw.zw.Write(p)
+		}
+		return cw.rw.Write(p)
+	}
+	cw.buf = append(cw.buf, p...)
+	if len(cw.buf) >= sniffLen {
+		if err := cw.decide(false); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush writes any data held back or buffered by the compressor
+// and flushes the underlying ResponseWriter, if it supports flushing.
+func (cw *compressWriter) Flush() {
+	if cw.status == 0 {
+		cw.WriteHeader(StatusOK)
+	}
+	if !cw.decided {
+		cw.decide(false)
+	}
+	if cw.zw != nil {
+		cw.zw.Flush()
+	}
+	NewResponseController(cw.rw).Flush()
+}
+
+// decide chooses whether to compress the response and writes its
+// header and any body data held back. final reports whether the
+// handler has returned, so that buf is the whole body.
+func (cw *compressWriter) decide(final bool) error {
+	cw.decided = true
+	h := cw.rw.Header()
+	addVary(h)
+
+	compress := cw.enc != ""
+	if compress {
+		if ce := h.Get("Content-Encoding"); ce != "" && !ascii.EqualFold(ce, "identity") {
+			compress = false
+		}
+		if cl := h.Get("Content-Length"); cl != "" {
+			if n, err := strconv.ParseInt(cl, 10, 64); err == nil && n < compressMinLength {
+				compress = false
+			}
+		}
+		if final && len(cw.buf) < compressMinLength && !cw.head {
+			compress = false
+		}
+	}
+	if compress {
+		ct, haveType := h["Content-Type"]
+		switch {
+		case haveType && len(ct) > 0:
+			compress = !isPrecompressedType(ct[0])
+		case haveType:
+			// Sniffing was suppressed; the type is unknown.
+		case len(cw.buf) > 0:
+			// Set the type now, as the Server would sniff
+			// the compressed data otherwise.
+			t := DetectContentType(cw.buf)
+			if isPrecompressedType(t) {
+				compress = false
+			} else {
+				h.Set("Content-Type", t)
+			}
+		default:
+			// The header is flushed before the body (a final
+			// body this short is not compressed), so the Server
+			// has nothing to sniff either.
+		}
+	}
+
+	if compress {
+		h.Del("Content-Length")
+		h.Del("Accept-Ranges")
+		h.Set("Content-Encoding", cw.enc)
+		if etag := h.Get("Etag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			h.Set("Etag", "W/"+etag)
+		}
+		if cw.head {
+			// There is no body to compress. Anything h writes
+			// is dropped, or the Server would count it for the
+			// Content-Length of the uncompressed response.
+			cw.discard = true
+		} else {
+			cw.zw = compressorPools[cw.enc].Get().(compressor)
+			cw.zw.Reset(cw.rw)
+		}
+	}
+	cw.rw.WriteHeader(cw.status)
+
+	if len(cw.buf) == 0 || cw.discard {
+		cw.buf = nil
+		return nil
+	}
+	var err error
+	if cw.zw != nil {
+		_, err = cw.zw.Write(cw.buf)
+	} else {
+		_, err = cw.rw.Write(cw.buf)
+	}
+	cw.buf = nil
+	return err
+}
+
+// finish completes the response after the handler has returned.
+// It returns an error if the rest of a compressed body could not
+// be written.
+func (cw *compressWriter) finish() error {
+	if cw.status == 0 {
+		// Nothing was written. Leave the response to the Server,
+		// as the handler may have hijacked the connection.
+		addVary(cw.rw.Header())
+		return nil
+	}
+	var err error
+	if !cw.decided {
+		err = cw.decide(true)
+	}
+	if cw.zw == nil {
+		// Errors writing an uncompressed body are the
+		// Server's to handle.
+		return nil
+	}
+	if cerr := cw.zw.Close(); err == nil {
+		err = cerr
+	}
+	cw.zw.Reset(nil)
+	compressorPools[cw.enc].Put(cw.zw)
+	cw.zw = nil
+	return err
+}
+
+// addVary adds Accept-Encoding to the Vary header of h,
+// unless it is already listed.
+func addVary(h Header) {
+	for _, v := range h["Vary"] {
+		for _, f := range strings.Split(v, ",") {
+			f = textproto.TrimString(f)
+			if f == "*" || ascii.EqualFold(f, "Accept-Encoding") {
+				return
+			}
+		}
+	}
+	h.Add("Vary", "Accept-Encoding")
+}
@@ -0,0 +1,393 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"compress/zstd"
+	"fmt"
+	"io"
+	. "net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", "gzip"},
+		{"GZIP", "gzip"},
+		{"x-gzip", "gzip"},
+		{"deflate", "deflate"},
+		{"zstd", "zstd"},
+		{"gzip, deflate, br, zstd", "gzip"},
+		{"deflate, zstd", "deflate"},
+		{"gzip;q=0.5, zstd", "zstd"},
+		{"gzip, deflate", "gzip"},
+		{"gzip;q=0.5, deflate", "deflate"},
+		{"gzip ; q=0.5 , deflate;q=0.6", "deflate"},
+		{"gzip;q=0", ""},
+		{"gzip;q=bogus", ""},
+		{"br", ""},
+		{"*", "gzip"},
+		{"*;q=0.1, zstd;q=0, gzip;q=0", "deflate"},
+		{"zstd;q=0.1, gzip;q=0.1, deflate;q=0.2", "deflate"},
+	}
+	for _, tt := range tests {
+		var accept []string
+		if tt.accept != "" {
+			accept = []string{tt.accept}
+		}
+		if got := ExportNegotiateEncoding(accept); got != tt.want {
+			t.Errorf("negotiateEncoding(%q) = %q; want %q", tt.accept, got, tt.want)
+		}
+	}
+}
+
+// compressTestBody is long enough, and compressible enough,
+// to be compressed by CompressHandler.
+var compressTestBody = strings.Repeat("<p>The quick brown fox jumps over the lazy dog.</p>\n", 100)
+
+func decodeBody(t *testing.T, enc string, body []byte) []byte {
+	t.Helper()
+	var r io.Reader
+	var err error
+	switch enc {
+	case "":
+		return body
+	case "gzip":
+		r, err = gzip.NewReader(bytes.NewReader(body))
+	case "deflate":
+		r, err = zlib.NewReader(bytes.NewReader(body))
+	case "zstd":
+		r = zstd.NewReader(bytes.NewReader(body))
+	default:
+		t.Fatalf("unexpected Content-Encoding %q", enc)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("decoding %s body: %v", enc, err)
+	}
+	return b
+}
+
+// getCompressed fetches url with the given Accept-Encoding and returns
+// the response and its decoded body.
+func getCompressed(t *testing.T, c *Client, url, accept string, hdr ...string) (*Response, string) {
+	t.Helper()
+	req, _ := NewRequest("GET", url, nil)
+	if accept != "" {
+		req.Header.Set("Accept-Encoding", accept)
+	}
+	for i := 0; i+1 < len(hdr); i += 2 {
+		req.Header.Set(hdr[i], hdr[i+1])
+	}
+	res, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accept == "" {
+		return res, string(body)
+	}
+	return res, string(decodeBody(t, res.Header.Get("Content-Encoding"), body))
+}
+
+func TestCompressHandler(t *testing.T) { run(t, testCompressHandler) }
+func testCompressHandler(t *testing.T, mode testMode) {
+	cst := newClientServerTest(t, mode, CompressHandler(HandlerFunc(func(w ResponseWriter, r *Request) {
+		w.Header().Set("Content-Length", fmt.Sprint(len(compressTestBody)))
+		io.WriteString(w, compressTestBody)
+	})))
+
+	for _, enc := range []string{"gzip", "deflate", "zstd"} {
+		res, body := getCompressed(t, cst.c, cst.ts.URL, enc)
+		if got := res.Header.Get("Content-Encoding"); got != enc {
+			t.Errorf("Accept-Encoding %s: Content-Encoding = %q", enc, got)
+		}
+		if body != compressTestBody {
+			t.Errorf("Accept-Encoding %s: wrong body", enc)
+		}
+		if res.ContentLength == int64(len(compressTestBody)) {
+			t.Errorf("Accept-Encoding %s: handler's Content-Length was kept", enc)
+		}
+		if got := res.Header.Get("Vary"); got != "Accept-Encoding" {
+			t.Errorf("Accept-Encoding %s: Vary = %q", enc, got)
+		}
+		if got := res.Header.Get("Content-Type"); !strings.HasPrefix(got, "text/html") {
+			t.Errorf("Accept-Encoding %s: Content-Type = %q; want sniffed text/html", enc, got)
+		}
+	}
+
+	// Without Accept-Encoding, the response is not compressed,
+	// but it still varies by Accept-Encoding.
+	tr := cst.tr
+	tr.DisableCompression = true
+	res, body := getCompressed(t, cst.c, cst.ts.URL, "")
+	if got := res.Header.Get("Content-Encoding"); got != "" {
+		t.Errorf("no Accept-Encoding: Content-Encoding = %q", got)
+	}
+	if body != compressTestBody || res.ContentLength != int64(len(compressTestBody)) {
+		t.Errorf("no Accept-Encoding: got %d-byte body, ContentLength %d", len(body), res.ContentLength)
+	}
+	if got := res.Header.Get("Vary"); got != "Accept-Encoding" {
+		t.Errorf("no Accept-Encoding: Vary = %q", got)
+	}
+
+	// The Transport asks for, and transparently decodes, gzip.
+	tr.DisableCompression = false
+	res, body = getCompressed(t, cst.c, cst.ts.URL, "")
+	if !res.Uncompressed || body != compressTestBody {
+		t.Errorf("Transport compression: Uncompressed = %v, body matches = %v", res.Uncompressed, body == compressTestBody)
+	}
+}
+
+func TestCompressHandlerSkips(t *testing.T) { run(t, testCompressHandlerSkips) }
+func testCompressHandlerSkips(t *testing.T, mode testMode) {
+	png := append([]byte("\x89PNG\x0D\x0A\x1A\x0A"), make([]byte, 1000)...)
+	cst := newClientServerTest(t, mode, CompressHandler(HandlerFunc(func(w ResponseWriter, r *Request) {
+		switch r.URL.Path {
+		case "/short":
+			io.WriteString(w, "short")
+		case "/png":
+			w.Write(png)
+		case "/jpeg":
+			w.Header().Set("Content-Type", "image/jpeg")
+			io.WriteString(w, compressTestBody)
+		case "/encoded":
+			w.Header().Set("Content-Encoding", "br")
+			io.WriteString(w, compressTestBody)
+		case "/notmodified":
+			w.WriteHeader(StatusNotModified)
+		case "/vary":
+			w.Header().Set("Vary", "Origin, accept-encoding")
+			io.WriteString(w, compressTestBody)
+		}
+	})))
+
+	for _, path := range []string{"/short", "/png", "/jpeg", "/encoded", "/notmodified"} {
+		req, _ := NewRequest("GET", cst.ts.URL+path, nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		res, err := cst.c.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		want := ""
+		if path == "/encoded" {
+			want = "br"
+		}
+		if got := res.Header.Get("Content-Encoding"); got != want {
+			t.Errorf("%s: Content-Encoding = %q; want %q", path, got, want)
+		}
+		if got := res.Header.Get("Vary"); got != "Accept-Encoding" {
+			t.Errorf("%s: Vary = %q; want Accept-Encoding", path, got)
+		}
+	}
+
+	res, _ := getCompressed(t, cst.c, cst.ts.URL+"/png", "gzip")
+	if got := res.Header.Get("Content-Type"); got != "image/png" {
+		t.Errorf("/png: Content-Type = %q; want image/png", got)
+	}
+
+	res, _ = getCompressed(t, cst.c, cst.ts.URL+"/vary", "gzip")
+	if got := res.Header.Values("Vary"); len(got) != 1 {
+		t.Errorf("/vary: Vary = %q; want the handler's value only", got)
+	}
+}
+
+func TestCompressHandlerServeContent(t *testing.T) { run(t, testCompressHandlerServeContent) }
+func testCompressHandlerServeContent(t *testing.T, mode testMode) {
+	modtime := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	cst := newClientServerTest(t, mode, CompressHandler(HandlerFunc(func(w ResponseWriter, r *Request) {
+		w.Header().Set("Etag", `"abc"`)
+		ServeContent(w, r, "page.html", modtime, strings.NewReader(compressTestBody))
+	})))
+
+	// A full response is compressed, without Accept-Ranges,
+	// and with a weak ETag.
+	res, body := getCompressed(t, cst.c, cst.ts.URL, "gzip")
+	if res.StatusCode != StatusOK || res.Header.Get("Content-Encoding") != "gzip" || body != compressTestBody {
+		t.Fatalf("full response: status %d, Content-Encoding %q", res.StatusCode, res.Header.Get("Content-Encoding"))
+	}
+	if got := res.Header.Get("Accept-Ranges"); got != "" {
+		t.Errorf("full response: Accept-Ranges = %q; want none", got)
+	}
+	if got := res.Header.Get("Etag"); got != `W/"abc"` {
+		t.Errorf("full response: ETag = %q; want W/\"abc\"", got)
+	}
+
+	// A range request gets the requested bytes of the identity
+	// representation, uncompressed.
+	res, body = getCompressed(t, cst.c, cst.ts.URL, "gzip", "Range", "bytes=10-19")
+	if res.StatusCode != StatusPartialContent {
+		t.Fatalf("range response: status %d; want 206", res.StatusCode)
+	}
+	if got := res.Header.Get("Content-Encoding"); got != "" {
+		t.Errorf("range response: Content-Encoding = %q; want none", got)
+	}
+	if want := compressTestBody[10:20]; body != want {
+		t.Errorf("range response: body = %q; want %q", body, want)
+	}
+
+	// The weak ETag still validates a cached compressed response.
+	res, _ = getCompressed(t, cst.c, cst.ts.URL, "gzip", "If-None-Match", `W/"abc"`)
+	if res.StatusCode != StatusNotModified {
+		t.Errorf("conditional request: status %d; want 304", res.StatusCode)
+	}
+}
+
+func TestCompressHandlerFlush(t *testing.T) { run(t, testCompressHandlerFlush) }
+func testCompressHandlerFlush(t *testing.T, mode testMode) {
+	proceed := make(chan bool)
+	cst := newClientServerTest(t, mode, CompressHandler(HandlerFunc(func(w ResponseWriter, r *Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, "data: first\n\n")
+		w.(Flusher).Flush()
+		<-proceed
+		io.WriteString(w, "data: second\n\n")
+	})))
+
+	req, _ := NewRequest("GET", cst.ts.URL, nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	res, err := cst.c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if got := res.Header.Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q; want gzip", got)
+	}
+	zr, err := gzip.NewReader(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The first event must be readable before the handler proceeds.
+	first := make([]byte, len("data: first\n\n"))
+	if _, err := io.ReadFull(zr, first); err != nil {
+		t.Fatal(err)
+	}
+	close(proceed)
+	rest, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(first) + string(rest); got != "data: first\n\ndata: second\n\n" {
+		t.Errorf("body = %q", got)
+	}
+}
+
+func TestCompressHandlerHead(t *testing.T) { run(t, testCompressHandlerHead) }
+func testCompressHandlerHead(t *testing.T, mode testMode) {
+	cst := newClientServerTest(t, mode, CompressHandler(HandlerFunc(func(w ResponseWriter, r *Request) {
+		w.Header().Set("Etag", `"abc"`)
+		io.WriteString(w, compressTestBody)
+	})))
+
+	get, _ := getCompressed(t, cst.c, cst.ts.URL, "gzip")
+	req, _ := NewRequest("HEAD", cst.ts.URL, nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	head, err := cst.c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head.Body.Close()
+	for _, k := range []string{"Content-Encoding", "Content-Type", "Etag", "Vary"} {
+		if g, w := head.Header.Get(k), get.Header.Get(k); g != w {
+			t.Errorf("HEAD response: %s = %q; want %q, as for GET", k, g, w)
+		}
+	}
+	if got := head.Header.Get("Content-Encoding"); got != "gzip" {
+		t.Errorf("HEAD response: Content-Encoding = %q; want gzip", got)
+	}
+}
+
+func TestCompressHandlerHeadServeContent(t *testing.T) {
+	run(t, testCompressHandlerHeadServeContent)
+}
+func testCompressHandlerHeadServeContent(t *testing.T, mode testMode) {
+	modtime := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	cst := newClientServerTest(t, mode, CompressHandler(HandlerFunc(func(w ResponseWriter, r *Request) {
+		ServeContent(w, r, "page.html", modtime, strings.NewReader(compressTestBody))
+	})))
+
+	// ServeContent sets a Content-Length and, for HEAD,
+	// writes no body. The Content-Length of the compressed
+	// GET response is known to the Server only.
+	get, _ := getCompressed(t, cst.c, cst.ts.URL, "gzip")
+	req, _ := NewRequest("HEAD", cst.ts.URL, nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	head, err := cst.c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head.Body.Close()
+	for _, k := range []string{"Content-Encoding", "Accept-Ranges", "Content-Type", "Vary"} {
+		if g, w := head.Header.Get(k), get.Header.Get(k); g != w {
+			t.Errorf("HEAD response: %s = %q; want %q, as for GET", k, g, w)
+		}
+	}
+	if got := head.Header.Get("Content-Encoding"); got != "gzip" {
+		t.Errorf("HEAD response: Content-Encoding = %q; want gzip", got)
+	}
+	if got := head.Header.Get("Content-Length"); got != "" {
+		t.Errorf("HEAD response: Content-Length = %q; want none", got)
+	}
+}
+
+func TestCompressHandlerFlushFirst(t *testing.T) { run(t, testCompressHandlerFlushFirst) }
+func testCompressHandlerFlushFirst(t *testing.T, mode testMode) {
+	cst := newClientServerTest(t, mode, CompressHandler(HandlerFunc(func(w ResponseWriter, r *Request) {
+		w.(Flusher).Flush()
+		io.WriteString(w, compressTestBody)
+	})))
+
+	res, body := getCompressed(t, cst.c, cst.ts.URL, "gzip")
+	if got := res.Header.Get("Content-Encoding"); got != "gzip" {
+		t.Errorf("Content-Encoding = %q; want gzip", got)
+	}
+	if body != compressTestBody {
+		t.Errorf("wrong body")
+	}
+}
+
+// failingResponseWriter is a ResponseWriter that fails to write a body.
+type failingResponseWriter struct {
+	header Header
+}
+
+func (w *failingResponseWriter) Header() Header  { return w.header }
+func (w *failingResponseWriter) WriteHeader(int) {}
+func (w *failingResponseWriter) Write(p []byte) (int, error) {
+	return 0, io.ErrClosedPipe
+}
+
+func TestCompressHandlerWriteError(t *testing.T) {
+	h := CompressHandler(HandlerFunc(func(w ResponseWriter, r *Request) {
+		io.WriteString(w, compressTestBody)
+	}))
+	req, _ := NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	defer func() {
+		if e := recover(); e != ErrAbortHandler {
+			t.Errorf("panic value = %v; want ErrAbortHandler", e)
+		}
+	}()
+	h.ServeHTTP(&failingResponseWriter{header: Header{}}, req)
+}
@@ -85,10 +85,11 @@ Server.TLSNextProto (for servers) to a non-nil, empty
 map. Alternatively, the following GODEBUG environment variables are
 currently supported:
 
-	GODEBUG=http2client=0  # disable HTTP/2 client support
-	GODEBUG=http2server=0  # disable HTTP/2 server support
-	GODEBUG=http2debug=1   # enable verbose HTTP/2 debug logs
-	GODEBUG=http2debug=2   # ... even more verbose, with frame dumps
+	GODEBUG=http2client=0    # disable HTTP/2 client support
+	GODEBUG=http2server=0    # disable HTTP/2 server support
+	GODEBUG=http2debug=1     # enable verbose HTTP/2 debug logs
+	GODEBUG=http2debug=2     # ... even more verbose, with frame dumps
+	GODEBUG=http2xconnect=1  # accept extended CONNECT requests (RFC 8441)
 
 The GODEBUG variables are not covered by Go's API compatibility
 promise. Please report any issues before disabling HTTP/2
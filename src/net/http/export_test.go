@@ -34,6 +34,7 @@ var (
 	Export_shouldCopyHeaderOnRedirect = shouldCopyHeaderOnRedirect
 	Export_writeStatusLine            = writeStatusLine
 	Export_is408Message               = is408Message
+	ExportNegotiateEncoding           = negotiateEncoding
 )
 
 const MaxWriteWaitBeforeConnReuse = maxWriteWaitBeforeConnReuse
@@ -288,6 +289,12 @@ func ExportSetH2GoawayTimeout(d time.Duration) (restore func()) {
 	return func() { http2goAwayTimeout = old }
 }
 
+func ExportSetH2ExtendedConnect(enable bool) (restore func()) {
+	old := http2enableExtendedConnect
+	http2enableExtendedConnect = enable
+	return func() { http2enableExtendedConnect = old }
+}
+
 func (r *Request) ExportIsReplayable() bool { return r.isReplayable() }
 
 // ExportCloseTransportConnsAbruptly closes all idle connections from
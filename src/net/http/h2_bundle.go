@@ -2811,7 +2811,7 @@ func (mh *http2MetaHeadersFrame) checkPseudos() error {
 	pf := mh.PseudoFields()
 	for i, hf := range pf {
 		switch hf.Name {
-		case ":method", ":path", ":scheme", ":authority":
+		case ":method", ":path", ":scheme", ":authority", ":protocol":
 			isRequest = true
 		case ":status":
 			isResponse = true
@@ -2819,7 +2819,7 @@ func (mh *http2MetaHeadersFrame) checkPseudos() error {
 			return http2pseudoHeaderError(hf.Name)
 		}
 		// Check for duplicates.
-		// This would be a bad algorithm, but N is 4.
+		// This would be a bad algorithm, but N is 5.
 		// And this doesn't allocate.
 		for _, hf2 := range pf[:i] {
 			if hf.Name == hf2.Name {
@@ -3268,6 +3268,10 @@ var (
 	http2logFrameWrites bool
 	http2logFrameReads  bool
 	http2inTests        bool
+
+	// enableExtendedConnect is whether servers accept extended CONNECT
+	// requests (RFC 8441), as set by GODEBUG=http2xconnect=1.
+	http2enableExtendedConnect bool
 )
 
 func init() {
@@ -3280,6 +3284,20 @@ func init() {
 		http2logFrameWrites = true
 		http2logFrameReads = true
 	}
+	http2enableExtendedConnect = http2godebug(e, "http2xconnect") == "1"
+}
+
+// godebug returns the value of the setting key in the GODEBUG
+// environment variable value e, or "" if it is not set.
+// As in the runtime, the last setting of key wins.
+func http2godebug(e, key string) string {
+	value := ""
+	for _, kv := range strings.Split(e, ",") {
+		if i := strings.IndexByte(kv, '='); i >= 0 && kv[:i] == key {
+			value = kv[i+1:]
+		}
+	}
+	return value
 }
 
 const (
@@ -3359,7 +3377,7 @@ func (s http2Setting) String() string {
 func (s http2Setting) Valid() error {
 	// Limits and error codes from 6.5.2 Defined SETTINGS Parameters
 	switch s.ID {
-	case http2SettingEnablePush:
+	case http2SettingEnablePush, http2SettingEnableConnectProtocol:
 		if s.Val != 1 && s.Val != 0 {
 			return http2ConnectionError(http2ErrCodeProtocol)
 		}
@@ -3386,15 +3404,19 @@ const (
 	http2SettingInitialWindowSize    http2SettingID = 0x4
 	http2SettingMaxFrameSize         http2SettingID = 0x5
 	http2SettingMaxHeaderListSize    http2SettingID = 0x6
+
+	// https://www.rfc-editor.org/rfc/rfc8441#section-3
+	http2SettingEnableConnectProtocol http2SettingID = 0x8
 )
 
 var http2settingName = map[http2SettingID]string{
-	http2SettingHeaderTableSize:      "HEADER_TABLE_SIZE",
-	http2SettingEnablePush:           "ENABLE_PUSH",
-	http2SettingMaxConcurrentStreams: "MAX_CONCURRENT_STREAMS",
-	http2SettingInitialWindowSize:    "INITIAL_WINDOW_SIZE",
-	http2SettingMaxFrameSize:         "MAX_FRAME_SIZE",
-	http2SettingMaxHeaderListSize:    "MAX_HEADER_LIST_SIZE",
+	http2SettingHeaderTableSize:       "HEADER_TABLE_SIZE",
+	http2SettingEnablePush:            "ENABLE_PUSH",
+	http2SettingMaxConcurrentStreams:  "MAX_CONCURRENT_STREAMS",
+	http2SettingInitialWindowSize:     "INITIAL_WINDOW_SIZE",
+	http2SettingMaxFrameSize:          "MAX_FRAME_SIZE",
+	http2SettingMaxHeaderListSize:     "MAX_HEADER_LIST_SIZE",
+	http2SettingEnableConnectProtocol: "ENABLE_CONNECT_PROTOCOL",
 }
 
 func (s http2SettingID) String() string {
@@ -4601,14 +4623,16 @@ func (sc *http2serverConn) serve() {
 		sc.vlogf("http2: server connection from %v on %p", sc.conn.RemoteAddr(), sc.hs)
 	}
 
-	sc.writeFrame(http2FrameWriteRequest{
-		write: http2writeSettings{
-			{http2SettingMaxFrameSize, sc.srv.maxReadFrameSize()},
-			{http2SettingMaxConcurrentStreams, sc.advMaxStreams},
-			{http2SettingMaxHeaderListSize, sc.maxHeaderListSize()},
-			{http2SettingInitialWindowSize, uint32(sc.srv.initialStreamRecvWindowSize())},
-		},
-	})
+	settings := http2writeSettings{
+		{http2SettingMaxFrameSize, sc.srv.maxReadFrameSize()},
+		{http2SettingMaxConcurrentStreams, sc.advMaxStreams},
+		{http2SettingMaxHeaderListSize, sc.maxHeaderListSize()},
+		{http2SettingInitialWindowSize, uint32(sc.srv.initialStreamRecvWindowSize())},
+	}
+	if http2enableExtendedConnect {
+		settings = append(settings, http2Setting{http2SettingEnableConnectProtocol, 1})
+	}
+	sc.writeFrame(http2FrameWriteRequest{write: settings})
 	sc.unackedSettings++
 
 	// Each connection starts with initialWindowSize inflow tokens.
@@ -5847,13 +5871,19 @@ func (sc *http2serverConn) newWriterAndRequest(st *http2stream, f *http2MetaHead
 		scheme:    f.PseudoValue("scheme"),
 		authority: f.PseudoValue("authority"),
 		path:      f.PseudoValue("path"),
+		protocol:  f.PseudoValue("protocol"),
 	}
 
-	isConnect := rp.method == "CONNECT"
+	// An extended CONNECT request (RFC 8441) carries a :protocol
+	// pseudo-header and the :scheme and :path of a regular request.
+	// It is only allowed if the server advertised support for it.
+	isConnect := rp.method == "CONNECT" && rp.protocol == ""
 	if isConnect {
 		if rp.path != "" || rp.scheme != "" || rp.authority == "" {
 			return nil, nil, sc.countError("bad_connect", http2streamError(f.StreamID, http2ErrCodeProtocol))
 		}
+	} else if rp.protocol != "" && (!http2enableExtendedConnect || rp.method != "CONNECT" || rp.authority == "") {
+		return nil, nil, sc.countError("bad_extended_connect", http2streamError(f.StreamID, http2ErrCodeProtocol))
 	} else if rp.method == "" || rp.path == "" || (rp.scheme != "https" && rp.scheme != "http") {
 		// See 8.1.2.6 Malformed Requests and Responses:
 		//
@@ -5875,6 +5905,9 @@ func (sc *http2serverConn) newWriterAndRequest(st *http2stream, f *http2MetaHead
 	if rp.authority == "" {
 		rp.authority = rp.header.Get("Host")
 	}
+	if rp.protocol != "" {
+		rp.header[":protocol"] = []string{rp.protocol}
+	}
 
 	rw, req, err := sc.newWriterAndRequestNoBody(st, rp)
 	if err != nil {
@@ -5901,6 +5934,7 @@ func (sc *http2serverConn) newWriterAndRequest(st *http2stream, f *http2MetaHead
 type http2requestParam struct {
 	method                  string
 	scheme, authority, path string
+	protocol                string
 	header                  Header
 }
 
@@ -5942,7 +5976,7 @@ func (sc *http2serverConn) newWriterAndRequestNoBody(st *http2stream, rp http2re
 
 	var url_ *url.URL
 	var requestURI string
-	if rp.method == "CONNECT" {
+	if rp.method == "CONNECT" && rp.protocol == "" {
 		url_ = &url.URL{Host: rp.authority}
 		requestURI = rp.authority // mimic HTTP/1 server behavior
 	} else {
@@ -6484,6 +6518,11 @@ func (w *http2responseWriter) SetWriteDeadline(deadline time.Time) error {
 	return nil
 }
 
+func (w *http2responseWriter) EnableFullDuplex() error {
+	// We always support full duplex responses, so this is a no-op.
+	return nil
+}
+
 func (w *http2responseWriter) Flush() {
 	w.FlushError()
 }
@@ -7208,6 +7247,7 @@ type http2ClientConn struct {
 	closing         bool
 	closed          bool
 	seenSettings    bool                          // true if we've seen a settings frame, false otherwise
+	seenSettingsChn chan struct{}                 // closed when seenSettings is set
 	wantSettingsAck bool                          // we sent a SETTINGS frame and haven't heard back
 	goAway          *http2GoAwayFrame             // if non-nil, the GoAwayFrame we received
 	goAwayDebug     string                        // goAway frame's debug data, retained as a string
@@ -7224,6 +7264,7 @@ type http2ClientConn struct {
 	maxConcurrentStreams  uint32
 	peerMaxHeaderListSize uint64
 	initialWindowSize     uint32
+	extendedConnect       bool // peer sent SETTINGS_ENABLE_CONNECT_PROTOCOL=1
 
 	// reqHeaderMu is a 1-element semaphore channel controlling access to sending new requests.
 	// Write to reqHeaderMu to lock it, read from it to unlock.
@@ -7628,6 +7669,7 @@ func (t *http2Transport) newClientConn(c net.Conn, singleUse bool) (*http2Client
 		wantSettingsAck:       true,
 		pings:                 make(map[[8]byte]chan struct{}),
 		reqHeaderMu:           make(chan struct{}, 1),
+		seenSettingsChn:       make(chan struct{}),
 	}
 	if d := t.idleConnTimeout(); d != 0 {
 		cc.idleTimeout = d
@@ -8041,6 +8083,42 @@ func (cc *http2ClientConn) responseHeaderTimeout() time.Duration {
 	return 0
 }
 
+// extendedConnectProtocol returns the protocol of an extended CONNECT
+// request (RFC 8441), given by its ":protocol" header, or "".
+func http2extendedConnectProtocol(req *Request) string {
+	if req.Method != "CONNECT" {
+		return ""
+	}
+	if vv := req.Header[":protocol"]; len(vv) > 0 {
+		return vv[0]
+	}
+	return ""
+}
+
+// awaitExtendedConnect waits for the server's initial SETTINGS frame
+// and reports whether the server supports extended CONNECT.
+func (cc *http2ClientConn) awaitExtendedConnect(cs *http2clientStream) error {
+	if cc.seenSettingsChn != nil {
+		select {
+		case <-cc.seenSettingsChn:
+		case <-cc.readerDone:
+			return cc.readerErr
+		case <-cs.reqCancel:
+			return http2errRequestCanceled
+		case <-cs.ctx.Done():
+			return cs.ctx.Err()
+		}
+	}
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if !cc.extendedConnect {
+		return http2errExtendedConnectNotSupported
+	}
+	return nil
+}
+
+var http2errExtendedConnectNotSupported = errors.New("http2: server does not support extended CONNECT")
+
 // checkConnHeaders checks whether req has any invalid connection-level headers.
 // per RFC 7540 section 8.1.2.2: Connection-Specific Header Fields.
 // Certain headers are special-cased as okay but not transmitted later.
@@ -8187,6 +8265,12 @@ func (cs *http2clientStream) writeRequest(req *Request) (err error) {
 		return err
 	}
 
+	if http2extendedConnectProtocol(req) != "" {
+		if err := cc.awaitExtendedConnect(cs); err != nil {
+			return err
+		}
+	}
+
 	// Acquire the new-request lock by writing to reqHeaderMu.
 	// This lock guards the critical section covering allocating a new stream ID
 	// (requires mu) and creating the stream (requires wmu).
@@ -8717,8 +8801,11 @@ func (cc *http2ClientConn) encodeHeaders(req *Request, addGzipHeader bool, trail
 		return nil, err
 	}
 
+	protocol := http2extendedConnectProtocol(req)
+	isConnect := req.Method == "CONNECT" && protocol == ""
+
 	var path string
-	if req.Method != "CONNECT" {
+	if !isConnect {
 		path = req.URL.RequestURI()
 		if !http2validPseudoPath(path) {
 			orig := path
@@ -8737,7 +8824,9 @@ func (cc *http2ClientConn) encodeHeaders(req *Request, addGzipHeader bool, trail
 	// potentially pollute our hpack state. (We want to be able to
 	// continue to reuse the hpack encoder for future requests)
 	for k, vv := range req.Header {
-		if !httpguts.ValidHeaderFieldName(k) {
+		if k == ":protocol" && protocol != "" {
+			// Sent as a pseudo-header; its value is checked below.
+		} else if !httpguts.ValidHeaderFieldName(k) {
 			return nil, fmt.Errorf("invalid HTTP header name %q", k)
 		}
 		for _, v := range vv {
@@ -8760,17 +8849,23 @@ func (cc *http2ClientConn) encodeHeaders(req *Request, addGzipHeader bool, trail
 			m = MethodGet
 		}
 		f(":method", m)
-		if req.Method != "CONNECT" {
+		if !isConnect {
 			f(":path", path)
 			f(":scheme", req.URL.Scheme)
 		}
+		if protocol != "" {
+			f(":protocol", protocol)
+		}
 		if trailers != "" {
 			f("trailer", trailers)
 		}
 
 		var didUA bool
 		for k, vv := range req.Header {
-			if http2asciiEqualFold(k, "host") || http2asciiEqualFold(k, "content-length") {
+			if k == ":protocol" {
+				// Sent above as a pseudo-header.
+				continue
+			} else if http2asciiEqualFold(k, "host") || http2asciiEqualFold(k, "content-length") {
 				// Host is :authority, already sent.
 				// Content-Length is automatic, set below.
 				continue
@@ -9682,6 +9777,16 @@ func (rl *http2clientConnReadLoop) processSettingsNoWrite(f *http2SettingsFrame)
 			seenMaxConcurrentStreams = true
 		case http2SettingMaxHeaderListSize:
 			cc.peerMaxHeaderListSize = uint64(s.Val)
+		case http2SettingEnableConnectProtocol:
+			if err := s.Valid(); err != nil {
+				return err
+			}
+			// RFC 8441, Section 3: the setting may not be
+			// withdrawn once enabled.
+			if cc.extendedConnect && s.Val == 0 {
+				return http2ConnectionError(http2ErrCodeProtocol)
+			}
+			cc.extendedConnect = s.Val == 1
 		case http2SettingInitialWindowSize:
 			// Values above the maximum flow-control
 			// window size of 2^31-1 MUST be treated as a
@@ -9720,6 +9825,7 @@ func (rl *http2clientConnReadLoop) processSettingsNoWrite(f *http2SettingsFrame)
 			cc.maxConcurrentStreams = http2defaultMaxConcurrentStreams
 		}
 		cc.seenSettings = true
+		close(cc.seenSettingsChn)
 	}
 
 	return nil
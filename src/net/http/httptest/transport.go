@@ -0,0 +1,357 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Implementation of Transport
+
+package httptest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"internal/txtar"
+	"io"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// A Transport is an http.RoundTripper for tests that answers requests
+// with responses recorded in a fixture file, so that a client can be
+// tested without a server. A Transport created by NewRecordingTransport
+// instead sends requests to a Server and records the exchanges, to
+// create or update the fixture file.
+//
+// A recorded exchange answers a request with the same method, URL and
+// body. When several recorded exchanges match a request, they are used
+// in the order they were recorded, and the last of them is reused once
+// all have been used. Request headers are not compared.
+//
+// The fixture file is a txtar archive (see golang.org/x/tools/txtar)
+// with a pair of files for each exchange, named "N/request" and
+// "N/response". A request file holds the request method and URL on
+// its first line, followed by a header and the request body; a
+// response file holds an HTTP/1.1 status line, header and body:
+//
+//	-- 1/request --
+//	POST http://example.com/echo
+//	Content-Length: 6
+//
+//	hello
+//	-- 1/response --
+//	HTTP/1.1 200 OK
+//	Content-Length: 6
+//	Content-Type: text/plain; charset=utf-8
+//
+//	hello
+//
+// The Content-Length, if present, gives the exact length of the body,
+// which lets a body end without a newline. A body that is not text, or
+// that contains a line beginning with "-- ", is stored in base64 and
+// marked by a "Httptest-Body-Encoding: base64" header.
+type Transport struct {
+	file string
+	srv  *Server // non-nil when recording
+
+	mu        sync.Mutex
+	exchanges []*exchange
+}
+
+// An exchange is a recorded request and its response.
+type exchange struct {
+	method string
+	url    string
+	body   []byte
+
+	status     string // e.g. "200 OK"
+	statusCode int
+	header     http.Header
+	respBody   []byte
+
+	used bool
+}
+
+// NewReplayTransport returns a Transport that answers requests with the
+// exchanges recorded in the named file.
+func NewReplayTransport(file string) (*Transport, error) {
+	a, err := txtar.ParseFile(file)
+	if err != nil {
+		return nil, err
+	}
+	t := &Transport{file: file}
+	for i := 0; i < len(a.Files); i += 2 {
+		name, _, _ := strings.Cut(a.Files[i].Name, "/")
+		if i+1 == len(a.Files) ||
+			a.Files[i].Name != name+"/request" ||
+			a.Files[i+1].Name != name+"/response" {
+			return nil, fmt.Errorf("httptest: %s: %s is not followed by its response", file, a.Files[i].Name)
+		}
+		x, err := parseExchange(a.Files[i].Data, a.Files[i+1].Data)
+		if err != nil {
+			return nil, fmt.Errorf("httptest: %s: exchange %s: %v", file, name, err)
+		}
+		t.exchanges = append(t.exchanges, x)
+	}
+	return t, nil
+}
+
+// NewRecordingTransport returns a Transport that sends each request to
+// srv, whatever the host in its URL, and records the exchanges. Close
+// writes them to the named file, replacing any previous recording.
+//
+// The request URLs are recorded unchanged, so a test can use a fixed
+// host such as example.com, and replay the recording later with
+// NewReplayTransport without running srv.
+func NewRecordingTransport(file string, srv *Server) *Transport {
+	return &Transport{file: file, srv: srv}
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	method := req.Method
+	if method == "" {
+		method = "GET"
+	}
+	if t.srv != nil {
+		return t.record(req, method, body)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var match *exchange
+	for _, x := range t.exchanges {
+		if x.method != method || x.url != req.URL.String() || !bytes.Equal(x.body, body) {
+			continue
+		}
+		match = x
+		if !x.used {
+			break
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("httptest: no exchange in %s for %s %s", t.file, method, req.URL)
+	}
+	match.used = true
+	return match.response(req), nil
+}
+
+// record sends req to t.srv and records the exchange.
+func (t *Transport) record(req *http.Request, method string, body []byte) (*http.Response, error) {
+	srvURL, err := url.Parse(t.srv.URL)
+	if err != nil {
+		return nil, err
+	}
+	out := req.Clone(req.Context())
+	out.URL.Scheme = srvURL.Scheme
+	out.URL.Host = srvURL.Host
+	if out.Host == "" {
+		out.Host = req.URL.Host
+	}
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	out.ContentLength = int64(len(body))
+	if len(body) == 0 {
+		out.Body = nil
+	}
+	res, err := t.srv.Client().Transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	x := &exchange{
+		method:     method,
+		url:        req.URL.String(),
+		body:       body,
+		status:     res.Status,
+		statusCode: res.StatusCode,
+		header:     res.Header.Clone(),
+		respBody:   respBody,
+	}
+	t.mu.Lock()
+	t.exchanges = append(t.exchanges, x)
+	t.mu.Unlock()
+	return x.response(req), nil
+}
+
+// Close writes the recorded exchanges to the fixture file, if t is
+// recording. It does nothing for a Transport created by
+// NewReplayTransport.
+func (t *Transport) Close() error {
+	if t.srv == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	a := &txtar.Archive{
+		Comment: []byte("HTTP exchanges recorded by httptest.NewRecordingTransport.\n"),
+	}
+	for i, x := range t.exchanges {
+		req, resp := x.format()
+		a.Files = append(a.Files,
+			txtar.File{Name: fmt.Sprintf("%d/request", i+1), Data: req},
+			txtar.File{Name: fmt.Sprintf("%d/response", i+1), Data: resp},
+		)
+	}
+	return os.WriteFile(t.file, txtar.Format(a), 0666)
+}
+
+// response returns a new Response to req for the exchange.
+func (x *exchange) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        x.status,
+		StatusCode:    x.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        x.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(x.respBody)),
+		ContentLength: int64(len(x.respBody)),
+		Request:       req,
+	}
+}
+
+const bodyEncodingHeader = "Httptest-Body-Encoding"
+
+// format returns the request and response files for the exchange.
+func (x *exchange) format() (req, resp []byte) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s %s\n", x.method, x.url)
+	writeBody(&buf, nil, x.body)
+	req = bytes.Clone(buf.Bytes())
+
+	buf.Reset()
+	fmt.Fprintf(&buf, "HTTP/1.1 %s\n", x.status)
+	h := x.header.Clone()
+	h.Del("Transfer-Encoding")
+	writeBody(&buf, h, x.respBody)
+	return req, buf.Bytes()
+}
+
+// writeBody writes h, with the Content-Length and encoding of body,
+// followed by a blank line and body.
+func writeBody(buf *bytes.Buffer, h http.Header, body []byte) {
+	if h == nil {
+		h = make(http.Header)
+	}
+	h.Del(bodyEncodingHeader)
+	h.Del("Content-Length")
+	if len(body) > 0 {
+		if !isText(body) {
+			body = []byte(base64.StdEncoding.EncodeToString(body))
+			h.Set(bodyEncodingHeader, "base64")
+		}
+		h.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+	// Header.Write sorts the keys but ends lines with CRLF,
+	// which does not belong in a text file.
+	var hbuf bytes.Buffer
+	h.Write(&hbuf)
+	buf.Write(bytes.ReplaceAll(hbuf.Bytes(), []byte("\r\n"), []byte("\n")))
+	buf.WriteString("\n")
+	buf.Write(body)
+}
+
+// isText reports whether body can be stored as is in a txtar file.
+func isText(body []byte) bool {
+	if !utf8.Valid(body) || bytes.IndexByte(body, '\r') >= 0 {
+		return false
+	}
+	return !bytes.HasPrefix(body, []byte("-- ")) && !bytes.Contains(body, []byte("\n-- "))
+}
+
+// parseExchange parses the request and response files of an exchange.
+func parseExchange(req, resp []byte) (*exchange, error) {
+	line, _, body, err := parseFile(req)
+	if err != nil {
+		return nil, fmt.Errorf("request: %v", err)
+	}
+	method, u, ok := strings.Cut(line, " ")
+	if !ok || method == "" || u == "" {
+		return nil, fmt.Errorf("malformed request line %q", line)
+	}
+	x := &exchange{method: method, url: u, body: body}
+
+	line, h, body, err := parseFile(resp)
+	if err != nil {
+		return nil, fmt.Errorf("response: %v", err)
+	}
+	proto, status, ok := strings.Cut(line, " ")
+	if !ok || proto != "HTTP/1.1" {
+		return nil, fmt.Errorf("malformed status line %q", line)
+	}
+	code, _, _ := strings.Cut(status, " ")
+	if len(code) != 3 {
+		return nil, fmt.Errorf("malformed status line %q", line)
+	}
+	x.statusCode, err = strconv.Atoi(code)
+	if err != nil || x.statusCode < 100 {
+		return nil, fmt.Errorf("malformed status line %q", line)
+	}
+	x.status = status
+	x.header = h
+	x.respBody = body
+	return x, nil
+}
+
+// parseFile parses a request or response file, returning its first
+// line, its header and its decoded body.
+func parseFile(data []byte) (line string, h http.Header, body []byte, err error) {
+	r := textproto.NewReader(bufio.NewReader(bytes.NewReader(data)))
+	line, err = r.ReadLine()
+	if err != nil {
+		return "", nil, nil, errors.New("missing first line")
+	}
+	mh, err := r.ReadMIMEHeader()
+	if err != nil && err != io.EOF {
+		return "", nil, nil, err
+	}
+	h = http.Header(mh)
+	if h == nil {
+		h = make(http.Header)
+	}
+	body, err = io.ReadAll(r.R)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if cl := h.Get("Content-Length"); cl != "" {
+		n, err := strconv.Atoi(cl)
+		if err != nil || n < 0 || n > len(body) {
+			return "", nil, nil, fmt.Errorf("bad Content-Length %q", cl)
+		}
+		body = body[:n]
+	} else {
+		body = nil
+	}
+	if enc := h.Get(bodyEncodingHeader); enc != "" {
+		if enc != "base64" {
+			return "", nil, nil, fmt.Errorf("unknown %s %q", bodyEncodingHeader, enc)
+		}
+		body, err = base64.StdEncoding.DecodeString(string(body))
+		if err != nil {
+			return "", nil, nil, err
+		}
+		h.Del(bodyEncodingHeader)
+		h.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+	return line, h, body, nil
+}
@@ -0,0 +1,159 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httptest
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTransportReplay(t *testing.T) {
+	const fixture = `Two exchanges.
+-- 1/request --
+GET http://example.com/hello
+-- 1/response --
+HTTP/1.1 200 OK
+Content-Type: text/plain
+Content-Length: 5
+
+hello
+-- 2/request --
+POST http://example.com/echo
+Content-Length: 4
+
+ping
+-- 2/response --
+HTTP/1.1 201 Created
+Content-Length: 4
+
+ping
+`
+	file := filepath.Join(t.TempDir(), "fixture.txt")
+	if err := os.WriteFile(file, []byte(fixture), 0666); err != nil {
+		t.Fatal(err)
+	}
+	tr, err := NewReplayTransport(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &http.Client{Transport: tr}
+
+	for i := 0; i < 2; i++ {
+		res, err := c.Get("http://example.com/hello")
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+		if res.StatusCode != 200 || string(body) != "hello" || res.Header.Get("Content-Type") != "text/plain" {
+			t.Errorf("GET: status %d, body %q, header %v", res.StatusCode, body, res.Header)
+		}
+	}
+
+	res, err := c.Post("http://example.com/echo", "text/plain", strings.NewReader("ping"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	if res.StatusCode != 201 || res.Status != "201 Created" || string(body) != "ping" {
+		t.Errorf("POST: status %q, body %q", res.Status, body)
+	}
+
+	for _, req := range []struct{ method, url, body string }{
+		{"POST", "http://example.com/echo", "pong"},
+		{"PUT", "http://example.com/echo", "ping"},
+		{"GET", "http://example.com/hello?x=1", ""},
+	} {
+		r, _ := http.NewRequest(req.method, req.url, strings.NewReader(req.body))
+		if _, err := c.Do(r); err == nil || !strings.Contains(err.Error(), "no exchange") {
+			t.Errorf("%s %s %q: err = %v; want no exchange", req.method, req.url, req.body, err)
+		}
+	}
+}
+
+func TestTransportRecord(t *testing.T) {
+	binary := []byte("\x00\x01\xff-- x --\n")
+	calls := 0
+	ts := NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch r.URL.Path {
+		case "/count":
+			io.WriteString(w, strings.Repeat("n", calls))
+		case "/echo":
+			w.Header().Set("X-Host", r.Host)
+			io.Copy(w, r.Body)
+		case "/binary":
+			w.Write(binary)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	type exchange struct {
+		method, url, body string
+		status            int
+		want              string
+	}
+	exchanges := []exchange{
+		{"GET", "http://example.com/count", "", 200, "n"},
+		{"GET", "http://example.com/count", "", 200, "nn"},
+		{"POST", "http://example.com/echo", "no newline", 200, "no newline"},
+		{"GET", "http://example.com/binary", "", 200, string(binary)},
+		{"GET", "http://example.com/missing", "", 404, "404 page not found\n"},
+	}
+	do := func(c *http.Client, x exchange) {
+		t.Helper()
+		r, _ := http.NewRequest(x.method, x.url, strings.NewReader(x.body))
+		res, err := c.Do(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+		if res.StatusCode != x.status || string(body) != x.want {
+			t.Errorf("%s %s: status %d, body %q; want %d, %q", x.method, x.url, res.StatusCode, body, x.status, x.want)
+		}
+	}
+
+	file := filepath.Join(t.TempDir(), "fixture.txt")
+	rec := NewRecordingTransport(file, ts)
+	for _, x := range exchanges {
+		do(&http.Client{Transport: rec}, x)
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+	ts.Close()
+
+	tr, err := NewReplayTransport(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, x := range exchanges {
+		do(&http.Client{Transport: tr}, x)
+	}
+	// The last recorded response is reused.
+	do(&http.Client{Transport: tr}, exchanges[1])
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"-- 3/request --\nPOST http://example.com/echo\n",
+		"X-Host: example.com\n",
+		"Httptest-Body-Encoding: base64\n",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("fixture file does not contain %q:\n%s", want, data)
+		}
+	}
+}
@@ -157,6 +157,15 @@ type Request struct {
 	// For incoming requests, the Host header is promoted to the
 	// Request.Host field and removed from the Header map.
 	//
+	// For extended CONNECT requests (RFC 8441), such as WebSockets
+	// over HTTP/2, the ":protocol" key holds the value of the
+	// :protocol pseudo-header. A client sends an extended CONNECT
+	// request by setting it on a request with Method "CONNECT";
+	// the Transport sends such requests only over HTTP/2, to servers
+	// that advertise support for them. The Server only advertises
+	// support for extended CONNECT, and accepts such requests, when
+	// run with GODEBUG=http2xconnect=1.
+	//
 	// HTTP defines that header names are case-insensitive. The
 	// request parser implements this by using CanonicalHeaderKey,
 	// making the first character and any characters following a
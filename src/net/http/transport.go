@@ -167,6 +167,23 @@ type Transport struct {
 	// If both are set, DialTLSContext takes priority.
 	DialTLS func(network, addr string) (net.Conn, error)
 
+	// DialServiceContext optionally specifies the dial function for
+	// creating unencrypted TCP connections for non-proxied HTTPS
+	// requests, which also returns the service binding of the
+	// connection's endpoint, from the server's DNS HTTPS records, if
+	// any. The DialService method of a net.Dialer is such a function.
+	// The TLS handshake then offers only the application protocols
+	// the binding lists, if it lists any of those the Transport would
+	// otherwise offer.
+	//
+	// The binding's Encrypted Client Hello configuration is not
+	// used, as package crypto/tls does not support ECH.
+	//
+	// If DialServiceContext is set, it is used for HTTPS requests in
+	// place of DialContext and Dial. It is not used if DialTLSContext
+	// or DialTLS is set.
+	DialServiceContext func(ctx context.Context, network, addr string) (net.Conn, *net.SVCB, error)
+
 	// TLSClientConfig specifies the TLS configuration to use with
 	// tls.Client.
 	// If nil, the default configuration is used.
@@ -319,6 +336,7 @@ func (t *Transport) Clone() *Transport {
 		Dial:                   t.Dial,
 		DialTLS:                t.DialTLS,
 		DialTLSContext:         t.DialTLSContext,
+		DialServiceContext:     t.DialServiceContext,
 		TLSHandshakeTimeout:    t.TLSHandshakeTimeout,
 		DisableKeepAlives:      t.DisableKeepAlives,
 		DisableCompression:     t.DisableCompression,
@@ -526,7 +544,10 @@ func (t *Transport) roundTrip(req *Request) (*Response, error) {
 	isHTTP := scheme == "http" || scheme == "https"
 	if isHTTP {
 		for k, vv := range req.Header {
-			if !httpguts.ValidHeaderFieldName(k) {
+			if k == ":protocol" && req.Method == "CONNECT" {
+				// The protocol of an extended CONNECT request,
+				// sent by the HTTP/2 transport.
+			} else if !httpguts.ValidHeaderFieldName(k) {
 				req.closeBody()
 				return nil, fmt.Errorf("net/http: invalid header field name %q", k)
 			}
@@ -599,6 +620,11 @@ func (t *Transport) roundTrip(req *Request) (*Response, error) {
 			// HTTP/2 path.
 			t.setReqCanceler(cancelKey, nil) // not cancelable with CancelRequest
 			resp, err = pconn.alt.RoundTrip(req)
+		} else if _, ok := req.Header[":protocol"]; ok && req.Method == "CONNECT" {
+			t.setReqCanceler(cancelKey, nil)
+			t.putOrCloseIdleConn(pconn)
+			req.closeBody()
+			return nil, errExtendedConnectHTTP1
 		} else {
 			resp, err = pconn.roundTrip(treq)
 		}
@@ -633,6 +659,8 @@ func (t *Transport) roundTrip(req *Request) (*Response, error) {
 	}
 }
 
+var errExtendedConnectHTTP1 = errors.New("net/http: extended CONNECT requires HTTP/2")
+
 var errCannotRewind = errors.New("net/http: cannot rewind body after connection loss")
 
 type readTrackingBody struct {
@@ -1519,7 +1547,9 @@ func (t *Transport) decConnsPerHost(key connectMethodKey) {
 // Add TLS to a persistent connection, i.e. negotiate a TLS session. If pconn is already a TLS
 // tunnel, this function establishes a nested TLS session inside the encrypted channel.
 // The remote endpoint's name may be overridden by TLSClientConfig.ServerName.
-func (pconn *persistConn) addTLS(ctx context.Context, name string, trace *httptrace.ClientTrace) error {
+// If svcb is not nil, it is the service binding of the remote endpoint,
+// and only the application protocols it supports are offered.
+func (pconn *persistConn) addTLS(ctx context.Context, name string, svcb *net.SVCB, trace *httptrace.ClientTrace) error {
 	// Initiate TLS and check remote host name against certificate.
 	cfg := cloneTLSConfig(pconn.t.TLSClientConfig)
 	if cfg.ServerName == "" {
@@ -1528,6 +1558,9 @@ func (pconn *persistConn) addTLS(ctx context.Context, name string, trace *httptr
 	if pconn.cacheKey.onlyH1 {
 		cfg.NextProtos = nil
 	}
+	if svcb != nil && len(cfg.NextProtos) > 0 {
+		cfg.NextProtos = serviceProtos(cfg.NextProtos, svcb.Protocols())
+	}
 	plainConn := pconn.conn
 	tlsConn := tls.Client(plainConn, cfg)
 	errc := make(chan error, 2)
@@ -1563,6 +1596,24 @@ func (pconn *persistConn) addTLS(ctx context.Context, name string, trace *httptr
 	return nil
 }
 
+// serviceProtos returns the protocols of protos that are listed in
+// supported, or protos if there are none.
+func serviceProtos(protos, supported []string) []string {
+	var both []string
+	for _, p := range protos {
+		for _, sp := range supported {
+			if p == sp {
+				both = append(both, p)
+				break
+			}
+		}
+	}
+	if len(both) == 0 {
+		return protos
+	}
+	return both
+}
+
 type erringRoundTripper interface {
 	RoundTripErr() error
 }
@@ -1611,7 +1662,16 @@ func (t *Transport) dialConn(ctx context.Context, cm connectMethod) (pconn *pers
 			pconn.tlsState = &cs
 		}
 	} else {
-		conn, err := t.dial(ctx, "tcp", cm.addr())
+		var (
+			conn net.Conn
+			svcb *net.SVCB
+			err  error
+		)
+		if cm.scheme() == "https" && cm.proxyURL == nil && t.DialServiceContext != nil {
+			conn, svcb, err = t.DialServiceContext(ctx, "tcp", cm.addr())
+		} else {
+			conn, err = t.dial(ctx, "tcp", cm.addr())
+		}
 		if err != nil {
 			return nil, wrapErr(err)
 		}
@@ -1621,7 +1681,7 @@ func (t *Transport) dialConn(ctx context.Context, cm connectMethod) (pconn *pers
 			if firstTLSHost, _, err = net.SplitHostPort(cm.addr()); err != nil {
 				return nil, wrapErr(err)
 			}
-			if err = pconn.addTLS(ctx, firstTLSHost, trace); err != nil {
+			if err = pconn.addTLS(ctx, firstTLSHost, svcb, trace); err != nil {
 				return nil, wrapErr(err)
 			}
 		}
@@ -1743,7 +1803,7 @@ func (t *Transport) dialConn(ctx context.Context, cm connectMethod) (pconn *pers
 	}
 
 	if cm.proxyURL != nil && cm.targetScheme == "https" {
-		if err := pconn.addTLS(ctx, cm.tlsHost(), trace); err != nil {
+		if err := pconn.addTLS(ctx, cm.tlsHost(), nil, trace); err != nil {
 			return nil, err
 		}
 	}
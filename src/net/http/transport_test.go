@@ -13,6 +13,7 @@ import (
 	"bufio"
 	"bytes"
 	"compress/gzip"
+	"compress/zstd"
 	"context"
 	"crypto/rand"
 	"crypto/tls"
@@ -1756,6 +1757,62 @@ func testTransportGzipShort(t *testing.T, mode testMode) {
 	}
 }
 
+// TestTransportZstd checks that the Transport does not ask for
+// Zstandard-encoded responses on its own, and leaves them alone when
+// the user asked for them.
+func TestTransportZstd(t *testing.T) { run(t, testTransportZstd) }
+func testTransportZstd(t *testing.T, mode testMode) {
+	const body = "hello, zstd, hello, zstd, hello, zstd"
+	ts := newClientServerTest(t, mode, HandlerFunc(func(w ResponseWriter, r *Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "zstd") {
+			io.WriteString(w, body)
+			return
+		}
+		w.Header().Set("Content-Encoding", "zstd")
+		zw := zstd.NewWriter(w)
+		io.WriteString(zw, body)
+		zw.Close()
+	})).ts
+	c := ts.Client()
+
+	res, err := c.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != body {
+		t.Errorf("body = %q; want %q", got, body)
+	}
+	if g := res.Header.Get("Content-Encoding"); g != "" {
+		t.Errorf("Content-Encoding = %q; want none", g)
+	}
+
+	req, _ := NewRequest("GET", ts.URL, nil)
+	req.Header.Set("Accept-Encoding", "zstd")
+	res, err = c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if g := res.Header.Get("Content-Encoding"); g != "zstd" {
+		t.Errorf("with explicit Accept-Encoding, Content-Encoding = %q; want zstd", g)
+	}
+	if res.Uncompressed {
+		t.Error("with explicit Accept-Encoding, Uncompressed = true; want false")
+	}
+	got, err = io.ReadAll(zstd.NewReader(res.Body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != body {
+		t.Errorf("with explicit Accept-Encoding, body = %q; want %q", got, body)
+	}
+}
+
 // Wait until number of goroutines is no greater than nmax, or time out.
 func waitNumGoroutine(nmax int) int {
 	nfinal := runtime.NumGoroutine()
@@ -5986,6 +6043,7 @@ func TestTransportClone(t *testing.T) {
 		Dial:                   func(network, addr string) (net.Conn, error) { panic("") },
 		DialTLS:                func(network, addr string) (net.Conn, error) { panic("") },
 		DialTLSContext:         func(ctx context.Context, network, addr string) (net.Conn, error) { panic("") },
+		DialServiceContext:     func(ctx context.Context, network, addr string) (net.Conn, *net.SVCB, error) { panic("") },
 		TLSClientConfig:        new(tls.Config),
 		TLSHandshakeTimeout:    time.Second,
 		DisableKeepAlives:      true,
@@ -6646,3 +6704,33 @@ func testHandlerAbortRacesBodyRead(t *testing.T, mode testMode) {
 	}
 	wg.Wait()
 }
+
+func TestTransportDialServiceContext(t *testing.T) {
+	run(t, testTransportDialServiceContext, []testMode{http2Mode})
+}
+func testTransportDialServiceContext(t *testing.T, mode testMode) {
+	cst := newClientServerTest(t, mode, HandlerFunc(func(w ResponseWriter, r *Request) {}))
+	for _, tt := range []struct {
+		svcb      *net.SVCB
+		wantProto int
+	}{
+		{nil, 2},
+		{&net.SVCB{Priority: 1, Target: "example.com."}, 1}, // only the default http/1.1
+		{&net.SVCB{Priority: 1, Target: "example.com.", ALPN: []string{"h2"}}, 2},
+		{&net.SVCB{Priority: 1, Target: "example.com.", ALPN: []string{"h3"}, NoDefaultALPN: true}, 2},
+	} {
+		cst.tr.CloseIdleConnections()
+		cst.tr.DialServiceContext = func(ctx context.Context, network, addr string) (net.Conn, *net.SVCB, error) {
+			c, err := net.Dial(network, cst.ts.Listener.Addr().String())
+			return c, tt.svcb, err
+		}
+		res, err := cst.c.Get(cst.ts.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if res.ProtoMajor != tt.wantProto {
+			t.Errorf("with binding %+v: got HTTP/%d; want HTTP/%d", tt.svcb, res.ProtoMajor, tt.wantProto)
+		}
+	}
+}
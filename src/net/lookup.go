@@ -7,6 +7,7 @@ package net
 import (
 	"context"
 	"errors"
+	"internal/bytealg"
 	"internal/nettrace"
 	"internal/singleflight"
 	"net/netip"
@@ -149,6 +150,15 @@ type Resolver struct {
 	// If nil, the default dialer is used.
 	Dial func(ctx context.Context, network, address string) (Conn, error)
 
+	// Transport optionally specifies how Go's built-in DNS resolver
+	// sends its queries. If non-nil, queries are sent with
+	// Transport.Exchange rather than over UDP or TCP to the name
+	// servers of the system configuration, such as /etc/resolv.conf,
+	// and Dial is not used. The system configuration still supplies
+	// the search list, the timeout and the number of attempts.
+	// Setting Transport implies PreferGo.
+	Transport DNSTransport
+
 	// lookupGroup merges LookupIPAddr calls together for lookups for the same
 	// host. The lookupGroup key is the LookupIPAddr.host argument.
 	// The return values are ([]IPAddr, error).
@@ -158,7 +168,18 @@ type Resolver struct {
 	// TODO(bradfitz): Timeout time.Duration?
 }
 
-func (r *Resolver) preferGo() bool     { return r != nil && r.PreferGo }
+// A DNSTransport sends the DNS queries of a Resolver, for example over
+// an encrypted connection to a particular server. Package
+// net/dnstransport implements DNS over TLS and DNS over HTTPS.
+type DNSTransport interface {
+	// Exchange sends query, a DNS message in the wire format of
+	// RFC 1035, and returns the response message. The context
+	// carries the deadline of the query. Exchange may be called
+	// concurrently from multiple goroutines.
+	Exchange(ctx context.Context, query []byte) ([]byte, error)
+}
+
+func (r *Resolver) preferGo() bool     { return r != nil && (r.PreferGo || r.Transport != nil) }
 func (r *Resolver) strictErrors() bool { return r != nil && r.StrictErrors }
 
 func (r *Resolver) getLookupGroup() *singleflight.Group {
@@ -630,6 +651,30 @@ func (r *Resolver) LookupTXT(ctx context.Context, name string) ([]string, error)
 	return r.lookupTXT(ctx, name)
 }
 
+// A DNSRecord is a DNS resource record, as returned by LookupRecords.
+type DNSRecord struct {
+	Name  string // owner name, with a trailing dot
+	Type  uint16 // record type, such as 65 for HTTPS records
+	Class uint16 // record class, usually 1 for the Internet
+	TTL   uint32 // time to live, in seconds
+
+	// Data is the record data (RDATA) in wire format. The domain
+	// names in the data of the record types of RFC 1035, such as MX
+	// and CNAME, are not compressed.
+	Data []byte
+}
+
+// LookupRecords returns the DNS resource records of type rrtype for
+// the given domain name, such as the HTTPS (65), CAA (257) or TLSA
+// (52) records, with their data in wire format for the caller to
+// decode. Records of other types in the response, such as the CNAME
+// records leading to the answer, are not returned.
+//
+// LookupRecords always uses Go's built-in DNS resolver.
+func (r *Resolver) LookupRecords(ctx context.Context, name string, rrtype uint16) ([]DNSRecord, error) {
+	return r.goLookupRecords(ctx, name, rrtype)
+}
+
 // LookupAddr performs a reverse lookup for the given address, returning a list
 // of names mapping to that address.
 //
@@ -898,6 +943,133 @@ func (r *Resolver) goLookupTXT(ctx context.Context, name string) ([]string, erro
 	return txts, nil
 }
 
+// goLookupRecords returns the resource records of type rrtype for name.
+func (r *Resolver) goLookupRecords(ctx context.Context, name string, rrtype uint16) ([]DNSRecord, error) {
+	qtype := dnsmessage.Type(rrtype)
+	p, server, err := r.lookup(ctx, name, qtype, nil)
+	if err != nil {
+		return nil, err
+	}
+	var rrs []DNSRecord
+	for {
+		h, err := p.AnswerHeader()
+		if err == dnsmessage.ErrSectionDone {
+			break
+		}
+		if err != nil {
+			return nil, &DNSError{
+				Err:    "cannot unmarshal DNS message",
+				Name:   name,
+				Server: server,
+			}
+		}
+		if h.Type != qtype {
+			if err := p.SkipAnswer(); err != nil {
+				return nil, &DNSError{
+					Err:    "cannot unmarshal DNS message",
+					Name:   name,
+					Server: server,
+				}
+			}
+			continue
+		}
+		data, err := recordData(&p, h.Type)
+		if err != nil {
+			return nil, &DNSError{
+				Err:    "cannot unmarshal DNS message",
+				Name:   name,
+				Server: server,
+			}
+		}
+		rrs = append(rrs, DNSRecord{
+			Name:  h.Name.String(),
+			Type:  uint16(h.Type),
+			Class: uint16(h.Class),
+			TTL:   h.TTL,
+			Data:  data,
+		})
+	}
+	return rrs, nil
+}
+
+// recordData returns the data of the current answer of p, which has
+// type typ. The domain names in the data of the record types that
+// allow compression are expanded, as they may point into the message.
+func recordData(p *dnsmessage.Parser, typ dnsmessage.Type) ([]byte, error) {
+	var b []byte
+	switch typ {
+	case dnsmessage.TypeCNAME:
+		rr, err := p.CNAMEResource()
+		if err != nil {
+			return nil, err
+		}
+		b = appendDNSName(b, rr.CNAME)
+	case dnsmessage.TypeNS:
+		rr, err := p.NSResource()
+		if err != nil {
+			return nil, err
+		}
+		b = appendDNSName(b, rr.NS)
+	case dnsmessage.TypePTR:
+		rr, err := p.PTRResource()
+		if err != nil {
+			return nil, err
+		}
+		b = appendDNSName(b, rr.PTR)
+	case dnsmessage.TypeMX:
+		rr, err := p.MXResource()
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, byte(rr.Pref>>8), byte(rr.Pref))
+		b = appendDNSName(b, rr.MX)
+	case dnsmessage.TypeSOA:
+		rr, err := p.SOAResource()
+		if err != nil {
+			return nil, err
+		}
+		b = appendDNSName(b, rr.NS)
+		b = appendDNSName(b, rr.MBox)
+		for _, v := range [...]uint32{rr.Serial, rr.Refresh, rr.Retry, rr.Expire, rr.MinTTL} {
+			b = append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+		}
+	case dnsmessage.TypeSRV:
+		rr, err := p.SRVResource()
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range [...]uint16{rr.Priority, rr.Weight, rr.Port} {
+			b = append(b, byte(v>>8), byte(v))
+		}
+		b = appendDNSName(b, rr.Target)
+	default:
+		rr, err := p.UnknownResource()
+		if err != nil {
+			return nil, err
+		}
+		b = rr.Data
+	}
+	return b, nil
+}
+
+// appendDNSName appends the uncompressed wire format of n to b.
+func appendDNSName(b []byte, n dnsmessage.Name) []byte {
+	name := n.String()
+	for len(name) > 0 && name != "." {
+		i := bytealg.IndexByteString(name, '.')
+		if i < 0 {
+			i = len(name)
+		}
+		b = append(b, byte(i))
+		b = append(b, name[:i]...)
+		if i < len(name) {
+			i++
+		}
+		name = name[i:]
+	}
+	return append(b, 0)
+}
+
 func parseCNAMEFromResources(resources []dnsmessage.Resource) (string, error) {
 	if len(resources) == 0 {
 		return "", errors.New("no CNAME record received")
@@ -192,12 +192,12 @@ func (r *Resolver) preferGoOverPlan9WithOrderAndConf() (hostLookupOrder, *dnsCon
 
 	// TODO(bradfitz): for now we only permit use of the PreferGo
 	// implementation when there's a non-nil Resolver with a
-	// non-nil Dialer. This is a sign that they the code is trying
+	// non-nil Dialer or Transport. This is a sign that they the code is trying
 	// to use their DNS-speaking net.Conn (such as an in-memory
 	// DNS cache) and they don't want to actually hit the network.
 	// Once we add support for looking the default DNS servers
 	// from plan9, though, then we can relax this.
-	return order, conf, order != hostLookupCgo && r != nil && r.Dial != nil
+	return order, conf, order != hostLookupCgo && r != nil && (r.Dial != nil || r.Transport != nil)
 }
 
 func (r *Resolver) lookupIP(ctx context.Context, network, host string) (addrs []IPAddr, err error) {
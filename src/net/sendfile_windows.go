@@ -7,7 +7,6 @@ package net
 import (
 	"internal/poll"
 	"io"
-	"os"
 	"syscall"
 )
 
@@ -29,7 +28,12 @@ func sendFile(fd *netFD, r io.Reader) (written int64, err error, handled bool) {
 		}
 	}
 
-	f, ok := r.(*os.File)
+	// r might be an *os.File or an os.fileWithoutWriteTo.
+	// Type assert to an interface rather than *os.File directly to handle the latter case.
+	f, ok := r.(interface {
+		io.Reader
+		Fd() uintptr
+	})
 	if !ok {
 		return 0, nil, false
 	}
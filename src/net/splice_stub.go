@@ -8,6 +8,10 @@ package net
 
 import "io"
 
-func splice(c *netFD, r io.Reader) (int64, error, bool) {
+func spliceFrom(c *netFD, r io.Reader) (int64, error, bool) {
+	return 0, nil, false
+}
+
+func spliceTo(w io.Writer, c *netFD) (int64, error, bool) {
 	return 0, nil, false
 }
@@ -23,6 +23,7 @@ func TestSplice(t *testing.T) {
 		t.Skip("skipping unix-to-tcp tests")
 	}
 	t.Run("unix-to-tcp", func(t *testing.T) { testSplice(t, "unix", "tcp") })
+	t.Run("tcp-to-unix", testSpliceToUnix)
 	t.Run("no-unixpacket", testSpliceNoUnixpacket)
 	t.Run("no-unixgram", testSpliceNoUnixgram)
 }
@@ -206,7 +207,7 @@ func testSpliceNoUnixpacket(t *testing.T) {
 	//
 	// What we want is err == nil and handled == false, i.e. we never
 	// called poll.Splice, because we know the unix socket's network.
-	_, err, handled := splice(serverDown.(*TCPConn).fd, serverUp)
+	_, err, handled := spliceFrom(serverDown.(*TCPConn).fd, serverUp)
 	if err != nil || handled != false {
 		t.Fatalf("got err = %v, handled = %t, want nil error, handled == false", err, handled)
 	}
@@ -227,12 +228,50 @@ func testSpliceNoUnixgram(t *testing.T) {
 	defer clientDown.Close()
 	defer serverDown.Close()
 	// Analogous to testSpliceNoUnixpacket.
-	_, err, handled := splice(serverDown.(*TCPConn).fd, up)
+	_, err, handled := spliceFrom(serverDown.(*TCPConn).fd, up)
 	if err != nil || handled != false {
 		t.Fatalf("got err = %v, handled = %t, want nil error, handled == false", err, handled)
 	}
 }
 
+func testSpliceToUnix(t *testing.T) {
+	clientUp, serverUp := spliceTestSocketPair(t, "tcp")
+	defer serverUp.Close()
+	clientDown, serverDown := spliceTestSocketPair(t, "unix")
+	defer clientDown.Close()
+
+	const size = 1 << 16
+	go func() {
+		clientUp.Write(make([]byte, size))
+		clientUp.Close()
+	}()
+	done := make(chan int64)
+	go func() {
+		n, _ := io.Copy(io.Discard, clientDown)
+		done <- n
+	}()
+	n, err, handled := spliceTo(serverDown, serverUp.(*TCPConn).fd)
+	serverDown.Close()
+	if !handled {
+		t.Fatal("spliceTo did not handle a copy to a unix stream socket")
+	}
+	if err != nil || n != size {
+		t.Fatalf("spliceTo = %d, %v; want %d, nil", n, err, size)
+	}
+	if got := <-done; got != size {
+		t.Errorf("read %d bytes from the unix socket; want %d", got, size)
+	}
+
+	c, err := ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if _, _, handled := spliceTo(c.(*UDPConn), serverUp.(*TCPConn).fd); handled {
+		t.Error("spliceTo handled a copy to a udp socket")
+	}
+}
+
 func BenchmarkSplice(b *testing.B) {
 	testHookUninstaller.Do(uninstallTestHooks)
 
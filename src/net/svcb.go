@@ -0,0 +1,245 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package net
+
+import (
+	"context"
+	"errors"
+	"internal/itoa"
+	"net/netip"
+	"sort"
+)
+
+// An SVCB is a service binding, as published in the SVCB and HTTPS
+// DNS records of RFC 9460. It describes an endpoint of a service and
+// how to connect to it.
+type SVCB struct {
+	// Priority orders the bindings of a service; lower values are
+	// preferred. Priority 0 marks an alias (AliasMode), which only
+	// names another domain to look up.
+	Priority uint16
+
+	// Target is the domain name of the endpoint, with a trailing
+	// dot. In the bindings returned by LookupHTTPS, it is never ".".
+	Target string
+
+	// ALPN lists the application protocols the endpoint supports,
+	// in addition to the default protocol unless NoDefaultALPN is set.
+	ALPN          []string
+	NoDefaultALPN bool
+
+	// Port is the port of the endpoint, or 0 for the port of the
+	// service itself.
+	Port uint16
+
+	// IPv4Hint and IPv6Hint list addresses of the endpoint, for use
+	// when the addresses of Target cannot be looked up.
+	IPv4Hint []netip.Addr
+	IPv6Hint []netip.Addr
+
+	// ECHConfig is the ECHConfigList of the endpoint, for the TLS
+	// Encrypted Client Hello extension, or nil.
+	ECHConfig []byte
+}
+
+// Protocols returns the application protocols the endpoint of an HTTPS
+// record supports: its ALPN list, followed by the default protocol
+// "http/1.1" unless NoDefaultALPN is set.
+func (s *SVCB) Protocols() []string {
+	protos := append([]string(nil), s.ALPN...)
+	if !s.NoDefaultALPN {
+		protos = append(protos, "http/1.1")
+	}
+	return protos
+}
+
+// tcpCompatible reports whether the endpoint of an HTTPS record
+// supports an application protocol over TCP, that is, any protocol
+// other than HTTP/3, which runs over QUIC.
+func (s *SVCB) tcpCompatible() bool {
+	for _, p := range s.Protocols() {
+		if p != "h3" && !(len(p) > 3 && p[:3] == "h3-") {
+			return true
+		}
+	}
+	return false
+}
+
+// Service parameter keys, from RFC 9460 section 14.3.2.
+const (
+	svcParamMandatory     = 0
+	svcParamALPN          = 1
+	svcParamNoDefaultALPN = 2
+	svcParamPort          = 3
+	svcParamIPv4Hint      = 4
+	svcParamECH           = 5
+	svcParamIPv6Hint      = 6
+)
+
+var errMalformedSVCB = errors.New("malformed SVCB record")
+
+// parseSVCB parses the data of an SVCB or HTTPS record. It returns
+// an error for a record with a mandatory parameter it does not
+// support, which clients must ignore.
+func parseSVCB(data []byte) (*SVCB, error) {
+	if len(data) < 3 {
+		return nil, errMalformedSVCB
+	}
+	s := &SVCB{Priority: uint16(data[0])<<8 | uint16(data[1])}
+	data = data[2:]
+
+	// The target name is never compressed.
+	var name []byte
+	for {
+		if len(data) == 0 {
+			return nil, errMalformedSVCB
+		}
+		n := int(data[0])
+		if n == 0 {
+			data = data[1:]
+			break
+		}
+		if n > 63 || len(data) < 1+n {
+			return nil, errMalformedSVCB
+		}
+		name = append(name, data[1:1+n]...)
+		name = append(name, '.')
+		data = data[1+n:]
+	}
+	if len(name) == 0 {
+		s.Target = "."
+	} else {
+		s.Target = string(name)
+	}
+
+	var mandatory []byte
+	lastKey := -1
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return nil, errMalformedSVCB
+		}
+		key := int(data[0])<<8 | int(data[1])
+		n := int(data[2])<<8 | int(data[3])
+		if key <= lastKey || len(data) < 4+n {
+			return nil, errMalformedSVCB
+		}
+		lastKey = key
+		v := data[4 : 4+n]
+		data = data[4+n:]
+		switch key {
+		case svcParamMandatory:
+			if n == 0 || n%2 != 0 {
+				return nil, errMalformedSVCB
+			}
+			mandatory = v
+		case svcParamALPN:
+			for len(v) > 0 {
+				l := int(v[0])
+				if l == 0 || len(v) < 1+l {
+					return nil, errMalformedSVCB
+				}
+				s.ALPN = append(s.ALPN, string(v[1:1+l]))
+				v = v[1+l:]
+			}
+		case svcParamNoDefaultALPN:
+			if n != 0 {
+				return nil, errMalformedSVCB
+			}
+			s.NoDefaultALPN = true
+		case svcParamPort:
+			if n != 2 {
+				return nil, errMalformedSVCB
+			}
+			s.Port = uint16(v[0])<<8 | uint16(v[1])
+		case svcParamIPv4Hint:
+			if n == 0 || n%4 != 0 {
+				return nil, errMalformedSVCB
+			}
+			for ; len(v) > 0; v = v[4:] {
+				s.IPv4Hint = append(s.IPv4Hint, netip.AddrFrom4([4]byte(v[:4])))
+			}
+		case svcParamECH:
+			s.ECHConfig = append([]byte(nil), v...)
+		case svcParamIPv6Hint:
+			if n == 0 || n%16 != 0 {
+				return nil, errMalformedSVCB
+			}
+			for ; len(v) > 0; v = v[16:] {
+				s.IPv6Hint = append(s.IPv6Hint, netip.AddrFrom16([16]byte(v[:16])))
+			}
+		}
+	}
+	for ; len(mandatory) > 0; mandatory = mandatory[2:] {
+		switch key := int(mandatory[0])<<8 | int(mandatory[1]); key {
+		case svcParamALPN, svcParamNoDefaultALPN, svcParamPort,
+			svcParamIPv4Hint, svcParamECH, svcParamIPv6Hint:
+		default:
+			return nil, errors.New("SVCB record has unsupported mandatory parameter key " + itoa.Itoa(key))
+		}
+	}
+	if s.NoDefaultALPN && len(s.ALPN) == 0 {
+		return nil, errMalformedSVCB
+	}
+	return s, nil
+}
+
+// typeHTTPS is the DNS record type of HTTPS records.
+const typeHTTPS = 65
+
+// maxSVCBAliases is the number of aliases LookupHTTPS follows.
+const maxSVCBAliases = 8
+
+// LookupHTTPS returns the service bindings published in the DNS HTTPS
+// records (RFC 9460) of the HTTPS service on the given host and port,
+// sorted by priority. The records of the default port, 443, are those
+// of the host's own name; those of any other port, including 80, are
+// at "_port._https.host". A port of 0 stands for 443. It follows the aliases (AliasMode records) the
+// service may have, and sets the Target of the bindings whose target
+// is the domain they were found at to that domain.
+//
+// Bindings that are malformed, or that have mandatory parameters this
+// package does not support, are ignored.
+func (r *Resolver) LookupHTTPS(ctx context.Context, host string, port int) ([]*SVCB, error) {
+	name := host
+	if port != 0 && port != 443 {
+		name = "_" + itoa.Itoa(port) + "._https." + host
+	}
+	if name == "" || name[len(name)-1] != '.' {
+		name += "."
+	}
+	for i := 0; ; i++ {
+		rrs, err := r.LookupRecords(ctx, name, typeHTTPS)
+		if err != nil {
+			return nil, err
+		}
+		var svcbs []*SVCB
+		var alias string
+		for _, rr := range rrs {
+			s, err := parseSVCB(rr.Data)
+			if err != nil {
+				continue
+			}
+			if s.Priority == 0 {
+				alias = s.Target
+				continue
+			}
+			if s.Target == "." {
+				s.Target = rr.Name
+			}
+			svcbs = append(svcbs, s)
+		}
+		if len(svcbs) > 0 || alias == "" {
+			sort.SliceStable(svcbs, func(i, j int) bool {
+				return svcbs[i].Priority < svcbs[j].Priority
+			})
+			return svcbs, nil
+		}
+		if alias == "." || i == maxSVCBAliases {
+			// The service is not available, or the aliases loop.
+			return nil, &DNSError{Err: errNoSuchHost.Error(), Name: host, IsNotFound: true}
+		}
+		name = alias
+	}
+}
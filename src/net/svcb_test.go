@@ -0,0 +1,290 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package net
+
+import (
+	"context"
+	"internal/itoa"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+type svcParam struct {
+	key   uint16
+	value []byte
+}
+
+// svcbData returns the data of an SVCB record.
+func svcbData(priority uint16, target string, params ...svcParam) []byte {
+	b := []byte{byte(priority >> 8), byte(priority)}
+	b = appendDNSName(b, mustNewName(target))
+	for _, p := range params {
+		b = append(b, byte(p.key>>8), byte(p.key), byte(len(p.value)>>8), byte(len(p.value)))
+		b = append(b, p.value...)
+	}
+	return b
+}
+
+func alpnParam(protos ...string) svcParam {
+	var v []byte
+	for _, p := range protos {
+		v = append(v, byte(len(p)))
+		v = append(v, p...)
+	}
+	return svcParam{svcParamALPN, v}
+}
+
+func portParam(port int) svcParam {
+	return svcParam{svcParamPort, []byte{byte(port >> 8), byte(port)}}
+}
+
+func TestParseSVCB(t *testing.T) {
+	v6 := netip.MustParseAddr("2001:db8::1")
+	tests := []struct {
+		data []byte
+		want *SVCB
+	}{
+		{svcbData(0, "alias.example."), &SVCB{Target: "alias.example."}},
+		{svcbData(1, "."), &SVCB{Priority: 1, Target: "."}},
+		{
+			svcbData(2, "svc.example.",
+				svcParam{svcParamMandatory, []byte{0, svcParamALPN}},
+				alpnParam("h2", "h3"),
+				svcParam{svcParamNoDefaultALPN, nil},
+				portParam(8443),
+				svcParam{svcParamIPv4Hint, []byte{192, 0, 2, 1, 192, 0, 2, 2}},
+				svcParam{svcParamECH, []byte("ech")},
+				svcParam{svcParamIPv6Hint, v6.AsSlice()},
+				svcParam{100, []byte("unknown key")},
+			),
+			&SVCB{
+				Priority:      2,
+				Target:        "svc.example.",
+				ALPN:          []string{"h2", "h3"},
+				NoDefaultALPN: true,
+				Port:          8443,
+				IPv4Hint:      []netip.Addr{netip.MustParseAddr("192.0.2.1"), netip.MustParseAddr("192.0.2.2")},
+				IPv6Hint:      []netip.Addr{v6},
+				ECHConfig:     []byte("ech"),
+			},
+		},
+
+		// Errors.
+		{[]byte{0, 1}, nil},
+		{svcbData(1, ".", portParam(1), alpnParam("h2")), nil},         // keys out of order
+		{svcbData(1, ".", svcParam{svcParamPort, []byte{1}}), nil},     // bad port
+		{svcbData(1, ".", svcParam{svcParamIPv4Hint, []byte{1}}), nil}, // bad hint
+		{svcbData(1, ".", svcParam{svcParamNoDefaultALPN, nil}), nil},  // no ALPN
+		{svcbData(1, ".", svcParam{svcParamMandatory, []byte{0, 100}}, svcParam{100, nil}), nil},
+		{svcbData(1, ".")[:5], nil},
+	}
+	for _, tt := range tests {
+		got, err := parseSVCB(tt.data)
+		if tt.want == nil {
+			if err == nil {
+				t.Errorf("parseSVCB(%x) = %+v; want error", tt.data, got)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseSVCB(%x) = %+v, %v; want %+v", tt.data, got, err, tt.want)
+		}
+	}
+}
+
+// newSVCBResolver returns a Resolver whose DNS server has the given
+// HTTPS records and A records, by name.
+func newSVCBResolver(t *testing.T, https map[string][][]byte, a map[string][4]byte) *Resolver {
+	conf, err := newResolvConfTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conf.teardown() })
+	if err := conf.writeAndUpdate([]string{"nameserver 192.0.2.1"}); err != nil {
+		t.Fatal(err)
+	}
+	fake := &fakeDNSTransport{rh: func(q dnsmessage.Message) (dnsmessage.Message, error) {
+		r := dnsmessage.Message{
+			Header: dnsmessage.Header{
+				ID:                 q.ID,
+				Response:           true,
+				RecursionAvailable: true,
+			},
+			Questions: q.Questions,
+		}
+		qq := q.Questions[0]
+		name := qq.Name.String()
+		switch qq.Type {
+		case typeHTTPS:
+			for _, data := range https[name] {
+				r.Answers = append(r.Answers, dnsmessage.Resource{
+					Header: dnsmessage.ResourceHeader{Name: qq.Name, Class: dnsmessage.ClassINET},
+					Body:   &dnsmessage.UnknownResource{Type: typeHTTPS, Data: data},
+				})
+			}
+		case dnsmessage.TypeA:
+			if ip, ok := a[name]; ok {
+				r.Answers = append(r.Answers, dnsmessage.Resource{
+					Header: dnsmessage.ResourceHeader{Name: qq.Name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET},
+					Body:   &dnsmessage.AResource{A: ip},
+				})
+			}
+		}
+		if len(r.Answers) == 0 && https[name] == nil && a[name] == [4]byte{} {
+			r.RCode = dnsmessage.RCodeNameError
+		}
+		return r, nil
+	}}
+	return &Resolver{Transport: fake}
+}
+
+func TestLookupHTTPS(t *testing.T) {
+	r := newSVCBResolver(t, map[string][][]byte{
+		"www.example.": {svcbData(0, "svc.example.")},
+		"svc.example.": {
+			svcbData(2, "backup.example."),
+			svcbData(1, ".", alpnParam("h2")),
+			svcbData(3, ".", svcParam{svcParamMandatory, []byte{0, 100}}, svcParam{100, nil}),
+		},
+		"_8443._https.example.": {svcbData(1, ".", portParam(9443))},
+		"loop.example.":         {svcbData(0, "loop.example.")},
+		"gone.example.":         {svcbData(0, ".")},
+	}, nil)
+	ctx := context.Background()
+
+	got, err := r.LookupHTTPS(ctx, "www.example", 443)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*SVCB{
+		{Priority: 1, Target: "svc.example.", ALPN: []string{"h2"}},
+		{Priority: 2, Target: "backup.example."},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LookupHTTPS(www.example) = %+v; want %+v", got, want)
+	}
+
+	got, err = r.LookupHTTPS(ctx, "example", 8443)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []*SVCB{{Priority: 1, Target: "_8443._https.example.", Port: 9443}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LookupHTTPS(example, 8443) = %+v; want %+v", got, want)
+	}
+
+	// Port 80 does not share the bindings of port 443.
+	if got, err := r.LookupHTTPS(ctx, "www.example", 80); err == nil {
+		t.Errorf("LookupHTTPS(www.example, 80) = %+v; want error", got)
+	}
+
+	for _, host := range []string{"loop.example", "gone.example", "none.example"} {
+		if got, err := r.LookupHTTPS(ctx, host, 443); err == nil {
+			t.Errorf("LookupHTTPS(%s) = %+v; want error", host, got)
+		}
+	}
+}
+
+func TestDialServiceHTTPSRecords(t *testing.T) {
+	ln := newLocalListener(t, "tcp4")
+	defer ln.Close()
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			c.Close()
+		}
+	}()
+	port := ln.Addr().(*TCPAddr).Port
+
+	closed := newLocalListener(t, "tcp4")
+	closedPort := closed.Addr().(*TCPAddr).Port
+	closed.Close()
+
+	hint := svcParam{svcParamIPv4Hint, []byte{127, 0, 0, 1}}
+	r := newSVCBResolver(t, map[string][][]byte{
+		// The first binding is only for HTTP/3, and the second one
+		// refuses connections.
+		"svc.example.": {
+			svcbData(1, ".", alpnParam("h3"), svcParam{svcParamNoDefaultALPN, nil}, portParam(port), hint),
+			svcbData(2, "refused.example.", portParam(closedPort)),
+			svcbData(3, ".", alpnParam("h2"), portParam(port), hint),
+		},
+	}, map[string][4]byte{
+		"refused.example.": {127, 0, 0, 1},
+		"plain.example.":   {127, 0, 0, 1},
+	})
+	d := &Dialer{Resolver: r, Timeout: 10 * time.Second}
+
+	// DialContext does not use HTTPS records, and svc.example has no
+	// address of its own.
+	if c, err := d.DialContext(context.Background(), "tcp", "svc.example:443"); err == nil {
+		c.Close()
+		t.Errorf("DialContext(svc.example:443) connected to %v; want error", c.RemoteAddr())
+	}
+
+	c, s, err := d.DialService(context.Background(), "tcp", "svc.example:443")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Close()
+	if got := c.RemoteAddr().(*TCPAddr).Port; got != port {
+		t.Errorf("connected to port %d; want %d", got, port)
+	}
+	if s == nil || s.Priority != 3 || !reflect.DeepEqual(s.Protocols(), []string{"h2", "http/1.1"}) {
+		t.Errorf("DialService returned binding %+v; want the one of priority 3", s)
+	}
+
+	// Without HTTPS records, the host is dialed.
+	c, s, err = d.DialService(context.Background(), "tcp", JoinHostPort("plain.example", itoa.Itoa(port)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Close()
+	if s != nil {
+		t.Errorf("DialService returned binding %+v; want none", s)
+	}
+}
+
+func TestDialRace(t *testing.T) {
+	ln := newLocalListener(t, "tcp4")
+	defer ln.Close()
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			c.Close()
+		}
+	}()
+	closed := newLocalListener(t, "tcp4")
+	closedAddr := closed.Addr().(*TCPAddr)
+	closed.Close()
+
+	// The attempt to the second address starts as soon as the first
+	// one fails, well before the fallback delay.
+	sd := &sysDialer{Dialer: Dialer{FallbackDelay: time.Hour}, network: "tcp"}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	c, err := sd.dialRace(ctx, addrList{closedAddr, ln.Addr()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Close()
+
+	_, err = sd.dialRace(ctx, addrList{closedAddr})
+	if err == nil {
+		t.Error("dialRace to a closed port succeeded")
+	}
+}
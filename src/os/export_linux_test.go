@@ -5,3 +5,5 @@
 package os
 
 var PollCopyFileRangeP = &pollCopyFileRange
+var PollSpliceP = &pollSplice
+var PollSendFileP = &pollSendFile
@@ -164,6 +164,39 @@ type onlyWriter struct {
 	io.Writer
 }
 
+// WriteTo implements io.WriterTo.
+func (f *File) WriteTo(w io.Writer) (n int64, err error) {
+	if err := f.checkValid("read"); err != nil {
+		return 0, err
+	}
+	n, handled, e := f.writeTo(w)
+	if handled {
+		return n, f.wrapErr("read", e)
+	}
+	return genericWriteTo(f, w) // without wrapping
+}
+
+// noWriteTo is an io.WriterTo whose WriteTo method is never called.
+// Embedded alongside another type, it hides the WriteTo method of
+// that other type.
+type noWriteTo struct{}
+
+func (noWriteTo) WriteTo(io.Writer) (int64, error) {
+	panic("can't happen")
+}
+
+// fileWithoutWriteTo implements all the methods of *File other
+// than WriteTo. This is used to permit WriteTo to call io.Copy
+// without leading to a recursive call to WriteTo.
+type fileWithoutWriteTo struct {
+	noWriteTo
+	*File
+}
+
+func genericWriteTo(f *File, w io.Writer) (int64, error) {
+	return io.Copy(w, fileWithoutWriteTo{File: f})
+}
+
 // Write writes len(b) bytes from b to the File.
 // It returns the number of bytes written and an error, if any.
 // Write returns a non-nil error when n != len(b).
@@ -593,7 +626,8 @@ func (f *File) SyscallConn() (syscall.RawConn, error) {
 // os.Open does. Additionally, the root of the fs.FS returned for a relative path,
 // DirFS("prefix"), will be affected by later calls to Chdir. DirFS is therefore not
 // a general substitute for a chroot-style security mechanism when the directory tree
-// contains arbitrary content.
+// contains arbitrary content. Use Root.FS to obtain a file system that
+// confines accesses to the directory tree, symbolic links included.
 //
 // The result implements fs.StatFS.
 func DirFS(dir string) fs.FS {
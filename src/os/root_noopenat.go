@@ -0,0 +1,181 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !unix
+
+package os
+
+import (
+	"errors"
+	"sync/atomic"
+	"syscall"
+)
+
+// root is the implementation of Root for platforms without openat.
+// It holds the name of the directory, and resolves file names
+// lexically, using Lstat and Readlink to follow symbolic links.
+// See the Root documentation for the limits of this approach.
+type root struct {
+	name   string
+	closed atomic.Bool
+}
+
+func (r *root) Name() string {
+	return r.name
+}
+
+func (r *root) Close() error {
+	// For consistency with the openat implementation,
+	// a second Close is not an error.
+	r.closed.Store(true)
+	return nil
+}
+
+var errRootSymlinkLoop = errors.New("too many levels of symbolic links")
+
+// openRootNolog is OpenRoot.
+func openRootNolog(name string) (*Root, error) {
+	fi, err := Stat(name)
+	if err != nil {
+		return nil, &PathError{Op: "open", Path: name, Err: underlyingError(err)}
+	}
+	if !fi.IsDir() {
+		return nil, &PathError{Op: "open", Path: name, Err: syscall.ENOTDIR}
+	}
+	return &Root{&root{name: name}}, nil
+}
+
+// openRootInRoot is Root.OpenRoot.
+func openRootInRoot(r *Root, name string) (*Root, error) {
+	path, err := rootResolve(r, name, true)
+	if err == nil {
+		var rr *Root
+		rr, err = openRootNolog(path)
+		if err == nil {
+			rr.root.name = joinPath(r.Name(), name)
+			return rr, nil
+		}
+	}
+	return nil, &PathError{Op: "openat", Path: name, Err: underlyingError(err)}
+}
+
+// rootOpenFileNolog is Root.OpenFile.
+func rootOpenFileNolog(r *Root, name string, flag int, perm FileMode) (*File, error) {
+	// See comment in the Unix rootOpenFileNolog.
+	followLast := flag&(O_CREATE|O_EXCL) != O_CREATE|O_EXCL
+	path, err := rootResolve(r, name, followLast)
+	if err != nil {
+		return nil, &PathError{Op: "openat", Path: name, Err: err}
+	}
+	f, err := openFileNolog(path, flag, perm)
+	if err != nil {
+		return nil, &PathError{Op: "openat", Path: name, Err: underlyingError(err)}
+	}
+	f.name = joinPath(r.Name(), name)
+	return f, nil
+}
+
+// rootMkdir is Root.Mkdir.
+func rootMkdir(r *Root, name string, perm FileMode) error {
+	path, err := rootResolve(r, name, false)
+	if err == nil {
+		err = Mkdir(path, perm)
+	}
+	if err != nil {
+		return &PathError{Op: "mkdirat", Path: name, Err: underlyingError(err)}
+	}
+	return nil
+}
+
+// rootRemove is Root.Remove.
+func rootRemove(r *Root, name string) error {
+	path, err := rootResolve(r, name, false)
+	if err == nil {
+		err = Remove(path)
+	}
+	if err != nil {
+		return &PathError{Op: "removeat", Path: name, Err: underlyingError(err)}
+	}
+	return nil
+}
+
+// rootStat is Root.Stat, or Root.Lstat if lstat is true.
+func rootStat(r *Root, name string, lstat bool) (FileInfo, error) {
+	path, err := rootResolve(r, name, !lstat)
+	var fi FileInfo
+	if err == nil {
+		fi, err = Lstat(path)
+	}
+	if err != nil {
+		op := "statat"
+		if lstat {
+			op = "lstatat"
+		}
+		return nil, &PathError{Op: op, Path: name, Err: underlyingError(err)}
+	}
+	return fi, nil
+}
+
+// rootResolve returns the name of the file name refers to in r,
+// following the symbolic links in name as long as they stay within r.
+// If followLast is false, a symbolic link in the last component of
+// name is not followed.
+//
+// Components that do not exist are taken to be directories; the
+// operation on the returned name reports the error.
+func rootResolve(r *Root, name string, followLast bool) (string, error) {
+	if r.root.closed.Load() {
+		return "", ErrClosed
+	}
+	parts, err := splitPathInRoot(name, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	symlinks := 0
+	for i := 0; i < len(parts); {
+		if parts[i] == ".." {
+			// See comment in doInRoot.
+			parts, err = collapseParent(parts, i)
+			if err != nil {
+				return "", err
+			}
+			i = 0
+			continue
+		}
+		if i == len(parts)-1 && !followLast {
+			break
+		}
+		next := r.root.join(parts[:i+1])
+		fi, err := Lstat(next)
+		if err != nil || fi.Mode()&ModeSymlink == 0 {
+			i++
+			continue
+		}
+		symlinks++
+		if symlinks > rootMaxSymlinks {
+			return "", errRootSymlinkLoop
+		}
+		link, err := Readlink(next)
+		if err != nil {
+			return "", underlyingError(err)
+		}
+		parts, err = splitPathInRoot(link, parts[:i], parts[i+1:])
+		if err != nil {
+			return "", err
+		}
+		if i == len(parts) {
+			parts = append(parts, ".")
+		}
+	}
+	return r.root.join(parts), nil
+}
+
+// join returns the name of the file parts refers to in r.
+func (r *root) join(parts []string) string {
+	name := r.name
+	for _, part := range parts {
+		name = joinPath(name, part)
+	}
+	return name
+}
@@ -0,0 +1,375 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os_test
+
+import (
+	"errors"
+	"internal/testenv"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"testing/fstest"
+)
+
+// makeRootTree creates a directory tree for the Root tests:
+//
+//	dir/
+//		outside           file outside the root
+//		root/
+//			f             file
+//			a/
+//				g         file
+//				lf        -> ../f
+//				la        -> .
+//			lg            -> a/g
+//			lout          -> ../outside
+//			labs          -> dir/outside, as an absolute name
+//			ldotdot       -> a/../../outside
+//			lloop         -> lloop
+//			ldangling     -> new
+//
+// The symbolic links are only created if the platform supports them.
+// makeRootTree returns dir and whether it created the links.
+func makeRootTree(t *testing.T) (dir string, links bool) {
+	dir = t.TempDir()
+	root := filepath.Join(dir, "root")
+	for _, d := range []string{root, filepath.Join(root, "a")} {
+		if err := os.Mkdir(d, 0o777); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, data := range map[string]string{
+		"outside":  "outside",
+		"root/f":   "f",
+		"root/a/g": "g",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), []byte(data), 0o666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !testenv.HasSymlink() {
+		return dir, false
+	}
+	for name, target := range map[string]string{
+		"root/a/lf":      "../f",
+		"root/a/la":      ".",
+		"root/lg":        "a/g",
+		"root/lout":      "../outside",
+		"root/labs":      filepath.Join(dir, "outside"),
+		"root/ldotdot":   "a/../../outside",
+		"root/lloop":     "lloop",
+		"root/ldangling": "new",
+	} {
+		if err := os.Symlink(filepath.FromSlash(target), filepath.Join(dir, filepath.FromSlash(name))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir, true
+}
+
+func TestRootOpen(t *testing.T) {
+	dir, links := makeRootTree(t)
+	r, err := os.OpenRoot(filepath.Join(dir, "root"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	for _, test := range []struct {
+		name    string
+		link    bool   // name involves a symbolic link
+		want    string // contents of the file, if it can be opened
+		escapes bool   // name escapes from the root
+	}{
+		{name: "f", want: "f"},
+		{name: "./f", want: "f"},
+		{name: "a/g", want: "g"},
+		{name: "a/../f", want: "f"},
+		{name: "a/./../a/g", want: "g"},
+		{name: "a//g", want: "g"},
+		{name: "a/lf", link: true, want: "f"},
+		{name: "a/la/la/g", link: true, want: "g"},
+		{name: "lg", link: true, want: "g"},
+		{name: "../outside", escapes: true},
+		{name: "a/../../outside", escapes: true},
+		{name: "a/../../root/f", escapes: true},
+		{name: filepath.Join(dir, "outside"), escapes: true},
+		{name: "lout", link: true, escapes: true},
+		{name: "labs", link: true, escapes: true},
+		{name: "ldotdot", link: true, escapes: true},
+		{name: "a/la/../../outside", link: true, escapes: true},
+		{name: "a/la/../f", link: true, want: "f"},
+	} {
+		if test.link && !links {
+			continue
+		}
+		f, err := r.Open(test.name)
+		if test.escapes {
+			if err == nil {
+				f.Close()
+				t.Errorf("Open(%q) succeeded, want error", test.name)
+			} else if !errors.Is(err, os.ErrPathEscapes) {
+				t.Errorf("Open(%q) = %v, want %v", test.name, err, os.ErrPathEscapes)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Open(%q) = %v", test.name, err)
+			continue
+		}
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil || string(data) != test.want {
+			t.Errorf("reading %q = %q, %v; want %q", test.name, data, err, test.want)
+		}
+	}
+}
+
+func TestRootOpenFileErrors(t *testing.T) {
+	dir, links := makeRootTree(t)
+	r, err := os.OpenRoot(filepath.Join(dir, "root"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	if _, err := r.Open("missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Open(missing) = %v, want ErrNotExist", err)
+	}
+	var pe *fs.PathError
+	if _, err := r.Open("missing"); !errors.As(err, &pe) || pe.Path != "missing" {
+		t.Errorf("Open(missing) = %v, want *PathError for %q", err, "missing")
+	}
+	if _, err := r.OpenFile("new", os.O_CREATE|os.O_WRONLY, 0o777|os.ModeSetuid); err == nil {
+		t.Errorf("OpenFile with ModeSetuid succeeded, want error")
+	}
+	if !links {
+		return
+	}
+	if _, err := r.Open("lloop"); err == nil {
+		t.Errorf("Open(lloop) succeeded, want error")
+	}
+}
+
+func TestRootCreate(t *testing.T) {
+	dir, links := makeRootTree(t)
+	r, err := os.OpenRoot(filepath.Join(dir, "root"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	f, err := r.Create("a/new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := f.Name(), filepath.Join(dir, "root", "a/new"); filepath.Clean(got) != filepath.Clean(want) {
+		t.Errorf("Name() = %q, want %q", got, want)
+	}
+	if _, err := f.WriteString("new"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if data, err := os.ReadFile(filepath.Join(dir, "root", "a", "new")); err != nil || string(data) != "new" {
+		t.Errorf("created file contains %q, %v; want %q", data, err, "new")
+	}
+
+	if _, err := r.Create("../new"); !errors.Is(err, os.ErrPathEscapes) {
+		t.Errorf("Create(../new) = %v, want %v", err, os.ErrPathEscapes)
+	}
+	if !links {
+		return
+	}
+
+	// Creating through a dangling link creates its target.
+	f, err = r.Create("ldangling")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if _, err := os.Stat(filepath.Join(dir, "root", "new")); err != nil {
+		t.Errorf("Create(ldangling) did not create the target: %v", err)
+	}
+
+	// But not with O_EXCL.
+	if _, err := r.OpenFile("ldangling", os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o666); err == nil {
+		t.Errorf("OpenFile(ldangling, O_CREATE|O_EXCL) succeeded, want error")
+	}
+
+	// Nor to a file outside the root.
+	if _, err := r.Create("lout"); !errors.Is(err, os.ErrPathEscapes) {
+		t.Errorf("Create(lout) = %v, want %v", err, os.ErrPathEscapes)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "outside")); err != nil || string(data) != "outside" {
+		t.Errorf("file outside root contains %q, %v after Create(lout)", data, err)
+	}
+}
+
+func TestRootMkdirRemove(t *testing.T) {
+	dir, links := makeRootTree(t)
+	r, err := os.OpenRoot(filepath.Join(dir, "root"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	if err := r.Mkdir("a/d", 0o777); err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := os.Stat(filepath.Join(dir, "root", "a", "d")); err != nil || !fi.IsDir() {
+		t.Fatalf("Mkdir(a/d) did not create a directory: %v", err)
+	}
+	if err := r.Mkdir("a/d", 0o777); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("second Mkdir(a/d) = %v, want ErrExist", err)
+	}
+	if err := r.Mkdir("../d", 0o777); !errors.Is(err, os.ErrPathEscapes) {
+		t.Errorf("Mkdir(../d) = %v, want %v", err, os.ErrPathEscapes)
+	}
+	if err := r.Mkdir("a/e", 0o777|os.ModeSticky); err == nil {
+		t.Errorf("Mkdir with ModeSticky succeeded, want error")
+	}
+
+	sub, err := r.OpenRoot("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sub.Remove("d"); err != nil {
+		t.Errorf("Remove(d) in a = %v", err)
+	}
+	if err := sub.Remove("../f"); !errors.Is(err, os.ErrPathEscapes) {
+		t.Errorf("Remove(../f) in a = %v, want %v", err, os.ErrPathEscapes)
+	}
+	sub.Close()
+	if _, err := os.Stat(filepath.Join(dir, "root", "a", "d")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("after Remove, Stat(a/d) = %v, want ErrNotExist", err)
+	}
+	if err := r.Remove("a"); err == nil {
+		t.Errorf("Remove of a non-empty directory succeeded, want error")
+	}
+	if !links {
+		return
+	}
+
+	// Remove does not follow links.
+	if err := r.Remove("lout"); err != nil {
+		t.Errorf("Remove(lout) = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "outside")); err != nil {
+		t.Errorf("Remove(lout) removed the link target: %v", err)
+	}
+}
+
+func TestRootStat(t *testing.T) {
+	dir, links := makeRootTree(t)
+	r, err := os.OpenRoot(filepath.Join(dir, "root"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	if fi, err := r.Stat("a/g"); err != nil || fi.Name() != "g" || fi.Size() != 1 || !fi.Mode().IsRegular() {
+		t.Errorf("Stat(a/g) = %v, %v", fi, err)
+	}
+	if fi, err := r.Stat("."); err != nil || !fi.IsDir() {
+		t.Errorf("Stat(.) = %v, %v", fi, err)
+	}
+	if _, err := r.Stat(".."); !errors.Is(err, os.ErrPathEscapes) {
+		t.Errorf("Stat(..) = %v, want %v", err, os.ErrPathEscapes)
+	}
+	if !links {
+		return
+	}
+	if fi, err := r.Stat("lg"); err != nil || !fi.Mode().IsRegular() {
+		t.Errorf("Stat(lg) = %v, %v", fi, err)
+	}
+	if fi, err := r.Lstat("lg"); err != nil || fi.Mode()&fs.ModeSymlink == 0 {
+		t.Errorf("Lstat(lg) = %v, %v", fi, err)
+	}
+	if fi, err := r.Lstat("lout"); err != nil || fi.Mode()&fs.ModeSymlink == 0 {
+		t.Errorf("Lstat(lout) = %v, %v", fi, err)
+	}
+	if _, err := r.Stat("lout"); !errors.Is(err, os.ErrPathEscapes) {
+		t.Errorf("Stat(lout) = %v, want %v", err, os.ErrPathEscapes)
+	}
+}
+
+func TestRootClose(t *testing.T) {
+	dir, _ := makeRootTree(t)
+	r, err := os.OpenRoot(filepath.Join(dir, "root"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := r.Open("f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// Files opened in the root outlive it.
+	if data, err := io.ReadAll(f); err != nil || string(data) != "f" {
+		t.Errorf("reading f after closing the root = %q, %v", data, err)
+	}
+	f.Close()
+	if _, err := r.Open("f"); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Open after Close = %v, want ErrClosed", err)
+	}
+	if got, want := r.Name(), filepath.Join(dir, "root"); got != want {
+		t.Errorf("Name after Close = %q, want %q", got, want)
+	}
+}
+
+func TestOpenRootNotDir(t *testing.T) {
+	dir, _ := makeRootTree(t)
+	if r, err := os.OpenRoot(filepath.Join(dir, "outside")); err == nil {
+		r.Close()
+		t.Errorf("OpenRoot of a file succeeded, want error")
+	}
+}
+
+func TestOpenInRoot(t *testing.T) {
+	dir, _ := makeRootTree(t)
+	f, err := os.OpenInRoot(filepath.Join(dir, "root"), "a/g")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if _, err := os.OpenInRoot(filepath.Join(dir, "root"), "../outside"); !errors.Is(err, os.ErrPathEscapes) {
+		t.Errorf("OpenInRoot(../outside) = %v, want %v", err, os.ErrPathEscapes)
+	}
+}
+
+func TestRootFS(t *testing.T) {
+	dir := t.TempDir()
+	for _, d := range []string{"dir", "dir/sub"} {
+		if err := os.Mkdir(filepath.Join(dir, filepath.FromSlash(d)), 0o777); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"a", "dir/b", "dir/sub/c"} {
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), []byte(name), 0o666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r, err := os.OpenRoot(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if err := fstest.TestFS(r.FS(), "a", "dir/b", "dir/sub/c"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.FS().Open("../a"); err == nil {
+		t.Errorf("FS().Open(../a) succeeded, want error")
+	}
+	if runtime.GOOS == "windows" {
+		if _, err := r.FS().Open(`dir\b`); err == nil {
+			t.Errorf(`FS().Open(dir\b) succeeded, want error`)
+		}
+	}
+}
@@ -0,0 +1,346 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package os
+
+import (
+	"internal/syscall/unix"
+	"runtime"
+	"sync"
+	"syscall"
+)
+
+// root is the Unix implementation of Root.
+// It holds a descriptor for the directory, and resolves file names
+// relative to it with openat and related system calls.
+type root struct {
+	name string
+
+	// fd is closed once Close has been called (closed is true)
+	// and no operation is using it (refs is 0).
+	mu     sync.Mutex
+	fd     int
+	refs   int
+	closed bool
+}
+
+func (r *root) Name() string {
+	return r.name
+}
+
+func (r *root) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.closed && r.refs == 0 {
+		syscall.Close(r.fd)
+	}
+	r.closed = true
+	runtime.SetFinalizer(r, nil) // no need for a finalizer any more
+	return nil
+}
+
+func (r *root) incref() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return ErrClosed
+	}
+	r.refs++
+	return nil
+}
+
+func (r *root) decref() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.refs <= 0 {
+		panic("bad Root refcount")
+	}
+	r.refs--
+	if r.closed && r.refs == 0 {
+		syscall.Close(r.fd)
+	}
+}
+
+// openRootNolog is OpenRoot.
+func openRootNolog(name string) (*Root, error) {
+	var fd int
+	err := ignoringEINTR(func() error {
+		var err error
+		fd, err = syscall.Open(name, syscall.O_RDONLY|syscall.O_CLOEXEC|unix.O_DIRECTORY, 0)
+		return err
+	})
+	if err != nil {
+		return nil, &PathError{Op: "open", Path: name, Err: err}
+	}
+	return newRoot(fd, name), nil
+}
+
+// newRoot returns a new Root.
+// It takes ownership of fd, a descriptor for the directory name.
+func newRoot(fd int, name string) *Root {
+	if !supportsCloseOnExec {
+		syscall.CloseOnExec(fd)
+	}
+	r := &Root{&root{
+		fd:   fd,
+		name: name,
+	}}
+	runtime.SetFinalizer(r.root, (*root).Close)
+	return r
+}
+
+// openRootInRoot is Root.OpenRoot.
+func openRootInRoot(r *Root, name string) (*Root, error) {
+	fd, err := doInRoot(r, name, rootOpenDir)
+	if err != nil {
+		return nil, &PathError{Op: "openat", Path: name, Err: err}
+	}
+	return newRoot(fd, joinPath(r.Name(), name)), nil
+}
+
+// rootOpenFileNolog is Root.OpenFile.
+func rootOpenFileNolog(r *Root, name string, flag int, perm FileMode) (*File, error) {
+	fd, err := doInRoot(r, name, func(parent int, name string) (int, error) {
+		var fd int
+		err := ignoringEINTR(func() error {
+			var err error
+			fd, err = unix.Openat(parent, name, flag|syscall.O_CLOEXEC|syscall.O_NOFOLLOW, syscallMode(perm))
+			return err
+		})
+		if err != nil {
+			// With O_CREATE|O_EXCL, open does not follow a symbolic
+			// link in the last component, and neither do we.
+			if flag&(O_CREATE|O_EXCL) != O_CREATE|O_EXCL {
+				err = checkSymlink(parent, name, err)
+			}
+			return -1, err
+		}
+		return fd, nil
+	})
+	if err != nil {
+		return nil, &PathError{Op: "openat", Path: name, Err: err}
+	}
+	if !supportsCloseOnExec {
+		syscall.CloseOnExec(fd)
+	}
+	return newFile(uintptr(fd), joinPath(r.Name(), name), kindOpenFile), nil
+}
+
+// rootOpenDir opens the directory name in the directory parent,
+// without following a symbolic link.
+func rootOpenDir(parent int, name string) (int, error) {
+	var fd int
+	err := ignoringEINTR(func() error {
+		var err error
+		fd, err = unix.Openat(parent, name, O_RDONLY|syscall.O_CLOEXEC|syscall.O_NOFOLLOW|unix.O_DIRECTORY, 0)
+		return err
+	})
+	if err != nil {
+		return -1, checkSymlink(parent, name, err)
+	}
+	if !supportsCloseOnExec {
+		syscall.CloseOnExec(fd)
+	}
+	return fd, nil
+}
+
+// rootMkdir is Root.Mkdir.
+func rootMkdir(r *Root, name string, perm FileMode) error {
+	_, err := doInRoot(r, name, func(parent int, name string) (struct{}, error) {
+		return struct{}{}, ignoringEINTR(func() error {
+			return unix.Mkdirat(parent, name, syscallMode(perm))
+		})
+	})
+	if err != nil {
+		return &PathError{Op: "mkdirat", Path: name, Err: err}
+	}
+	return nil
+}
+
+// rootRemove is Root.Remove.
+func rootRemove(r *Root, name string) error {
+	_, err := doInRoot(r, name, func(parent int, name string) (struct{}, error) {
+		return struct{}{}, removeat(parent, name)
+	})
+	if err != nil {
+		return &PathError{Op: "removeat", Path: name, Err: err}
+	}
+	return nil
+}
+
+// removeat removes the file or directory name in the directory parent.
+func removeat(parent int, name string) error {
+	// See comment in Remove.
+	e := ignoringEINTR(func() error {
+		return unix.Unlinkat(parent, name, 0)
+	})
+	if e == nil {
+		return nil
+	}
+	e1 := ignoringEINTR(func() error {
+		return unix.Unlinkat(parent, name, unix.AT_REMOVEDIR)
+	})
+	if e1 == nil {
+		return nil
+	}
+	if e1 != syscall.ENOTDIR {
+		e = e1
+	}
+	return e
+}
+
+// rootStat is Root.Stat, or Root.Lstat if lstat is true.
+func rootStat(r *Root, name string, lstat bool) (FileInfo, error) {
+	fi, err := doInRoot(r, name, func(parent int, name string) (FileInfo, error) {
+		var fs fileStat
+		err := ignoringEINTR(func() error {
+			return unix.Fstatat(parent, name, &fs.sys, unix.AT_SYMLINK_NOFOLLOW)
+		})
+		if err != nil {
+			return nil, err
+		}
+		fillFileStatFromSys(&fs, name)
+		if !lstat && fs.Mode()&ModeSymlink != 0 {
+			return nil, checkSymlink(parent, name, syscall.ELOOP)
+		}
+		return &fs, nil
+	})
+	if err != nil {
+		op := "statat"
+		if lstat {
+			op = "lstatat"
+		}
+		return nil, &PathError{Op: op, Path: name, Err: err}
+	}
+	return fi, nil
+}
+
+// errSymlink reports that the file an operation was applied to is
+// a symbolic link, which the operation should follow.
+// It holds the target of the link.
+// doInRoot follows the link, and never returns an errSymlink.
+type errSymlink string
+
+func (errSymlink) Error() string { panic("errSymlink is not user-visible") }
+
+// checkSymlink returns an errSymlink if name in the directory parent
+// is a symbolic link, and origErr otherwise.
+func checkSymlink(parent int, name string, origErr error) error {
+	link, err := readlinkat(parent, name)
+	if err != nil {
+		return origErr
+	}
+	return errSymlink(link)
+}
+
+// readlinkat returns the target of the symbolic link name
+// in the directory parent.
+func readlinkat(parent int, name string) (string, error) {
+	for len := 128; ; len *= 2 {
+		b := make([]byte, len)
+		var (
+			n int
+			e error
+		)
+		for {
+			n, e = fixCount(unix.Readlinkat(parent, name, b))
+			if e != syscall.EINTR {
+				break
+			}
+		}
+		// buffer too small
+		if runtime.GOOS == "aix" && e == syscall.ERANGE {
+			continue
+		}
+		if e != nil {
+			return "", e
+		}
+		if n < len {
+			return string(b[0:n]), nil
+		}
+	}
+}
+
+// doInRoot performs an operation on the file name in r.
+//
+// It opens the directory holding the last component of name,
+// one component at a time and without following symbolic links,
+// and calls f with that directory and the last component.
+// If f returns an errSymlink, or a component of name is a
+// symbolic link, doInRoot follows the link and starts over from
+// the link's directory, as long as it stays within r.
+func doInRoot[T any](r *Root, name string, f func(parent int, name string) (T, error)) (ret T, err error) {
+	if err := r.root.incref(); err != nil {
+		return ret, err
+	}
+	defer r.root.decref()
+
+	parts, err := splitPathInRoot(name, nil, nil)
+	if err != nil {
+		return ret, err
+	}
+
+	rootfd := r.root.fd
+	dirfd := rootfd
+	defer func() {
+		if dirfd != rootfd {
+			syscall.Close(dirfd)
+		}
+	}()
+
+	symlinks := 0
+	i := 0
+	for {
+		if parts[i] == ".." {
+			// Opening ".." could leave the root, so rather than
+			// opening it, remove it along with the component it
+			// cancels out, and start over from the root.
+			parts, err = collapseParent(parts, i)
+			if err != nil {
+				return ret, err
+			}
+			i = 0
+			if dirfd != rootfd {
+				syscall.Close(dirfd)
+				dirfd = rootfd
+			}
+			continue
+		}
+
+		if i == len(parts)-1 {
+			ret, err = f(dirfd, parts[i])
+		} else {
+			var fd int
+			fd, err = rootOpenDir(dirfd, parts[i])
+			if err == nil {
+				if dirfd != rootfd {
+					syscall.Close(dirfd)
+				}
+				dirfd = fd
+				i++
+				continue
+			}
+		}
+
+		link, ok := err.(errSymlink)
+		if !ok {
+			return ret, err
+		}
+		symlinks++
+		if symlinks > rootMaxSymlinks {
+			return ret, syscall.ELOOP
+		}
+		// The link is relative to dirfd, which holds parts[:i].
+		parts, err = splitPathInRoot(string(link), parts[:i], parts[i+1:])
+		if err != nil {
+			return ret, err
+		}
+		if i == len(parts) {
+			// The link refers to dirfd itself.
+			parts = append(parts, ".")
+		}
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os_test
+
+import (
+	"bytes"
+	"internal/poll"
+	"io"
+	"math/rand"
+	"net"
+	. "os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestSendFile(t *testing.T) {
+	sizes := []int{
+		1,
+		42,
+		1025,
+		32769,
+		1 << 20,
+	}
+	for _, network := range []string{"tcp", "unix"} {
+		for _, size := range sizes {
+			t.Run(network+"/"+strconv.Itoa(size), func(t *testing.T) {
+				testSendFile(t, network, int64(size))
+			})
+		}
+	}
+}
+
+func testSendFile(t *testing.T, network string, size int64) {
+	called := false
+	orig := *PollSendFileP
+	*PollSendFileP = func(dst *poll.FD, src int, remain int64) (int64, error, bool) {
+		called = true
+		return orig(dst, src, remain)
+	}
+	t.Cleanup(func() { *PollSendFileP = orig })
+
+	src, data := newZeroCopyFile(t, size)
+	client, server := newZeroCopySocketPair(t, network)
+
+	done := make(chan []byte)
+	go func() {
+		b, _ := io.ReadAll(client)
+		done <- b
+	}()
+	n, err := io.Copy(server, src)
+	server.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != size {
+		t.Errorf("io.Copy = %d; want %d", n, size)
+	}
+	if !called {
+		t.Error("io.Copy from a file to a socket did not use sendfile")
+	}
+	if got := <-done; !bytes.Equal(got, data) {
+		t.Errorf("received %d bytes different from the %d sent", len(got), len(data))
+	}
+}
+
+func TestSpliceFile(t *testing.T) {
+	for _, network := range []string{"tcp", "unix"} {
+		t.Run("SocketToFile/"+network, func(t *testing.T) {
+			called := hookSplice(t)
+			client, server := newZeroCopySocketPair(t, network)
+
+			data := make([]byte, 1<<20)
+			rand.New(rand.NewSource(1)).Read(data)
+			go func() {
+				client.Write(data)
+				client.Close()
+			}()
+
+			dst, err := Create(filepath.Join(t.TempDir(), "dst"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer dst.Close()
+			lr := &io.LimitedReader{R: server, N: int64(len(data)) - 1}
+			n, err := io.Copy(dst, lr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != int64(len(data))-1 || lr.N != 0 {
+				t.Errorf("io.Copy = %d, LimitedReader.N = %d; want %d, 0", n, lr.N, len(data)-1)
+			}
+			if !*called {
+				t.Error("io.Copy from a socket to a file did not use splice")
+			}
+			mustSeekStart(t, dst)
+			mustContainData(t, dst, data[:len(data)-1])
+		})
+
+		t.Run("PipeToSocket/"+network, func(t *testing.T) {
+			called := hookSplice(t)
+			client, server := newZeroCopySocketPair(t, network)
+			pr, pw, err := Pipe()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer pr.Close()
+
+			data := []byte("hello, splice")
+			go func() {
+				pw.Write(data)
+				pw.Close()
+			}()
+			done := make(chan []byte)
+			go func() {
+				b, _ := io.ReadAll(client)
+				done <- b
+			}()
+			if _, err := io.Copy(server, pr); err != nil {
+				t.Fatal(err)
+			}
+			server.Close()
+			if !*called {
+				t.Error("io.Copy from a pipe to a socket did not use splice")
+			}
+			if got := <-done; !bytes.Equal(got, data) {
+				t.Errorf("received %q; want %q", got, data)
+			}
+		})
+	}
+}
+
+func hookSplice(t *testing.T) *bool {
+	called := new(bool)
+	orig := *PollSpliceP
+	*PollSpliceP = func(dst, src *poll.FD, remain int64) (int64, bool, string, error) {
+		*called = true
+		return orig(dst, src, remain)
+	}
+	t.Cleanup(func() { *PollSpliceP = orig })
+	return called
+}
+
+func newZeroCopyFile(t *testing.T, size int64) (*File, []byte) {
+	t.Helper()
+	data := make([]byte, size)
+	rand.New(rand.NewSource(size)).Read(data)
+	f, err := Create(filepath.Join(t.TempDir(), "src"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+	if _, err := f.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	mustSeekStart(t, f)
+	return f, data
+}
+
+// newZeroCopySocketPair returns both ends of a connected stream socket.
+func newZeroCopySocketPair(t *testing.T, network string) (client, server net.Conn) {
+	t.Helper()
+	addr := "127.0.0.1:0"
+	if network == "unix" {
+		addr = filepath.Join(t.TempDir(), "sock")
+	}
+	ln, err := net.Listen(network, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		c, err := ln.Accept()
+		if err != nil {
+			t.Error(err)
+		}
+		accepted <- c
+	}()
+	client, err = net.Dial(network, ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	server = <-accepted
+	if server == nil {
+		t.FailNow()
+	}
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+	return client, server
+}
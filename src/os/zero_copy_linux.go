@@ -0,0 +1,200 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package os
+
+import (
+	"internal/poll"
+	"io"
+	"syscall"
+)
+
+var (
+	pollCopyFileRange = poll.CopyFileRange
+	pollSplice        = poll.Splice
+	pollSendFile      = poll.SendFile
+)
+
+func (f *File) writeTo(w io.Writer) (written int64, handled bool, err error) {
+	pfd, network := getPollFDAndNetwork(w)
+	// sendfile(2) and splice(2) only help with stream-oriented
+	// sockets; datagrams are sent one by one anyway.
+	if pfd == nil || !pfd.IsStream || !isUnixOrTCP(string(network)) {
+		return
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, false, nil
+	}
+	switch mode := fi.Mode(); {
+	case mode.IsRegular():
+		sc, err := f.SyscallConn()
+		if err != nil {
+			return 0, false, nil
+		}
+		var werr error
+		rerr := sc.Read(func(fd uintptr) bool {
+			written, werr, handled = pollSendFile(pfd, int(fd), 1<<62)
+			return true
+		})
+		if werr == nil {
+			werr = rerr
+		}
+		return written, handled, wrapSyscallError("sendfile", werr)
+	case mode&(ModeNamedPipe|ModeSocket) != 0 && f.nonblock:
+		// sendfile(2) cannot read from pipes and sockets,
+		// but splice(2) can.
+		var sc string
+		written, handled, sc, err = pollSplice(pfd, &f.pfd, 1<<62)
+		return written, handled, wrapSyscallError(sc, err)
+	}
+	return 0, false, nil
+}
+
+func (f *File) readFrom(r io.Reader) (written int64, handled bool, err error) {
+	// Neither copy_file_range(2) nor splice(2) supports destinations
+	// opened with O_APPEND, so don't even try.
+	if f.appendMode {
+		return 0, false, nil
+	}
+	written, handled, err = f.copyFileRange(r)
+	if handled {
+		return
+	}
+	return f.spliceToFile(r)
+}
+
+func (f *File) spliceToFile(r io.Reader) (written int64, handled bool, err error) {
+	var (
+		remain int64
+		lr     *io.LimitedReader
+	)
+	if lr, r, remain = tryLimitedReader(r); remain <= 0 {
+		return 0, true, nil
+	}
+
+	var pfd *poll.FD
+	if src, ok := unwrapFile(r); ok {
+		// copy_file_range(2) has already been tried, and failed;
+		// splice(2) can still move data out of a pipe.
+		if src.checkValid("ReadFrom") != nil || src == f {
+			return
+		}
+		fi, err := src.Stat()
+		if err != nil || fi.Mode()&ModeNamedPipe == 0 || !src.nonblock {
+			return 0, false, nil
+		}
+		pfd = &src.pfd
+	} else {
+		var network poll.String
+		pfd, network = getPollFDAndNetwork(r)
+		// splice(2) only helps with stream-oriented sockets, and
+		// datagram sockets are not even supported by older kernels.
+		if pfd == nil || !pfd.IsStream || !isUnixOrTCP(string(network)) {
+			return
+		}
+	}
+
+	if !f.nonblock {
+		// splice(2) would fail with EAGAIN on a full pipe in
+		// blocking mode, which the poller cannot wait for.
+		if fi, err := f.Stat(); err != nil || !fi.Mode().IsRegular() {
+			return 0, false, nil
+		}
+	}
+
+	var syscallName string
+	written, handled, syscallName, err = pollSplice(&f.pfd, pfd, remain)
+
+	if lr != nil {
+		lr.N = remain - written
+	}
+
+	return written, handled, wrapSyscallError(syscallName, err)
+}
+
+func (f *File) copyFileRange(r io.Reader) (written int64, handled bool, err error) {
+	var (
+		remain int64
+		lr     *io.LimitedReader
+	)
+	if lr, r, remain = tryLimitedReader(r); remain <= 0 {
+		return 0, true, nil
+	}
+
+	src, ok := unwrapFile(r)
+	if !ok {
+		return 0, false, nil
+	}
+	if src.checkValid("ReadFrom") != nil {
+		// Avoid returning the error as we report handled as false,
+		// leave further error handling as the responsibility of the caller.
+		return 0, false, nil
+	}
+
+	written, handled, err = pollCopyFileRange(&f.pfd, &src.pfd, remain)
+	if lr != nil {
+		lr.N -= written
+	}
+	return written, handled, wrapSyscallError("copy_file_range", err)
+}
+
+// unwrapFile returns the *File behind r, which is either a *File or,
+// when the copy comes from File.WriteTo, a fileWithoutWriteTo.
+func unwrapFile(r io.Reader) (*File, bool) {
+	switch v := r.(type) {
+	case *File:
+		return v, true
+	case fileWithoutWriteTo:
+		return v.File, true
+	}
+	return nil, false
+}
+
+// tryLimitedReader tries to assert the io.Reader to io.LimitedReader, it returns the io.LimitedReader,
+// the underlying io.Reader and the remaining amount of bytes if the assertion succeeds,
+// otherwise it just returns the original io.Reader and the theoretical unlimited remaining amount of bytes.
+func tryLimitedReader(r io.Reader) (*io.LimitedReader, io.Reader, int64) {
+	var remain int64 = 1<<63 - 1 // by default, copy until EOF
+
+	lr, ok := r.(*io.LimitedReader)
+	if !ok {
+		return nil, r, remain
+	}
+
+	remain = lr.N
+	return lr, lr.R, remain
+}
+
+// getPollFDAndNetwork returns the poll.FD and the network of the
+// socket behind i, through the extension methods of the
+// syscall.RawConn of the net package, or nil if i is not a socket.
+func getPollFDAndNetwork(i any) (*poll.FD, poll.String) {
+	sc, ok := i.(syscall.Conn)
+	if !ok {
+		return nil, ""
+	}
+	rc, err := sc.SyscallConn()
+	if err != nil {
+		return nil, ""
+	}
+	irc, ok := rc.(interface {
+		PollFD() *poll.FD
+		Network() poll.String
+	})
+	if !ok {
+		return nil, ""
+	}
+	return irc.PollFD(), irc.Network()
+}
+
+func isUnixOrTCP(network string) bool {
+	switch network {
+	case "tcp", "tcp4", "tcp6", "unix":
+		return true
+	default:
+		return false
+	}
+}
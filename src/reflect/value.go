@@ -1985,7 +1985,11 @@ func copyVal(typ *rtype, fl flag, ptr unsafe.Pointer) Value {
 // The arguments to a Call on the returned function should not include
 // a receiver; the returned function will always use v as the receiver.
 // Method panics if i is out of range or if v is a nil interface value.
+//
+//go:noinline
 func (v Value) Method(i int) Value {
+	// Method must not be inlined: the compiler marks its callers for
+	// the linker, which otherwise removes methods that are never called.
 	if v.typ == nil {
 		panic(&ValueError{"reflect.Value.Method", Invalid})
 	}
@@ -2021,7 +2025,10 @@ func (v Value) NumMethod() int {
 // The arguments to a Call on the returned function should not include
 // a receiver; the returned function will always use v as the receiver.
 // It returns the zero Value if no method was found.
+//
+//go:noinline
 func (v Value) MethodByName(name string) Value {
+	// MethodByName must not be inlined, for the same reason as Method.
 	if v.typ == nil {
 		panic(&ValueError{"reflect.Value.MethodByName", Invalid})
 	}
@@ -27,6 +27,7 @@ var (
 	x86HasPOPCNT bool
 	x86HasSSE41  bool
 	x86HasFMA    bool
+	x86HasAVX2   bool
 
 	armHasVFPv4 bool
 
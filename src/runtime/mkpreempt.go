@@ -244,10 +244,14 @@ func genAMD64() {
 			l.add("MOVQ", reg, 8)
 		}
 	}
+	// Vector code compiled with GOEXPERIMENT=simd keeps 256-bit values
+	// in the Y registers, so save them in full if the CPU has AVX.
 	lSSE := layout{stack: l.stack, sp: "SP"}
+	lAVX := layout{stack: l.stack, sp: "SP"}
 	for _, reg := range regNamesAMD64 {
 		if strings.HasPrefix(reg, "X") {
 			lSSE.add("MOVUPS", reg, 16)
+			lAVX.add("VMOVDQU", "Y"+reg[1:], 32)
 		}
 	}
 
@@ -258,30 +262,43 @@ func genAMD64() {
 	p("// Save flags before clobbering them")
 	p("PUSHFQ")
 	p("// obj doesn't understand ADD/SUB on SP, but does understand ADJSP")
-	p("ADJSP $%d", lSSE.stack)
+	p("ADJSP $%d", lAVX.stack)
 	p("// But vet doesn't know ADJSP, so suppress vet stack checking")
 	p("NOP SP")
 
 	l.save()
 
-	// Apparently, the signal handling code path in darwin kernel leaves
-	// the upper bits of Y registers in a dirty state, which causes
-	// many SSE operations (128-bit and narrower) become much slower.
-	// Clear the upper bits to get to a clean state. See issue #37174.
-	// It is safe here as Go code don't use the upper bits of Y registers.
-	p("#ifdef GOOS_darwin")
 	p("#ifndef hasAVX")
 	p("CMPB internal∕cpu·X86+const_offsetX86HasAVX(SB), $0")
-	p("JE 2(PC)")
+	p("JE saveSSE")
 	p("#endif")
+	lAVX.save()
+	// Clear the upper bits of the Y registers, now that they are saved,
+	// so that the SSE instructions in the Go code below don't pay for
+	// them. Apparently, the signal handling code path in darwin kernel
+	// leaves them in a dirty state even if no Go code used them. See
+	// issue #37174.
 	p("VZEROUPPER")
-	p("#endif")
-
+	p("#ifndef hasAVX")
+	p("JMP preempt")
+	label("saveSSE:")
 	lSSE.save()
+	label("preempt:")
+	p("#endif")
 	p("CALL ·asyncPreempt2(SB)")
+	p("#ifndef hasAVX")
+	p("CMPB internal∕cpu·X86+const_offsetX86HasAVX(SB), $0")
+	p("JE restoreSSE")
+	p("#endif")
+	lAVX.restore()
+	p("#ifndef hasAVX")
+	p("JMP restored")
+	label("restoreSSE:")
 	lSSE.restore()
+	label("restored:")
+	p("#endif")
 	l.restore()
-	p("ADJSP $%d", -lSSE.stack)
+	p("ADJSP $%d", -lAVX.stack)
 	p("POPFQ")
 	p("POPQ BP")
 	p("RET")
@@ -354,10 +371,19 @@ func genARM64() {
 		"MOVD %d(RSP), R0\nMOVD R0, FPSR",
 		8)
 	// TODO: FPCR? I don't think we'll change it, so no need to save.
-	// Add floating point registers F0-F31.
+	// Add floating point registers F0-F31. Vector code compiled with
+	// GOEXPERIMENT=simd keeps 128-bit values in F0-F15, so save those in
+	// full. FSTPQ needs a multiple of 16 for its offset.
+	if l.stack%16 != 0 {
+		l.stack += 8
+	}
 	for i := 0; i < 31; i += 2 {
 		reg := fmt.Sprintf("(F%d, F%d)", i, i+1)
-		l.add2("FSTPD", "FLDPD", reg, 16)
+		if i < 16 {
+			l.add2("FSTPQ", "FLDPQ", reg, 32)
+		} else {
+			l.add2("FSTPD", "FLDPD", reg, 16)
+		}
 	}
 	if l.stack%16 != 0 {
 		l.stack += 8 // SP needs 16-byte alignment
@@ -628,6 +628,7 @@ func cpuinit(env string) {
 		x86HasPOPCNT = cpu.X86.HasPOPCNT
 		x86HasSSE41 = cpu.X86.HasSSE41
 		x86HasFMA = cpu.X86.HasFMA
+		x86HasAVX2 = cpu.X86.HasAVX2
 
 	case "arm":
 		armHasVFPv4 = cpu.ARM.HasVFPv4
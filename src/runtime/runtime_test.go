@@ -306,6 +306,7 @@ func TestTrailingZero(t *testing.T) {
 
 func TestAppendGrowth(t *testing.T) {
 	var x []int64
+	Escape(&x) // so that x grows on the heap, not in a stack buffer
 	check := func(want int) {
 		if cap(x) != want {
 			t.Errorf("len=%d, cap=%d, want cap=%d", len(x), cap(x), want)
@@ -327,6 +328,7 @@ var One = []int64{1}
 
 func TestAppendSliceGrowth(t *testing.T) {
 	var x []int64
+	Escape(&x) // so that x grows on the heap, not in a stack buffer
 	check := func(want int) {
 		if cap(x) != want {
 			t.Errorf("len=%d, cap=%d, want cap=%d", len(x), cap(x), want)
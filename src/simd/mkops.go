@@ -0,0 +1,155 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build ignore
+
+// This program generates ops.go, the vector types and their portable
+// implementations. The compiler replaces most of the methods with vector
+// instructions; see cmd/compile/internal/ssagen/simdintrinsics.go.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+)
+
+var header = `// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Code generated by go run mkops.go. DO NOT EDIT.
+
+//go:build goexperiment.simd
+
+package simd
+`
+
+type vec struct {
+	elem  string // lane type
+	bits  int    // lane size in bits
+	lanes int
+	float bool
+}
+
+func (v vec) Name() string {
+	return fmt.Sprintf("%s%sx%d", upper(v.elem[:1]), v.elem[1:], v.lanes)
+}
+
+func upper(s string) string {
+	return string(s[0] - 'a' + 'A')
+}
+
+func main() {
+	var vecs []vec
+	for _, width := range []int{128, 256} {
+		for _, bits := range []int{8, 16, 32, 64} {
+			for _, elem := range []string{"int", "uint"} {
+				vecs = append(vecs, vec{fmt.Sprint(elem, bits), bits, width / bits, false})
+			}
+		}
+		for _, bits := range []int{32, 64} {
+			vecs = append(vecs, vec{fmt.Sprint("float", bits), bits, width / bits, true})
+		}
+	}
+
+	buf := bytes.NewBufferString(header)
+	for _, v := range vecs {
+		gen(buf, v)
+	}
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("ops.go", out, 0666); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func gen(buf *bytes.Buffer, v vec) {
+	p := func(format string, args ...any) {
+		fmt.Fprintf(buf, format, args...)
+		buf.WriteByte('\n')
+	}
+	t, e := v.Name(), v.elem
+
+	p("")
+	p("// %s is a %d-bit vector of %d %s lanes.", t, v.bits*v.lanes, v.lanes, e)
+	p("type %s [%d]%s", t, v.lanes, e)
+	p("")
+	p("// Load%s returns a vector of the first %d elements of s.", t, v.lanes)
+	p("// It panics if len(s) < %d.", v.lanes)
+	p("func Load%s(s []%s) %s {", t, e, t)
+	p("	return %s(s)", t)
+	p("}")
+	p("")
+	p("// Store stores the lanes of x in the first %d elements of s.", v.lanes)
+	p("// It panics if len(s) < %d.", v.lanes)
+	p("func (x %s) Store(s []%s) {", t, e)
+	p("	*(*%s)(s) = x", t)
+	p("}")
+	p("")
+	p("// Broadcast%s returns a vector with all lanes set to e.", t)
+	p("func Broadcast%s(e %s) %s {", t, e, t)
+	p("	var x %s", t)
+	p("	for i := range x {")
+	p("		x[i] = e")
+	p("	}")
+	p("	return x")
+	p("}")
+
+	binop := func(name, op, doc string) {
+		p("")
+		p("// %s returns the lane-wise %s.", name, doc)
+		p("func (x %s) %s(y %s) %s {", t, name, t, t)
+		p("	for i := range x {")
+		p("		x[i] %s= y[i]", op)
+		p("	}")
+		p("	return x")
+		p("}")
+	}
+	binop("Add", "+", "sum x+y")
+	binop("Sub", "-", "difference x-y")
+	if v.float {
+		binop("Mul", "*", "product x*y")
+		binop("Div", "/", "quotient x/y")
+		return
+	}
+	if v.bits == 16 || v.bits == 32 {
+		binop("Mul", "*", "product x*y, truncated to the lane size")
+	}
+	binop("And", "&", "bitwise and x&y")
+	binop("Or", "|", "bitwise or x|y")
+	binop("Xor", "^", "bitwise exclusive or x^y")
+	binop("AndNot", "&^", "bit clear x&^y")
+
+	p("")
+	p("// Equal returns a vector whose lanes have all bits set where the")
+	p("// lanes of x and y are equal, and are zero where they differ.")
+	p("func (x %s) Equal(y %s) %s {", t, t, t)
+	p("	var z %s", t)
+	p("	for i := range x {")
+	p("		if x[i] == y[i] {")
+	p("			z[i] = ^%s(0)", e)
+	p("		}")
+	p("	}")
+	p("	return z")
+	p("}")
+
+	if v.bits == 8 {
+		p("")
+		p("// Mask returns a bit mask of the most significant bits of the")
+		p("// lanes of x: bit i of the result is the top bit of x[i].")
+		p("func (x %s) Mask() uint32 {", t)
+		p("	var m uint32")
+		p("	for i := range x {")
+		p("		m |= uint32(uint8(x[i])>>7) << i")
+		p("	}")
+		p("	return m")
+		p("}")
+	}
+}
@@ -0,0 +1,1681 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Code generated by go run mkops.go. DO NOT EDIT.
+
+//go:build goexperiment.simd
+
+package simd
+
+// Int8x16 is a 128-bit vector of 16 int8 lanes.
+type Int8x16 [16]int8
+
+// LoadInt8x16 returns a vector of the first 16 elements of s.
+// It panics if len(s) < 16.
+func LoadInt8x16(s []int8) Int8x16 {
+	return Int8x16(s)
+}
+
+// Store stores the lanes of x in the first 16 elements of s.
+// It panics if len(s) < 16.
+func (x Int8x16) Store(s []int8) {
+	*(*Int8x16)(s) = x
+}
+
+// BroadcastInt8x16 returns a vector with all lanes set to e.
+func BroadcastInt8x16(e int8) Int8x16 {
+	var x Int8x16
+	for i := range x {
+		x[i] = e
+	}
+	return x
+}
+
+// Add returns the lane-wise sum x+y.
+func (x Int8x16) Add(y Int8x16) Int8x16 {
+	for i := range x {
+		x[i] += y[i]
+	}
+	return x
+}
+
+// Sub returns the lane-wise difference x-y.
+func (x Int8x16) Sub(y Int8x16) Int8x16 {
+	for i := range x {
+		x[i] -= y[i]
+	}
+	return x
+}
+
+// And returns the lane-wise bitwise and x&y.
+func (x Int8x16) And(y Int8x16) Int8x16 {
+	for i := range x {
+		x[i] &= y[i]
+	}
+	return x
+}
+
+// Or returns the lane-wise bitwise or x|y.
+func (x Int8x16) Or(y Int8x16) Int8x16 {
+	for i := range x {
+		x[i] |= y[i]
+	}
+	return x
+}
+
+// Xor returns the lane-wise bitwise exclusive or x^y.
+func (x Int8x16) Xor(y Int8x16) Int8x16 {
+	for i := range x {
+		x[i] ^= y[i]
+	}
+	return x
+}
+
+// AndNot returns the lane-wise bit clear x&^y.
+func (x Int8x16) AndNot(y Int8x16) Int8x16 {
+	for i := range x {
+		x[i] &^= y[i]
+	}
+	return x
+}
+
+// Equal returns a vector whose lanes have all bits set where the
+// lanes of x and y are equal, and are zero where they differ.
+func (x Int8x16) Equal(y Int8x16) Int8x16 {
+	var z Int8x16
+	for i := range x {
+		if x[i] == y[i] {
+			z[i] = ^int8(0)
+		}
+	}
+	return z
+}
+
+// Mask returns a bit mask of the most significant bits of the
+// lanes of x: bit i of the result is the top bit of x[i].
+func (x Int8x16) Mask() uint32 {
+	var m uint32
+	for i := range x {
+		m |= uint32(uint8(x[i])>>7) << i
+	}
+	return m
+}
+
+// Uint8x16 is a 128-bit vector of 16 uint8 lanes.
+type Uint8x16 [16]uint8
+
+// LoadUint8x16 returns a vector of the first 16 elements of s.
+// It panics if len(s) < 16.
+func LoadUint8x16(s []uint8) Uint8x16 {
+	return Uint8x16(s)
+}
+
+// Store stores the lanes of x in the first 16 elements of s.
+// It panics if len(s) < 16.
+func (x Uint8x16) Store(s []uint8) {
+	*(*Uint8x16)(s) = x
+}
+
+// BroadcastUint8x16 returns a vector with all lanes set to e.
+func BroadcastUint8x16(e uint8) Uint8x16 {
+	var x Uint8x16
+	for i := range x {
+		x[i] = e
+	}
+	return x
+}
+
+// Add returns the lane-wise sum x+y.
+func (x Uint8x16) Add(y Uint8x16) Uint8x16 {
+	for i := range x {
+		x[i] += y[i]
+	}
+	return x
+}
+
+// Sub returns the lane-wise difference x-y.
+func (x Uint8x16) Sub(y Uint8x16) Uint8x16 {
+	for i := range x {
+		x[i] -= y[i]
+	}
+	return x
+}
+
+// And returns the lane-wise bitwise and x&y.
+func (x Uint8x16) And(y Uint8x16) Uint8x16 {
+	for i := range x {
+		x[i] &= y[i]
+	}
+	return x
+}
+
+// Or returns the lane-wise bitwise or x|y.
+func (x Uint8x16) Or(y Uint8x16) Uint8x16 {
+	for i := range x {
+		x[i] |= y[i]
+	}
+	return x
+}
+
+// Xor returns the lane-wise bitwise exclusive or x^y.
+func (x Uint8x16) Xor(y Uint8x16) Uint8x16 {
+	for i := range x {
+		x[i] ^= y[i]
+	}
+	return x
+}
+
+// AndNot returns the lane-wise bit clear x&^y.
+func (x Uint8x16) AndNot(y Uint8x16) Uint8x16 {
+	for i := range x {
+		x[i] &^= y[i]
+	}
+	return x
+}
+
+// Equal returns a vector whose lanes have all bits set where the
+// lanes of x and y are equal, and are zero where they differ.
+func (x Uint8x16) Equal(y Uint8x16) Uint8x16 {
+	var z Uint8x16
+	for i := range x {
+		if x[i] == y[i] {
+			z[i] = ^uint8(0)
+		}
+	}
+	return z
+}
+
+// Mask returns a bit mask of the most significant bits of the
+// lanes of x: bit i of the result is the top bit of x[i].
+func (x Uint8x16) Mask() uint32 {
+	var m uint32
+	for i := range x {
+		m |= uint32(uint8(x[i])>>7) << i
+	}
+	return m
+}
+
+// Int16x8 is a 128-bit vector of 8 int16 lanes.
+type Int16x8 [8]int16
+
+// LoadInt16x8 returns a vector of the first 8 elements of s.
+// It panics if len(s) < 8.
+func LoadInt16x8(s []int16) Int16x8 {
+	return Int16x8(s)
+}
+
+// Store stores the lanes of x in the first 8 elements of s.
+// It panics if len(s) < 8.
+func (x Int16x8) Store(s []int16) {
+	*(*Int16x8)(s) = x
+}
+
+// BroadcastInt16x8 returns a vector with all lanes set to e.
+func BroadcastInt16x8(e int16) Int16x8 {
+	var x Int16x8
+	for i := range x {
+		x[i] = e
+	}
+	return x
+}
+
+// Add returns the lane-wise sum x+y.
+func (x Int16x8) Add(y Int16x8) Int16x8 {
+	for i := range x {
+		x[i] += y[i]
+	}
+	return x
+}
+
+// Sub returns the lane-wise difference x-y.
+func (x Int16x8) Sub(y Int16x8) Int16x8 {
+	for i := range x {
+		x[i] -= y[i]
+	}
+	return x
+}
+
+// Mul returns the lane-wise product x*y, truncated to the lane size.
+func (x Int16x8) Mul(y Int16x8) Int16x8 {
+	for i := range x {
+		x[i] *= y[i]
+	}
+	return x
+}
+
+// And returns the lane-wise bitwise and x&y.
+func (x Int16x8) And(y Int16x8) Int16x8 {
+	for i := range x {
+		x[i] &= y[i]
+	}
+	return x
+}
+
+// Or returns the lane-wise bitwise or x|y.
+func (x Int16x8) Or(y Int16x8) Int16x8 {
+	for i := range x {
+		x[i] |= y[i]
+	}
+	return x
+}
+
+// Xor returns the lane-wise bitwise exclusive or x^y.
+func (x Int16x8) Xor(y Int16x8) Int16x8 {
+	for i := range x {
+		x[i] ^= y[i]
+	}
+	return x
+}
+
+// AndNot returns the lane-wise bit clear x&^y.
+func (x Int16x8) AndNot(y Int16x8) Int16x8 {
+	for i := range x {
+		x[i] &^= y[i]
+	}
+	return x
+}
+
+// Equal returns a vector whose lanes have all bits set where the
+// lanes of x and y are equal, and are zero where they differ.
+func (x Int16x8) Equal(y Int16x8) Int16x8 {
+	var z Int16x8
+	for i := range x {
+		if x[i] == y[i] {
+			z[i] = ^int16(0)
+		}
+	}
+	return z
+}
+
+// Uint16x8 is a 128-bit vector of 8 uint16 lanes.
+type Uint16x8 [8]uint16
+
+// LoadUint16x8 returns a vector of the first 8 elements of s.
+// It panics if len(s) < 8.
+func LoadUint16x8(s []uint16) Uint16x8 {
+	return Uint16x8(s)
+}
+
+// Store stores the lanes of x in the first 8 elements of s.
+// It panics if len(s) < 8.
+func (x Uint16x8) Store(s []uint16) {
+	*(*Uint16x8)(s) = x
+}
+
+// BroadcastUint16x8 returns a vector with all lanes set to e.
+func BroadcastUint16x8(e uint16) Uint16x8 {
+	var x Uint16x8
+	for i := range x {
+		x[i] = e
+	}
+	return x
+}
+
+// Add returns the lane-wise sum x+y.
+func (x Uint16x8) Add(y Uint16x8) Uint16x8 {
+	for i := range x {
+		x[i] += y[i]
+	}
+	return x
+}
+
+// Sub returns the lane-wise difference x-y.
+func (x Uint16x8) Sub(y Uint16x8) Uint16x8 {
+	for i := range x {
+		x[i] -= y[i]
+	}
+	return x
+}
+
+// Mul returns the lane-wise product x*y, truncated to the lane size.
+func (x Uint16x8) Mul(y Uint16x8) Uint16x8 {
+	for i := range x {
+		x[i] *= y[i]
+	}
+	return x
+}
+
+// And returns the lane-wise bitwise and x&y.
+func (x Uint16x8) And(y Uint16x8) Uint16x8 {
+	for i := range x {
+		x[i] &= y[i]
+	}
+	return x
+}
+
+// Or returns the lane-wise bitwise or x|y.
+func (x Uint16x8) Or(y Uint16x8) Uint16x8 {
+	for i := range x {
+		x[i] |= y[i]
+	}
+	return x
+}
+
+// Xor returns the lane-wise bitwise exclusive or x^y.
+func (x Uint16x8) Xor(y Uint16x8) Uint16x8 {
+	for i := range x {
+		x[i] ^= y[i]
+	}
+	return x
+}
+
+// AndNot returns the lane-wise bit clear x&^y.
+func (x Uint16x8) AndNot(y Uint16x8) Uint16x8 {
+	for i := range x {
+		x[i] &^= y[i]
+	}
+	return x
+}
+
+// Equal returns a vector whose lanes have all bits set where the
+// lanes of x and y are equal, and are zero where they differ.
+func (x Uint16x8) Equal(y Uint16x8) Uint16x8 {
+	var z Uint16x8
+	for i := range x {
+		if x[i] == y[i] {
+			z[i] = ^uint16(0)
+		}
+	}
+	return z
+}
+
+// Int32x4 is a 128-bit vector of 4 int32 lanes.
+type Int32x4 [4]int32
+
+// LoadInt32x4 returns a vector of the first 4 elements of s.
+// It panics if len(s) < 4.
+func LoadInt32x4(s []int32) Int32x4 {
+	return Int32x4(s)
+}
+
+// Store stores the lanes of x in the first 4 elements of s.
+// It panics if len(s) < 4.
+func (x Int32x4) Store(s []int32) {
+	*(*Int32x4)(s) = x
+}
+
+// BroadcastInt32x4 returns a vector with all lanes set to e.
+func BroadcastInt32x4(e int32) Int32x4 {
+	var x Int32x4
+	for i := range x {
+		x[i] = e
+	}
+	return x
+}
+
+// Add returns the lane-wise sum x+y.
+func (x Int32x4) Add(y Int32x4) Int32x4 {
+	for i := range x {
+		x[i] += y[i]
+	}
+	return x
+}
+
+// Sub returns the lane-wise difference x-y.
+func (x Int32x4) Sub(y Int32x4) Int32x4 {
+	for i := range x {
+		x[i] -= y[i]
+	}
+	return x
+}
+
+// Mul returns the lane-wise product x*y, truncated to the lane size.
+func (x Int32x4) Mul(y Int32x4) Int32x4 {
+	for i := range x {
+		x[i] *= y[i]
+	}
+	return x
+}
+
+// And returns the lane-wise bitwise and x&y.
+func (x Int32x4) And(y Int32x4) Int32x4 {
+	for i := range x {
+		x[i] &= y[i]
+	}
+	return x
+}
+
+// Or returns the lane-wise bitwise or x|y.
+func (x Int32x4) Or(y Int32x4) Int32x4 {
+	for i := range x {
+		x[i] |= y[i]
+	}
+	return x
+}
+
+// Xor returns the lane-wise bitwise exclusive or x^y.
+func (x Int32x4) Xor(y Int32x4) Int32x4 {
+	for i := range x {
+		x[i] ^= y[i]
+	}
+	return x
+}
+
+// AndNot returns the lane-wise bit clear x&^y.
+func (x Int32x4) AndNot(y Int32x4) Int32x4 {
+	for i := range x {
+		x[i] &^= y[i]
+	}
+	return x
+}
+
+// Equal returns a vector whose lanes have all bits set where the
+// lanes of x and y are equal, and are zero where they differ.
+func (x Int32x4) Equal(y Int32x4) Int32x4 {
+	var z Int32x4
+	for i := range x {
+		if x[i] == y[i] {
+			z[i] = ^int32(0)
+		}
+	}
+	return z
+}
+
+// Uint32x4 is a 128-bit vector of 4 uint32 lanes.
+type Uint32x4 [4]uint32
+
+// LoadUint32x4 returns a vector of the first 4 elements of s.
+// It panics if len(s) < 4.
+func LoadUint32x4(s []uint32) Uint32x4 {
+	return Uint32x4(s)
+}
+
+// Store stores the lanes of x in the first 4 elements of s.
+// It panics if len(s) < 4.
+func (x Uint32x4) Store(s []uint32) {
+	*(*Uint32x4)(s) = x
+}
+
+// BroadcastUint32x4 returns a vector with all lanes set to e.
+func BroadcastUint32x4(e uint32) Uint32x4 {
+	var x Uint32x4
+	for i := range x {
+		x[i] = e
+	}
+	return x
+}
+
+// Add returns the lane-wise sum x+y.
+func (x Uint32x4) Add(y Uint32x4) Uint32x4 {
+	for i := range x {
+		x[i] += y[i]
+	}
+	return x
+}
+
+// Sub returns the lane-wise difference x-y.
+func (x Uint32x4) Sub(y Uint32x4) Uint32x4 {
+	for i := range x {
+		x[i] -= y[i]
+	}
+	return x
+}
+
+// Mul returns the lane-wise product x*y, truncated to the lane size.
+func (x Uint32x4) Mul(y Uint32x4) Uint32x4 {
+	for i := range x {
+		x[i] *= y[i]
+	}
+	return x
+}
+
+// And returns the lane-wise bitwise and x&y.
+func (x Uint32x4) And(y Uint32x4) Uint32x4 {
+	for i := range x {
+		x[i] &= y[i]
+	}
+	return x
+}
+
+// Or returns the lane-wise bitwise or x|y.
+func (x Uint32x4) Or(y Uint32x4) Uint32x4 {
+	for i := range x {
+		x[i] |= y[i]
+	}
+	return x
+}
+
+// Xor returns the lane-wise bitwise exclusive or x^y.
+func (x Uint32x4) Xor(y Uint32x4) Uint32x4 {
+	for i := range x {
+		x[i] ^= y[i]
+	}
+	return x
+}
+
+// AndNot returns the lane-wise bit clear x&^y.
+func (x Uint32x4) AndNot(y Uint32x4) Uint32x4 {
+	for i := range x {
+		x[i] &^= y[i]
+	}
+	return x
+}
+
+// Equal returns a vector whose lanes have all bits set where the
+// lanes of x and y are equal, and are zero where they differ.
+func (x Uint32x4) Equal(y Uint32x4) Uint32x4 {
+	var z Uint32x4
+	for i := range x {
+		if x[i] == y[i] {
+			z[i] = ^uint32(0)
+		}
+	}
+	return z
+}
+
+// Int64x2 is a 128-bit vector of 2 int64 lanes.
+type Int64x2 [2]int64
+
+// LoadInt64x2 returns a vector of the first 2 elements of s.
+// It panics if len(s) < 2.
+func LoadInt64x2(s []int64) Int64x2 {
+	return Int64x2(s)
+}
+
+// Store stores the lanes of x in the first 2 elements of s.
+// It panics if len(s) < 2.
+func (x Int64x2) Store(s []int64) {
+	*(*Int64x2)(s) = x
+}
+
+// BroadcastInt64x2 returns a vector with all lanes set to e.
+func BroadcastInt64x2(e int64) Int64x2 {
+	var x Int64x2
+	for i := range x {
+		x[i] = e
+	}
+	return x
+}
+
+// Add returns the lane-wise sum x+y.
+func (x Int64x2) Add(y Int64x2) Int64x2 {
+	for i := range x {
+		x[i] += y[i]
+	}
+	return x
+}
+
+// Sub returns the lane-wise difference x-y.
+func (x Int64x2) Sub(y Int64x2) Int64x2 {
+	for i := range x {
+		x[i] -= y[i]
+	}
+	return x
+}
+
+// And returns the lane-wise bitwise and x&y.
+func (x Int64x2) And(y Int64x2) Int64x2 {
+	for i := range x {
+		x[i] &= y[i]
+	}
+	return x
+}
+
+// Or returns the lane-wise bitwise or x|y.
+func (x Int64x2) Or(y Int64x2) Int64x2 {
+	for i := range x {
+		x[i] |= y[i]
+	}
+	return x
+}
+
+// Xor returns the lane-wise bitwise exclusive or x^y.
+func (x Int64x2) Xor(y Int64x2) Int64x2 {
+	for i := range x {
+		x[i] ^= y[i]
+	}
+	return x
+}
+
+// AndNot returns the lane-wise bit clear x&^y.
+func (x Int64x2) AndNot(y Int64x2) Int64x2 {
+	for i := range x {
+		x[i] &^= y[i]
+	}
+	return x
+}
+
+// Equal returns a vector whose lanes have all bits set where the
+// lanes of x and y are equal, and are zero where they differ.
+func (x Int64x2) Equal(y Int64x2) Int64x2 {
+	var z Int64x2
+	for i := range x {
+		if x[i] == y[i] {
+			z[i] = ^int64(0)
+		}
+	}
+	return z
+}
+
+// Uint64x2 is a 128-bit vector of 2 uint64 lanes.
+type Uint64x2 [2]uint64
+
+// LoadUint64x2 returns a vector of the first 2 elements of s.
+// It panics if len(s) < 2.
+func LoadUint64x2(s []uint64) Uint64x2 {
+	return Uint64x2(s)
+}
+
+// Store stores the lanes of x in the first 2 elements of s.
+// It panics if len(s) < 2.
+func (x Uint64x2) Store(s []uint64) {
+	*(*Uint64x2)(s) = x
+}
+
+// BroadcastUint64x2 returns a vector with all lanes set to e.
+func BroadcastUint64x2(e uint64) Uint64x2 {
+	var x Uint64x2
+	for i := range x {
+		x[i] = e
+	}
+	return x
+}
+
+// Add returns the lane-wise sum x+y.
+func (x Uint64x2) Add(y Uint64x2) Uint64x2 {
+	for i := range x {
+		x[i] += y[i]
+	}
+	return x
+}
+
+// Sub returns the lane-wise difference x-y.
+func (x Uint64x2) Sub(y Uint64x2) Uint64x2 {
+	for i := range x {
+		x[i] -= y[i]
+	}
+	return x
+}
+
+// And returns the lane-wise bitwise and x&y.
+func (x Uint64x2) And(y Uint64x2) Uint64x2 {
+	for i := range x {
+		x[i] &= y[i]
+	}
+	return x
+}
+
+// Or returns the lane-wise bitwise or x|y.
+func (x Uint64x2) Or(y Uint64x2) Uint64x2 {
+	for i := range x {
+		x[i] |= y[i]
+	}
+	return x
+}
+
+// Xor returns the lane-wise bitwise exclusive or x^y.
+func (x Uint64x2) Xor(y Uint64x2) Uint64x2 {
+	for i := range x {
+		x[i] ^= y[i]
+	}
+	return x
+}
+
+// AndNot returns the lane-wise bit clear x&^y.
+func (x Uint64x2) AndNot(y Uint64x2) Uint64x2 {
+	for i := range x {
+		x[i] &^= y[i]
+	}
+	return x
+}
+
+// Equal returns a vector whose lanes have all bits set where the
+// lanes of x and y are equal, and are zero where they differ.
+func (x Uint64x2) Equal(y Uint64x2) Uint64x2 {
+	var z Uint64x2
+	for i := range x {
+		if x[i] == y[i] {
+			z[i] = ^uint64(0)
+		}
+	}
+	return z
+}
+
+// Float32x4 is a 128-bit vector of 4 float32 lanes.
+type Float32x4 [4]float32
+
+// LoadFloat32x4 returns a vector of the first 4 elements of s.
+// It panics if len(s) < 4.
+func LoadFloat32x4(s []float32) Float32x4 {
+	return Float32x4(s)
+}
+
+// Store stores the lanes of x in the first 4 elements of s.
+// It panics if len(s) < 4.
+func (x Float32x4) Store(s []float32) {
+	*(*Float32x4)(s) = x
+}
+
+// BroadcastFloat32x4 returns a vector with all lanes set to e.
+func BroadcastFloat32x4(e float32) Float32x4 {
+	var x Float32x4
+	for i := range x {
+		x[i] = e
+	}
+	return x
+}
+
+// Add returns the lane-wise sum x+y.
+func (x Float32x4) Add(y Float32x4) Float32x4 {
+	for i := range x {
+		x[i] += y[i]
+	}
+	return x
+}
+
+// Sub returns the lane-wise difference x-y.
+func (x Float32x4) Sub(y Float32x4) Float32x4 {
+	for i := range x {
+		x[i] -= y[i]
+	}
+	return x
+}
+
+// Mul returns the lane-wise product x*y.
+func (x Float32x4) Mul(y Float32x4) Float32x4 {
+	for i := range x {
+		x[i] *= y[i]
+	}
+	return x
+}
+
+// Div returns the lane-wise quotient x/y.
+func (x Float32x4) Div(y Float32x4) Float32x4 {
+	for i := range x {
+		x[i] /= y[i]
+	}
+	return x
+}
+
+// Float64x2 is a 128-bit vector of 2 float64 lanes.
+type Float64x2 [2]float64
+
+// LoadFloat64x2 returns a vector of the first 2 elements of s.
+// It panics if len(s) < 2.
+func LoadFloat64x2(s []float64) Float64x2 {
+	return Float64x2(s)
+}
+
+// Store stores the lanes of x in the first 2 elements of s.
+// It panics if len(s) < 2.
+func (x Float64x2) Store(s []float64) {
+	*(*Float64x2)(s) = x
+}
+
+// BroadcastFloat64x2 returns a vector with all lanes set to e.
+func BroadcastFloat64x2(e float64) Float64x2 {
+	var x Float64x2
+	for i := range x {
+		x[i] = e
+	}
+	return x
+}
+
+// Add returns the lane-wise sum x+y.
+func (x Float64x2) Add(y Float64x2) Float64x2 {
+	for i := range x {
+		x[i] += y[i]
+	}
+	return x
+}
+
+// Sub returns the lane-wise difference x-y.
+func (x Float64x2) Sub(y Float64x2) Float64x2 {
+	for i := range x {
+		x[i] -= y[i]
+	}
+	return x
+}
+
+// Mul returns the lane-wise product x*y.
+func (x Float64x2) Mul(y Float64x2) Float64x2 {
+	for i := range x {
+		x[i] *= y[i]
+	}
+	return x
+}
+
+// Div returns the lane-wise quotient x/y.
+func (x Float64x2) Div(y Float64x2) Float64x2 {
+	for i := range x {
+		x[i] /= y[i]
+	}
+	return x
+}
+
+// Int8x32 is a 256-bit vector of 32 int8 lanes.
+type Int8x32 [32]int8
+
+// LoadInt8x32 returns a vector of the first 32 elements of s.
+// It panics if len(s) < 32.
+func LoadInt8x32(s []int8) Int8x32 {
+	return Int8x32(s)
+}
+
+// Store stores the lanes of x in the first 32 elements of s.
+// It panics if len(s) < 32.
+func (x Int8x32) Store(s []int8) {
+	*(*Int8x32)(s) = x
+}
+
+// BroadcastInt8x32 returns a vector with all lanes set to e.
+func BroadcastInt8x32(e int8) Int8x32 {
+	var x Int8x32
+	for i := range x {
+		x[i] = e
+	}
+	return x
+}
+
+// Add returns the lane-wise sum x+y.
+func (x Int8x32) Add(y Int8x32) Int8x32 {
+	for i := range x {
+		x[i] += y[i]
+	}
+	return x
+}
+
+// Sub returns the lane-wise difference x-y.
+func (x Int8x32) Sub(y Int8x32) Int8x32 {
+	for i := range x {
+		x[i] -= y[i]
+	}
+	return x
+}
+
+// And returns the lane-wise bitwise and x&y.
+func (x Int8x32) And(y Int8x32) Int8x32 {
+	for i := range x {
+		x[i] &= y[i]
+	}
+	return x
+}
+
+// Or returns the lane-wise bitwise or x|y.
+func (x Int8x32) Or(y Int8x32) Int8x32 {
+	for i := range x {
+		x[i] |= y[i]
+	}
+	return x
+}
+
+// Xor returns the lane-wise bitwise exclusive or x^y.
+func (x Int8x32) Xor(y Int8x32) Int8x32 {
+	for i := range x {
+		x[i] ^= y[i]
+	}
+	return x
+}
+
+// AndNot returns the lane-wise bit clear x&^y.
+func (x Int8x32) AndNot(y Int8x32) Int8x32 {
+	for i := range x {
+		x[i] &^= y[i]
+	}
+	return x
+}
+
+// Equal returns a vector whose lanes have all bits set where the
+// lanes of x and y are equal, and are zero where they differ.
+func (x Int8x32) Equal(y Int8x32) Int8x32 {
+	var z Int8x32
+	for i := range x {
+		if x[i] == y[i] {
+			z[i] = ^int8(0)
+		}
+	}
+	return z
+}
+
+// Mask returns a bit mask of the most significant bits of the
+// lanes of x: bit i of the result is the top bit of x[i].
+func (x Int8x32) Mask() uint32 {
+	var m uint32
+	for i := range x {
+		m |= uint32(uint8(x[i])>>7) << i
+	}
+	return m
+}
+
+// Uint8x32 is a 256-bit vector of 32 uint8 lanes.
+type Uint8x32 [32]uint8
+
+// LoadUint8x32 returns a vector of the first 32 elements of s.
+// It panics if len(s) < 32.
+func LoadUint8x32(s []uint8) Uint8x32 {
+	return Uint8x32(s)
+}
+
+// Store stores the lanes of x in the first 32 elements of s.
+// It panics if len(s) < 32.
+func (x Uint8x32) Store(s []uint8) {
+	*(*Uint8x32)(s) = x
+}
+
+// BroadcastUint8x32 returns a vector with all lanes set to e.
+func BroadcastUint8x32(e uint8) Uint8x32 {
+	var x Uint8x32
+	for i := range x {
+		x[i] = e
+	}
+	return x
+}
+
+// Add returns the lane-wise sum x+y.
+func (x Uint8x32) Add(y Uint8x32) Uint8x32 {
+	for i := range x {
+		x[i] += y[i]
+	}
+	return x
+}
+
+// Sub returns the lane-wise difference x-y.
+func (x Uint8x32) Sub(y Uint8x32) Uint8x32 {
+	for i := range x {
+		x[i] -= y[i]
+	}
+	return x
+}
+
+// And returns the lane-wise bitwise and x&y.
+func (x Uint8x32) And(y Uint8x32) Uint8x32 {
+	for i := range x {
+		x[i] &= y[i]
+	}
+	return x
+}
+
+// Or returns the lane-wise bitwise or x|y.
+func (x Uint8x32) Or(y Uint8x32) Uint8x32 {
+	for i := range x {
+		x[i] |= y[i]
+	}
+	return x
+}
+
+// Xor returns the lane-wise bitwise exclusive or x^y.
+func (x Uint8x32) Xor(y Uint8x32) Uint8x32 {
+	for i := range x {
+		x[i] ^= y[i]
+	}
+	return x
+}
+
+// AndNot returns the lane-wise bit clear x&^y.
+func (x Uint8x32) AndNot(y Uint8x32) Uint8x32 {
+	for i := range x {
+		x[i] &^= y[i]
+	}
+	return x
+}
+
+// Equal returns a vector whose lanes have all bits set where the
+// lanes of x and y are equal, and are zero where they differ.
+func (x Uint8x32) Equal(y Uint8x32) Uint8x32 {
+	var z Uint8x32
+	for i := range x {
+		if x[i] == y[i] {
+			z[i] = ^uint8(0)
+		}
+	}
+	return z
+}
+
+// Mask returns a bit mask of the most significant bits of the
+// lanes of x: bit i of the result is the top bit of x[i].
+func (x Uint8x32) Mask() uint32 {
+	var m uint32
+	for i := range x {
+		m |= uint32(uint8(x[i])>>7) << i
+	}
+	return m
+}
+
+// Int16x16 is a 256-bit vector of 16 int16 lanes.
+type Int16x16 [16]int16
+
+// LoadInt16x16 returns a vector of the first 16 elements of s.
+// It panics if len(s) < 16.
+func LoadInt16x16(s []int16) Int16x16 {
+	return Int16x16(s)
+}
+
+// Store stores the lanes of x in the first 16 elements of s.
+// It panics if len(s) < 16.
+func (x Int16x16) Store(s []int16) {
+	*(*Int16x16)(s) = x
+}
+
+// BroadcastInt16x16 returns a vector with all lanes set to e.
+func BroadcastInt16x16(e int16) Int16x16 {
+	var x Int16x16
+	for i := range x {
+		x[i] = e
+	}
+	return x
+}
+
+// Add returns the lane-wise sum x+y.
+func (x Int16x16) Add(y Int16x16) Int16x16 {
+	for i := range x {
+		x[i] += y[i]
+	}
+	return x
+}
+
+// Sub returns the lane-wise difference x-y.
+func (x Int16x16) Sub(y Int16x16) Int16x16 {
+	for i := range x {
+		x[i] -= y[i]
+	}
+	return x
+}
+
+// Mul returns the lane-wise product x*y, truncated to the lane size.
+func (x Int16x16) Mul(y Int16x16) Int16x16 {
+	for i := range x {
+		x[i] *= y[i]
+	}
+	return x
+}
+
+// And returns the lane-wise bitwise and x&y.
+func (x Int16x16) And(y Int16x16) Int16x16 {
+	for i := range x {
+		x[i] &= y[i]
+	}
+	return x
+}
+
+// Or returns the lane-wise bitwise or x|y.
+func (x Int16x16) Or(y Int16x16) Int16x16 {
+	for i := range x {
+		x[i] |= y[i]
+	}
+	return x
+}
+
+// Xor returns the lane-wise bitwise exclusive or x^y.
+func (x Int16x16) Xor(y Int16x16) Int16x16 {
+	for i := range x {
+		x[i] ^= y[i]
+	}
+	return x
+}
+
+// AndNot returns the lane-wise bit clear x&^y.
+func (x Int16x16) AndNot(y Int16x16) Int16x16 {
+	for i := range x {
+		x[i] &^= y[i]
+	}
+	return x
+}
+
+// Equal returns a vector whose lanes have all bits set where the
+// lanes of x and y are equal, and are zero where they differ.
+func (x Int16x16) Equal(y Int16x16) Int16x16 {
+	var z Int16x16
+	for i := range x {
+		if x[i] == y[i] {
+			z[i] = ^int16(0)
+		}
+	}
+	return z
+}
+
+// Uint16x16 is a 256-bit vector of 16 uint16 lanes.
+type Uint16x16 [16]uint16
+
+// LoadUint16x16 returns a vector of the first 16 elements of s.
+// It panics if len(s) < 16.
+func LoadUint16x16(s []uint16) Uint16x16 {
+	return Uint16x16(s)
+}
+
+// Store stores the lanes of x in the first 16 elements of s.
+// It panics if len(s) < 16.
+func (x Uint16x16) Store(s []uint16) {
+	*(*Uint16x16)(s) = x
+}
+
+// BroadcastUint16x16 returns a vector with all lanes set to e.
+func BroadcastUint16x16(e uint16) Uint16x16 {
+	var x Uint16x16
+	for i := range x {
+		x[i] = e
+	}
+	return x
+}
+
+// Add returns the lane-wise sum x+y.
+func (x Uint16x16) Add(y Uint16x16) Uint16x16 {
+	for i := range x {
+		x[i] += y[i]
+	}
+	return x
+}
+
+// Sub returns the lane-wise difference x-y.
+func (x Uint16x16) Sub(y Uint16x16) Uint16x16 {
+	for i := range x {
+		x[i] -= y[i]
+	}
+	return x
+}
+
+// Mul returns the lane-wise product x*y, truncated to the lane size.
+func (x Uint16x16) Mul(y Uint16x16) Uint16x16 {
+	for i := range x {
+		x[i] *= y[i]
+	}
+	return x
+}
+
+// And returns the lane-wise bitwise and x&y.
+func (x Uint16x16) And(y Uint16x16) Uint16x16 {
+	for i := range x {
+		x[i] &= y[i]
+	}
+	return x
+}
+
+// Or returns the lane-wise bitwise or x|y.
+func (x Uint16x16) Or(y Uint16x16) Uint16x16 {
+	for i := range x {
+		x[i] |= y[i]
+	}
+	return x
+}
+
+// Xor returns the lane-wise bitwise exclusive or x^y.
+func (x Uint16x16) Xor(y Uint16x16) Uint16x16 {
+	for i := range x {
+		x[i] ^= y[i]
+	}
+	return x
+}
+
+// AndNot returns the lane-wise bit clear x&^y.
+func (x Uint16x16) AndNot(y Uint16x16) Uint16x16 {
+	for i := range x {
+		x[i] &^= y[i]
+	}
+	return x
+}
+
+// Equal returns a vector whose lanes have all bits set where the
+// lanes of x and y are equal, and are zero where they differ.
+func (x Uint16x16) Equal(y Uint16x16) Uint16x16 {
+	var z Uint16x16
+	for i := range x {
+		if x[i] == y[i] {
+			z[i] = ^uint16(0)
+		}
+	}
+	return z
+}
+
+// Int32x8 is a 256-bit vector of 8 int32 lanes.
+type Int32x8 [8]int32
+
+// LoadInt32x8 returns a vector of the first 8 elements of s.
+// It panics if len(s) < 8.
+func LoadInt32x8(s []int32) Int32x8 {
+	return Int32x8(s)
+}
+
+// Store stores the lanes of x in the first 8 elements of s.
+// It panics if len(s) < 8.
+func (x Int32x8) Store(s []int32) {
+	*(*Int32x8)(s) = x
+}
+
+// BroadcastInt32x8 returns a vector with all lanes set to e.
+func BroadcastInt32x8(e int32) Int32x8 {
+	var x Int32x8
+	for i := range x {
+		x[i] = e
+	}
+	return x
+}
+
+// Add returns the lane-wise sum x+y.
+func (x Int32x8) Add(y Int32x8) Int32x8 {
+	for i := range x {
+		x[i] += y[i]
+	}
+	return x
+}
+
+// Sub returns the lane-wise difference x-y.
+func (x Int32x8) Sub(y Int32x8) Int32x8 {
+	for i := range x {
+		x[i] -= y[i]
+	}
+	return x
+}
+
+// Mul returns the lane-wise product x*y, truncated to the lane size.
+func (x Int32x8) Mul(y Int32x8) Int32x8 {
+	for i := range x {
+		x[i] *= y[i]
+	}
+	return x
+}
+
+// And returns the lane-wise bitwise and x&y.
+func (x Int32x8) And(y Int32x8) Int32x8 {
+	for i := range x {
+		x[i] &= y[i]
+	}
+	return x
+}
+
+// Or returns the lane-wise bitwise or x|y.
+func (x Int32x8) Or(y Int32x8) Int32x8 {
+	for i := range x {
+		x[i] |= y[i]
+	}
+	return x
+}
+
+// Xor returns the lane-wise bitwise exclusive or x^y.
+func (x Int32x8) Xor(y Int32x8) Int32x8 {
+	for i := range x {
+		x[i] ^= y[i]
+	}
+	return x
+}
+
+// AndNot returns the lane-wise bit clear x&^y.
+func (x Int32x8) AndNot(y Int32x8) Int32x8 {
+	for i := range x {
+		x[i] &^= y[i]
+	}
+	return x
+}
+
+// Equal returns a vector whose lanes have all bits set where the
+// lanes of x and y are equal, and are zero where they differ.
+func (x Int32x8) Equal(y Int32x8) Int32x8 {
+	var z Int32x8
+	for i := range x {
+		if x[i] == y[i] {
+			z[i] = ^int32(0)
+		}
+	}
+	return z
+}
+
+// Uint32x8 is a 256-bit vector of 8 uint32 lanes.
+type Uint32x8 [8]uint32
+
+// LoadUint32x8 returns a vector of the first 8 elements of s.
+// It panics if len(s) < 8.
+func LoadUint32x8(s []uint32) Uint32x8 {
+	return Uint32x8(s)
+}
+
+// Store stores the lanes of x in the first 8 elements of s.
+// It panics if len(s) < 8.
+func (x Uint32x8) Store(s []uint32) {
+	*(*Uint32x8)(s) = x
+}
+
+// BroadcastUint32x8 returns a vector with all lanes set to e.
+func BroadcastUint32x8(e uint32) Uint32x8 {
+	var x Uint32x8
+	for i := range x {
+		x[i] = e
+	}
+	return x
+}
+
+// Add returns the lane-wise sum x+y.
+func (x Uint32x8) Add(y Uint32x8) Uint32x8 {
+	for i := range x {
+		x[i] += y[i]
+	}
+	return x
+}
+
+// Sub returns the lane-wise difference x-y.
+func (x Uint32x8) Sub(y Uint32x8) Uint32x8 {
+	for i := range x {
+		x[i] -= y[i]
+	}
+	return x
+}
+
+// Mul returns the lane-wise product x*y, truncated to the lane size.
+func (x Uint32x8) Mul(y Uint32x8) Uint32x8 {
+	for i := range x {
+		x[i] *= y[i]
+	}
+	return x
+}
+
+// And returns the lane-wise bitwise and x&y.
+func (x Uint32x8) And(y Uint32x8) Uint32x8 {
+	for i := range x {
+		x[i] &= y[i]
+	}
+	return x
+}
+
+// Or returns the lane-wise bitwise or x|y.
+func (x Uint32x8) Or(y Uint32x8) Uint32x8 {
+	for i := range x {
+		x[i] |= y[i]
+	}
+	return x
+}
+
+// Xor returns the lane-wise bitwise exclusive or x^y.
+func (x Uint32x8) Xor(y Uint32x8) Uint32x8 {
+	for i := range x {
+		x[i] ^= y[i]
+	}
+	return x
+}
+
+// AndNot returns the lane-wise bit clear x&^y.
+func (x Uint32x8) AndNot(y Uint32x8) Uint32x8 {
+	for i := range x {
+		x[i] &^= y[i]
+	}
+	return x
+}
+
+// Equal returns a vector whose lanes have all bits set where the
+// lanes of x and y are equal, and are zero where they differ.
+func (x Uint32x8) Equal(y Uint32x8) Uint32x8 {
+	var z Uint32x8
+	for i := range x {
+		if x[i] == y[i] {
+			z[i] = ^uint32(0)
+		}
+	}
+	return z
+}
+
+// Int64x4 is a 256-bit vector of 4 int64 lanes.
+type Int64x4 [4]int64
+
+// LoadInt64x4 returns a vector of the first 4 elements of s.
+// It panics if len(s) < 4.
+func LoadInt64x4(s []int64) Int64x4 {
+	return Int64x4(s)
+}
+
+// Store stores the lanes of x in the first 4 elements of s.
+// It panics if len(s) < 4.
+func (x Int64x4) Store(s []int64) {
+	*(*Int64x4)(s) = x
+}
+
+// BroadcastInt64x4 returns a vector with all lanes set to e.
+func BroadcastInt64x4(e int64) Int64x4 {
+	var x Int64x4
+	for i := range x {
+		x[i] = e
+	}
+	return x
+}
+
+// Add returns the lane-wise sum x+y.
+func (x Int64x4) Add(y Int64x4) Int64x4 {
+	for i := range x {
+		x[i] += y[i]
+	}
+	return x
+}
+
+// Sub returns the lane-wise difference x-y.
+func (x Int64x4) Sub(y Int64x4) Int64x4 {
+	for i := range x {
+		x[i] -= y[i]
+	}
+	return x
+}
+
+// And returns the lane-wise bitwise and x&y.
+func (x Int64x4) And(y Int64x4) Int64x4 {
+	for i := range x {
+		x[i] &= y[i]
+	}
+	return x
+}
+
+// Or returns the lane-wise bitwise or x|y.
+func (x Int64x4) Or(y Int64x4) Int64x4 {
+	for i := range x {
+		x[i] |= y[i]
+	}
+	return x
+}
+
+// Xor returns the lane-wise bitwise exclusive or x^y.
+func (x Int64x4) Xor(y Int64x4) Int64x4 {
+	for i := range x {
+		x[i] ^= y[i]
+	}
+	return x
+}
+
+// AndNot returns the lane-wise bit clear x&^y.
+func (x Int64x4) AndNot(y Int64x4) Int64x4 {
+	for i := range x {
+		x[i] &^= y[i]
+	}
+	return x
+}
+
+// Equal returns a vector whose lanes have all bits set where the
+// lanes of x and y are equal, and are zero where they differ.
+func (x Int64x4) Equal(y Int64x4) Int64x4 {
+	var z Int64x4
+	for i := range x {
+		if x[i] == y[i] {
+			z[i] = ^int64(0)
+		}
+	}
+	return z
+}
+
+// Uint64x4 is a 256-bit vector of 4 uint64 lanes.
+type Uint64x4 [4]uint64
+
+// LoadUint64x4 returns a vector of the first 4 elements of s.
+// It panics if len(s) < 4.
+func LoadUint64x4(s []uint64) Uint64x4 {
+	return Uint64x4(s)
+}
+
+// Store stores the lanes of x in the first 4 elements of s.
+// It panics if len(s) < 4.
+func (x Uint64x4) Store(s []uint64) {
+	*(*Uint64x4)(s) = x
+}
+
+// BroadcastUint64x4 returns a vector with all lanes set to e.
+func BroadcastUint64x4(e uint64) Uint64x4 {
+	var x Uint64x4
+	for i := range x {
+		x[i] = e
+	}
+	return x
+}
+
+// Add returns the lane-wise sum x+y.
+func (x Uint64x4) Add(y Uint64x4) Uint64x4 {
+	for i := range x {
+		x[i] += y[i]
+	}
+	return x
+}
+
+// Sub returns the lane-wise difference x-y.
+func (x Uint64x4) Sub(y Uint64x4) Uint64x4 {
+	for i := range x {
+		x[i] -= y[i]
+	}
+	return x
+}
+
+// And returns the lane-wise bitwise and x&y.
+func (x Uint64x4) And(y Uint64x4) Uint64x4 {
+	for i := range x {
+		x[i] &= y[i]
+	}
+	return x
+}
+
+// Or returns the lane-wise bitwise or x|y.
+func (x Uint64x4) Or(y Uint64x4) Uint64x4 {
+	for i := range x {
+		x[i] |= y[i]
+	}
+	return x
+}
+
+// Xor returns the lane-wise bitwise exclusive or x^y.
+func (x Uint64x4) Xor(y Uint64x4) Uint64x4 {
+	for i := range x {
+		x[i] ^= y[i]
+	}
+	return x
+}
+
+// AndNot returns the lane-wise bit clear x&^y.
+func (x Uint64x4) AndNot(y Uint64x4) Uint64x4 {
+	for i := range x {
+		x[i] &^= y[i]
+	}
+	return x
+}
+
+// Equal returns a vector whose lanes have all bits set where the
+// lanes of x and y are equal, and are zero where they differ.
+func (x Uint64x4) Equal(y Uint64x4) Uint64x4 {
+	var z Uint64x4
+	for i := range x {
+		if x[i] == y[i] {
+			z[i] = ^uint64(0)
+		}
+	}
+	return z
+}
+
+// Float32x8 is a 256-bit vector of 8 float32 lanes.
+type Float32x8 [8]float32
+
+// LoadFloat32x8 returns a vector of the first 8 elements of s.
+// It panics if len(s) < 8.
+func LoadFloat32x8(s []float32) Float32x8 {
+	return Float32x8(s)
+}
+
+// Store stores the lanes of x in the first 8 elements of s.
+// It panics if len(s) < 8.
+func (x Float32x8) Store(s []float32) {
+	*(*Float32x8)(s) = x
+}
+
+// BroadcastFloat32x8 returns a vector with all lanes set to e.
+func BroadcastFloat32x8(e float32) Float32x8 {
+	var x Float32x8
+	for i := range x {
+		x[i] = e
+	}
+	return x
+}
+
+// Add returns the lane-wise sum x+y.
+func (x Float32x8) Add(y Float32x8) Float32x8 {
+	for i := range x {
+		x[i] += y[i]
+	}
+	return x
+}
+
+// Sub returns the lane-wise difference x-y.
+func (x Float32x8) Sub(y Float32x8) Float32x8 {
+	for i := range x {
+		x[i] -= y[i]
+	}
+	return x
+}
+
+// Mul returns the lane-wise product x*y.
+func (x Float32x8) Mul(y Float32x8) Float32x8 {
+	for i := range x {
+		x[i] *= y[i]
+	}
+	return x
+}
+
+// Div returns the lane-wise quotient x/y.
+func (x Float32x8) Div(y Float32x8) Float32x8 {
+	for i := range x {
+		x[i] /= y[i]
+	}
+	return x
+}
+
+// Float64x4 is a 256-bit vector of 4 float64 lanes.
+type Float64x4 [4]float64
+
+// LoadFloat64x4 returns a vector of the first 4 elements of s.
+// It panics if len(s) < 4.
+func LoadFloat64x4(s []float64) Float64x4 {
+	return Float64x4(s)
+}
+
+// Store stores the lanes of x in the first 4 elements of s.
+// It panics if len(s) < 4.
+func (x Float64x4) Store(s []float64) {
+	*(*Float64x4)(s) = x
+}
+
+// BroadcastFloat64x4 returns a vector with all lanes set to e.
+func BroadcastFloat64x4(e float64) Float64x4 {
+	var x Float64x4
+	for i := range x {
+		x[i] = e
+	}
+	return x
+}
+
+// Add returns the lane-wise sum x+y.
+func (x Float64x4) Add(y Float64x4) Float64x4 {
+	for i := range x {
+		x[i] += y[i]
+	}
+	return x
+}
+
+// Sub returns the lane-wise difference x-y.
+func (x Float64x4) Sub(y Float64x4) Float64x4 {
+	for i := range x {
+		x[i] -= y[i]
+	}
+	return x
+}
+
+// Mul returns the lane-wise product x*y.
+func (x Float64x4) Mul(y Float64x4) Float64x4 {
+	for i := range x {
+		x[i] *= y[i]
+	}
+	return x
+}
+
+// Div returns the lane-wise quotient x/y.
+func (x Float64x4) Div(y Float64x4) Float64x4 {
+	for i := range x {
+		x[i] /= y[i]
+	}
+	return x
+}
@@ -0,0 +1,45 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build goexperiment.simd
+
+/*
+Package simd provides fixed-width vector types and operations on them.
+
+Each vector type is an array of 128 or 256 bits of integer or
+floating-point lanes, such as Uint8x16 (16 uint8 lanes) or Float64x4
+(4 float64 lanes). Operations on vectors, like Add or Equal, are lane-wise.
+As the types are arrays, individual lanes can be read and written by
+indexing.
+
+The package is portable: every operation has a plain Go implementation.
+On amd64 and arm64 the compiler replaces most operations with vector
+instructions instead, so that loops written with this package run
+several lanes at a time without assembly, and can be inlined. Operations
+that need a CPU feature which is not available, like AVX2 for the 256-bit
+vectors on amd64, check for it at run time and fall back to the Go
+implementation. Native256 reports whether that is the case.
+
+On arm64, only the operations on 128-bit integer vectors are replaced,
+except for the multiplications. The operations on floating-point and
+256-bit vectors always run the Go implementation.
+
+This package is experimental, and only available when building with
+GOEXPERIMENT=simd. Its API may change.
+*/
+package simd
+
+//go:generate go run mkops.go
+
+import (
+	"internal/cpu"
+	"internal/goarch"
+)
+
+// Native256 reports whether the operations on 256-bit vectors are
+// implemented with vector instructions on this machine. When they are
+// not, the 128-bit vector types are likely faster.
+func Native256() bool {
+	return goarch.GOARCH == "amd64" && cpu.X86.HasAVX2
+}
@@ -0,0 +1,391 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build goexperiment.simd
+
+package simd_test
+
+import (
+	"math/rand"
+	"runtime"
+	"simd"
+	"sync/atomic"
+	"testing"
+)
+
+// The methods are called directly, not through function values, so
+// that the compiler replaces them with vector instructions. The
+// expected results are computed lane by lane.
+
+func lanes[E any](x, y []E, f func(a, b E) E) []E {
+	z := make([]E, len(x))
+	for i := range x {
+		z[i] = f(x[i], y[i])
+	}
+	return z
+}
+
+func check[E comparable](t *testing.T, name string, got, want []E) {
+	t.Helper()
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("%s = %v, want %v", name, got, want)
+			return
+		}
+	}
+}
+
+func repeat[E any](e E, n int) []E {
+	s := make([]E, n)
+	for i := range s {
+		s[i] = e
+	}
+	return s
+}
+
+// fill sets the elements of x and y to random values, making half of
+// the pairs x[i], y[i] equal.
+func fill[E any](r *rand.Rand, x, y []E, gen func(*rand.Rand) E) {
+	for i := range x {
+		x[i] = gen(r)
+		y[i] = gen(r)
+		if i%2 == 0 {
+			y[i] = x[i]
+		}
+	}
+}
+
+func eqMask[E comparable](ones E) func(a, b E) E {
+	return func(a, b E) E {
+		var zero E
+		if a == b {
+			return ones
+		}
+		return zero
+	}
+}
+
+func genUint8(r *rand.Rand) uint8                                       { return uint8(r.Uint32()) }
+func genInt16(r *rand.Rand) int16                                       { return int16(r.Uint32()) }
+func genInt32(r *rand.Rand) int32                                       { return int32(r.Uint32()) }
+func genInt64(r *rand.Rand) int64                                       { return int64(r.Uint64()) }
+func genFloat32(r *rand.Rand) float32                                   { return float32(r.NormFloat64() * 100) }
+func genFloat64(r *rand.Rand) float64                                   { return r.NormFloat64() * 1e6 }
+func add[E int16 | int32 | int64 | uint8 | float32 | float64](a, b E) E { return a + b }
+func sub[E int16 | int32 | int64 | uint8 | float32 | float64](a, b E) E { return a - b }
+func mul[E int16 | int32 | float32 | float64](a, b E) E                 { return a * b }
+func div[E float32 | float64](a, b E) E                                 { return a / b }
+func and[E int16 | int32 | int64 | uint8](a, b E) E                     { return a & b }
+func or[E int16 | int32 | int64 | uint8](a, b E) E                      { return a | b }
+func xor[E int16 | int32 | int64 | uint8](a, b E) E                     { return a ^ b }
+func andNot[E int16 | int32 | int64 | uint8](a, b E) E                  { return a &^ b }
+
+func TestUint8x16(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		var x, y simd.Uint8x16
+		fill(r, x[:], y[:], genUint8)
+		z := x.Add(y)
+		check(t, "Add", z[:], lanes(x[:], y[:], add[uint8]))
+		z = x.Sub(y)
+		check(t, "Sub", z[:], lanes(x[:], y[:], sub[uint8]))
+		z = x.And(y)
+		check(t, "And", z[:], lanes(x[:], y[:], and[uint8]))
+		z = x.Or(y)
+		check(t, "Or", z[:], lanes(x[:], y[:], or[uint8]))
+		z = x.Xor(y)
+		check(t, "Xor", z[:], lanes(x[:], y[:], xor[uint8]))
+		z = x.AndNot(y)
+		check(t, "AndNot", z[:], lanes(x[:], y[:], andNot[uint8]))
+		z = x.Equal(y)
+		check(t, "Equal", z[:], lanes(x[:], y[:], eqMask[uint8](0xff)))
+		var want uint32
+		for i, e := range x {
+			want |= uint32(e>>7) << i
+		}
+		if got := x.Mask(); got != want {
+			t.Errorf("Mask = %#x, want %#x", got, want)
+		}
+		z = simd.BroadcastUint8x16(x[3])
+		check(t, "Broadcast", z[:], repeat(x[3], 16))
+	}
+}
+
+func TestUint8x32(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		var x, y simd.Uint8x32
+		fill(r, x[:], y[:], genUint8)
+		z := x.Add(y)
+		check(t, "Add", z[:], lanes(x[:], y[:], add[uint8]))
+		z = x.Sub(y)
+		check(t, "Sub", z[:], lanes(x[:], y[:], sub[uint8]))
+		z = x.And(y)
+		check(t, "And", z[:], lanes(x[:], y[:], and[uint8]))
+		z = x.Or(y)
+		check(t, "Or", z[:], lanes(x[:], y[:], or[uint8]))
+		z = x.Xor(y)
+		check(t, "Xor", z[:], lanes(x[:], y[:], xor[uint8]))
+		z = x.AndNot(y)
+		check(t, "AndNot", z[:], lanes(x[:], y[:], andNot[uint8]))
+		z = x.Equal(y)
+		check(t, "Equal", z[:], lanes(x[:], y[:], eqMask[uint8](0xff)))
+		var want uint32
+		for i, e := range x {
+			want |= uint32(e>>7) << i
+		}
+		if got := x.Mask(); got != want {
+			t.Errorf("Mask = %#x, want %#x", got, want)
+		}
+		z = simd.BroadcastUint8x32(x[3])
+		check(t, "Broadcast", z[:], repeat(x[3], 32))
+	}
+}
+
+func TestInt16x8(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		var x, y simd.Int16x8
+		fill(r, x[:], y[:], genInt16)
+		z := x.Add(y)
+		check(t, "Add", z[:], lanes(x[:], y[:], add[int16]))
+		z = x.Sub(y)
+		check(t, "Sub", z[:], lanes(x[:], y[:], sub[int16]))
+		z = x.Mul(y)
+		check(t, "Mul", z[:], lanes(x[:], y[:], mul[int16]))
+		z = x.AndNot(y)
+		check(t, "AndNot", z[:], lanes(x[:], y[:], andNot[int16]))
+		z = x.Equal(y)
+		check(t, "Equal", z[:], lanes(x[:], y[:], eqMask[int16](-1)))
+		z = simd.BroadcastInt16x8(x[5])
+		check(t, "Broadcast", z[:], repeat(x[5], 8))
+	}
+}
+
+func TestInt16x16(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		var x, y simd.Int16x16
+		fill(r, x[:], y[:], genInt16)
+		z := x.Add(y)
+		check(t, "Add", z[:], lanes(x[:], y[:], add[int16]))
+		z = x.Sub(y)
+		check(t, "Sub", z[:], lanes(x[:], y[:], sub[int16]))
+		z = x.Mul(y)
+		check(t, "Mul", z[:], lanes(x[:], y[:], mul[int16]))
+		z = x.AndNot(y)
+		check(t, "AndNot", z[:], lanes(x[:], y[:], andNot[int16]))
+		z = x.Equal(y)
+		check(t, "Equal", z[:], lanes(x[:], y[:], eqMask[int16](-1)))
+		z = simd.BroadcastInt16x16(x[5])
+		check(t, "Broadcast", z[:], repeat(x[5], 16))
+	}
+}
+
+func TestInt32x4(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		var x, y simd.Int32x4
+		fill(r, x[:], y[:], genInt32)
+		z := x.Add(y)
+		check(t, "Add", z[:], lanes(x[:], y[:], add[int32]))
+		z = x.Sub(y)
+		check(t, "Sub", z[:], lanes(x[:], y[:], sub[int32]))
+		z = x.Mul(y)
+		check(t, "Mul", z[:], lanes(x[:], y[:], mul[int32]))
+		z = x.Or(y)
+		check(t, "Or", z[:], lanes(x[:], y[:], or[int32]))
+		z = x.Equal(y)
+		check(t, "Equal", z[:], lanes(x[:], y[:], eqMask[int32](-1)))
+		z = simd.BroadcastInt32x4(x[1])
+		check(t, "Broadcast", z[:], repeat(x[1], 4))
+	}
+}
+
+func TestInt32x8(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		var x, y simd.Int32x8
+		fill(r, x[:], y[:], genInt32)
+		z := x.Add(y)
+		check(t, "Add", z[:], lanes(x[:], y[:], add[int32]))
+		z = x.Sub(y)
+		check(t, "Sub", z[:], lanes(x[:], y[:], sub[int32]))
+		z = x.Mul(y)
+		check(t, "Mul", z[:], lanes(x[:], y[:], mul[int32]))
+		z = x.Or(y)
+		check(t, "Or", z[:], lanes(x[:], y[:], or[int32]))
+		z = x.Equal(y)
+		check(t, "Equal", z[:], lanes(x[:], y[:], eqMask[int32](-1)))
+		z = simd.BroadcastInt32x8(x[1])
+		check(t, "Broadcast", z[:], repeat(x[1], 8))
+	}
+}
+
+func TestInt64x2(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		var x, y simd.Int64x2
+		fill(r, x[:], y[:], genInt64)
+		z := x.Add(y)
+		check(t, "Add", z[:], lanes(x[:], y[:], add[int64]))
+		z = x.Sub(y)
+		check(t, "Sub", z[:], lanes(x[:], y[:], sub[int64]))
+		z = x.Xor(y)
+		check(t, "Xor", z[:], lanes(x[:], y[:], xor[int64]))
+		z = x.Equal(y)
+		check(t, "Equal", z[:], lanes(x[:], y[:], eqMask[int64](-1)))
+		z = simd.BroadcastInt64x2(x[1])
+		check(t, "Broadcast", z[:], repeat(x[1], 2))
+	}
+}
+
+func TestInt64x4(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		var x, y simd.Int64x4
+		fill(r, x[:], y[:], genInt64)
+		z := x.Add(y)
+		check(t, "Add", z[:], lanes(x[:], y[:], add[int64]))
+		z = x.Sub(y)
+		check(t, "Sub", z[:], lanes(x[:], y[:], sub[int64]))
+		z = x.Xor(y)
+		check(t, "Xor", z[:], lanes(x[:], y[:], xor[int64]))
+		z = x.Equal(y)
+		check(t, "Equal", z[:], lanes(x[:], y[:], eqMask[int64](-1)))
+		z = simd.BroadcastInt64x4(x[1])
+		check(t, "Broadcast", z[:], repeat(x[1], 4))
+	}
+}
+
+func TestFloat32x4(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		var x, y simd.Float32x4
+		fill(r, x[:], y[:], genFloat32)
+		z := x.Add(y)
+		check(t, "Add", z[:], lanes(x[:], y[:], add[float32]))
+		z = x.Sub(y)
+		check(t, "Sub", z[:], lanes(x[:], y[:], sub[float32]))
+		z = x.Mul(y)
+		check(t, "Mul", z[:], lanes(x[:], y[:], mul[float32]))
+		z = x.Div(y)
+		check(t, "Div", z[:], lanes(x[:], y[:], div[float32]))
+	}
+}
+
+func TestFloat64x4(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		var x, y simd.Float64x4
+		fill(r, x[:], y[:], genFloat64)
+		z := x.Add(y)
+		check(t, "Add", z[:], lanes(x[:], y[:], add[float64]))
+		z = x.Sub(y)
+		check(t, "Sub", z[:], lanes(x[:], y[:], sub[float64]))
+		z = x.Mul(y)
+		check(t, "Mul", z[:], lanes(x[:], y[:], mul[float64]))
+		z = x.Div(y)
+		check(t, "Div", z[:], lanes(x[:], y[:], div[float64]))
+	}
+}
+
+//go:noinline
+func countEqual(s []byte, c byte) int {
+	n := 0
+	cs := simd.BroadcastUint8x16(c)
+	for len(s) >= 16 {
+		m := simd.LoadUint8x16(s).Equal(cs).Mask()
+		for ; m != 0; m &= m - 1 {
+			n++
+		}
+		s = s[16:]
+	}
+	for _, b := range s {
+		if b == c {
+			n++
+		}
+	}
+	return n
+}
+
+//go:noinline
+func sumFloat64s(s []float64) float64 {
+	var acc simd.Float64x4
+	for len(s) >= 4 {
+		acc = acc.Add(simd.LoadFloat64x4(s))
+		s = s[4:]
+	}
+	var sum float64
+	for _, e := range acc {
+		sum += e
+	}
+	for _, e := range s {
+		sum += e
+	}
+	return sum
+}
+
+// TestLoops checks operations whose results stay in vector registers
+// across loop iterations.
+func TestLoops(t *testing.T) {
+	b := make([]byte, 1000)
+	want := 0
+	for i := range b {
+		b[i] = byte(i * 7)
+		if b[i] == 42 {
+			want++
+		}
+	}
+	if got := countEqual(b, 42); got != want {
+		t.Errorf("countEqual = %d, want %d", got, want)
+	}
+
+	f := make([]float64, 103)
+	for i := range f {
+		f[i] = float64(i)
+	}
+	if got, want := sumFloat64s(f), 103.0*102/2; got != want {
+		t.Errorf("sumFloat64s = %v, want %v", got, want)
+	}
+}
+
+func TestStore(t *testing.T) {
+	s := []int32{1, 2, 3, 4, 5}
+	x := simd.LoadInt32x4(s[1:])
+	x.Add(simd.BroadcastInt32x4(10)).Store(s)
+	check(t, "Store", s, []int32{12, 13, 14, 15, 5})
+}
+
+//go:noinline
+func countUp(n int, stop *atomic.Bool) (simd.Int64x4, simd.Int64x2) {
+	var acc4 simd.Int64x4
+	var acc2 simd.Int64x2
+	one4 := simd.BroadcastInt64x4(1)
+	one2 := simd.BroadcastInt64x2(1)
+	for i := 0; i < n; i++ {
+		acc4 = acc4.Add(one4)
+		acc2 = acc2.Add(one2)
+	}
+	stop.Store(true)
+	return acc4, acc2
+}
+
+// TestPreempt checks that vectors in registers survive asynchronous
+// preemption, which the garbage collector requests while countUp runs.
+func TestPreempt(t *testing.T) {
+	n := 10_000_000
+	if testing.Short() {
+		n = 1_000_000
+	}
+	var stop atomic.Bool
+	go func() {
+		for !stop.Load() {
+			runtime.GC()
+		}
+	}()
+	acc4, acc2 := countUp(n, &stop)
+	check(t, "Int64x4", acc4[:], repeat(int64(n), 4))
+	check(t, "Int64x2", acc2[:], repeat(int64(n), 2))
+}
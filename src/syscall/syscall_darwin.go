@@ -219,6 +219,8 @@ func Kill(pid int, signum Signal) (err error) { return kill(pid, int(signum), 1)
 //sys	fcntlPtr(fd int, cmd int, arg unsafe.Pointer) (val int, err error) = SYS_fcntl
 //sys   unlinkat(fd int, path string, flags int) (err error)
 //sys   openat(fd int, path string, flags int, perm uint32) (fdret int, err error)
+//sys   mkdirat(fd int, path string, mode uint32) (err error)
+//sys   readlinkat(fd int, path string, buf []byte) (n int, err error)
 //sys	getcwd(buf []byte) (n int, err error)
 
 func init() {
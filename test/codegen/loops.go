@@ -0,0 +1,51 @@
+// asmcheck
+
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package codegen
+
+// ------------------------------ //
+//    loop-invariant hoisting      //
+// ------------------------------ //
+
+type buffer struct {
+	buf []byte
+}
+
+func countZeros(b *buffer) (n int) {
+	// The length of b.buf is loaded once, before the loop.
+	for i := 0; i < len(b.buf); i++ { // amd64:-`CMPQ\t[0-9]*\(`
+		if b.buf[i] == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// ------------------------------ //
+//    unrolling                   //
+// ------------------------------ //
+
+func sum4(a *[4]int) (s int) {
+	for i := 0; i < 4; i++ { // amd64:-"CMPQ",-"JLT" arm64:-"CMP",-"BLT"
+		s += a[i] // amd64:`ADDQ\t24\(` arm64:`MOVD\t24\(`
+	}
+	return s
+}
+
+func sumOdd(a *[8]int) (s int) {
+	for i := 7; i > 0; i -= 2 { // amd64:-"CMPQ",-"JGT" arm64:-"CMP",-"BGT"
+		s += a[i] // amd64:`ADDQ\t8\(` arm64:`MOVD\t8\(`
+	}
+	return s
+}
+
+func sumLong(a *[100]int) (s int) {
+	// Too many iterations to unroll.
+	for i := 0; i < 100; i++ { // amd64:"CMPQ" arm64:"CMP"
+		s += a[i]
+	}
+	return s
+}
@@ -719,3 +719,92 @@ func zero_uint64_2(d1, d2 []uint64) {
 	d1[0], d1[1] = 0, 0 // arm64:"STP",-"MOVB",-"MOVH"
 	d2[1], d2[0] = 0, 0 // arm64:"STP",-"MOVB",-"MOVH"
 }
+
+// ------------------- //
+//    Unusual shapes   //
+// ------------------- //
+
+// Check that loads and stores are combined whatever the order of the
+// bytes in the OR trees and in the store sequences.
+
+func load_le64_shuffled(b []byte) uint64 {
+	_ = b[7]
+	// amd64:`MOVQ\s\(.*\),`,-`MOV[BWL]\t[^$]`,-`OR`
+	// arm64:`MOVD\s\(R[0-9]+\),`,-`MOV[BHW]`
+	// ppc64le:`MOVD\s`,-`MOV[BHW]Z`
+	return uint64(b[4])<<32 | uint64(b[0]) | uint64(b[7])<<56 | uint64(b[2])<<16 |
+		uint64(b[1])<<8 | uint64(b[6])<<48 | uint64(b[3])<<24 | uint64(b[5])<<40
+}
+
+func load_be32_shuffled(b []byte) uint32 {
+	_ = b[3]
+	// amd64/v1,amd64/v2:`BSWAPL`,-`MOV[BW]`,-`OR`
+	// amd64/v3:`MOVBEL`
+	// arm64:`REVW`,-`MOV[BH]`
+	// ppc64:`MOVWZ\s`,-`MOV[BH]Z\s`
+	return uint32(b[2])<<8 | uint32(b[0])<<24 | uint32(b[3]) | uint32(b[1])<<16
+}
+
+func load_le32_shifted(b []byte) uint64 {
+	_ = b[3]
+	// amd64:`MOVL\s\(.*\),`,`SHLQ\t[$]16`,-`MOV[BW]`,-`OR`
+	// arm64:`MOVWU\s\(R[0-9]+\),`,-`MOV[BH]`
+	return uint64(b[0])<<16 | uint64(b[1])<<24 | uint64(b[2])<<32 | uint64(b[3])<<40
+}
+
+func load_be16_varint(b []byte, i int) uint16 {
+	// amd64:`MOVWLZX\s\(.*\),`,`ROLW`,-`MOVB`,-`OR`
+	// arm64:`MOVHU\s\(R[0-9]+\)`,`REV16W`,-`MOVB`
+	return uint16(b[i+1]) | uint16(b[i])<<8
+}
+
+func store_le64_shuffled(b []byte, x uint64) {
+	_ = b[7]
+	// amd64:`MOVQ\s.*\(.*\)$`,-`SHR.`
+	// arm64:`MOVD`,-`MOV[WBH]`
+	// ppc64le:`MOVD\s`,-`MOV[BHW]\s`
+	b[3] = byte(x >> 24)
+	b[0] = byte(x)
+	b[6] = byte(x >> 48)
+	b[1] = byte(x >> 8)
+	b[7] = byte(x >> 56)
+	b[5] = byte(x >> 40)
+	b[2] = byte(x >> 16)
+	b[4] = byte(x >> 32)
+}
+
+func store_be32_shuffled(b []byte, x uint32) {
+	_ = b[3]
+	// amd64/v1,amd64/v2:`MOVL\s`,-`MOV[BW]`,-`SHR.`
+	// amd64/v3:`MOVBEL`,-`SHR.`
+	// arm64:`MOVW\s`,-`MOV[BH]`
+	// ppc64:`MOVW\s`,-`MOV[BH]\s`
+	b[1] = byte(x >> 16)
+	b[3] = byte(x)
+	b[0] = byte(x >> 24)
+	b[2] = byte(x >> 8) // amd64/v1,amd64/v2:`BSWAPL` arm64:`REVW`
+}
+
+func store_le32_high(b []byte, x uint64) {
+	_ = b[3]
+	// amd64:`MOVL\s`,-`MOV[BW]`
+	// arm64:`MOVW\s`,-`MOV[BH]`
+	b[2] = byte(x >> 48)
+	b[0] = byte(x >> 32)
+	b[3] = byte(x >> 56)
+	b[1] = byte(x >> 40) // amd64:`SHRQ\t[$]32` arm64:`LSR\t[$]32`
+}
+
+func store_const_shuffled(b []byte) {
+	_ = b[7]
+	// amd64:`MOVQ\s[$]506097522914230528`,-`MOV[BWL]\s`
+	// arm64:`MOVD\s`,-`MOV[BHW]\s`
+	b[7], b[2], b[5], b[0], b[3], b[6], b[1], b[4] = 7, 2, 5, 0, 3, 6, 1, 4
+}
+
+func copy_bytes_shuffled(dst, src []byte) {
+	_, _ = dst[7], src[7]
+	// amd64:`MOVQ\s\(.*\),`,-`MOV[BWL]\s`
+	// arm64:`MOVD\s\(R[0-9]+\),`,-`MOV[BHW]\s`
+	dst[6], dst[1], dst[4], dst[3], dst[0], dst[7], dst[2], dst[5] = src[6], src[1], src[4], src[3], src[0], src[7], src[2], src[5]
+}
@@ -0,0 +1,55 @@
+// errorcheck -0 -m -l
+
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Test escape analysis for make with a size that is only known at
+// run time. Such slices get a stack buffer if they do not escape.
+
+package escape
+
+var sink interface{}
+
+type big [64]byte
+
+func local(n int) int {
+	s := make([]int, n)     // ERROR "make\(\[\]int, n\) does not escape"
+	t := make([]byte, 1, n) // ERROR "make\(\[\]byte, 1, n\) does not escape"
+	return len(s) + len(t)
+}
+
+func returned(n int) []int {
+	return make([]int, n) // ERROR "make\(\[\]int, n\) escapes to heap"
+}
+
+func leaked(n int) {
+	sink = make([]int, n) // ERROR "make\(\[\]int, n\) escapes to heap"
+}
+
+func tooBig(n int) int {
+	s := make([]big, n) // ERROR "make\(\[\]big, n\) escapes to heap"
+	return len(s)
+}
+
+func zeroSized(n int) int {
+	s := make([]struct{}, n) // ERROR "make\(\[\]struct {}, n\) escapes to heap"
+	return len(s)
+}
+
+func inLoop(n int) int {
+	t := 0
+	for i := 0; i < n; i++ {
+		s := make([]int, i) // ERROR "make\(\[\]int, i\) does not escape"
+		t += len(s)
+	}
+	return t
+}
+
+func outlivesLoop(n int) int {
+	var keep []int
+	for i := 0; i < n; i++ {
+		keep = make([]int, i) // ERROR "make\(\[\]int, i\) escapes to heap"
+	}
+	return len(keep)
+}
@@ -1,4 +1,4 @@
-//errorcheck -0 -m -m
+//errorcheck -0 -m -m -d=variablemakethreshold=0
 
 // Copyright 2020 The Go Authors. All rights reserved.
 // Use of this source code is governed by a BSD-style
@@ -0,0 +1,46 @@
+// run
+
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Methods looked up by MethodByName with a constant name
+// must be kept by the linker, even though other methods
+// are not.
+
+package main
+
+import "reflect"
+
+var called int
+
+type foo struct{}
+
+func (foo) X() { called++ }
+func (foo) Y() { panic("FAIL") }
+
+type byName interface {
+	MethodByName(string) (reflect.Method, bool)
+}
+
+func main() {
+	v := reflect.ValueOf(foo{})
+	v.MethodByName("X").Call(nil)
+
+	m, ok := v.Type().MethodByName("X")
+	if !ok {
+		panic("FAIL")
+	}
+	m.Func.Call([]reflect.Value{v})
+
+	// A call through another interface is also seen.
+	var t byName = v.Type()
+	if m, ok = t.MethodByName("X"); !ok {
+		panic("FAIL")
+	}
+	m.Func.Interface().(func(foo))(foo{})
+
+	if called != 3 {
+		panic("FAIL")
+	}
+}